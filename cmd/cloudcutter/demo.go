@@ -0,0 +1,54 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/tpelletiersophos/cloudcutter/internal/deeplink"
+	"github.com/tpelletiersophos/cloudcutter/internal/services/aws/dynamodb"
+	"github.com/tpelletiersophos/cloudcutter/internal/services/aws/dynamodb/ddbfake"
+	"github.com/tpelletiersophos/cloudcutter/internal/services/elastic/esfake"
+)
+
+// startDemo starts the in-process Elasticsearch and DynamoDB of --demo with generated logs,
+// users and orders, the bulk files of --demo-data and the table fixtures of --demo-tables, and
+// points link at them: the local profile, the Elastic view, and the generated logs of the last
+// 24 hours unless the link names another view or index.
+func startDemo(link deeplink.Link, files, tableFiles []string) (deeplink.Link, *esfake.Server, error) {
+	now := time.Now()
+	tables := ddbfake.New()
+	if err := tables.LoadDemo(now); err != nil {
+		return link, nil, fmt.Errorf("failed to load demo tables: %w", err)
+	}
+	for _, file := range tableFiles {
+		if err := tables.LoadFixtureFile(file); err != nil {
+			return link, nil, fmt.Errorf("failed to load demo tables: %w", err)
+		}
+	}
+
+	cluster := esfake.NewServer()
+	if err := cluster.LoadDemo(now); err != nil {
+		cluster.Close()
+		return link, nil, fmt.Errorf("failed to load demo data: %w", err)
+	}
+	for _, file := range files {
+		if err := cluster.LoadBulkFile(file); err != nil {
+			cluster.Close()
+			return link, nil, fmt.Errorf("failed to load demo data: %w", err)
+		}
+	}
+	dynamodb.UseLocalClient(tables)
+
+	link.Profile = "local"
+	link.Region = ""
+	if link.View == "" {
+		link.View = deeplink.ViewElastic
+	}
+	if link.View == deeplink.ViewElastic && link.Index == "" {
+		link.Index = esfake.DemoIndexPattern
+		if link.Timeframe == "" {
+			link.Timeframe = "24h"
+		}
+	}
+	return link, cluster, nil
+}
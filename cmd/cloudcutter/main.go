@@ -10,24 +10,51 @@ import (
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
+	"github.com/tpelletiersophos/cloudcutter/internal/cli"
+	appconfig "github.com/tpelletiersophos/cloudcutter/internal/config"
+	"github.com/tpelletiersophos/cloudcutter/internal/deeplink"
 	"github.com/tpelletiersophos/cloudcutter/internal/logger"
+	"github.com/tpelletiersophos/cloudcutter/internal/services/elastic/esfake"
+	"github.com/tpelletiersophos/cloudcutter/internal/session"
 	"github.com/tpelletiersophos/cloudcutter/internal/ui/views"
 
 	awssdk "github.com/aws/aws-sdk-go-v2/aws"
-	"github.com/tpelletiersophos/cloudcutter/internal/services"
 	"github.com/tpelletiersophos/cloudcutter/internal/ui"
 	"github.com/tpelletiersophos/cloudcutter/internal/ui/manager"
 	ddbv "github.com/tpelletiersophos/cloudcutter/internal/ui/views/dynamodb"
 	elasticView "github.com/tpelletiersophos/cloudcutter/internal/ui/views/elastic"
+	logsView "github.com/tpelletiersophos/cloudcutter/internal/ui/views/logs"
 )
 
 var (
 	debugLevel string
+	startLink  deeplink.Link
+	demo       bool
+	demoData   []string
+	demoTables []string
 	rootCmd    = &cobra.Command{
-		Use:   "cloudcutter",
+		Use:   "cloudcutter [cloudcutter://link]",
 		Short: "Cloudcutter CLI",
-		Run: func(cmd *cobra.Command, args []string) {
-			runApplication()
+		Example: `  cloudcutter --profile opal_dev --view elastic --index 'logs-*' --filter 'level=error' --timeframe 1h
+  cloudcutter --profile opal_dev --view dynamodb --table users
+  cloudcutter 'cloudcutter://elastic?profile=opal_dev&index=logs-*&filter=level%3Derror'
+  cloudcutter --demo --demo-data deployments/local/data/sample-data.json
+  cloudcutter --demo --view dynamodb --demo-tables deployments/local/data/dynamodb-tables.json`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			link, err := resolveStartLink(cmd, args)
+			if err != nil {
+				return err
+			}
+			var cluster *esfake.Server
+			if demo || len(demoData) > 0 || len(demoTables) > 0 {
+				if link, cluster, err = startDemo(link, demoData, demoTables); err != nil {
+					return err
+				}
+				defer cluster.Close()
+			}
+			runApplication(link, cluster)
+			return nil
 		},
 	}
 )
@@ -36,11 +63,66 @@ func init() {
 	rootCmd.PersistentFlags().StringVar(&debugLevel, "logging", "info", "Set the debug level (e.g., debug, info, warn, error)")
 	viper.BindPFlag("logging", rootCmd.PersistentFlags().Lookup("logging"))
 
+	flags := rootCmd.Flags()
+	flags.StringVar(&startLink.Profile, "profile", "", "Authenticate with this profile instead of choosing one")
+	flags.StringVar(&startLink.Region, "region", "", "Start in this region")
+	flags.StringVar(&startLink.View, "view", "", "Open this view: elastic or dynamodb")
+	flags.StringVar(&startLink.Index, "index", "", "Elastic index or pattern to open")
+	flags.StringVar(&startLink.Table, "table", "", "DynamoDB table to open")
+	flags.StringArrayVar(&startLink.Filters, "filter", nil, "Elastic filter to apply; repeat for more")
+	flags.StringVar(&startLink.Timeframe, "timeframe", "", "Elastic timeframe, e.g. 15m, 12h, 7d")
+	flags.BoolVar(&demo, "demo", false, "Run against an in-process Elasticsearch and DynamoDB with generated data instead of AWS")
+	flags.StringArrayVar(&demoData, "demo-data", nil, "Elasticsearch bulk (NDJSON) file to load in demo mode; repeat for more. Implies --demo")
+	flags.StringArrayVar(&demoTables, "demo-tables", nil, "DynamoDB table fixture (JSON) to load in demo mode; repeat for more. Implies --demo")
+
 	viper.SetDefault("logging", "info")
 	viper.AutomaticEnv()
+
+	rootCmd.AddCommand(cli.NewESCommand(), cli.NewDDBCommand())
+}
+
+// resolveStartLink combines a cloudcutter:// argument with the startup flags, which take precedence.
+// Asking for an index, filter or timeframe implies the Elastic view, and a table the DynamoDB view.
+func resolveStartLink(cmd *cobra.Command, args []string) (deeplink.Link, error) {
+	var link deeplink.Link
+	if len(args) == 1 {
+		parsed, err := deeplink.Parse(args[0])
+		if err != nil {
+			return deeplink.Link{}, err
+		}
+		link = parsed
+	}
+
+	flags := cmd.Flags()
+	override := func(name string, target *string, value string) {
+		if flags.Changed(name) {
+			*target = value
+		}
+	}
+	override("profile", &link.Profile, startLink.Profile)
+	override("region", &link.Region, startLink.Region)
+	override("view", &link.View, startLink.View)
+	override("index", &link.Index, startLink.Index)
+	override("table", &link.Table, startLink.Table)
+	override("timeframe", &link.Timeframe, startLink.Timeframe)
+	if flags.Changed("filter") {
+		link.Filters = startLink.Filters
+	}
+
+	if link.View == "" {
+		switch {
+		case link.Index != "" || len(link.Filters) > 0 || link.Timeframe != "":
+			link.View = deeplink.ViewElastic
+		case link.Table != "":
+			link.View = deeplink.ViewDynamoDB
+		}
+	}
+	return link, link.Validate()
 }
 
-func runApplication() {
+// runApplication runs the UI from link. A demo cluster, when given, serves the local profile's
+// Elasticsearch, as startDemo's tables serve its DynamoDB, and sessions are not saved.
+func runApplication(link deeplink.Link, demoCluster *esfake.Server) {
 	ctx := context.Background()
 	app := ui.NewApp()
 
@@ -60,36 +142,76 @@ func runApplication() {
 	}
 	defer logInstance.Close()
 
-	defaultConfig, err := config.LoadDefaultConfig(ctx, config.WithRegion("us-west-2"))
+	settings, err := appconfig.Load(appconfig.Path())
+	if err != nil {
+		log.Fatalf("%v", err)
+	}
+	useDemoCluster := func(settings *appconfig.Config) {
+		if demoCluster != nil {
+			settings.Clusters.Elasticsearch.LocalEndpoint = demoCluster.URL
+		}
+	}
+	useDemoCluster(settings)
+	if err := settings.Apply(); err != nil {
+		log.Fatalf("%v", err)
+	}
+
+	defaultConfig, err := config.LoadDefaultConfig(ctx, config.WithRegion(settings.Regions.Default))
 	if err != nil {
 		logInstance.Error("Failed to load default config", "error", err)
 		defaultConfig = awssdk.Config{}
 	}
 
 	viewManager := manager.NewViewManager(ctx, app, defaultConfig, logInstance)
+	viewManager.SetRegions(settings.Regions.Available)
 
-	viewManager.ShowProfileSelector()
+	if demoCluster == nil {
+		sessions, err := session.Open(session.Path())
+		if err != nil {
+			logInstance.Warn("Ignoring the saved session", "error", err)
+		}
+		viewManager.SetSessionStore(sessions)
+	}
 
-	// Register lazy views
-	services, _ := services.New(defaultConfig, "us-west-2")
-	viewManager.RegisterLazyView(manager.ViewDynamoDB, func() (views.View, error) {
-		currentConfig := viewManager.GetCurrentConfig()
-		if err := services.InitializeDynamoDB(currentConfig); err != nil {
-			return nil, err
+	watcher, err := appconfig.Watch(appconfig.Path(), func(settings *appconfig.Config, err error) {
+		if err == nil {
+			useDemoCluster(settings)
+			err = settings.Apply()
 		}
-		return ddbv.NewView(viewManager, services.DynamoDB), nil
-	})
-	viewManager.RegisterLazyView(manager.ViewElastic, func() (views.View, error) {
-		currentConfig := viewManager.GetCurrentConfig()
-		if err := services.InitializeElastic(currentConfig); err != nil {
-			return nil, err
+		if err != nil {
+			logInstance.Error("Failed to reload config", "error", err)
+			viewManager.StatusChan <- fmt.Sprintf("[red]Config not reloaded:[-] %v", err)
+			return
 		}
-		elasticViewInstance, err := elasticView.NewView(viewManager, services.Elastic, "main-summary-*")
+		viewManager.SetRegions(settings.Regions.Available)
+		viewManager.StatusChan <- "Reloaded " + appconfig.Path()
+	})
+	if err != nil {
+		logInstance.Warn("Not watching config for changes", "error", err)
+	} else {
+		defer watcher.Close()
+	}
+
+	// Register lazy views; each session tab builds its own from its own services
+	viewManager.RegisterLazyView(manager.ViewDynamoDB, func() error {
+		return viewManager.Services().InitializeDynamoDB(viewManager.GetCurrentConfig())
+	}, func() (views.View, error) {
+		return ddbv.NewView(viewManager, viewManager.Services().DynamoDB), nil
+	})
+	viewManager.RegisterLazyView(manager.ViewElastic, func() error {
+		return viewManager.Services().InitializeElastic(viewManager.GetCurrentConfig())
+	}, func() (views.View, error) {
+		elasticViewInstance, err := elasticView.NewView(viewManager, viewManager.Services().Elastic, "")
 		if err != nil {
 			return nil, fmt.Errorf("failed to create elastic view: %w", err)
 		}
 		return elasticViewInstance, nil
 	})
+	viewManager.RegisterLazyView(manager.ViewLogs, nil, func() (views.View, error) {
+		return logsView.NewView(viewManager, logInstance.Dir()), nil
+	})
+
+	viewManager.Open(link)
 
 	if err := viewManager.Run(); err != nil {
 		logInstance.Error("Application error", "error", err)
@@ -0,0 +1,233 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+	"gopkg.in/ini.v1"
+)
+
+// defaultRoleDuration is requested for assumed roles without duration_seconds. One hour is the
+// most STS allows for chained roles and keeps MFA prompts to at most one per hour.
+const defaultRoleDuration = time.Hour
+
+// RoleHop is one role_arn profile in an assume-role chain.
+type RoleHop struct {
+	Profile         string
+	RoleARN         string
+	SourceProfile   string
+	MFASerial       string
+	ExternalID      string
+	RoleSessionName string
+	Duration        time.Duration
+}
+
+// RoleChain is the hops needed to reach a profile, outermost first, and the profile whose own
+// credentials start the chain.
+type RoleChain struct {
+	Hops        []RoleHop
+	BaseProfile string
+}
+
+// MFARequest asks the user for a token code for an MFA device.
+type MFARequest struct {
+	Profile   string
+	RoleARN   string
+	MFASerial string
+}
+
+// AWSCredentialsPath returns the shared credentials file path, honoring AWS_SHARED_CREDENTIALS_FILE.
+func AWSCredentialsPath() string {
+	if path := os.Getenv("AWS_SHARED_CREDENTIALS_FILE"); path != "" {
+		return path
+	}
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(homeDir, ".aws", "credentials")
+}
+
+// loadSharedProfiles merges the keys of every profile in the shared config and credentials files.
+func loadSharedProfiles(configPath, credentialsPath string) map[string]map[string]string {
+	profiles := make(map[string]map[string]string)
+	merge := func(name string, section *ini.Section) {
+		if profiles[name] == nil {
+			profiles[name] = make(map[string]string)
+		}
+		for _, key := range section.Keys() {
+			profiles[name][key.Name()] = key.String()
+		}
+	}
+
+	if cfg, err := ini.Load(configPath); err == nil {
+		for _, section := range cfg.Sections() {
+			if name, ok := sharedConfigProfileName(section.Name()); ok {
+				merge(name, section)
+			}
+		}
+	}
+	if cfg, err := ini.Load(credentialsPath); err == nil {
+		for _, section := range cfg.Sections() {
+			if section.Name() != ini.DefaultSection {
+				merge(section.Name(), section)
+			}
+		}
+	}
+	return profiles
+}
+
+// LoadRoleChain follows role_arn/source_profile links from profile. It returns false when the
+// profile does not assume a role, or uses a layout (credential_source, a profile sourcing itself)
+// that is left to the SDK.
+func LoadRoleChain(configPath, credentialsPath, profile string) (*RoleChain, bool, error) {
+	profiles := loadSharedProfiles(configPath, credentialsPath)
+
+	chain := &RoleChain{}
+	visited := make(map[string]bool)
+	name := profile
+
+	for {
+		keys, ok := profiles[name]
+		if !ok {
+			if name == profile {
+				return nil, false, nil
+			}
+			return nil, false, fmt.Errorf("source_profile %s not found", name)
+		}
+
+		roleARN := keys["role_arn"]
+		if roleARN == "" {
+			break
+		}
+		if keys["credential_source"] != "" || keys["source_profile"] == name {
+			if name == profile {
+				return nil, false, nil
+			}
+			// Let the SDK resolve this hop and everything below it
+			break
+		}
+
+		if visited[name] {
+			return nil, false, fmt.Errorf("source_profile loop detected at profile %s", name)
+		}
+		visited[name] = true
+
+		hop := RoleHop{
+			Profile:         name,
+			RoleARN:         roleARN,
+			SourceProfile:   keys["source_profile"],
+			MFASerial:       keys["mfa_serial"],
+			ExternalID:      keys["external_id"],
+			RoleSessionName: keys["role_session_name"],
+			Duration:        defaultRoleDuration,
+		}
+		if hop.SourceProfile == "" {
+			return nil, false, fmt.Errorf("profile %s has role_arn but no source_profile", name)
+		}
+		if seconds := keys["duration_seconds"]; seconds != "" {
+			n, err := strconv.Atoi(seconds)
+			if err != nil {
+				return nil, false, fmt.Errorf("profile %s has invalid duration_seconds %q", name, seconds)
+			}
+			hop.Duration = time.Duration(n) * time.Second
+		}
+
+		chain.Hops = append(chain.Hops, hop)
+		name = hop.SourceProfile
+	}
+
+	if len(chain.Hops) == 0 {
+		return nil, false, nil
+	}
+	chain.BaseProfile = name
+	return chain, true, nil
+}
+
+// SetMFATokenHandler registers the callback that prompts for an MFA code. It blocks until the
+// user answers or cancels.
+func (a *Authenticator) SetMFATokenHandler(fn func(MFARequest) (string, error)) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.onMFAToken = fn
+}
+
+func (a *Authenticator) mfaTokenProvider(hop RoleHop) func() (string, error) {
+	return func() (string, error) {
+		a.mu.RLock()
+		prompt := a.onMFAToken
+		a.mu.RUnlock()
+
+		if prompt == nil {
+			return "", fmt.Errorf("profile %s requires an MFA code but no prompt is available", hop.Profile)
+		}
+		a.sendStatus(fmt.Sprintf("MFA code required for %s", hop.Profile))
+		return prompt(MFARequest{Profile: hop.Profile, RoleARN: hop.RoleARN, MFASerial: hop.MFASerial})
+	}
+}
+
+// authenticateAssumeRole assumes every role in chain from its base profile's credentials, which may
+// come from any provider. Each hop's credentials are cached for the rest of the session, so switching
+// back to a profile or changing region does not ask for MFA again while they are valid.
+func (a *Authenticator) authenticateAssumeRole(ctx context.Context, chain *RoleChain, region string) (aws.Config, error) {
+	// Reuse the outermost cached hop; it already wraps every hop below it
+	a.mu.RLock()
+	start := len(chain.Hops)
+	var reused aws.CredentialsProvider
+	for i, hop := range chain.Hops {
+		if cached, ok := a.roleCredentials[hop.Profile]; ok {
+			reused, start = cached, i
+			break
+		}
+	}
+	a.mu.RUnlock()
+
+	var cfg aws.Config
+	var err error
+	if reused != nil {
+		// The base profile's credentials are not needed again, nor its provider's login
+		cfg, err = config.LoadDefaultConfig(ctx, config.WithRegion(region), config.WithCredentialsProvider(reused))
+	} else {
+		cfg, err = a.authenticate(ctx, chain.BaseProfile, region)
+	}
+	if err != nil {
+		return aws.Config{}, fmt.Errorf("failed to load source profile %s: %w", chain.BaseProfile, err)
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	for i := start - 1; i >= 0; i-- {
+		hop := chain.Hops[i]
+		client := sts.NewFromConfig(cfg)
+		provider := stscreds.NewAssumeRoleProvider(client, hop.RoleARN, func(o *stscreds.AssumeRoleOptions) {
+			o.Duration = hop.Duration
+			if hop.RoleSessionName != "" {
+				o.RoleSessionName = hop.RoleSessionName
+			}
+			if hop.ExternalID != "" {
+				o.ExternalID = aws.String(hop.ExternalID)
+			}
+			if hop.MFASerial != "" {
+				o.SerialNumber = aws.String(hop.MFASerial)
+				o.TokenProvider = a.mfaTokenProvider(hop)
+			}
+		})
+
+		cached := aws.NewCredentialsCache(provider, func(o *aws.CredentialsCacheOptions) {
+			o.ExpiryWindow = credentialsExpiryWindow
+		})
+		a.roleCredentials[hop.Profile] = cached
+		cfg.Credentials = cached
+	}
+
+	return cfg, nil
+}
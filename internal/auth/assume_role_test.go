@@ -0,0 +1,200 @@
+package auth
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+const testRoleConfig = `
+[profile base]
+region = us-west-2
+
+[profile jump]
+role_arn = arn:aws:iam::111111111111:role/Jump
+source_profile = base
+mfa_serial = arn:aws:iam::000000000000:mfa/alice
+
+[profile prod]
+role_arn = arn:aws:iam::222222222222:role/ReadOnly
+source_profile = jump
+external_id = partner-123
+role_session_name = alice
+duration_seconds = 1800
+
+[profile ec2]
+role_arn = arn:aws:iam::333333333333:role/Instance
+credential_source = Ec2InstanceMetadata
+
+[profile via-ec2]
+role_arn = arn:aws:iam::444444444444:role/Chained
+source_profile = ec2
+
+[profile loop-a]
+role_arn = arn:aws:iam::555555555555:role/A
+source_profile = loop-b
+
+[profile loop-b]
+role_arn = arn:aws:iam::555555555555:role/B
+source_profile = loop-a
+
+[profile dangling]
+role_arn = arn:aws:iam::666666666666:role/Dangling
+source_profile = missing
+`
+
+const testRoleCredentials = `
+[base]
+aws_access_key_id = AKIAEXAMPLE
+aws_secret_access_key = secret
+`
+
+// writeRoleFiles writes the role config and the base profile's credentials.
+func writeRoleFiles(t *testing.T) (string, string) {
+	t.Helper()
+	return writeSharedConfig(t, testRoleConfig), writeSharedConfig(t, testRoleCredentials)
+}
+
+func TestLoadRoleChain(t *testing.T) {
+	configPath, credentialsPath := writeRoleFiles(t)
+
+	t.Run("multi-hop chain with MFA and external ID", func(t *testing.T) {
+		chain, isRole, err := LoadRoleChain(configPath, credentialsPath, "prod")
+		if err != nil || !isRole {
+			t.Fatalf("expected role chain, got isRole=%v err=%v", isRole, err)
+		}
+
+		expected := &RoleChain{
+			Hops: []RoleHop{
+				{
+					Profile:         "prod",
+					RoleARN:         "arn:aws:iam::222222222222:role/ReadOnly",
+					SourceProfile:   "jump",
+					ExternalID:      "partner-123",
+					RoleSessionName: "alice",
+					Duration:        30 * time.Minute,
+				},
+				{
+					Profile:       "jump",
+					RoleARN:       "arn:aws:iam::111111111111:role/Jump",
+					SourceProfile: "base",
+					MFASerial:     "arn:aws:iam::000000000000:mfa/alice",
+					Duration:      defaultRoleDuration,
+				},
+			},
+			BaseProfile: "base",
+		}
+		if !reflect.DeepEqual(chain, expected) {
+			t.Errorf("expected %+v, got %+v", expected, chain)
+		}
+	})
+
+	t.Run("hops resolved by the SDK become the base", func(t *testing.T) {
+		chain, isRole, err := LoadRoleChain(configPath, credentialsPath, "via-ec2")
+		if err != nil || !isRole {
+			t.Fatalf("expected role chain, got isRole=%v err=%v", isRole, err)
+		}
+		if chain.BaseProfile != "ec2" || len(chain.Hops) != 1 {
+			t.Errorf("expected one hop from ec2, got %+v", chain)
+		}
+	})
+
+	tests := []struct {
+		name    string
+		profile string
+		isRole  bool
+		wantErr bool
+	}{
+		{name: "profile without role", profile: "base"},
+		{name: "credential_source left to the SDK", profile: "ec2"},
+		{name: "unknown profile", profile: "nope"},
+		{name: "source_profile loop", profile: "loop-a", wantErr: true},
+		{name: "missing source_profile", profile: "dangling", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, isRole, err := LoadRoleChain(configPath, credentialsPath, tt.profile)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("expected error=%v, got %v", tt.wantErr, err)
+			}
+			if isRole != tt.isRole {
+				t.Errorf("expected isRole=%v, got %v", tt.isRole, isRole)
+			}
+		})
+	}
+}
+
+func TestAuthenticateAssumeRoleCachesCredentials(t *testing.T) {
+	configPath, credentialsPath := writeRoleFiles(t)
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("AWS_CONFIG_FILE", configPath)
+	t.Setenv("AWS_SHARED_CREDENTIALS_FILE", credentialsPath)
+
+	a, err := New(nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	chain, _, err := LoadRoleChain(configPath, credentialsPath, "prod")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	first, err := a.authenticateAssumeRole(context.Background(), chain, "us-west-2")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(a.roleCredentials) != 2 {
+		t.Errorf("expected both hops to be cached, got %d", len(a.roleCredentials))
+	}
+
+	// A cached hop needs nothing from the base profile; unreadable provider rules would fail it
+	rules := filepath.Join(os.Getenv("HOME"), ".cloudcutter", "providers.json")
+	if err := os.MkdirAll(filepath.Dir(rules), 0o700); err != nil {
+		t.Fatalf("failed to create config dir: %v", err)
+	}
+	if err := os.WriteFile(rules, []byte("{"), 0o600); err != nil {
+		t.Fatalf("failed to write provider rules: %v", err)
+	}
+
+	second, err := a.authenticateAssumeRole(context.Background(), chain, "eu-west-1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if first.Credentials != second.Credentials {
+		t.Error("expected cached credentials to be reused across regions")
+	}
+	if second.Region != "eu-west-1" {
+		t.Errorf("expected region eu-west-1, got %s", second.Region)
+	}
+}
+
+func TestMFATokenProvider(t *testing.T) {
+	a, err := New(nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	hop := RoleHop{Profile: "jump", RoleARN: "arn:aws:iam::111111111111:role/Jump", MFASerial: "arn:aws:iam::000000000000:mfa/alice"}
+
+	if _, err := a.mfaTokenProvider(hop)(); err == nil {
+		t.Error("expected error without an MFA prompt")
+	}
+
+	var asked MFARequest
+	a.SetMFATokenHandler(func(req MFARequest) (string, error) {
+		asked = req
+		return "123456", nil
+	})
+
+	code, err := a.mfaTokenProvider(hop)()
+	if err != nil || code != "123456" {
+		t.Errorf("expected code 123456, got %q (err %v)", code, err)
+	}
+	if asked.MFASerial != hop.MFASerial || asked.Profile != "jump" {
+		t.Errorf("unexpected MFA request %+v", asked)
+	}
+}
@@ -1,21 +1,24 @@
 package auth
 
 import (
-	"bytes"
 	"context"
 	"fmt"
-	"os/exec"
-	"strings"
 	"sync"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
+	awsservice "github.com/tpelletiersophos/cloudcutter/internal/services/aws"
 )
 
 type Session struct {
-	Config  aws.Config
-	Profile string
-	Region  string
+	Config   aws.Config
+	Profile  string
+	Region   string
+	Identity *awsservice.Identity // effective caller identity, nil for local or when unavailable
+	Policy   *EnvironmentPolicy   // protected environment the session belongs to, nil when unprotected
+
+	credentials *sessionCredentials
 }
 
 type Authenticator struct {
@@ -23,24 +26,21 @@ type Authenticator struct {
 	currentSession   *Session
 	isAuthenticating bool
 	onStatus         func(string)
-	opalConfig       OpalConfig
-	opalProfiles     map[string]string // maps profile names to role IDs
+	opalRules        []ProviderRule // opal.json profile tags, tried after providers.json
+	opalEnvironments map[string]OpalEnvironment
+	onDeviceAuth     func(DeviceAuthorization)
+	onMFAToken       func(MFARequest) (string, error)
+	cancelLogin      context.CancelFunc
+	roleCredentials  map[string]aws.CredentialsProvider // assumed-role credentials cached per profile
 }
 
 func New(statusFn func(string)) (*Authenticator, error) {
-	opalConfig := LoadOpalConfig()
-
-	opalProfiles := make(map[string]string)
-	for _, env := range opalConfig.Environments {
-		for _, profileTag := range env.ProfileTags {
-			opalProfiles[profileTag] = env.RoleID
-		}
-	}
-
+	opal := LoadOpalConfig()
 	return &Authenticator{
-		onStatus:     statusFn,
-		opalConfig:   opalConfig,
-		opalProfiles: opalProfiles,
+		onStatus:         statusFn,
+		opalRules:        opalRules(opal),
+		opalEnvironments: opal.Environments,
+		roleCredentials:  make(map[string]aws.CredentialsProvider),
 	}, nil
 }
 
@@ -84,17 +84,7 @@ func (a *Authenticator) SwitchProfile(ctx context.Context, profile, region strin
 	}
 
 	a.sendStatus(fmt.Sprintf("Switching to profile %s in %s", profile, region))
-	var cfg aws.Config
-	var err error
-
-	if roleID, isOpal := a.opalProfiles[profile]; isOpal {
-		cfg, err = a.authenticateOpal(ctx, profile, region, roleID)
-	} else if profile == "local" {
-		cfg, err = a.authenticateLocal(ctx, region)
-	} else {
-		cfg, err = a.authenticateStandard(ctx, profile, region)
-	}
-
+	cfg, err := a.authenticate(ctx, profile, region)
 	if err != nil {
 		return nil, fmt.Errorf("authentication failed: %w", err)
 	}
@@ -105,6 +95,33 @@ func (a *Authenticator) SwitchProfile(ctx context.Context, profile, region strin
 		Region:  region,
 	}
 
+	// Resolving the identity also assumes any roles now, so MFA is asked for during the switch
+	// rather than on the first request a view makes
+	if profile != "local" {
+		identity, err := awsservice.CallerIdentity(ctx, cfg)
+		if err != nil {
+			return nil, fmt.Errorf("authentication failed: %w", err)
+		}
+		session.Identity = identity
+	}
+
+	accountID := ""
+	if session.Identity != nil {
+		accountID = session.Identity.Account
+	}
+	if session.Policy, err = a.resolvePolicy(profile, accountID); err != nil {
+		return nil, err
+	}
+
+	if cfg.Credentials != nil {
+		session.credentials = a.newSessionCredentials(cfg.Credentials, profile, region)
+		session.Config.Credentials = session.credentials
+		if profile != "local" {
+			// Already cached by the identity lookup; this only records the expiry
+			session.credentials.Retrieve(ctx)
+		}
+	}
+
 	a.mu.Lock()
 	a.currentSession = session
 	a.mu.Unlock()
@@ -112,46 +129,58 @@ func (a *Authenticator) SwitchProfile(ctx context.Context, profile, region strin
 	return session, nil
 }
 
-func (a *Authenticator) authenticateStandard(ctx context.Context, profile, region string) (aws.Config, error) {
-	opts := []func(*config.LoadOptions) error{
-		config.WithRegion(region),
+// authenticate loads a configuration for profile, from the provider its rules select or else
+// from whatever the shared config describes.
+func (a *Authenticator) authenticate(ctx context.Context, profile, region string) (aws.Config, error) {
+	provider, err := a.selectProvider(profile)
+	if err != nil {
+		return aws.Config{}, err
 	}
-
-	if profile != "" && profile != "default" {
-		opts = append(opts, config.WithSharedConfigProfile(profile))
+	if provider != nil {
+		return provider.Config(ctx, profile, region)
 	}
 
-	return config.LoadDefaultConfig(ctx, opts...)
-}
-
-func (a *Authenticator) authenticateOpal(ctx context.Context, profile, region, roleID string) (aws.Config, error) {
-	if err := a.runOpalCommand(ctx, roleID, profile); err != nil {
+	if profile == "local" {
+		return a.authenticateLocal(ctx, region)
+	}
+	if ssoProfile, isSSO := a.ssoProfile(profile); isSSO {
+		return a.authenticateSSO(ctx, ssoProfile, region)
+	}
+	chain, isRole, err := LoadRoleChain(AWSConfigPath(), AWSCredentialsPath(), profile)
+	if err != nil {
 		return aws.Config{}, err
 	}
-
+	if isRole {
+		return a.authenticateAssumeRole(ctx, chain, region)
+	}
 	return a.authenticateStandard(ctx, profile, region)
 }
 
-func (a *Authenticator) runOpalCommand(ctx context.Context, roleID, profileName string) error {
-	cmd := exec.Command("opal", "iam-roles:start", "--id", roleID, "--profileName", profileName)
-	var stdout, stderr bytes.Buffer
-	cmd.Stdout = &stdout
-	cmd.Stderr = &stderr
-
-	a.sendStatus("Starting Opal authentication...")
-	if err := cmd.Run(); err != nil {
-		output := stdout.String() + stderr.String()
+// ssoProfile looks profile up in the shared config on every switch so edits are picked up without a restart.
+func (a *Authenticator) ssoProfile(profile string) (SSOProfile, bool) {
+	// Profiles that can't be used are left out; the profile selector logs them
+	profiles, _ := LoadSSOProfiles(AWSConfigPath())
+	p, ok := profiles[profile]
+	return p, ok
+}
 
-		if strings.Contains(output, "Enter your email") ||
-			strings.Contains(output, "session is invalid or expired") {
-			return fmt.Errorf("opal session expired. Please run '%s' in terminal first", profileName)
-		}
+func (a *Authenticator) authenticateStandard(ctx context.Context, profile, region string) (aws.Config, error) {
+	opts := []func(*config.LoadOptions) error{
+		config.WithRegion(region),
+	}
 
-		return fmt.Errorf("Opal command failed: %v\nOutput: %s", err, output)
+	if profile != "" && profile != "default" {
+		opts = append(opts, config.WithSharedConfigProfile(profile))
 	}
 
-	a.sendStatus("Opal authentication completed successfully")
-	return nil
+	// Roles the SDK assumes itself (credential_source, self-sourced profiles) still prompt for MFA
+	opts = append(opts, config.WithAssumeRoleCredentialOptions(func(o *stscreds.AssumeRoleOptions) {
+		if o.SerialNumber != nil {
+			o.TokenProvider = a.mfaTokenProvider(RoleHop{Profile: profile, MFASerial: aws.ToString(o.SerialNumber)})
+		}
+	}))
+
+	return config.LoadDefaultConfig(ctx, opts...)
 }
 
 func (a *Authenticator) authenticateLocal(ctx context.Context, region string) (aws.Config, error) {
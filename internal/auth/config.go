@@ -8,13 +8,13 @@ import (
 
 // OpalConfig represents the configuration for Opal
 type OpalConfig struct {
-	Environments map[string]OpalEnvironment `json:"environments"`
+	Environments map[string]OpalEnvironment `json:"environments" yaml:"environments"`
 }
 
 // OpalEnvironment represents an Opal environment configuration
 type OpalEnvironment struct {
-	RoleID      string   `json:"roleId"`
-	ProfileTags []string `json:"profileTags"`
+	RoleID      string   `json:"roleId" yaml:"roleId"`
+	ProfileTags []string `json:"profileTags" yaml:"profileTags"`
 }
 
 // DefaultOpalConfig returns the default Opal configuration
@@ -0,0 +1,166 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+)
+
+// CredentialsRefreshWindow is how long before expiry a session's credentials are refreshed.
+const CredentialsRefreshWindow = credentialsExpiryWindow
+
+// refreshReuseWindow lets requests that fail together share one re-authentication: a refresh
+// requested this soon after the last one just retries with the new credentials.
+const refreshReuseWindow = 30 * time.Second
+
+// sessionCredentials is the provider handed out in a Session's config. Views keep their own copies
+// of that config, so a refresh swaps the underlying provider rather than replacing the config.
+type sessionCredentials struct {
+	mu          sync.RWMutex
+	provider    aws.CredentialsProvider
+	expires     time.Time
+	canExpire   bool
+	refreshedAt time.Time
+	failedFor   time.Time // expiry whose proactive refresh failed, so it is not retried every tick
+
+	refreshMu sync.Mutex
+	refresh   func(ctx context.Context) (aws.CredentialsProvider, error)
+}
+
+func (a *Authenticator) newSessionCredentials(provider aws.CredentialsProvider, profile, region string) *sessionCredentials {
+	return &sessionCredentials{
+		provider: provider,
+		refresh: func(ctx context.Context) (aws.CredentialsProvider, error) {
+			return a.reauthenticate(ctx, profile, region)
+		},
+	}
+}
+
+// Retrieve returns the current provider's credentials and records when they expire.
+func (c *sessionCredentials) Retrieve(ctx context.Context) (aws.Credentials, error) {
+	c.mu.RLock()
+	provider := c.provider
+	c.mu.RUnlock()
+
+	creds, err := provider.Retrieve(ctx)
+	if err != nil {
+		return aws.Credentials{}, err
+	}
+
+	c.mu.Lock()
+	c.expires, c.canExpire = creds.Expires, creds.CanExpire
+	c.mu.Unlock()
+	return creds, nil
+}
+
+// RefreshCredentials re-authenticates the session's profile and swaps in the new credentials.
+func (c *sessionCredentials) RefreshCredentials(ctx context.Context) error {
+	c.refreshMu.Lock()
+	defer c.refreshMu.Unlock()
+
+	c.mu.RLock()
+	recent := time.Since(c.refreshedAt) < refreshReuseWindow
+	c.mu.RUnlock()
+	if recent {
+		return nil
+	}
+
+	provider, err := c.refresh(ctx)
+	if err != nil {
+		return err
+	}
+	creds, err := provider.Retrieve(ctx)
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	c.provider = provider
+	c.expires, c.canExpire = creds.Expires, creds.CanExpire
+	c.refreshedAt = time.Now()
+	c.mu.Unlock()
+	return nil
+}
+
+func (c *sessionCredentials) expiry() (time.Time, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.expires, c.canExpire
+}
+
+// Expires reports when the session's credentials expire. It returns false when they do not
+// expire or the source does not say, as with static keys written by Opal.
+func (s *Session) Expires() (time.Time, bool) {
+	if s.credentials == nil {
+		return time.Time{}, false
+	}
+	return s.credentials.expiry()
+}
+
+// reauthenticate runs profile's login again from scratch. Cached role hops are dropped first,
+// since they were assumed from the credentials being replaced.
+func (a *Authenticator) reauthenticate(ctx context.Context, profile, region string) (aws.CredentialsProvider, error) {
+	a.mu.Lock()
+	if a.isAuthenticating {
+		a.mu.Unlock()
+		return nil, fmt.Errorf("authentication already in progress")
+	}
+	a.isAuthenticating = true
+	a.mu.Unlock()
+
+	defer func() {
+		a.mu.Lock()
+		a.isAuthenticating = false
+		a.mu.Unlock()
+	}()
+
+	if chain, isRole, err := LoadRoleChain(AWSConfigPath(), AWSCredentialsPath(), profile); err == nil && isRole {
+		a.mu.Lock()
+		for _, hop := range chain.Hops {
+			delete(a.roleCredentials, hop.Profile)
+		}
+		a.mu.Unlock()
+	}
+
+	a.sendStatus(fmt.Sprintf("Refreshing credentials for profile %s", profile))
+	cfg, err := a.authenticate(ctx, profile, region)
+	if err != nil {
+		return nil, fmt.Errorf("failed to refresh credentials: %w", err)
+	}
+	return cfg.Credentials, nil
+}
+
+// RefreshIfExpiring re-authenticates the current session when its credentials expire within
+// CredentialsRefreshWindow. It reports whether a refresh was attempted. A failed attempt, such as
+// a cancelled MFA prompt, is not repeated for the same credentials; requests that then fail with
+// ExpiredToken still trigger their own refresh.
+func (a *Authenticator) RefreshIfExpiring(ctx context.Context) (bool, error) {
+	session := a.Current()
+	if session == nil || session.credentials == nil || a.IsAuthenticating() {
+		return false, nil
+	}
+
+	c := session.credentials
+	expires, ok := c.expiry()
+	if !ok || time.Until(expires) > CredentialsRefreshWindow {
+		return false, nil
+	}
+
+	c.mu.RLock()
+	failed := c.failedFor.Equal(expires)
+	c.mu.RUnlock()
+	if failed {
+		return false, nil
+	}
+
+	if err := c.RefreshCredentials(ctx); err != nil {
+		c.mu.Lock()
+		c.failedFor = expires
+		c.mu.Unlock()
+		return true, err
+	}
+	return true, nil
+}
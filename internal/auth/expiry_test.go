@@ -0,0 +1,113 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+)
+
+func staticProvider(key string, expires time.Time) aws.CredentialsProviderFunc {
+	return func(context.Context) (aws.Credentials, error) {
+		return aws.Credentials{AccessKeyID: key, CanExpire: !expires.IsZero(), Expires: expires}, nil
+	}
+}
+
+func TestSessionCredentialsRefresh(t *testing.T) {
+	expiring := time.Now().Add(time.Minute)
+	renewed := time.Now().Add(time.Hour)
+
+	refreshes := 0
+	c := &sessionCredentials{
+		provider: staticProvider("old", expiring),
+		refresh: func(context.Context) (aws.CredentialsProvider, error) {
+			refreshes++
+			return staticProvider("new", renewed), nil
+		},
+	}
+	session := &Session{Config: aws.Config{Credentials: c}, credentials: c}
+
+	if _, ok := session.Expires(); ok {
+		t.Error("expected no expiry before credentials are retrieved")
+	}
+
+	creds, err := session.Config.Credentials.Retrieve(context.Background())
+	if err != nil || creds.AccessKeyID != "old" {
+		t.Fatalf("expected old credentials, got %+v (err %v)", creds, err)
+	}
+	if expires, ok := session.Expires(); !ok || !expires.Equal(expiring) {
+		t.Errorf("expected expiry %v, got %v (ok %v)", expiring, expires, ok)
+	}
+
+	// Requests that fail together share one refresh
+	for i := 0; i < 3; i++ {
+		if err := c.RefreshCredentials(context.Background()); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if refreshes != 1 {
+		t.Errorf("expected 1 refresh, got %d", refreshes)
+	}
+
+	// Copies of the config made before the refresh see the new credentials
+	creds, err = session.Config.Copy().Credentials.Retrieve(context.Background())
+	if err != nil || creds.AccessKeyID != "new" {
+		t.Errorf("expected new credentials, got %+v (err %v)", creds, err)
+	}
+	if expires, _ := session.Expires(); !expires.Equal(renewed) {
+		t.Errorf("expected expiry %v, got %v", renewed, expires)
+	}
+}
+
+func TestRefreshIfExpiring(t *testing.T) {
+	newAuthenticator := func(expires time.Time, refresh func(context.Context) (aws.CredentialsProvider, error)) (*Authenticator, *sessionCredentials) {
+		c := &sessionCredentials{provider: staticProvider("key", expires), refresh: refresh}
+		if _, err := c.Retrieve(context.Background()); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return &Authenticator{currentSession: &Session{credentials: c}}, c
+	}
+	renew := func(context.Context) (aws.CredentialsProvider, error) {
+		return staticProvider("new", time.Now().Add(time.Hour)), nil
+	}
+
+	t.Run("not yet due", func(t *testing.T) {
+		a, _ := newAuthenticator(time.Now().Add(time.Hour), renew)
+		if attempted, err := a.RefreshIfExpiring(context.Background()); attempted || err != nil {
+			t.Errorf("expected no refresh, got attempted=%v err=%v", attempted, err)
+		}
+	})
+
+	t.Run("credentials without expiry", func(t *testing.T) {
+		a, _ := newAuthenticator(time.Time{}, renew)
+		if attempted, _ := a.RefreshIfExpiring(context.Background()); attempted {
+			t.Error("expected no refresh for credentials that do not expire")
+		}
+	})
+
+	t.Run("inside refresh window", func(t *testing.T) {
+		a, c := newAuthenticator(time.Now().Add(time.Minute), renew)
+		if attempted, err := a.RefreshIfExpiring(context.Background()); !attempted || err != nil {
+			t.Fatalf("expected refresh, got attempted=%v err=%v", attempted, err)
+		}
+		if expires, _ := c.expiry(); time.Until(expires) < 50*time.Minute {
+			t.Errorf("expected renewed expiry, got %v", expires)
+		}
+	})
+
+	t.Run("failed refresh is not repeated", func(t *testing.T) {
+		calls := 0
+		a, _ := newAuthenticator(time.Now().Add(time.Minute), func(context.Context) (aws.CredentialsProvider, error) {
+			calls++
+			return nil, errors.New("MFA prompt cancelled")
+		})
+		if _, err := a.RefreshIfExpiring(context.Background()); err == nil {
+			t.Fatal("expected refresh error")
+		}
+		if attempted, _ := a.RefreshIfExpiring(context.Background()); attempted || calls != 1 {
+			t.Errorf("expected the failed refresh not to be retried, got attempted=%v calls=%d", attempted, calls)
+		}
+	})
+}
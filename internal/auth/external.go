@@ -0,0 +1,160 @@
+package auth
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/credentials/processcreds"
+)
+
+// CredentialProcessProvider runs a command that prints credentials in the credential_process
+// format. With no Process set it uses the profile's own credential_process setting.
+type CredentialProcessProvider struct {
+	auth    *Authenticator
+	Process string
+}
+
+func (p *CredentialProcessProvider) Name() string { return ProviderCredentialProcess }
+
+func (p *CredentialProcessProvider) Config(ctx context.Context, profile, region string) (aws.Config, error) {
+	if p.Process == "" {
+		if loadSharedProfiles(AWSConfigPath(), AWSCredentialsPath())[profile]["credential_process"] == "" {
+			return aws.Config{}, fmt.Errorf("profile %s has no credential_process", profile)
+		}
+		return p.auth.authenticateStandard(ctx, profile, region)
+	}
+
+	process := expandArgs([]string{p.Process}, map[string]string{"profile": profile, "region": region})[0]
+	return loadWithCredentials(ctx, region, processcreds.NewProvider(process))
+}
+
+// EnvProvider reads static credentials from environment variables named Prefix+ACCESS_KEY_ID,
+// Prefix+SECRET_ACCESS_KEY and optionally Prefix+SESSION_TOKEN.
+type EnvProvider struct {
+	Prefix string // defaults to AWS_; {profile} becomes the upper-cased profile name
+}
+
+func (p *EnvProvider) Name() string { return ProviderEnv }
+
+func (p *EnvProvider) Config(ctx context.Context, profile, region string) (aws.Config, error) {
+	prefix := p.Prefix
+	if prefix == "" {
+		prefix = "AWS_"
+	}
+	prefix = strings.ReplaceAll(prefix, "{profile}", envName(profile))
+
+	accessKey := os.Getenv(prefix + "ACCESS_KEY_ID")
+	secretKey := os.Getenv(prefix + "SECRET_ACCESS_KEY")
+	if accessKey == "" || secretKey == "" {
+		return aws.Config{}, fmt.Errorf("%sACCESS_KEY_ID and %sSECRET_ACCESS_KEY must be set for profile %s", prefix, prefix, profile)
+	}
+
+	provider := credentials.NewStaticCredentialsProvider(accessKey, secretKey, os.Getenv(prefix+"SESSION_TOKEN"))
+	return loadWithCredentials(ctx, region, provider)
+}
+
+// envName turns a profile name into an environment variable fragment, e.g. team-dev -> TEAM_DEV.
+func envName(profile string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToUpper(r)
+		}
+		return '_'
+	}, profile)
+}
+
+// ExecProvider runs an external broker command that prints JSON credentials, either at the top
+// level as credential_process does or under "Credentials" as `aws sts assume-role` does. The
+// command runs again whenever the credentials it returned are about to expire.
+type ExecProvider struct {
+	Command   []string
+	AccountID string
+	Timeout   time.Duration
+}
+
+func (p *ExecProvider) Name() string { return ProviderExec }
+
+func (p *ExecProvider) Config(ctx context.Context, profile, region string) (aws.Config, error) {
+	args := expandArgs(p.Command, map[string]string{"profile": profile, "region": region, "accountId": p.AccountID})
+	return loadWithCredentials(ctx, region, aws.CredentialsProviderFunc(func(ctx context.Context) (aws.Credentials, error) {
+		return p.run(ctx, args)
+	}))
+}
+
+// externalCredentials is the credential shape shared by credential_process and STS responses.
+type externalCredentials struct {
+	AccessKeyID     string     `json:"AccessKeyId"`
+	SecretAccessKey string     `json:"SecretAccessKey"`
+	SessionToken    string     `json:"SessionToken"`
+	Expiration      *time.Time `json:"Expiration"`
+}
+
+func (p *ExecProvider) run(ctx context.Context, args []string) (aws.Credentials, error) {
+	timeout := p.Timeout
+	if timeout <= 0 {
+		timeout = defaultCommandTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return aws.Credentials{}, fmt.Errorf("credential command %s failed: %v\nOutput: %s", args[0], err, stderr.String())
+	}
+
+	return parseExternalCredentials(stdout.Bytes())
+}
+
+func parseExternalCredentials(data []byte) (aws.Credentials, error) {
+	var out struct {
+		externalCredentials
+		Credentials *externalCredentials `json:"Credentials"`
+	}
+	if err := json.Unmarshal(data, &out); err != nil {
+		return aws.Credentials{}, fmt.Errorf("credential command printed invalid JSON: %w", err)
+	}
+
+	creds := out.externalCredentials
+	if out.Credentials != nil {
+		creds = *out.Credentials
+	}
+	if creds.AccessKeyID == "" || creds.SecretAccessKey == "" {
+		return aws.Credentials{}, fmt.Errorf("credential command output has no AccessKeyId or SecretAccessKey")
+	}
+
+	result := aws.Credentials{
+		AccessKeyID:     creds.AccessKeyID,
+		SecretAccessKey: creds.SecretAccessKey,
+		SessionToken:    creds.SessionToken,
+		Source:          ProviderExec,
+	}
+	if creds.Expiration != nil {
+		result.CanExpire = true
+		result.Expires = *creds.Expiration
+	}
+	return result, nil
+}
+
+// loadWithCredentials loads the default configuration for region with provider's credentials,
+// cached until shortly before they expire.
+func loadWithCredentials(ctx context.Context, region string, provider aws.CredentialsProvider) (aws.Config, error) {
+	return config.LoadDefaultConfig(ctx,
+		config.WithRegion(region),
+		config.WithCredentialsProvider(aws.NewCredentialsCache(provider, func(o *aws.CredentialsCacheOptions) {
+			o.ExpiryWindow = credentialsExpiryWindow
+		})),
+	)
+}
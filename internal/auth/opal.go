@@ -0,0 +1,64 @@
+package auth
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+)
+
+// defaultOpalCommand starts an Opal IAM role session that writes credentials for the profile.
+var defaultOpalCommand = []string{"opal", "iam-roles:start", "--id", "{roleId}", "--profileName", "{profile}"}
+
+// OpalProvider runs the Opal CLI, which writes credentials for the profile into the shared
+// credentials file, then loads the profile.
+type OpalProvider struct {
+	auth    *Authenticator
+	RoleID  string
+	Command []string // defaults to defaultOpalCommand
+	Timeout time.Duration
+}
+
+func (p *OpalProvider) Name() string { return ProviderOpal }
+
+func (p *OpalProvider) Config(ctx context.Context, profile, region string) (aws.Config, error) {
+	command := p.Command
+	if len(command) == 0 {
+		command = defaultOpalCommand
+	}
+	args := expandArgs(command, map[string]string{"profile": profile, "region": region, "roleId": p.RoleID})
+
+	ctx, cancel := context.WithTimeout(ctx, p.Timeout)
+	defer cancel()
+	if err := p.auth.runOpalCommand(ctx, args, profile); err != nil {
+		return aws.Config{}, err
+	}
+
+	return p.auth.authenticateStandard(ctx, profile, region)
+}
+
+func (a *Authenticator) runOpalCommand(ctx context.Context, args []string, profileName string) error {
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	a.sendStatus("Starting Opal authentication...")
+	if err := cmd.Run(); err != nil {
+		output := stdout.String() + stderr.String()
+
+		if strings.Contains(output, "Enter your email") ||
+			strings.Contains(output, "session is invalid or expired") {
+			return fmt.Errorf("opal session expired. Please run '%s' in terminal first", profileName)
+		}
+
+		return fmt.Errorf("Opal command failed: %v\nOutput: %s", err, output)
+	}
+
+	a.sendStatus("Opal authentication completed successfully")
+	return nil
+}
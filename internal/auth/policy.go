@@ -0,0 +1,122 @@
+package auth
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+)
+
+// Protection levels an environment policy applies to mutating operations.
+const (
+	ProtectionConfirm  = "confirm"  // ask for the profile name to be typed before each mutation
+	ProtectionReadOnly = "readonly" // refuse mutations outright
+)
+
+// EnvironmentPolicy marks the sessions it matches as a protected environment. A policy matches when
+// the profile is one of its Opal environment's profile tags, if set, and its selectors match.
+type EnvironmentPolicy struct {
+	Name            string `json:"name" yaml:"name"` // shown in the header and status bar, e.g. PROD
+	Protection      string `json:"protection" yaml:"protection"`
+	OpalEnvironment string `json:"opalEnvironment,omitempty" yaml:"opalEnvironment,omitempty"` // an environment in opal.json, e.g. prod
+	ProfileSelector `yaml:",inline"`
+}
+
+// PolicyConfig is the contents of ~/.cloudcutter/policies.json.
+type PolicyConfig struct {
+	Policies []EnvironmentPolicy `json:"policies"`
+}
+
+// DefaultPolicies treats every profile tagged as Opal prod as PROD and confirms its mutations.
+func DefaultPolicies() []EnvironmentPolicy {
+	return []EnvironmentPolicy{{Name: "PROD", Protection: ProtectionConfirm, OpalEnvironment: "prod"}}
+}
+
+// PolicyConfigPath returns the environment policy file path.
+func PolicyConfigPath() string {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(homeDir, ".cloudcutter", "policies.json")
+}
+
+// LoadPolicies reads and validates the policies in path. A missing file means DefaultPolicies;
+// an empty list turns protection off.
+func LoadPolicies(path string) ([]EnvironmentPolicy, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return DefaultPolicies(), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var cfg PolicyConfig
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("invalid policy config %s: %w", path, err)
+	}
+
+	for i := range cfg.Policies {
+		if err := cfg.Policies[i].validate(); err != nil {
+			return nil, fmt.Errorf("invalid policy %d in %s: %w", i+1, path, err)
+		}
+	}
+	return cfg.Policies, nil
+}
+
+func (p *EnvironmentPolicy) validate() error {
+	if p.Name == "" {
+		return fmt.Errorf("policy needs a name")
+	}
+	if p.OpalEnvironment == "" && p.empty() {
+		return fmt.Errorf("policy needs an opalEnvironment, profile, profileRegex or accountId selector")
+	}
+	if err := p.ProfileSelector.validate(); err != nil {
+		return err
+	}
+
+	switch p.Protection {
+	case ProtectionConfirm, ProtectionReadOnly:
+	default:
+		return fmt.Errorf("unknown protection %q", p.Protection)
+	}
+	return nil
+}
+
+// ReadOnly reports whether the policy refuses mutations.
+func (p *EnvironmentPolicy) ReadOnly() bool {
+	return p != nil && p.Protection == ProtectionReadOnly
+}
+
+// resolvePolicy returns the first policy from config.yaml, or else policies.json, matching profile
+// in account, or nil when the session is unprotected. accountID may be empty when neither the
+// identity nor the shared config says.
+func (a *Authenticator) resolvePolicy(profile, accountID string) (*EnvironmentPolicy, error) {
+	policies := currentSettings().Policies
+	if policies == nil {
+		var err error
+		if policies, err = LoadPolicies(PolicyConfigPath()); err != nil {
+			return nil, err
+		}
+	}
+	_, opalEnvironments := a.opal()
+	if accountID == "" {
+		accountID = profileAccountID(loadSharedProfiles(AWSConfigPath(), AWSCredentialsPath())[profile])
+	}
+
+	for _, policy := range policies {
+		if policy.OpalEnvironment != "" {
+			env, ok := opalEnvironments[policy.OpalEnvironment]
+			if !ok || !slices.Contains(env.ProfileTags, profile) {
+				continue
+			}
+		}
+		if policy.Matches(profile, accountID) {
+			return &policy, nil
+		}
+	}
+	return nil, nil
+}
@@ -0,0 +1,106 @@
+package auth
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const testPolicies = `{
+  "policies": [
+    {"name": "AUDIT", "protection": "readonly", "accountId": "9999*"},
+    {"name": "PROD", "protection": "confirm", "opalEnvironment": "prod"},
+    {"name": "STAGING", "protection": "confirm", "profileRegex": "-staging$"}
+  ]
+}`
+
+func writePolicies(t *testing.T, policies string) {
+	t.Helper()
+	writeProviderFiles(t, `{"rules": []}`)
+	home, _ := os.UserHomeDir()
+	if err := os.WriteFile(filepath.Join(home, ".cloudcutter", "policies.json"), []byte(policies), 0o600); err != nil {
+		t.Fatalf("failed to write policies: %v", err)
+	}
+}
+
+func TestResolvePolicy(t *testing.T) {
+	writePolicies(t, testPolicies)
+	a := &Authenticator{opalEnvironments: map[string]OpalEnvironment{
+		"prod": {RoleID: "role-prod", ProfileTags: []string{"opal_prod"}},
+	}}
+
+	tests := []struct {
+		profile   string
+		accountID string
+		expected  string
+	}{
+		{profile: "opal_prod", expected: "PROD"},
+		{profile: "team-staging", expected: "STAGING"},
+		{profile: "by-account", expected: "AUDIT"},
+		{profile: "opal_prod", accountID: "999911112222", expected: "AUDIT"},
+		{profile: "by-account", accountID: "123456789012"},
+		{profile: "opal_dev"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.profile+tt.accountID, func(t *testing.T) {
+			policy, err := a.resolvePolicy(tt.profile, tt.accountID)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			name := ""
+			if policy != nil {
+				name = policy.Name
+			}
+			if name != tt.expected {
+				t.Errorf("expected policy %q, got %q", tt.expected, name)
+			}
+		})
+	}
+
+	policy, _ := a.resolvePolicy("by-account", "")
+	if !policy.ReadOnly() {
+		t.Error("expected the AUDIT policy to be read-only")
+	}
+}
+
+func TestLoadPolicies(t *testing.T) {
+	policies, err := LoadPolicies(filepath.Join(t.TempDir(), "missing.json"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(policies) != 1 || policies[0].OpalEnvironment != "prod" || policies[0].Protection != ProtectionConfirm {
+		t.Errorf("expected the default prod policy, got %+v", policies)
+	}
+
+	tests := []struct {
+		name     string
+		policies string
+	}{
+		{name: "no name", policies: `{"policies": [{"protection": "confirm", "profile": "*"}]}`},
+		{name: "no selector", policies: `{"policies": [{"name": "PROD", "protection": "confirm"}]}`},
+		{name: "unknown protection", policies: `{"policies": [{"name": "PROD", "protection": "warn", "profile": "*"}]}`},
+		{name: "bad regex", policies: `{"policies": [{"name": "PROD", "protection": "confirm", "profileRegex": "("}]}`},
+		{name: "invalid JSON", policies: `{"policies": [`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "policies.json")
+			if err := os.WriteFile(path, []byte(tt.policies), 0o600); err != nil {
+				t.Fatalf("failed to write policies: %v", err)
+			}
+			if _, err := LoadPolicies(path); err == nil {
+				t.Error("expected validation error")
+			}
+		})
+	}
+
+	path := filepath.Join(t.TempDir(), "policies.json")
+	if err := os.WriteFile(path, []byte(`{"policies": []}`), 0o600); err != nil {
+		t.Fatalf("failed to write policies: %v", err)
+	}
+	if policies, err := LoadPolicies(path); err != nil || len(policies) != 0 {
+		t.Errorf("expected an empty list to turn protection off, got %v (err %v)", policies, err)
+	}
+}
@@ -0,0 +1,252 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+)
+
+// Provider types that can be named in a provider rule.
+const (
+	ProviderOpal              = "opal"
+	ProviderSSO               = "sso"
+	ProviderCredentialProcess = "credential_process"
+	ProviderEnv               = "env"
+	ProviderExec              = "exec"
+)
+
+// Provider obtains a configuration with credentials for a profile.
+type Provider interface {
+	Name() string
+	Config(ctx context.Context, profile, region string) (aws.Config, error)
+}
+
+// ProfileSelector picks profiles by name or account. Every selector that is set must match.
+type ProfileSelector struct {
+	Profile      string `json:"profile,omitempty" yaml:"profile,omitempty"`           // glob on the profile name, e.g. "*-prod"
+	ProfileRegex string `json:"profileRegex,omitempty" yaml:"profileRegex,omitempty"` // regular expression on the profile name
+	AccountID    string `json:"accountId,omitempty" yaml:"accountId,omitempty"`       // glob on the profile's account id
+
+	profileRegex *regexp.Regexp
+}
+
+// ProviderRule selects a provider for the profiles it matches. Rules are tried in order and the
+// first match wins.
+type ProviderRule struct {
+	Type            string `json:"type" yaml:"type"`
+	ProfileSelector `yaml:",inline"`
+
+	// Provider settings. Command arguments may use {profile}, {region}, {accountId} and {roleId}.
+	RoleID    string   `json:"roleId,omitempty" yaml:"roleId,omitempty"`       // opal
+	Command   []string `json:"command,omitempty" yaml:"command,omitempty"`     // opal (overrides the default), exec
+	Process   string   `json:"process,omitempty" yaml:"process,omitempty"`     // credential_process; empty uses the profile's own
+	EnvPrefix string   `json:"envPrefix,omitempty" yaml:"envPrefix,omitempty"` // env; defaults to AWS_, {profile} becomes the upper-cased name
+	Timeout   string   `json:"timeout,omitempty" yaml:"timeout,omitempty"`     // opal, exec; a Go duration, default 2m
+}
+
+// ProviderConfig is the contents of ~/.cloudcutter/providers.json.
+type ProviderConfig struct {
+	Rules []ProviderRule `json:"rules"`
+}
+
+// defaultCommandTimeout bounds broker commands, which may wait on a browser login.
+const defaultCommandTimeout = 2 * time.Minute
+
+// ProviderConfigPath returns the provider rules file path.
+func ProviderConfigPath() string {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(homeDir, ".cloudcutter", "providers.json")
+}
+
+// LoadProviderRules reads and validates the rules in path. A missing file means no rules.
+func LoadProviderRules(path string) ([]ProviderRule, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var cfg ProviderConfig
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("invalid provider config %s: %w", path, err)
+	}
+
+	for i := range cfg.Rules {
+		if err := cfg.Rules[i].validate(); err != nil {
+			return nil, fmt.Errorf("invalid provider rule %d in %s: %w", i+1, path, err)
+		}
+	}
+	return cfg.Rules, nil
+}
+
+func (r *ProviderRule) validate() error {
+	if r.empty() {
+		return fmt.Errorf("rule needs a profile, profileRegex or accountId selector")
+	}
+	if err := r.ProfileSelector.validate(); err != nil {
+		return err
+	}
+	if r.Timeout != "" {
+		if _, err := time.ParseDuration(r.Timeout); err != nil {
+			return fmt.Errorf("invalid timeout %q: %w", r.Timeout, err)
+		}
+	}
+
+	switch r.Type {
+	case ProviderOpal:
+		if r.RoleID == "" {
+			return fmt.Errorf("opal rule needs a roleId")
+		}
+	case ProviderExec:
+		if len(r.Command) == 0 {
+			return fmt.Errorf("exec rule needs a command")
+		}
+	case ProviderSSO, ProviderCredentialProcess, ProviderEnv:
+	default:
+		return fmt.Errorf("unknown provider type %q", r.Type)
+	}
+	return nil
+}
+
+func (r *ProfileSelector) empty() bool {
+	return r.Profile == "" && r.ProfileRegex == "" && r.AccountID == ""
+}
+
+// validate checks the patterns and compiles the regular expression.
+func (r *ProfileSelector) validate() error {
+	if r.Profile != "" {
+		if _, err := path.Match(r.Profile, ""); err != nil {
+			return fmt.Errorf("invalid profile pattern %q: %w", r.Profile, err)
+		}
+	}
+	if r.AccountID != "" {
+		if _, err := path.Match(r.AccountID, ""); err != nil {
+			return fmt.Errorf("invalid accountId pattern %q: %w", r.AccountID, err)
+		}
+	}
+	if r.ProfileRegex != "" {
+		re, err := regexp.Compile(r.ProfileRegex)
+		if err != nil {
+			return fmt.Errorf("invalid profileRegex %q: %w", r.ProfileRegex, err)
+		}
+		r.profileRegex = re
+	}
+	return nil
+}
+
+// Matches reports whether profile is selected. Its account id may be empty when unknown.
+func (r ProfileSelector) Matches(profile, accountID string) bool {
+	if r.Profile != "" {
+		if ok, _ := path.Match(r.Profile, profile); !ok {
+			return false
+		}
+	}
+	if r.profileRegex != nil && !r.profileRegex.MatchString(profile) {
+		return false
+	}
+	if r.AccountID != "" {
+		if ok, _ := path.Match(r.AccountID, accountID); accountID == "" || !ok {
+			return false
+		}
+	}
+	return true
+}
+
+func (r ProviderRule) timeout() time.Duration {
+	if d, err := time.ParseDuration(r.Timeout); err == nil && d > 0 {
+		return d
+	}
+	return defaultCommandTimeout
+}
+
+// expandArgs fills the placeholders in a rule's command for one profile.
+func expandArgs(args []string, values map[string]string) []string {
+	expanded := make([]string, len(args))
+	for i, arg := range args {
+		for key, value := range values {
+			arg = strings.ReplaceAll(arg, "{"+key+"}", value)
+		}
+		expanded[i] = arg
+	}
+	return expanded
+}
+
+// opalRules turns the profileTags in opal.json into exact-match opal rules.
+func opalRules(cfg OpalConfig) []ProviderRule {
+	var rules []ProviderRule
+	for _, env := range cfg.Environments {
+		for _, tag := range env.ProfileTags {
+			rules = append(rules, ProviderRule{
+				Type:            ProviderOpal,
+				ProfileSelector: ProfileSelector{Profile: tag},
+				RoleID:          env.RoleID,
+			})
+		}
+	}
+	return rules
+}
+
+// profileAccountID returns the account a shared-config profile belongs to, when it says.
+func profileAccountID(keys map[string]string) string {
+	for _, key := range []string{"sso_account_id", "aws_account_id"} {
+		if keys[key] != "" {
+			return keys[key]
+		}
+	}
+	// arn:partition:iam::account:role/name
+	if parts := strings.Split(keys["role_arn"], ":"); len(parts) >= 6 {
+		return parts[4]
+	}
+	return ""
+}
+
+// newProvider builds the provider a rule names.
+func (a *Authenticator) newProvider(rule ProviderRule, accountID string) Provider {
+	switch rule.Type {
+	case ProviderOpal:
+		return &OpalProvider{auth: a, RoleID: rule.RoleID, Command: rule.Command, Timeout: rule.timeout()}
+	case ProviderSSO:
+		return &SSOProvider{auth: a}
+	case ProviderCredentialProcess:
+		return &CredentialProcessProvider{auth: a, Process: rule.Process}
+	case ProviderEnv:
+		return &EnvProvider{Prefix: rule.EnvPrefix}
+	default:
+		return &ExecProvider{Command: rule.Command, AccountID: accountID, Timeout: rule.timeout()}
+	}
+}
+
+// selectProvider returns the provider for profile: the first matching rule from config.yaml, then
+// providers.json, then the Opal profile tags, then nil to fall back to what the shared config describes.
+func (a *Authenticator) selectProvider(profile string) (Provider, error) {
+	fileRules, err := LoadProviderRules(ProviderConfigPath())
+	if err != nil {
+		return nil, err
+	}
+	opal, _ := a.opal()
+	rules := append(slices.Clone(currentSettings().Providers), fileRules...)
+	rules = append(rules, opal...)
+
+	accountID := profileAccountID(loadSharedProfiles(AWSConfigPath(), AWSCredentialsPath())[profile])
+	for _, rule := range rules {
+		if rule.Matches(profile, accountID) {
+			return a.newProvider(rule, accountID), nil
+		}
+	}
+	return nil, nil
+}
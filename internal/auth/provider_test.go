@@ -0,0 +1,206 @@
+package auth
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+const testProviderRules = `{
+  "rules": [
+    {"type": "exec", "profile": "broker-*", "command": ["broker", "creds", "--account", "{accountId}"]},
+    {"type": "env", "profileRegex": "^ci-(dev|test)$", "envPrefix": "{profile}_"},
+    {"type": "credential_process", "accountId": "9999*"},
+    {"type": "opal", "profile": "team-prod", "roleId": "role-123", "timeout": "5m"}
+  ]
+}`
+
+const testProviderSharedConfig = `
+[profile broker-a]
+sso_account_id = 111111111111
+
+[profile by-account]
+role_arn = arn:aws:iam::999900001111:role/Reader
+source_profile = base
+`
+
+func writeProviderFiles(t *testing.T, rules string) {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	if err := os.MkdirAll(filepath.Join(home, ".cloudcutter"), 0o700); err != nil {
+		t.Fatalf("failed to create config dir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(home, ".cloudcutter", "providers.json"), []byte(rules), 0o600); err != nil {
+		t.Fatalf("failed to write provider rules: %v", err)
+	}
+
+	t.Setenv("AWS_CONFIG_FILE", writeSharedConfig(t, testProviderSharedConfig))
+	t.Setenv("AWS_SHARED_CREDENTIALS_FILE", filepath.Join(home, "credentials"))
+}
+
+func TestSelectProvider(t *testing.T) {
+	writeProviderFiles(t, testProviderRules)
+	a := &Authenticator{opalRules: opalRules(OpalConfig{Environments: map[string]OpalEnvironment{
+		"dev": {RoleID: "role-dev", ProfileTags: []string{"opal_dev"}},
+	}})}
+
+	tests := []struct {
+		profile  string
+		expected string
+	}{
+		{profile: "broker-a", expected: ProviderExec},
+		{profile: "ci-dev", expected: ProviderEnv},
+		{profile: "ci-prod"},
+		{profile: "by-account", expected: ProviderCredentialProcess},
+		{profile: "team-prod", expected: ProviderOpal},
+		{profile: "opal_dev", expected: ProviderOpal},
+		{profile: "default"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.profile, func(t *testing.T) {
+			provider, err := a.selectProvider(tt.profile)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			name := ""
+			if provider != nil {
+				name = provider.Name()
+			}
+			if name != tt.expected {
+				t.Errorf("expected provider %q, got %q", tt.expected, name)
+			}
+		})
+	}
+
+	provider, _ := a.selectProvider("broker-a")
+	if exec := provider.(*ExecProvider); exec.AccountID != "111111111111" {
+		t.Errorf("expected account id from shared config, got %q", exec.AccountID)
+	}
+	provider, _ = a.selectProvider("team-prod")
+	if opal := provider.(*OpalProvider); opal.RoleID != "role-123" || opal.Timeout != 5*time.Minute {
+		t.Errorf("unexpected opal provider %+v", opal)
+	}
+}
+
+func TestLoadProviderRulesValidation(t *testing.T) {
+	tests := []struct {
+		name  string
+		rules string
+	}{
+		{name: "no selector", rules: `{"rules": [{"type": "env"}]}`},
+		{name: "unknown type", rules: `{"rules": [{"type": "vault", "profile": "*"}]}`},
+		{name: "bad regex", rules: `{"rules": [{"type": "env", "profileRegex": "("}]}`},
+		{name: "bad glob", rules: `{"rules": [{"type": "env", "profile": "["}]}`},
+		{name: "opal without role", rules: `{"rules": [{"type": "opal", "profile": "*"}]}`},
+		{name: "exec without command", rules: `{"rules": [{"type": "exec", "profile": "*"}]}`},
+		{name: "bad timeout", rules: `{"rules": [{"type": "exec", "profile": "*", "command": ["x"], "timeout": "soon"}]}`},
+		{name: "invalid JSON", rules: `{"rules": [`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "providers.json")
+			if err := os.WriteFile(path, []byte(tt.rules), 0o600); err != nil {
+				t.Fatalf("failed to write rules: %v", err)
+			}
+			if _, err := LoadProviderRules(path); err == nil {
+				t.Error("expected validation error")
+			}
+		})
+	}
+
+	rules, err := LoadProviderRules(filepath.Join(t.TempDir(), "missing.json"))
+	if err != nil || rules != nil {
+		t.Errorf("expected no rules for a missing file, got %v (err %v)", rules, err)
+	}
+}
+
+func TestProfileAccountID(t *testing.T) {
+	tests := []struct {
+		keys     map[string]string
+		expected string
+	}{
+		{keys: map[string]string{"sso_account_id": "111111111111"}, expected: "111111111111"},
+		{keys: map[string]string{"aws_account_id": "222222222222"}, expected: "222222222222"},
+		{keys: map[string]string{"role_arn": "arn:aws:iam::333333333333:role/Reader"}, expected: "333333333333"},
+		{keys: map[string]string{"region": "us-west-2"}},
+		{},
+	}
+
+	for _, tt := range tests {
+		if got := profileAccountID(tt.keys); got != tt.expected {
+			t.Errorf("profileAccountID(%v): expected %q, got %q", tt.keys, tt.expected, got)
+		}
+	}
+}
+
+func TestExecProvider(t *testing.T) {
+	expiration := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+	output := `{"Credentials": {"AccessKeyId": "AKIA{accountId}", "SecretAccessKey": "secret", "SessionToken": "token", "Expiration": "` +
+		expiration.Format(time.RFC3339) + `"}}`
+
+	provider := &ExecProvider{Command: []string{"sh", "-c", "printf '%s' '" + output + "'"}, AccountID: "123"}
+	cfg, err := provider.Config(context.Background(), "broker-a", "us-west-2")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	creds, err := cfg.Credentials.Retrieve(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if creds.AccessKeyID != "AKIA123" || creds.SessionToken != "token" {
+		t.Errorf("unexpected credentials %+v", creds)
+	}
+	if !creds.CanExpire {
+		t.Error("expected credentials with an Expiration to expire")
+	}
+
+	failing := &ExecProvider{Command: []string{"sh", "-c", "echo denied >&2; exit 1"}}
+	cfg, err = failing.Config(context.Background(), "broker-a", "us-west-2")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := cfg.Credentials.Retrieve(context.Background()); err == nil {
+		t.Error("expected error from a failing command")
+	}
+}
+
+func TestParseExternalCredentials(t *testing.T) {
+	creds, err := parseExternalCredentials([]byte(`{"Version": 1, "AccessKeyId": "AKIA", "SecretAccessKey": "secret"}`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if creds.AccessKeyID != "AKIA" || creds.CanExpire {
+		t.Errorf("unexpected credentials %+v", creds)
+	}
+
+	for _, data := range []string{`not json`, `{"AccessKeyId": "AKIA"}`, `{}`} {
+		if _, err := parseExternalCredentials([]byte(data)); err == nil {
+			t.Errorf("expected error for %s", data)
+		}
+	}
+}
+
+func TestEnvProvider(t *testing.T) {
+	t.Setenv("CI_DEV_ACCESS_KEY_ID", "AKIADEV")
+	t.Setenv("CI_DEV_SECRET_ACCESS_KEY", "secret")
+
+	cfg, err := (&EnvProvider{Prefix: "{profile}_"}).Config(context.Background(), "ci-dev", "us-west-2")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	creds, err := cfg.Credentials.Retrieve(context.Background())
+	if err != nil || creds.AccessKeyID != "AKIADEV" {
+		t.Errorf("expected AKIADEV, got %+v (err %v)", creds, err)
+	}
+
+	if _, err := (&EnvProvider{Prefix: "{profile}_"}).Config(context.Background(), "ci-test", "us-west-2"); err == nil {
+		t.Error("expected error when the variables are not set")
+	}
+}
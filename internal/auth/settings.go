@@ -0,0 +1,73 @@
+package auth
+
+import (
+	"fmt"
+	"sync"
+)
+
+// Settings are the auth section of config.yaml. Whatever it leaves out is still read from
+// opal.json, providers.json and policies.json.
+type Settings struct {
+	Opal      *OpalConfig         `yaml:"opal"`      // replaces opal.json
+	Providers []ProviderRule      `yaml:"providers"` // tried before the rules in providers.json
+	Policies  []EnvironmentPolicy `yaml:"policies"`  // replaces policies.json; an empty list turns protection off
+}
+
+var (
+	settingsMu sync.RWMutex
+	settings   Settings
+)
+
+// Validate checks every section and compiles the selectors' regular expressions.
+func (s *Settings) Validate() error {
+	if s.Opal != nil {
+		if len(s.Opal.Environments) == 0 {
+			return fmt.Errorf("opal.environments must not be empty")
+		}
+		for name, env := range s.Opal.Environments {
+			if env.RoleID == "" {
+				return fmt.Errorf("opal.environments.%s.roleId must be set", name)
+			}
+			if len(env.ProfileTags) == 0 {
+				return fmt.Errorf("opal.environments.%s.profileTags must not be empty", name)
+			}
+		}
+	}
+	for i := range s.Providers {
+		if err := s.Providers[i].validate(); err != nil {
+			return fmt.Errorf("providers[%d]: %w", i, err)
+		}
+	}
+	for i := range s.Policies {
+		if err := s.Policies[i].validate(); err != nil {
+			return fmt.Errorf("policies[%d]: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// UseSettings validates s and makes every authenticator use it from the next sign-in on.
+func UseSettings(s Settings) error {
+	if err := s.Validate(); err != nil {
+		return err
+	}
+	settingsMu.Lock()
+	defer settingsMu.Unlock()
+	settings = s
+	return nil
+}
+
+func currentSettings() Settings {
+	settingsMu.RLock()
+	defer settingsMu.RUnlock()
+	return settings
+}
+
+// opal returns the Opal rules and environments: config.yaml's when it has them, else those loaded
+// from opal.json when the authenticator was created.
+func (a *Authenticator) opal() ([]ProviderRule, map[string]OpalEnvironment) {
+	if s := currentSettings(); s.Opal != nil {
+		return opalRules(*s.Opal), s.Opal.Environments
+	}
+	return a.opalRules, a.opalEnvironments
+}
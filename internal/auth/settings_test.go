@@ -0,0 +1,113 @@
+package auth
+
+import (
+	"strings"
+	"testing"
+)
+
+func useSettings(t *testing.T, s Settings) {
+	t.Helper()
+	if err := UseSettings(s); err != nil {
+		t.Fatalf("UseSettings failed: %v", err)
+	}
+	t.Cleanup(func() { _ = UseSettings(Settings{}) })
+}
+
+func TestSettingsValidate(t *testing.T) {
+	tests := []struct {
+		name     string
+		settings Settings
+		expected string
+	}{
+		{name: "empty", settings: Settings{}},
+		{
+			name:     "opal without environments",
+			settings: Settings{Opal: &OpalConfig{}},
+			expected: "opal.environments must not be empty",
+		},
+		{
+			name: "opal environment without role",
+			settings: Settings{Opal: &OpalConfig{Environments: map[string]OpalEnvironment{
+				"dev": {ProfileTags: []string{"opal_dev"}},
+			}}},
+			expected: "opal.environments.dev.roleId must be set",
+		},
+		{
+			name:     "provider without selector",
+			settings: Settings{Providers: []ProviderRule{{Type: ProviderEnv}}},
+			expected: "providers[0]: rule needs",
+		},
+		{
+			name: "policy with unknown protection",
+			settings: Settings{Policies: []EnvironmentPolicy{
+				{Name: "PROD", Protection: "never", ProfileSelector: ProfileSelector{Profile: "prod"}},
+			}},
+			expected: `policies[0]: unknown protection "never"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.settings.Validate()
+			if tt.expected == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.expected) {
+				t.Fatalf("expected error containing %q, got %v", tt.expected, err)
+			}
+		})
+	}
+}
+
+func TestSettingsOverrideFiles(t *testing.T) {
+	writePolicies(t, testPolicies)
+	a := &Authenticator{opalEnvironments: map[string]OpalEnvironment{
+		"prod": {RoleID: "role-prod", ProfileTags: []string{"opal_prod"}},
+	}}
+
+	useSettings(t, Settings{
+		Opal: &OpalConfig{Environments: map[string]OpalEnvironment{
+			"live": {RoleID: "role-live", ProfileTags: []string{"opal_live"}},
+		}},
+		Providers: []ProviderRule{{Type: ProviderEnv, ProfileSelector: ProfileSelector{ProfileRegex: "^ci-"}}},
+		Policies: []EnvironmentPolicy{
+			{Name: "LIVE", Protection: ProtectionReadOnly, OpalEnvironment: "live"},
+		},
+	})
+
+	provider, err := a.selectProvider("ci-runner")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if provider == nil || provider.Name() != ProviderEnv {
+		t.Errorf("expected the env provider from config.yaml, got %v", provider)
+	}
+
+	provider, err = a.selectProvider("opal_live")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if opal, ok := provider.(*OpalProvider); !ok || opal.RoleID != "role-live" {
+		t.Errorf("expected the opal provider for role-live, got %#v", provider)
+	}
+
+	policy, err := a.resolvePolicy("opal_live", "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if policy == nil || policy.Name != "LIVE" {
+		t.Errorf("expected the LIVE policy, got %v", policy)
+	}
+
+	// policies.json no longer applies
+	policy, err = a.resolvePolicy("team-staging", "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if policy != nil {
+		t.Errorf("expected no policy, got %q", policy.Name)
+	}
+}
@@ -0,0 +1,402 @@
+package auth
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/ssooidc"
+	ssooidctypes "github.com/aws/aws-sdk-go-v2/service/ssooidc/types"
+	"gopkg.in/ini.v1"
+)
+
+const (
+	ssoClientName       = "cloudcutter"
+	ssoDeviceGrantType  = "urn:ietf:params:oauth:grant-type:device_code"
+	ssoRefreshGrantType = "refresh_token"
+
+	// ssoTokenExpiryWindow treats cached tokens this close to expiry as expired, matching the SDK.
+	ssoTokenExpiryWindow = 5 * time.Minute
+
+	// credentialsExpiryWindow refreshes role credentials this long before they expire.
+	credentialsExpiryWindow = 5 * time.Minute
+)
+
+// SSOProfile is a shared-config profile that authenticates through IAM Identity Center.
+type SSOProfile struct {
+	Name        string
+	SessionName string // sso_session name; empty for legacy profiles
+	StartURL    string
+	Region      string
+	Scopes      []string
+}
+
+// CacheKey returns the key the AWS CLI and SDK hash to name the profile's token cache file.
+func (p SSOProfile) CacheKey() string {
+	if p.SessionName != "" {
+		return p.SessionName
+	}
+	return p.StartURL
+}
+
+// DeviceAuthorization is what the user needs to approve an SSO device login in a browser.
+type DeviceAuthorization struct {
+	Profile                 string
+	VerificationURI         string
+	VerificationURIComplete string
+	UserCode                string
+	ExpiresAt               time.Time
+}
+
+// ssoToken mirrors the token files in ~/.aws/sso/cache written by the AWS CLI and read by the SDK.
+type ssoToken struct {
+	StartURL              string     `json:"startUrl,omitempty"`
+	Region                string     `json:"region,omitempty"`
+	AccessToken           string     `json:"accessToken"`
+	ExpiresAt             time.Time  `json:"expiresAt"`
+	RefreshToken          string     `json:"refreshToken,omitempty"`
+	ClientID              string     `json:"clientId,omitempty"`
+	ClientSecret          string     `json:"clientSecret,omitempty"`
+	RegistrationExpiresAt *time.Time `json:"registrationExpiresAt,omitempty"`
+}
+
+func (t *ssoToken) valid(now time.Time) bool {
+	return t.AccessToken != "" && now.Add(ssoTokenExpiryWindow).Before(t.ExpiresAt)
+}
+
+func (t *ssoToken) canRefresh(now time.Time) bool {
+	if t.RefreshToken == "" || t.ClientID == "" || t.ClientSecret == "" {
+		return false
+	}
+	return t.RegistrationExpiresAt == nil || now.Before(*t.RegistrationExpiresAt)
+}
+
+// oidcClient is the subset of the SSO OIDC API used by the device authorization flow.
+type oidcClient interface {
+	RegisterClient(ctx context.Context, params *ssooidc.RegisterClientInput, optFns ...func(*ssooidc.Options)) (*ssooidc.RegisterClientOutput, error)
+	StartDeviceAuthorization(ctx context.Context, params *ssooidc.StartDeviceAuthorizationInput, optFns ...func(*ssooidc.Options)) (*ssooidc.StartDeviceAuthorizationOutput, error)
+	CreateToken(ctx context.Context, params *ssooidc.CreateTokenInput, optFns ...func(*ssooidc.Options)) (*ssooidc.CreateTokenOutput, error)
+}
+
+// AWSConfigPath returns the shared config file path, honoring AWS_CONFIG_FILE.
+func AWSConfigPath() string {
+	if path := os.Getenv("AWS_CONFIG_FILE"); path != "" {
+		return path
+	}
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(homeDir, ".aws", "config")
+}
+
+// LoadSSOProfiles returns the SSO profiles defined in the shared config file, keyed by profile name.
+// Both sso_session and legacy sso_start_url profiles are recognised. A profile that can't be used,
+// such as one missing sso_start_url or sso_region, is left out and described in the returned error
+// while the valid profiles are still returned.
+func LoadSSOProfiles(path string) (map[string]SSOProfile, error) {
+	cfg, err := ini.Load(path)
+	if err != nil {
+		return nil, err
+	}
+
+	profiles := make(map[string]SSOProfile)
+	var skipped []error
+	for _, section := range cfg.Sections() {
+		name, ok := sharedConfigProfileName(section.Name())
+		if !ok {
+			continue
+		}
+
+		profile := SSOProfile{Name: name}
+		if sessionName := section.Key("sso_session").String(); sessionName != "" {
+			session, err := cfg.GetSection("sso-session " + sessionName)
+			if err != nil {
+				skipped = append(skipped, fmt.Errorf("profile %s references missing sso-session %s", name, sessionName))
+				continue
+			}
+			profile.SessionName = sessionName
+			profile.StartURL = session.Key("sso_start_url").String()
+			profile.Region = session.Key("sso_region").String()
+			profile.Scopes = splitScopes(session.Key("sso_registration_scopes").String())
+		} else if startURL := section.Key("sso_start_url").String(); startURL != "" {
+			profile.StartURL = startURL
+			profile.Region = section.Key("sso_region").String()
+		} else {
+			continue
+		}
+
+		if profile.StartURL == "" || profile.Region == "" {
+			skipped = append(skipped, fmt.Errorf("profile %s is missing sso_start_url or sso_region", name))
+			continue
+		}
+		profiles[name] = profile
+	}
+	return profiles, errors.Join(skipped...)
+}
+
+// sharedConfigProfileName maps a shared config section name to its profile name.
+func sharedConfigProfileName(section string) (string, bool) {
+	switch {
+	case section == "default":
+		return "default", true
+	case strings.HasPrefix(section, "profile "):
+		return strings.TrimSpace(strings.TrimPrefix(section, "profile ")), true
+	default:
+		return "", false
+	}
+}
+
+func splitScopes(value string) []string {
+	var scopes []string
+	for _, scope := range strings.Split(value, ",") {
+		if scope = strings.TrimSpace(scope); scope != "" {
+			scopes = append(scopes, scope)
+		}
+	}
+	return scopes
+}
+
+// ssoCachePath returns ~/.aws/sso/cache/<sha1(key)>.json, the file name the AWS CLI and SDK use.
+func ssoCachePath(key string) (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	hash := sha1.Sum([]byte(key))
+	return filepath.Join(homeDir, ".aws", "sso", "cache", strings.ToLower(hex.EncodeToString(hash[:]))+".json"), nil
+}
+
+func loadSSOToken(path string) (*ssoToken, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var token ssoToken
+	if err := json.Unmarshal(data, &token); err != nil {
+		return nil, fmt.Errorf("invalid cached SSO token %s: %w", path, err)
+	}
+	return &token, nil
+}
+
+func storeSSOToken(path string, token *ssoToken) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(token, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o600)
+}
+
+// SetDeviceAuthHandler registers the callback that shows a pending device login to the user.
+func (a *Authenticator) SetDeviceAuthHandler(fn func(DeviceAuthorization)) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.onDeviceAuth = fn
+}
+
+// CancelLogin aborts a device login waiting for browser approval.
+func (a *Authenticator) CancelLogin() {
+	a.mu.Lock()
+	cancel := a.cancelLogin
+	a.mu.Unlock()
+	if cancel != nil {
+		cancel()
+	}
+}
+
+// SSOProvider logs in through IAM Identity Center using the profile's sso settings. Profiles with
+// those settings use it without a rule; a rule only makes the choice explicit.
+type SSOProvider struct {
+	auth *Authenticator
+}
+
+func (p *SSOProvider) Name() string { return ProviderSSO }
+
+func (p *SSOProvider) Config(ctx context.Context, profile, region string) (aws.Config, error) {
+	ssoProfile, ok := p.auth.ssoProfile(profile)
+	if !ok {
+		return aws.Config{}, fmt.Errorf("profile %s has no sso_session or sso_start_url", profile)
+	}
+	return p.auth.authenticateSSO(ctx, ssoProfile, region)
+}
+
+func (a *Authenticator) authenticateSSO(ctx context.Context, profile SSOProfile, region string) (aws.Config, error) {
+	if err := a.ensureSSOToken(ctx, profile); err != nil {
+		return aws.Config{}, err
+	}
+
+	// The SDK resolves role credentials from the cached token and refreshes them ahead of expiry
+	return config.LoadDefaultConfig(ctx,
+		config.WithRegion(region),
+		config.WithSharedConfigProfile(profile.Name),
+		config.WithCredentialsCacheOptions(func(o *aws.CredentialsCacheOptions) {
+			o.ExpiryWindow = credentialsExpiryWindow
+		}),
+	)
+}
+
+// ensureSSOToken makes sure a usable access token is cached for profile, refreshing it or running
+// the device authorization flow when needed.
+func (a *Authenticator) ensureSSOToken(ctx context.Context, profile SSOProfile) error {
+	path, err := ssoCachePath(profile.CacheKey())
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	cached, err := loadSSOToken(path)
+	if err == nil && cached.valid(now) {
+		return nil
+	}
+
+	client := ssooidc.NewFromConfig(aws.Config{Region: profile.Region})
+
+	if err == nil && profile.SessionName != "" && cached.canRefresh(now) {
+		a.sendStatus("Refreshing SSO token...")
+		token, refreshErr := refreshSSOToken(ctx, client, cached, now)
+		if refreshErr == nil {
+			return storeSSOToken(path, token)
+		}
+		a.sendStatus(fmt.Sprintf("SSO token refresh failed, starting device login: %v", refreshErr))
+	}
+
+	loginCtx, cancel := context.WithCancel(ctx)
+	a.mu.Lock()
+	a.cancelLogin = cancel
+	notify := a.onDeviceAuth
+	a.mu.Unlock()
+	defer func() {
+		cancel()
+		a.mu.Lock()
+		a.cancelLogin = nil
+		a.mu.Unlock()
+	}()
+
+	a.sendStatus(fmt.Sprintf("Starting SSO device login for %s", profile.Name))
+	token, err := runDeviceAuthorization(loginCtx, client, profile, notify, time.After)
+	if err != nil {
+		return err
+	}
+
+	a.sendStatus("SSO login completed")
+	return storeSSOToken(path, token)
+}
+
+func refreshSSOToken(ctx context.Context, client oidcClient, cached *ssoToken, now time.Time) (*ssoToken, error) {
+	out, err := client.CreateToken(ctx, &ssooidc.CreateTokenInput{
+		ClientId:     aws.String(cached.ClientID),
+		ClientSecret: aws.String(cached.ClientSecret),
+		GrantType:    aws.String(ssoRefreshGrantType),
+		RefreshToken: aws.String(cached.RefreshToken),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	token := *cached
+	token.AccessToken = aws.ToString(out.AccessToken)
+	token.ExpiresAt = now.Add(time.Duration(out.ExpiresIn) * time.Second).UTC().Truncate(time.Second)
+	if out.RefreshToken != nil {
+		token.RefreshToken = aws.ToString(out.RefreshToken)
+	}
+	return &token, nil
+}
+
+// runDeviceAuthorization registers a public client, starts a device authorization and polls for
+// the token until the user approves it, the code expires or ctx is cancelled.
+func runDeviceAuthorization(ctx context.Context, client oidcClient, profile SSOProfile, notify func(DeviceAuthorization), after func(time.Duration) <-chan time.Time) (*ssoToken, error) {
+	registration, err := client.RegisterClient(ctx, &ssooidc.RegisterClientInput{
+		ClientName: aws.String(ssoClientName),
+		ClientType: aws.String("public"),
+		Scopes:     profile.Scopes,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to register SSO client: %w", err)
+	}
+
+	device, err := client.StartDeviceAuthorization(ctx, &ssooidc.StartDeviceAuthorizationInput{
+		ClientId:     registration.ClientId,
+		ClientSecret: registration.ClientSecret,
+		StartUrl:     aws.String(profile.StartURL),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to start SSO device authorization: %w", err)
+	}
+
+	started := time.Now()
+	if notify != nil {
+		notify(DeviceAuthorization{
+			Profile:                 profile.Name,
+			VerificationURI:         aws.ToString(device.VerificationUri),
+			VerificationURIComplete: aws.ToString(device.VerificationUriComplete),
+			UserCode:                aws.ToString(device.UserCode),
+			ExpiresAt:               started.Add(time.Duration(device.ExpiresIn) * time.Second),
+		})
+	}
+
+	interval := time.Duration(device.Interval) * time.Second
+	if interval <= 0 {
+		interval = 5 * time.Second
+	}
+
+	for {
+		out, err := client.CreateToken(ctx, &ssooidc.CreateTokenInput{
+			ClientId:     registration.ClientId,
+			ClientSecret: registration.ClientSecret,
+			GrantType:    aws.String(ssoDeviceGrantType),
+			DeviceCode:   device.DeviceCode,
+		})
+		if err == nil {
+			now := time.Now()
+			token := &ssoToken{
+				StartURL:     profile.StartURL,
+				Region:       profile.Region,
+				AccessToken:  aws.ToString(out.AccessToken),
+				ExpiresAt:    now.Add(time.Duration(out.ExpiresIn) * time.Second).UTC().Truncate(time.Second),
+				RefreshToken: aws.ToString(out.RefreshToken),
+			}
+			// Client registration is only worth caching when the token can be refreshed with it
+			if token.RefreshToken != "" {
+				registrationExpiresAt := time.Unix(registration.ClientSecretExpiresAt, 0).UTC()
+				token.ClientID = aws.ToString(registration.ClientId)
+				token.ClientSecret = aws.ToString(registration.ClientSecret)
+				token.RegistrationExpiresAt = &registrationExpiresAt
+			}
+			return token, nil
+		}
+
+		var pending *ssooidctypes.AuthorizationPendingException
+		var slowDown *ssooidctypes.SlowDownException
+		var expired *ssooidctypes.ExpiredTokenException
+		switch {
+		case errors.As(err, &pending):
+		case errors.As(err, &slowDown):
+			interval += 5 * time.Second
+		case errors.As(err, &expired):
+			return nil, fmt.Errorf("SSO device code expired before the login was approved")
+		default:
+			if ctx.Err() != nil {
+				return nil, fmt.Errorf("SSO login cancelled")
+			}
+			return nil, fmt.Errorf("SSO login failed: %w", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("SSO login cancelled")
+		case <-after(interval):
+		}
+	}
+}
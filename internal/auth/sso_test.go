@@ -0,0 +1,297 @@
+package auth
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials/ssocreds"
+	"github.com/aws/aws-sdk-go-v2/service/ssooidc"
+	ssooidctypes "github.com/aws/aws-sdk-go-v2/service/ssooidc/types"
+)
+
+const testSharedConfig = `
+[default]
+region = us-west-2
+
+[profile plain]
+region = us-east-1
+
+[profile team-dev]
+sso_session = corp
+sso_account_id = 111111111111
+sso_role_name = ReadOnly
+
+[profile legacy]
+sso_start_url = https://legacy.awsapps.com/start
+sso_region = us-east-1
+sso_account_id = 222222222222
+sso_role_name = Admin
+
+[sso-session corp]
+sso_start_url = https://corp.awsapps.com/start
+sso_region = eu-west-1
+sso_registration_scopes = sso:account:access, openid
+`
+
+func writeSharedConfig(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+	return path
+}
+
+func TestLoadSSOProfiles(t *testing.T) {
+	profiles, err := LoadSSOProfiles(writeSharedConfig(t, testSharedConfig))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := map[string]SSOProfile{
+		"team-dev": {
+			Name:        "team-dev",
+			SessionName: "corp",
+			StartURL:    "https://corp.awsapps.com/start",
+			Region:      "eu-west-1",
+			Scopes:      []string{"sso:account:access", "openid"},
+		},
+		"legacy": {
+			Name:     "legacy",
+			StartURL: "https://legacy.awsapps.com/start",
+			Region:   "us-east-1",
+		},
+	}
+	if !reflect.DeepEqual(profiles, expected) {
+		t.Errorf("expected %+v, got %+v", expected, profiles)
+	}
+
+	if key := profiles["team-dev"].CacheKey(); key != "corp" {
+		t.Errorf("expected sso-session cache key, got %s", key)
+	}
+	if key := profiles["legacy"].CacheKey(); key != "https://legacy.awsapps.com/start" {
+		t.Errorf("expected start URL cache key, got %s", key)
+	}
+}
+
+func TestLoadSSOProfilesMissingSession(t *testing.T) {
+	_, err := LoadSSOProfiles(writeSharedConfig(t, "[profile broken]\nsso_session = nope\n"))
+	if err == nil {
+		t.Error("expected error for missing sso-session section")
+	}
+}
+
+func TestLoadSSOProfilesSkipsInvalid(t *testing.T) {
+	content := testSharedConfig + `
+[profile no-region]
+sso_start_url = https://other.awsapps.com/start
+
+[profile broken]
+sso_session = nope
+`
+	profiles, err := LoadSSOProfiles(writeSharedConfig(t, content))
+	if err == nil {
+		t.Error("expected error describing the skipped profiles")
+	}
+	if _, ok := profiles["no-region"]; ok {
+		t.Error("profile missing sso_region should be skipped")
+	}
+	if _, ok := profiles["broken"]; ok {
+		t.Error("profile with missing sso-session should be skipped")
+	}
+	if len(profiles) != 2 {
+		t.Errorf("expected the 2 valid profiles to be kept, got %v", profiles)
+	}
+}
+
+func TestSSOTokenCacheCompatibleWithSDK(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	path, err := ssoCachePath("corp")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	sdkPath, err := ssocreds.StandardCachedTokenFilepath("corp")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if path != sdkPath {
+		t.Fatalf("cache path %s does not match SDK path %s", path, sdkPath)
+	}
+
+	registrationExpiresAt := time.Now().Add(90 * 24 * time.Hour).UTC().Truncate(time.Second)
+	token := &ssoToken{
+		StartURL:              "https://corp.awsapps.com/start",
+		Region:                "eu-west-1",
+		AccessToken:           "access-token",
+		ExpiresAt:             time.Now().Add(time.Hour).UTC().Truncate(time.Second),
+		RefreshToken:          "refresh-token",
+		ClientID:              "client-id",
+		ClientSecret:          "client-secret",
+		RegistrationExpiresAt: &registrationExpiresAt,
+	}
+	if err := storeSSOToken(path, token); err != nil {
+		t.Fatalf("failed to store token: %v", err)
+	}
+
+	loaded, err := loadSSOToken(path)
+	if err != nil {
+		t.Fatalf("failed to load token: %v", err)
+	}
+	if !reflect.DeepEqual(loaded, token) {
+		t.Errorf("expected %+v, got %+v", token, loaded)
+	}
+
+	// The SDK must accept the cached token without refreshing it
+	provider := ssocreds.NewSSOTokenProvider(nil, path)
+	bearer, err := provider.RetrieveBearerToken(context.Background())
+	if err != nil {
+		t.Fatalf("SDK failed to read cached token: %v", err)
+	}
+	if bearer.Value != "access-token" {
+		t.Errorf("expected access-token, got %s", bearer.Value)
+	}
+}
+
+func TestSSOTokenValidity(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		name       string
+		token      ssoToken
+		valid      bool
+		canRefresh bool
+	}{
+		{
+			name:  "fresh token",
+			token: ssoToken{AccessToken: "a", ExpiresAt: now.Add(time.Hour)},
+			valid: true,
+		},
+		{
+			name:  "token inside expiry window",
+			token: ssoToken{AccessToken: "a", ExpiresAt: now.Add(time.Minute)},
+		},
+		{
+			name:       "expired token with refresh registration",
+			token:      ssoToken{AccessToken: "a", ExpiresAt: now.Add(-time.Hour), RefreshToken: "r", ClientID: "c", ClientSecret: "s"},
+			canRefresh: true,
+		},
+		{
+			name: "expired registration",
+			token: ssoToken{AccessToken: "a", ExpiresAt: now.Add(-time.Hour), RefreshToken: "r", ClientID: "c", ClientSecret: "s",
+				RegistrationExpiresAt: aws.Time(now.Add(-time.Minute))},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.token.valid(now); got != tt.valid {
+				t.Errorf("valid: expected %v, got %v", tt.valid, got)
+			}
+			if got := tt.token.canRefresh(now); got != tt.canRefresh {
+				t.Errorf("canRefresh: expected %v, got %v", tt.canRefresh, got)
+			}
+		})
+	}
+}
+
+// fakeOIDC replays CreateToken errors before returning a token.
+type fakeOIDC struct {
+	tokenErrors []error
+	calls       int
+}
+
+func (f *fakeOIDC) RegisterClient(context.Context, *ssooidc.RegisterClientInput, ...func(*ssooidc.Options)) (*ssooidc.RegisterClientOutput, error) {
+	return &ssooidc.RegisterClientOutput{
+		ClientId:              aws.String("client-id"),
+		ClientSecret:          aws.String("client-secret"),
+		ClientSecretExpiresAt: time.Now().Add(24 * time.Hour).Unix(),
+	}, nil
+}
+
+func (f *fakeOIDC) StartDeviceAuthorization(context.Context, *ssooidc.StartDeviceAuthorizationInput, ...func(*ssooidc.Options)) (*ssooidc.StartDeviceAuthorizationOutput, error) {
+	return &ssooidc.StartDeviceAuthorizationOutput{
+		DeviceCode:              aws.String("device-code"),
+		UserCode:                aws.String("ABCD-EFGH"),
+		VerificationUri:         aws.String("https://device.sso.eu-west-1.amazonaws.com/"),
+		VerificationUriComplete: aws.String("https://device.sso.eu-west-1.amazonaws.com/?user_code=ABCD-EFGH"),
+		ExpiresIn:               600,
+		Interval:                1,
+	}, nil
+}
+
+func (f *fakeOIDC) CreateToken(_ context.Context, params *ssooidc.CreateTokenInput, _ ...func(*ssooidc.Options)) (*ssooidc.CreateTokenOutput, error) {
+	f.calls++
+	if len(f.tokenErrors) > 0 {
+		err := f.tokenErrors[0]
+		f.tokenErrors = f.tokenErrors[1:]
+		return nil, err
+	}
+	return &ssooidc.CreateTokenOutput{
+		AccessToken:  aws.String("access-token"),
+		RefreshToken: aws.String("refresh-token"),
+		ExpiresIn:    3600,
+	}, nil
+}
+
+func TestRunDeviceAuthorization(t *testing.T) {
+	profile := SSOProfile{Name: "team-dev", SessionName: "corp", StartURL: "https://corp.awsapps.com/start", Region: "eu-west-1"}
+	immediately := func(time.Duration) <-chan time.Time {
+		ch := make(chan time.Time, 1)
+		ch <- time.Now()
+		return ch
+	}
+
+	t.Run("polls until approved", func(t *testing.T) {
+		client := &fakeOIDC{tokenErrors: []error{
+			&ssooidctypes.AuthorizationPendingException{},
+			&ssooidctypes.SlowDownException{},
+		}}
+
+		var shown DeviceAuthorization
+		token, err := runDeviceAuthorization(context.Background(), client, profile, func(d DeviceAuthorization) {
+			shown = d
+		}, immediately)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if client.calls != 3 {
+			t.Errorf("expected 3 CreateToken calls, got %d", client.calls)
+		}
+		if shown.UserCode != "ABCD-EFGH" || shown.Profile != "team-dev" {
+			t.Errorf("unexpected device authorization %+v", shown)
+		}
+		if token.AccessToken != "access-token" || token.StartURL != profile.StartURL || token.Region != profile.Region {
+			t.Errorf("unexpected token %+v", token)
+		}
+		if token.ClientID != "client-id" || token.RegistrationExpiresAt == nil {
+			t.Errorf("expected client registration to be cached with refresh token, got %+v", token)
+		}
+	})
+
+	t.Run("expired device code", func(t *testing.T) {
+		client := &fakeOIDC{tokenErrors: []error{&ssooidctypes.ExpiredTokenException{}}}
+		_, err := runDeviceAuthorization(context.Background(), client, profile, nil, immediately)
+		if err == nil {
+			t.Error("expected error for expired device code")
+		}
+	})
+
+	t.Run("cancelled while waiting", func(t *testing.T) {
+		client := &fakeOIDC{tokenErrors: []error{&ssooidctypes.AuthorizationPendingException{}}}
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		never := func(time.Duration) <-chan time.Time { return nil }
+		_, err := runDeviceAuthorization(ctx, client, profile, nil, never)
+		if err == nil {
+			t.Error("expected error when login is cancelled")
+		}
+	})
+}
@@ -0,0 +1,105 @@
+// Package cli implements the headless subcommands, which reuse the TUI's authentication and
+// services and print their results to stdout for scripts and cron jobs.
+package cli
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/spf13/pflag"
+	"github.com/tpelletiersophos/cloudcutter/internal/auth"
+	"github.com/tpelletiersophos/cloudcutter/internal/config"
+)
+
+// options are the flags shared by every headless command.
+type options struct {
+	profile string
+	region  string
+	output  string
+	limit   int
+	quiet   bool
+}
+
+func (o *options) addFlags(flags *pflag.FlagSet, defaultLimit int) {
+	profile := os.Getenv("AWS_PROFILE")
+	if profile == "" {
+		profile = "default"
+	}
+	flags.StringVar(&o.profile, "profile", profile, "AWS profile to authenticate with")
+	flags.StringVar(&o.region, "region", "", "AWS region (default regions.default in config.yaml)")
+	flags.StringVarP(&o.output, "output", "o", FormatJSON, "Output format: "+strings.Join(formats, ", "))
+	flags.IntVar(&o.limit, "limit", defaultLimit, "Maximum number of results")
+	flags.BoolVarP(&o.quiet, "quiet", "q", false, "Do not print authentication progress to stderr")
+}
+
+func (o *options) validate() error {
+	if !slices.Contains(formats, o.output) {
+		return fmt.Errorf("unknown output format %q (use %s)", o.output, strings.Join(formats, ", "))
+	}
+	if o.limit < 0 {
+		return fmt.Errorf("--limit must not be negative")
+	}
+	return nil
+}
+
+// authenticate signs in to the profile the same way the TUI does, after applying config.yaml.
+// Progress, SSO device logins and MFA prompts go to stderr so stdout only carries results.
+func (o *options) authenticate(ctx context.Context, stderr io.Writer) (aws.Config, error) {
+	cfg, err := config.Load(config.Path())
+	if err != nil {
+		return aws.Config{}, err
+	}
+	if err := cfg.Apply(); err != nil {
+		return aws.Config{}, err
+	}
+	if o.region == "" {
+		o.region = cfg.Regions.Default
+	}
+
+	authenticator, err := auth.New(func(status string) {
+		if !o.quiet {
+			fmt.Fprintln(stderr, status)
+		}
+	})
+	if err != nil {
+		return aws.Config{}, err
+	}
+
+	authenticator.SetDeviceAuthHandler(func(device auth.DeviceAuthorization) {
+		fmt.Fprintf(stderr, "Open %s and confirm the code %s to sign in to %s\n",
+			device.VerificationURIComplete, device.UserCode, device.Profile)
+	})
+	authenticator.SetMFATokenHandler(func(req auth.MFARequest) (string, error) {
+		fmt.Fprintf(stderr, "MFA code for %s (%s): ", req.Profile, req.MFASerial)
+		code, err := bufio.NewReader(os.Stdin).ReadString('\n')
+		if err != nil {
+			return "", fmt.Errorf("reading MFA code: %w", err)
+		}
+		return strings.TrimSpace(code), nil
+	})
+
+	session, err := authenticator.SwitchProfile(ctx, o.profile, o.region)
+	if err != nil {
+		return aws.Config{}, err
+	}
+	return session.Config, nil
+}
+
+// parseKeyValues parses repeated name=value flags.
+func parseKeyValues(flag string, pairs []string) (map[string]string, error) {
+	values := make(map[string]string, len(pairs))
+	for _, pair := range pairs {
+		name, value, ok := strings.Cut(pair, "=")
+		if !ok || name == "" {
+			return nil, fmt.Errorf("--%s must be name=value, got %q", flag, pair)
+		}
+		values[name] = value
+	}
+	return values, nil
+}
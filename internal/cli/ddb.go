@@ -0,0 +1,144 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	dynamodbtypes "github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/spf13/cobra"
+	"github.com/tpelletiersophos/cloudcutter/internal/services/aws/dynamodb"
+)
+
+type tableOptions struct {
+	options
+	index string
+	keys  []string
+}
+
+// tableRequest reads items from an authenticated service.
+type tableRequest func(ctx context.Context, svc dynamodb.Interface, table *dynamodbtypes.TableDescription) ([]map[string]dynamodbtypes.AttributeValue, error)
+
+// NewDDBCommand returns the `ddb` command group.
+func NewDDBCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "ddb",
+		Short: "Read DynamoDB tables without starting the UI",
+	}
+	cmd.AddCommand(newScanCommand(), newQueryCommand(), newGetCommand())
+	return cmd
+}
+
+func newScanCommand() *cobra.Command {
+	opts := &tableOptions{}
+	cmd := &cobra.Command{
+		Use:     "scan <table>",
+		Short:   "Scan a table",
+		Example: "  cloudcutter ddb scan users --limit 50 -o csv",
+		Args:    cobra.ExactArgs(1),
+	}
+	opts.addFlags(cmd.Flags(), 100)
+	cmd.RunE = opts.runE(func(ctx context.Context, svc dynamodb.Interface, table *dynamodbtypes.TableDescription) ([]map[string]dynamodbtypes.AttributeValue, error) {
+		return svc.Scan(ctx, *table.TableName, opts.limit)
+	})
+	return cmd
+}
+
+func newQueryCommand() *cobra.Command {
+	opts := &tableOptions{}
+	cmd := &cobra.Command{
+		Use:   "query <table>",
+		Short: "Query a table or secondary index by partition key, and optionally sort key",
+		Example: `  cloudcutter ddb query orders --key customerId=42 -o table
+  cloudcutter ddb query orders --index byStatus --key status=open --limit 20`,
+		Args: cobra.ExactArgs(1),
+	}
+	opts.addFlags(cmd.Flags(), 100)
+	cmd.Flags().StringVar(&opts.index, "index", "", "Secondary index to query")
+	cmd.Flags().StringArrayVar(&opts.keys, "key", nil, "Key condition as name=value; repeat for the sort key")
+	cmd.RunE = opts.runE(func(ctx context.Context, svc dynamodb.Interface, table *dynamodbtypes.TableDescription) ([]map[string]dynamodbtypes.AttributeValue, error) {
+		key, err := opts.key(table)
+		if err != nil {
+			return nil, err
+		}
+		return svc.Query(ctx, *table.TableName, opts.index, key, opts.limit)
+	})
+	return cmd
+}
+
+func newGetCommand() *cobra.Command {
+	opts := &tableOptions{}
+	cmd := &cobra.Command{
+		Use:     "get <table>",
+		Short:   "Get one item by its primary key",
+		Example: "  cloudcutter ddb get orders --key customerId=42 --key orderId=2024-001",
+		Args:    cobra.ExactArgs(1),
+	}
+	opts.addFlags(cmd.Flags(), 1)
+	_ = cmd.Flags().MarkHidden("limit")
+	cmd.Flags().StringArrayVar(&opts.keys, "key", nil, "Primary key attribute as name=value; repeat for the sort key")
+	cmd.RunE = opts.runE(func(ctx context.Context, svc dynamodb.Interface, table *dynamodbtypes.TableDescription) ([]map[string]dynamodbtypes.AttributeValue, error) {
+		key, err := opts.key(table)
+		if err != nil {
+			return nil, err
+		}
+		item, err := svc.GetItem(ctx, *table.TableName, key)
+		if err != nil || item == nil {
+			return nil, err
+		}
+		return []map[string]dynamodbtypes.AttributeValue{item}, nil
+	})
+	return cmd
+}
+
+// runE authenticates, describes the table named by the first argument and prints what request reads.
+func (o *tableOptions) runE(request tableRequest) func(*cobra.Command, []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		if err := o.validate(); err != nil {
+			return err
+		}
+
+		ctx := cmd.Context()
+		cfg, err := o.authenticate(ctx, cmd.ErrOrStderr())
+		if err != nil {
+			return err
+		}
+		return readTable(ctx, cmd.OutOrStdout(), o.output, dynamodb.NewService(cfg), args[0], request)
+	}
+}
+
+// readTable prints the items request reads from tableName, key attributes first.
+func readTable(ctx context.Context, w io.Writer, format string, svc dynamodb.Interface, tableName string, request tableRequest) error {
+	table, err := svc.DescribeTable(ctx, tableName)
+	if err != nil {
+		return fmt.Errorf("failed to describe table %s: %w", tableName, err)
+	}
+
+	items, err := request(ctx, svc, table)
+	if err != nil {
+		return err
+	}
+
+	records := make([]map[string]any, len(items))
+	for i, item := range items {
+		records[i] = dynamodb.ItemToMap(item)
+	}
+
+	var keyNames []string
+	for _, element := range table.KeySchema {
+		keyNames = append(keyNames, *element.AttributeName)
+	}
+	return WriteRecords(w, format, records, keyNames...)
+}
+
+func (o *tableOptions) key(table *dynamodbtypes.TableDescription) (map[string]dynamodbtypes.AttributeValue, error) {
+	if len(o.keys) == 0 {
+		return nil, fmt.Errorf("--key is required")
+	}
+	values, err := parseKeyValues("key", o.keys)
+	if err != nil {
+		return nil, err
+	}
+	return dynamodb.KeyFromStrings(table, values)
+}
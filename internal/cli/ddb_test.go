@@ -0,0 +1,60 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	dynamodbtypes "github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/tpelletiersophos/cloudcutter/internal/services/aws/dynamodb"
+)
+
+type fakeTables struct {
+	dynamodb.Interface
+	table *dynamodbtypes.TableDescription
+}
+
+func (f *fakeTables) DescribeTable(ctx context.Context, tableName string) (*dynamodbtypes.TableDescription, error) {
+	return f.table, nil
+}
+
+func TestReadTable(t *testing.T) {
+	svc := &fakeTables{table: &dynamodbtypes.TableDescription{
+		TableName: aws.String("orders"),
+		KeySchema: []dynamodbtypes.KeySchemaElement{
+			{AttributeName: aws.String("customerId"), KeyType: dynamodbtypes.KeyTypeHash},
+			{AttributeName: aws.String("orderId"), KeyType: dynamodbtypes.KeyTypeRange},
+		},
+		AttributeDefinitions: []dynamodbtypes.AttributeDefinition{
+			{AttributeName: aws.String("customerId"), AttributeType: dynamodbtypes.ScalarAttributeTypeN},
+			{AttributeName: aws.String("orderId"), AttributeType: dynamodbtypes.ScalarAttributeTypeS},
+		},
+	}}
+
+	opts := &tableOptions{keys: []string{"customerId=42", "orderId=a-1"}}
+	var buf bytes.Buffer
+	err := readTable(context.Background(), &buf, FormatCSV, svc, "orders",
+		func(ctx context.Context, _ dynamodb.Interface, table *dynamodbtypes.TableDescription) ([]map[string]dynamodbtypes.AttributeValue, error) {
+			key, err := opts.key(table)
+			if err != nil {
+				return nil, err
+			}
+			assert.Equal(t, &dynamodbtypes.AttributeValueMemberN{Value: "42"}, key["customerId"])
+			return []map[string]dynamodbtypes.AttributeValue{{
+				"total":      &dynamodbtypes.AttributeValueMemberN{Value: "9.50"},
+				"orderId":    key["orderId"],
+				"customerId": key["customerId"],
+			}}, nil
+		})
+	assert.NoError(t, err)
+	assert.Equal(t, "customerId,orderId,total\n42,a-1,9.50\n", buf.String(), "key attributes come first")
+
+	opts.keys = []string{"status=open"}
+	_, err = opts.key(svc.table)
+	assert.ErrorContains(t, err, "not a key attribute")
+	opts.keys = nil
+	_, err = opts.key(svc.table)
+	assert.Error(t, err)
+}
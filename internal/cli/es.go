@@ -0,0 +1,136 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/elastic/go-elasticsearch/v6"
+	"github.com/spf13/cobra"
+	esservice "github.com/tpelletiersophos/cloudcutter/internal/services/elastic"
+	elasticView "github.com/tpelletiersophos/cloudcutter/internal/ui/views/elastic"
+)
+
+// maxSearchSize is the largest page Elasticsearch returns without scrolling.
+const maxSearchSize = 10000
+
+type searchOptions struct {
+	options
+	index     string
+	timeframe string
+	filters   []string
+}
+
+// NewESCommand returns the `es` command group.
+func NewESCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "es",
+		Short: "Query Elasticsearch without starting the UI",
+	}
+	cmd.AddCommand(newSearchCommand())
+	return cmd
+}
+
+func newSearchCommand() *cobra.Command {
+	opts := &searchOptions{}
+	cmd := &cobra.Command{
+		Use:   "search",
+		Short: "Search an index with the same filter syntax as the Elastic view",
+		Example: `  cloudcutter es search --index 'main-summary-*' --timeframe 1h --filter 'level=error' -o ndjson
+  cloudcutter es search --filter 'status>=500' --filter 'host=web-1' --limit 10 -o table`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			cmd.SilenceUsage = true
+			return opts.run(cmd.Context(), cmd.OutOrStdout(), cmd.ErrOrStderr())
+		},
+	}
+
+	opts.addFlags(cmd.Flags(), 100)
+	cmd.Flags().StringVar(&opts.index, "index", "", "Index or index pattern to search (default views.elastic.search.default_index in config.yaml)")
+	cmd.Flags().StringVar(&opts.timeframe, "timeframe", "", "Only match documents from this long ago until now, e.g. 15m, 12h, 7d")
+	cmd.Flags().StringArrayVar(&opts.filters, "filter", nil, "Filter such as field=value or field>=10; repeat to combine with AND")
+	return cmd
+}
+
+func (o *searchOptions) run(ctx context.Context, stdout, stderr io.Writer) error {
+	if err := o.validate(); err != nil {
+		return err
+	}
+	if o.limit == 0 || o.limit > maxSearchSize {
+		return fmt.Errorf("--limit must be between 1 and %d", maxSearchSize)
+	}
+
+	query, err := elasticView.BuildQuery(o.filters, o.limit, o.timeframe, elasticView.NewFieldCache())
+	if err != nil {
+		return err
+	}
+	delete(query, "highlight")
+
+	cfg, err := o.authenticate(ctx, stderr)
+	if err != nil {
+		return err
+	}
+	if o.index == "" {
+		o.index = elasticView.GetGlobalConfig().Search.DefaultIndex
+	}
+	client, err := esservice.NewClient(cfg, o.profile)
+	if err != nil {
+		return err
+	}
+
+	records, err := searchDocuments(ctx, client, o.index, query)
+	if err != nil {
+		return err
+	}
+	return WriteRecords(stdout, o.output, records, "_index", "_id")
+}
+
+// searchDocuments runs query against index and returns each hit's source with its _index and _id.
+func searchDocuments(ctx context.Context, client *elasticsearch.Client, index string, query map[string]any) ([]map[string]any, error) {
+	body, err := json.Marshal(query)
+	if err != nil {
+		return nil, fmt.Errorf("error encoding query: %v", err)
+	}
+
+	res, err := client.Search(
+		client.Search.WithContext(ctx),
+		client.Search.WithIndex(index),
+		client.Search.WithBody(bytes.NewReader(body)),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("search error: %v", err)
+	}
+	defer res.Body.Close()
+
+	data, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error reading response: %v", err)
+	}
+	if res.IsError() {
+		return nil, fmt.Errorf("%s: %s", res.Status(), strings.TrimSpace(string(data)))
+	}
+
+	var result esservice.ESSearchResult
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, fmt.Errorf("error decoding response: %v", err)
+	}
+
+	records := make([]map[string]any, 0, len(result.Hits.Hits))
+	for _, hit := range result.Hits.Hits {
+		record := make(map[string]any)
+		if len(hit.Source) > 0 {
+			dec := json.NewDecoder(bytes.NewReader(hit.Source))
+			dec.UseNumber()
+			if err := dec.Decode(&record); err != nil {
+				return nil, fmt.Errorf("error decoding document %s: %v", hit.ID, err)
+			}
+		}
+		record["_index"] = hit.Index
+		record["_id"] = hit.ID
+		records = append(records, record)
+	}
+	return records, nil
+}
@@ -0,0 +1,67 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/elastic/go-elasticsearch/v6"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSearchDocuments(t *testing.T) {
+	var gotPath string
+	var gotQuery map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		body, _ := io.ReadAll(r.Body)
+		_ = json.Unmarshal(body, &gotQuery)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = io.WriteString(w, `{"took": 1, "hits": {"total": 1, "hits": [
+			{"_index": "logs-1", "_id": "a", "_source": {"level": "error", "count": 12345678901234567890}}
+		]}}`)
+	}))
+	defer server.Close()
+
+	client, err := elasticsearch.NewClient(elasticsearch.Config{Addresses: []string{server.URL}})
+	assert.NoError(t, err)
+
+	records, err := searchDocuments(context.Background(), client, "logs-*", map[string]any{"size": 5})
+	assert.NoError(t, err)
+	assert.Equal(t, "/logs-*/_search", gotPath)
+	assert.Equal(t, float64(5), gotQuery["size"])
+	assert.Equal(t, []map[string]any{{
+		"_index": "logs-1",
+		"_id":    "a",
+		"level":  "error",
+		"count":  json.Number("12345678901234567890"),
+	}}, records)
+}
+
+func TestSearchDocumentsError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = io.WriteString(w, `{"error": "parsing_exception"}`)
+	}))
+	defer server.Close()
+
+	client, err := elasticsearch.NewClient(elasticsearch.Config{Addresses: []string{server.URL}})
+	assert.NoError(t, err)
+
+	_, err = searchDocuments(context.Background(), client, "logs-*", map[string]any{})
+	assert.ErrorContains(t, err, "parsing_exception")
+}
+
+func TestSearchOptionsValidation(t *testing.T) {
+	for _, opts := range []searchOptions{
+		{options: options{output: "yaml", limit: 10}},
+		{options: options{output: FormatJSON, limit: 0}},
+		{options: options{output: FormatJSON, limit: maxSearchSize + 1}},
+		{options: options{output: FormatJSON, limit: 10}, filters: []string{"=broken"}},
+	} {
+		assert.Error(t, opts.run(context.Background(), io.Discard, io.Discard), "%+v", opts)
+	}
+}
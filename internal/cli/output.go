@@ -0,0 +1,141 @@
+package cli
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"sort"
+	"strings"
+	"text/tabwriter"
+)
+
+// Output formats supported by the headless commands.
+const (
+	FormatJSON   = "json"
+	FormatNDJSON = "ndjson"
+	FormatCSV    = "csv"
+	FormatTable  = "table"
+)
+
+var formats = []string{FormatJSON, FormatNDJSON, FormatCSV, FormatTable}
+
+// WriteRecords writes records to w in format. CSV and table output flatten nested objects into
+// dotted columns; leading names the columns to put first when records have them.
+func WriteRecords(w io.Writer, format string, records []map[string]any, leading ...string) error {
+	switch format {
+	case FormatJSON:
+		if records == nil {
+			records = []map[string]any{}
+		}
+		enc := json.NewEncoder(w)
+		enc.SetEscapeHTML(false)
+		enc.SetIndent("", "  ")
+		return enc.Encode(records)
+	case FormatNDJSON:
+		enc := json.NewEncoder(w)
+		enc.SetEscapeHTML(false)
+		for _, record := range records {
+			if err := enc.Encode(record); err != nil {
+				return err
+			}
+		}
+		return nil
+	case FormatCSV, FormatTable:
+		rows := make([]map[string]string, len(records))
+		for i, record := range records {
+			rows[i] = make(map[string]string)
+			flattenValue("", record, rows[i])
+		}
+		columns := recordColumns(rows, leading)
+		if format == FormatCSV {
+			return writeCSV(w, columns, rows)
+		}
+		return writeTable(w, columns, rows)
+	default:
+		return fmt.Errorf("unknown output format %q (use %s)", format, strings.Join(formats, ", "))
+	}
+}
+
+// flattenValue stores value under prefix, descending into objects with dotted names. Other values
+// than strings are written as JSON.
+func flattenValue(prefix string, value any, out map[string]string) {
+	switch v := value.(type) {
+	case map[string]any:
+		for key, elem := range v {
+			name := key
+			if prefix != "" {
+				name = prefix + "." + key
+			}
+			flattenValue(name, elem, out)
+		}
+	case string:
+		out[prefix] = v
+	case nil:
+		out[prefix] = ""
+	default:
+		data, err := json.Marshal(v)
+		if err != nil {
+			data = []byte(fmt.Sprint(v))
+		}
+		out[prefix] = string(data)
+	}
+}
+
+// recordColumns returns the leading columns that occur, then every other column in name order.
+func recordColumns(rows []map[string]string, leading []string) []string {
+	seen := make(map[string]bool)
+	for _, row := range rows {
+		for name := range row {
+			seen[name] = true
+		}
+	}
+
+	var columns, rest []string
+	for _, name := range leading {
+		if seen[name] && !slices.Contains(columns, name) {
+			columns = append(columns, name)
+		}
+	}
+	for name := range seen {
+		if !slices.Contains(columns, name) {
+			rest = append(rest, name)
+		}
+	}
+	sort.Strings(rest)
+	return append(columns, rest...)
+}
+
+func writeCSV(w io.Writer, columns []string, rows []map[string]string) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(columns); err != nil {
+		return err
+	}
+	for _, row := range rows {
+		record := make([]string, len(columns))
+		for i, name := range columns {
+			record[i] = row[name]
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+var tableCellReplacer = strings.NewReplacer("\t", " ", "\n", " ", "\r", " ")
+
+func writeTable(w io.Writer, columns []string, rows []map[string]string) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, strings.ToUpper(strings.Join(columns, "\t")))
+	for _, row := range rows {
+		cells := make([]string, len(columns))
+		for i, name := range columns {
+			cells[i] = tableCellReplacer.Replace(row[name])
+		}
+		fmt.Fprintln(tw, strings.Join(cells, "\t"))
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,78 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testRecords() []map[string]any {
+	return []map[string]any{
+		{"_id": "1", "name": "web-1", "meta": map[string]any{"status": json.Number("500"), "tags": []any{"a", "b"}}},
+		{"_id": "2", "name": "line\tbreak\nhere", "extra": nil},
+	}
+}
+
+func TestWriteRecords(t *testing.T) {
+	tests := []struct {
+		format   string
+		expected string
+	}{
+		{
+			format: FormatNDJSON,
+			expected: `{"_id":"1","meta":{"status":500,"tags":["a","b"]},"name":"web-1"}
+{"_id":"2","extra":null,"name":"line\tbreak\nhere"}
+`,
+		},
+		{
+			format: FormatCSV,
+			expected: `_id,name,extra,meta.status,meta.tags
+1,web-1,,500,"[""a"",""b""]"
+2,"line	break
+here",,,
+`,
+		},
+		{
+			format: FormatTable,
+			expected: `_ID  NAME             EXTRA  META.STATUS  META.TAGS
+1    web-1                   500          ["a","b"]
+2    line break here                      
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			var buf bytes.Buffer
+			assert.NoError(t, WriteRecords(&buf, tt.format, testRecords(), "_id", "name", "missing"))
+			assert.Equal(t, tt.expected, buf.String())
+		})
+	}
+}
+
+func TestWriteRecordsJSON(t *testing.T) {
+	var buf bytes.Buffer
+	assert.NoError(t, WriteRecords(&buf, FormatJSON, nil))
+	assert.Equal(t, "[]\n", buf.String(), "no results is an empty array, not null")
+
+	buf.Reset()
+	assert.NoError(t, WriteRecords(&buf, FormatJSON, testRecords()[:1]))
+	var decoded []map[string]any
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &decoded))
+	assert.Equal(t, "web-1", decoded[0]["name"])
+
+	assert.Error(t, WriteRecords(&buf, "yaml", nil))
+}
+
+func TestParseKeyValues(t *testing.T) {
+	values, err := parseKeyValues("key", []string{"id=42", "sk=a=b", "empty="})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"id": "42", "sk": "a=b", "empty": ""}, values)
+
+	_, err = parseKeyValues("key", []string{"novalue"})
+	assert.Error(t, err)
+	_, err = parseKeyValues("key", []string{"=42"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,155 @@
+// Package config reads ~/.cloudcutter/config.yaml, the one file that configures authentication,
+// clusters, regions, view defaults, key bindings and the theme, and watches it for changes.
+//
+// Every setting has a default, so the file and each of its sections are optional. Environment
+// variables such as ELASTIC_VIEW_PAGE_SIZE still override what the file says.
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/tpelletiersophos/cloudcutter/internal/auth"
+	"github.com/tpelletiersophos/cloudcutter/internal/services/elastic"
+	"github.com/tpelletiersophos/cloudcutter/internal/ui/common"
+	"github.com/tpelletiersophos/cloudcutter/internal/ui/components/region"
+	"github.com/tpelletiersophos/cloudcutter/internal/ui/style"
+	elasticView "github.com/tpelletiersophos/cloudcutter/internal/ui/views/elastic"
+	"gopkg.in/yaml.v3"
+)
+
+// Config is the contents of config.yaml.
+type Config struct {
+	Auth     auth.Settings                `yaml:"auth"`
+	Clusters Clusters                     `yaml:"clusters"`
+	Regions  Regions                      `yaml:"regions"`
+	Views    Views                        `yaml:"views"`
+	Keymaps  map[string]map[string]string `yaml:"keymaps"` // context, then action, to keys
+	Theme    string                       `yaml:"theme"`
+}
+
+// Clusters are the search clusters sessions connect to.
+type Clusters struct {
+	Elasticsearch elastic.ClusterConfig `yaml:"elasticsearch"`
+}
+
+// Regions are the region new sessions start in and those the region selector offers.
+type Regions struct {
+	Default   string   `yaml:"default"`
+	Available []string `yaml:"available"`
+}
+
+// Views hold each view's defaults.
+type Views struct {
+	Elastic elasticView.ElasticViewConfig `yaml:"elastic"`
+}
+
+// Path returns the config file path.
+func Path() string {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(homeDir, ".cloudcutter", "config.yaml")
+}
+
+// Default returns the configuration used when there is no config file.
+func Default() *Config {
+	return &Config{
+		Clusters: Clusters{Elasticsearch: elastic.DefaultClusterConfig()},
+		Regions: Regions{
+			Default:   "us-west-2",
+			Available: slices.Clone(region.DefaultRegions),
+		},
+		Views: Views{Elastic: *elasticView.NewConfigManager().GetConfig()},
+	}
+}
+
+// Load reads path over the defaults, applies the environment overrides and validates the result.
+// A missing file is the defaults. Errors name the file and the setting, or the line, at fault.
+func Load(path string) (*Config, error) {
+	cfg := Default()
+
+	data, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	if len(bytes.TrimSpace(data)) > 0 {
+		dec := yaml.NewDecoder(bytes.NewReader(data))
+		dec.KnownFields(true)
+		if err := dec.Decode(cfg); err != nil && !errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("invalid config %s: %w", path, err)
+		}
+	}
+
+	if err := elasticView.NewConfigManagerFrom(&cfg.Views.Elastic).LoadFromEnvironment(); err != nil {
+		return nil, fmt.Errorf("invalid config %s: views.elastic: %w", path, err)
+	}
+	if err := cfg.validate(); err != nil {
+		return nil, fmt.Errorf("invalid config %s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// validate checks the sections the environment does not touch; views.elastic is checked by its
+// ConfigManager as the overrides are applied.
+func (c *Config) validate() error {
+	if err := c.Auth.Validate(); err != nil {
+		return fmt.Errorf("auth.%w", err)
+	}
+	if err := c.Clusters.Elasticsearch.Validate(); err != nil {
+		return fmt.Errorf("clusters.elasticsearch.%w", err)
+	}
+
+	if len(c.Regions.Available) == 0 {
+		return fmt.Errorf("regions.available must not be empty")
+	}
+	for i, name := range c.Regions.Available {
+		if name == "" {
+			return fmt.Errorf("regions.available[%d] must not be empty", i)
+		}
+		if slices.Index(c.Regions.Available, name) != i {
+			return fmt.Errorf("regions.available lists %s twice", name)
+		}
+	}
+	if !slices.Contains(c.Regions.Available, c.Regions.Default) {
+		return fmt.Errorf("regions.default %q is not in regions.available", c.Regions.Default)
+	}
+
+	if _, err := common.NewKeymap(c.Keymaps); err != nil {
+		return fmt.Errorf("keymaps.%w", err)
+	}
+	if _, err := style.LoadTheme(c.Theme); err != nil {
+		return fmt.Errorf("theme: %w", err)
+	}
+	return nil
+}
+
+// Apply makes the process use c for authentication, cluster endpoints and Elastic view defaults
+// from the next sign-in, connection or view on, and for key bindings and the theme at once.
+// Regions are the caller's to apply.
+func (c *Config) Apply() error {
+	if err := auth.UseSettings(c.Auth); err != nil {
+		return fmt.Errorf("auth.%w", err)
+	}
+	if err := elastic.UseClusterConfig(c.Clusters.Elasticsearch); err != nil {
+		return fmt.Errorf("clusters.elasticsearch.%w", err)
+	}
+	keymap, err := common.NewKeymap(c.Keymaps)
+	if err != nil {
+		return fmt.Errorf("keymaps.%w", err)
+	}
+	common.UseKeymap(keymap)
+	theme, err := style.LoadTheme(c.Theme)
+	if err != nil {
+		return fmt.Errorf("theme: %w", err)
+	}
+	style.Use(theme)
+	elasticView.SetGlobalConfigManager(elasticView.NewConfigManagerFrom(&c.Views.Elastic))
+	return nil
+}
@@ -0,0 +1,193 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tpelletiersophos/cloudcutter/internal/auth"
+)
+
+func writeConfig(t *testing.T, contents string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(contents), 0o600); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+	return path
+}
+
+func TestLoadMissingFile(t *testing.T) {
+	cfg, err := Load(filepath.Join(t.TempDir(), "config.yaml"))
+	assert.NoError(t, err)
+	assert.Equal(t, Default(), cfg)
+}
+
+func TestLoad(t *testing.T) {
+	path := writeConfig(t, `
+auth:
+  policies: []
+  providers:
+    - type: env
+      profileRegex: ^ci-
+clusters:
+  elasticsearch:
+    endpoint: https://search-{region}.example.com
+regions:
+  default: eu-west-1
+  available: [eu-west-1, eu-central-1]
+views:
+  elastic:
+    pagination:
+      default_page_size: 25
+    search:
+      default_index: logs-*
+      scroll_timeout: 2m
+keymaps:
+  elastic.results:
+    next_page: n, pgdn
+theme: gruvbox
+`)
+
+	cfg, err := Load(path)
+	assert.NoError(t, err)
+	assert.NotNil(t, cfg.Auth.Policies, "an empty list turns protection off rather than using the defaults")
+	assert.Empty(t, cfg.Auth.Policies)
+	if assert.Len(t, cfg.Auth.Providers, 1) {
+		assert.Equal(t, auth.ProviderEnv, cfg.Auth.Providers[0].Type)
+		assert.True(t, cfg.Auth.Providers[0].Matches("ci-runner", ""))
+	}
+	assert.Equal(t, "https://search-{region}.example.com", cfg.Clusters.Elasticsearch.Endpoint)
+	assert.Equal(t, "http://localhost:9200", cfg.Clusters.Elasticsearch.LocalEndpoint, "unset keys keep their defaults")
+	assert.Equal(t, Regions{Default: "eu-west-1", Available: []string{"eu-west-1", "eu-central-1"}}, cfg.Regions)
+	assert.Equal(t, 25, cfg.Views.Elastic.Pagination.DefaultPageSize)
+	assert.Equal(t, 1000, cfg.Views.Elastic.Pagination.MaxPageSize)
+	assert.Equal(t, "logs-*", cfg.Views.Elastic.Search.DefaultIndex)
+	assert.Equal(t, 2*time.Minute, cfg.Views.Elastic.Search.ScrollTimeout)
+	assert.Equal(t, "n, pgdn", cfg.Keymaps["elastic.results"]["next_page"])
+	assert.Equal(t, "gruvbox", cfg.Theme)
+}
+
+func TestLoadEnvironmentOverrides(t *testing.T) {
+	t.Setenv("ELASTIC_VIEW_PAGE_SIZE", "75")
+	path := writeConfig(t, "views:\n  elastic:\n    pagination:\n      default_page_size: 25\n")
+
+	cfg, err := Load(path)
+	assert.NoError(t, err)
+	assert.Equal(t, 75, cfg.Views.Elastic.Pagination.DefaultPageSize)
+}
+
+func TestLoadErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		contents string
+		expected string
+	}{
+		{
+			name:     "unknown key",
+			contents: "regions:\n  default: us-east-1\n  availble: [us-east-1]\n",
+			expected: "line 3: field availble not found",
+		},
+		{
+			name:     "wrong type",
+			contents: "views:\n  elastic:\n    pagination:\n      default_page_size: many\n",
+			expected: "line 4: cannot unmarshal !!str `many` into int",
+		},
+		{
+			name:     "elastic view validation",
+			contents: "views:\n  elastic:\n    pagination:\n      default_page_size: 0\n",
+			expected: "views.elastic: pagination.default_page_size must be >= 1, got 0",
+		},
+		{
+			name:     "default region not available",
+			contents: "regions:\n  default: mars-1\n",
+			expected: `regions.default "mars-1" is not in regions.available`,
+		},
+		{
+			name:     "duplicate region",
+			contents: "regions:\n  available: [us-west-2, us-west-2]\n",
+			expected: "regions.available lists us-west-2 twice",
+		},
+		{
+			name:     "cluster endpoint without region",
+			contents: "clusters:\n  elasticsearch:\n    endpoint: https://search.example.com\n",
+			expected: `clusters.elasticsearch.endpoint "https://search.example.com" must contain {region}`,
+		},
+		{
+			name:     "auth policy",
+			contents: "auth:\n  policies:\n    - name: PROD\n      protection: confirm\n",
+			expected: "auth.policies[0]: policy needs",
+		},
+		{
+			name:     "empty key binding",
+			contents: "keymaps:\n  elastic:\n    refresh: \"\"\n",
+			expected: "keymaps.elastic.refresh must name a key",
+		},
+		{
+			name:     "unknown key action",
+			contents: "keymaps:\n  elastic:\n    refresh: ctrl+l\n",
+			expected: "keymaps.elastic.refresh: unknown action",
+		},
+		{
+			name:     "conflicting key binding",
+			contents: "keymaps:\n  elastic.results:\n    compare: g\n",
+			expected: "keymaps.elastic.results.first_row (g g) conflicts with elastic.results.compare (g)",
+		},
+		{
+			name:     "unknown theme",
+			contents: "theme: neon\n",
+			expected: `theme: unknown theme "neon"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := writeConfig(t, tt.contents)
+			_, err := Load(path)
+			if assert.Error(t, err) {
+				assert.Contains(t, err.Error(), path)
+				assert.Contains(t, err.Error(), tt.expected)
+			}
+		})
+	}
+}
+
+func TestWatch(t *testing.T) {
+	path := writeConfig(t, "regions:\n  default: us-east-1\n")
+
+	changes := make(chan *Config, 10)
+	errs := make(chan error, 10)
+	w, err := Watch(path, func(cfg *Config, err error) {
+		if err != nil {
+			errs <- err
+			return
+		}
+		changes <- cfg
+	})
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer w.Close()
+
+	assert.NoError(t, os.WriteFile(path, []byte("regions:\n  default: eu-west-1\n"), 0o600))
+	select {
+	case cfg := <-changes:
+		assert.Equal(t, "eu-west-1", cfg.Regions.Default)
+	case err := <-errs:
+		t.Fatalf("unexpected error: %v", err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("config was not reloaded")
+	}
+
+	assert.NoError(t, os.WriteFile(path, []byte("regions:\n  default: mars-1\n"), 0o600))
+	select {
+	case err := <-errs:
+		assert.Contains(t, err.Error(), "regions.default")
+	case <-changes:
+		t.Fatal("an invalid config should not load")
+	case <-time.After(5 * time.Second):
+		t.Fatal("config was not reloaded")
+	}
+}
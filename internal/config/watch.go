@@ -0,0 +1,67 @@
+package config
+
+import (
+	"path/filepath"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// reloadDelay lets an editor finish writing, which is often several events, before reloading.
+const reloadDelay = 200 * time.Millisecond
+
+// Watcher reloads a config file whenever it changes.
+type Watcher struct {
+	watcher *fsnotify.Watcher
+	done    chan struct{}
+}
+
+// Watch calls onChange with the reloaded configuration, or the error that kept it from loading,
+// each time path is written, replaced or removed. The directory is watched rather than the file so
+// editors that save by renaming, and a file created after startup, are both seen.
+func Watch(path string, onChange func(*Config, error)) (*Watcher, error) {
+	fsw, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+	if err := fsw.Add(filepath.Dir(path)); err != nil {
+		fsw.Close()
+		return nil, err
+	}
+
+	w := &Watcher{watcher: fsw, done: make(chan struct{})}
+	go w.run(path, onChange)
+	return w, nil
+}
+
+func (w *Watcher) run(path string, onChange func(*Config, error)) {
+	defer close(w.done)
+
+	var reload <-chan time.Time
+	for {
+		select {
+		case event, ok := <-w.watcher.Events:
+			if !ok {
+				return
+			}
+			if filepath.Clean(event.Name) == filepath.Clean(path) && !event.Has(fsnotify.Chmod) {
+				reload = time.After(reloadDelay)
+			}
+		case err, ok := <-w.watcher.Errors:
+			if !ok {
+				return
+			}
+			onChange(nil, err)
+		case <-reload:
+			reload = nil
+			onChange(Load(path))
+		}
+	}
+}
+
+// Close stops watching and waits for a reload in progress to finish.
+func (w *Watcher) Close() error {
+	err := w.watcher.Close()
+	<-w.done
+	return err
+}
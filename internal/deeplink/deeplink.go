@@ -0,0 +1,114 @@
+// Package deeplink encodes where CloudCutter opens — a profile, region, view and the view's query —
+// as a cloudcutter:// URI that can be shared and passed back on the command line.
+package deeplink
+
+import (
+	"fmt"
+	"net/url"
+)
+
+// Scheme is the URI scheme of deep links.
+const Scheme = "cloudcutter"
+
+// Views a link can open.
+const (
+	ViewElastic  = "elastic"
+	ViewDynamoDB = "dynamodb"
+)
+
+// Link is the state a deep link or the startup flags open. Empty fields keep their defaults.
+type Link struct {
+	Profile   string
+	Region    string
+	View      string
+	Index     string   // elastic
+	Filters   []string // elastic
+	Timeframe string   // elastic
+	Table     string   // dynamodb
+}
+
+// Parse reads a link such as
+//
+//	cloudcutter://elastic?profile=opal_dev&region=us-west-2&index=main-summary-*&filter=level%3Derror&timeframe=1h
+//	cloudcutter://dynamodb?profile=opal_dev&table=users
+func Parse(raw string) (Link, error) {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return Link{}, fmt.Errorf("invalid link %q: %w", raw, err)
+	}
+	if u.Scheme != Scheme {
+		return Link{}, fmt.Errorf("invalid link %q: scheme must be %s://", raw, Scheme)
+	}
+
+	query, err := url.ParseQuery(u.RawQuery)
+	if err != nil {
+		return Link{}, fmt.Errorf("invalid link %q: %w", raw, err)
+	}
+	link := Link{
+		Profile:   query.Get("profile"),
+		Region:    query.Get("region"),
+		View:      u.Host,
+		Index:     query.Get("index"),
+		Filters:   query["filter"],
+		Timeframe: query.Get("timeframe"),
+		Table:     query.Get("table"),
+	}
+	if link.View == "" {
+		link.View = query.Get("view")
+	}
+	return link, link.Validate()
+}
+
+// Validate checks the view is known and only carries state that view understands.
+func (l Link) Validate() error {
+	switch l.View {
+	case "":
+		if l.Index != "" || len(l.Filters) > 0 || l.Timeframe != "" || l.Table != "" {
+			return fmt.Errorf("a view is needed to open an index, table, filter or timeframe")
+		}
+	case ViewElastic:
+		if l.Table != "" {
+			return fmt.Errorf("table only applies to the %s view", ViewDynamoDB)
+		}
+	case ViewDynamoDB:
+		if l.Index != "" || len(l.Filters) > 0 || l.Timeframe != "" {
+			return fmt.Errorf("index, filter and timeframe only apply to the %s view", ViewElastic)
+		}
+	default:
+		return fmt.Errorf("unknown view %q (use %s or %s)", l.View, ViewElastic, ViewDynamoDB)
+	}
+	return nil
+}
+
+// IsZero reports whether the link opens nothing in particular.
+func (l Link) IsZero() bool {
+	return l.Profile == "" && l.Region == "" && l.View == ""
+}
+
+// String encodes the link as a cloudcutter:// URI that Parse reads back.
+func (l Link) String() string {
+	query := url.Values{}
+	set := func(key, value string) {
+		if value != "" {
+			query.Set(key, value)
+		}
+	}
+	set("profile", l.Profile)
+	set("region", l.Region)
+	set("index", l.Index)
+	set("timeframe", l.Timeframe)
+	set("table", l.Table)
+	for _, filter := range l.Filters {
+		query.Add("filter", filter)
+	}
+
+	if l.View == "" {
+		// url.URL drops the // when there is no host
+		if len(query) == 0 {
+			return Scheme + "://"
+		}
+		return Scheme + "://?" + query.Encode()
+	}
+	u := url.URL{Scheme: Scheme, Host: l.View, RawQuery: query.Encode()}
+	return u.String()
+}
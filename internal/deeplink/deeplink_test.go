@@ -0,0 +1,54 @@
+package deeplink
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	link, err := Parse("cloudcutter://elastic?profile=opal_dev&region=eu-west-1&index=main-summary-*&filter=level%3Derror&filter=host%3Dweb-1&timeframe=1h")
+	assert.NoError(t, err)
+	assert.Equal(t, Link{
+		Profile:   "opal_dev",
+		Region:    "eu-west-1",
+		View:      ViewElastic,
+		Index:     "main-summary-*",
+		Filters:   []string{"level=error", "host=web-1"},
+		Timeframe: "1h",
+	}, link)
+
+	link, err = Parse("cloudcutter://?view=dynamodb&table=users")
+	assert.NoError(t, err)
+	assert.Equal(t, Link{View: ViewDynamoDB, Table: "users"}, link)
+
+	for _, raw := range []string{
+		"https://elastic?index=logs",
+		"cloudcutter://s3?profile=dev",
+		"cloudcutter://dynamodb?index=logs",
+		"cloudcutter://elastic?table=users",
+		"cloudcutter://?index=logs",
+		"cloudcutter://elastic?%zz",
+	} {
+		_, err := Parse(raw)
+		assert.Error(t, err, raw)
+	}
+}
+
+func TestStringRoundTrip(t *testing.T) {
+	links := []Link{
+		{Profile: "opal_prod", Region: "us-west-2", View: ViewElastic, Index: "logs-*", Filters: []string{"status>=500", "msg=a & b"}, Timeframe: "today"},
+		{Profile: "dev", View: ViewDynamoDB, Table: "orders"},
+		{Profile: "dev", Region: "local"},
+		{},
+	}
+
+	for _, link := range links {
+		parsed, err := Parse(link.String())
+		assert.NoError(t, err, link.String())
+		assert.Equal(t, link, parsed, link.String())
+	}
+
+	assert.Equal(t, "cloudcutter://dynamodb?profile=dev&table=orders", links[1].String())
+	assert.Equal(t, "cloudcutter://", Link{}.String())
+}
@@ -0,0 +1,181 @@
+// Package diagnostics keeps what the diagnostics panel shows about failures: how often each error
+// code has been handled, and the last service requests that failed, with their payloads.
+package diagnostics
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+// MaxFailures is how many failed requests are kept.
+const MaxFailures = 50
+
+// maxPayload caps the request and response bodies kept for a failed request.
+const maxPayload = 8 << 10
+
+// FailedRequest is a service request that returned an error or an error status.
+type FailedRequest struct {
+	Time     time.Time
+	Service  string // e.g. "elasticsearch" or "dynamodb"
+	Method   string
+	URL      string
+	Status   int // 0 when there was no response
+	Request  string
+	Response string
+	Error    string
+}
+
+// Recorder counts handled errors by code and keeps the last failed requests.
+type Recorder struct {
+	mu       sync.Mutex
+	errors   map[string]int64
+	failures []FailedRequest // oldest first
+	keep     int
+}
+
+// NewRecorder returns a recorder keeping the last keep failed requests.
+func NewRecorder(keep int) *Recorder {
+	return &Recorder{errors: make(map[string]int64), keep: max(keep, 1)}
+}
+
+// RecordError counts an error handled with code.
+func (r *Recorder) RecordError(code string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.errors[code]++
+}
+
+// ErrorCount is how many errors of one code were handled.
+type ErrorCount struct {
+	Code  string
+	Count int64
+}
+
+// ErrorCounts returns the handled errors by code, most frequent first.
+func (r *Recorder) ErrorCounts() []ErrorCount {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	counts := make([]ErrorCount, 0, len(r.errors))
+	for code, count := range r.errors {
+		counts = append(counts, ErrorCount{Code: code, Count: count})
+	}
+	sort.Slice(counts, func(i, j int) bool {
+		if counts[i].Count != counts[j].Count {
+			return counts[i].Count > counts[j].Count
+		}
+		return counts[i].Code < counts[j].Code
+	})
+	return counts
+}
+
+// RecordFailure keeps a failed request, dropping the oldest beyond the limit.
+func (r *Recorder) RecordFailure(failure FailedRequest) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.failures = append(r.failures, failure)
+	if len(r.failures) > r.keep {
+		r.failures = r.failures[len(r.failures)-r.keep:]
+	}
+}
+
+// Failures returns the kept failed requests, newest first.
+func (r *Recorder) Failures() []FailedRequest {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	failures := make([]FailedRequest, len(r.failures))
+	for i, failure := range r.failures {
+		failures[len(failures)-1-i] = failure
+	}
+	return failures
+}
+
+// Default is the recorder the services and error handlers report to.
+var Default = NewRecorder(MaxFailures)
+
+// RecordError counts an error handled with code in the default recorder.
+func RecordError(code string) {
+	Default.RecordError(code)
+}
+
+// HTTPClient is the client interface the AWS SDK sends requests with.
+type HTTPClient interface {
+	Do(*http.Request) (*http.Response, error)
+}
+
+// WrapClient records the requests client sends for service that fail.
+func WrapClient(service string, client HTTPClient) HTTPClient {
+	return &recordingClient{service: service, send: client.Do}
+}
+
+// WrapTransport records the requests transport sends for service that fail. A nil transport is
+// http.DefaultTransport.
+func WrapTransport(service string, transport http.RoundTripper) http.RoundTripper {
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	return &recordingClient{service: service, send: transport.RoundTrip}
+}
+
+type recordingClient struct {
+	service string
+	send    func(*http.Request) (*http.Response, error)
+}
+
+func (c *recordingClient) Do(req *http.Request) (*http.Response, error) {
+	return c.RoundTrip(req)
+}
+
+func (c *recordingClient) RoundTrip(req *http.Request) (*http.Response, error) {
+	var payload []byte
+	if req.Body != nil && req.Body != http.NoBody {
+		body, err := io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		payload = body
+		req.Body = io.NopCloser(bytes.NewReader(body))
+	}
+
+	res, err := c.send(req)
+	if err == nil && res.StatusCode < http.StatusBadRequest {
+		return res, nil
+	}
+
+	failure := FailedRequest{
+		Time:    time.Now(),
+		Service: c.service,
+		Method:  req.Method,
+		URL:     req.URL.Redacted(),
+		Request: truncate(payload),
+	}
+	if err != nil {
+		failure.Error = err.Error()
+	} else {
+		failure.Status = res.StatusCode
+		body, readErr := io.ReadAll(res.Body)
+		res.Body.Close()
+		res.Body = io.NopCloser(bytes.NewReader(body))
+		failure.Response = truncate(body)
+		if readErr != nil {
+			failure.Error = readErr.Error()
+		}
+	}
+	Default.RecordFailure(failure)
+	return res, err
+}
+
+func truncate(payload []byte) string {
+	if len(payload) <= maxPayload {
+		return string(payload)
+	}
+	return fmt.Sprintf("%s… (%d bytes)", payload[:maxPayload], len(payload))
+}
@@ -0,0 +1,75 @@
+package diagnostics
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecorder(t *testing.T) {
+	r := NewRecorder(2)
+	r.RecordError("TIMEOUT")
+	r.RecordError("NETWORK_FAILURE")
+	r.RecordError("TIMEOUT")
+	assert.Equal(t, []ErrorCount{{"TIMEOUT", 2}, {"NETWORK_FAILURE", 1}}, r.ErrorCounts())
+
+	for _, url := range []string{"/a", "/b", "/c"} {
+		r.RecordFailure(FailedRequest{URL: url})
+	}
+	failures := r.Failures()
+	if assert.Len(t, failures, 2, "only the last requests are kept") {
+		assert.Equal(t, "/c", failures[0].URL, "newest first")
+		assert.Equal(t, "/b", failures[1].URL)
+	}
+}
+
+func TestWrapTransport(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if r.URL.Path == "/ok" {
+			w.Write(body)
+			return
+		}
+		w.WriteHeader(http.StatusBadRequest)
+		io.WriteString(w, `{"error":"parsing_exception"}`)
+	}))
+	defer server.Close()
+
+	saved := Default
+	Default = NewRecorder(MaxFailures)
+	t.Cleanup(func() { Default = saved })
+
+	client := &http.Client{Transport: WrapTransport("elasticsearch", nil)}
+	res, err := client.Post(server.URL+"/ok", "application/json", strings.NewReader(`{"size":1}`))
+	if assert.NoError(t, err) {
+		body, _ := io.ReadAll(res.Body)
+		assert.Equal(t, `{"size":1}`, string(body), "the request body still reaches the server")
+	}
+	assert.Empty(t, Default.Failures(), "successful requests are not kept")
+
+	res, err = client.Post(server.URL+"/logs-*/_search", "application/json", strings.NewReader(`{"query":{}}`))
+	if assert.NoError(t, err) {
+		body, _ := io.ReadAll(res.Body)
+		assert.Equal(t, `{"error":"parsing_exception"}`, string(body), "the caller still reads the response")
+	}
+	failures := Default.Failures()
+	if assert.Len(t, failures, 1) {
+		assert.Equal(t, "elasticsearch", failures[0].Service)
+		assert.Equal(t, http.MethodPost, failures[0].Method)
+		assert.Equal(t, http.StatusBadRequest, failures[0].Status)
+		assert.Equal(t, `{"query":{}}`, failures[0].Request)
+		assert.Equal(t, `{"error":"parsing_exception"}`, failures[0].Response)
+	}
+
+	req, _ := http.NewRequest(http.MethodPost, "http://127.0.0.1:1/", strings.NewReader("{}"))
+	_, err = WrapClient("dynamodb", http.DefaultClient).Do(req)
+	assert.Error(t, err)
+	if failures := Default.Failures(); assert.Len(t, failures, 2) {
+		assert.Equal(t, "dynamodb", failures[0].Service)
+		assert.NotEmpty(t, failures[0].Error, "a request without a response keeps its error")
+	}
+}
@@ -0,0 +1,89 @@
+package logger
+
+import (
+	"bytes"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// entryTimeFormat is how an entry header writes its time.
+const entryTimeFormat = "2006-01-02 15:04:05.000"
+
+// entryFooter closes every entry.
+const entryFooter = "===================="
+
+var entryHeader = regexp.MustCompile(`^=== \[([A-Z0-9+-]+)\] (\d{4}-\d{2}-\d{2} \d{2}:\d{2}:\d{2}\.\d{3}) ===$`)
+
+// Entry is one entry of a log file, as read back for the log viewer.
+type Entry struct {
+	Time    time.Time
+	Level   Level
+	Message string // the message and its arguments, as written
+	Source  string // the prefix of the file it came from, e.g. "cloudcutter" or "es_svc"
+}
+
+// Summary is the first line of the entry's message.
+func (e Entry) Summary() string {
+	summary, _, _ := strings.Cut(e.Message, "\n")
+	return summary
+}
+
+// ParseEntries reads the complete entries in data, tagging them with source. It returns how many
+// bytes they took, so a reader tailing a file can resume after them once the rest is written.
+// Lines outside an entry are skipped.
+func ParseEntries(data []byte, source string) ([]Entry, int) {
+	var (
+		entries  []Entry
+		current  *Entry
+		body     []string
+		consumed int
+	)
+	for offset := 0; offset < len(data); {
+		end := bytes.IndexByte(data[offset:], '\n')
+		if end < 0 {
+			break // the last line is still being written
+		}
+		line := strings.TrimRight(string(data[offset:offset+end]), "\r")
+		offset += end + 1
+
+		switch {
+		case current == nil:
+			if match := entryHeader.FindStringSubmatch(line); match != nil {
+				level, err := ParseLevel(match[1])
+				if err != nil {
+					level = INFO
+				}
+				at, _ := time.ParseInLocation(entryTimeFormat, match[2], time.Local)
+				current, body = &Entry{Time: at, Level: level, Source: source}, nil
+			} else {
+				consumed = offset
+			}
+		case line == entryFooter:
+			current.Message = strings.Join(body, "\n")
+			entries = append(entries, *current)
+			current, consumed = nil, offset
+		default:
+			body = append(body, line)
+		}
+	}
+	return entries, consumed
+}
+
+// CurrentFiles lists the files loggers writing to dir use today, one per prefix.
+func CurrentFiles(dir string) ([]string, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*_"+time.Now().Format("2006-01-02")+".log"))
+	sort.Strings(files)
+	return files, err
+}
+
+// FileSource is the prefix a log file was written under, e.g. "es_svc" for es_svc_2025-01-02.log.
+func FileSource(path string) string {
+	name := strings.TrimSuffix(filepath.Base(path), ".log")
+	if i := strings.LastIndex(name, "_"); i > 0 {
+		return name[:i]
+	}
+	return name
+}
@@ -194,6 +194,11 @@ func (l *Logger) Log(level Level, message string, args ...any) {
 	}
 }
 
+// Dir returns the directory the log files are written to.
+func (l *Logger) Dir() string {
+	return l.logDir
+}
+
 func (l *Logger) Debug(message string, args ...any) {
 	l.Log(DEBUG, message, args...)
 }
@@ -101,3 +101,46 @@ func TestLogger(t *testing.T) {
 func contains(content []byte, substr string) bool {
 	return strings.Contains(string(content), substr)
 }
+
+func TestParseEntries(t *testing.T) {
+	dir := t.TempDir()
+	l, err := logger.New(logger.Config{LogDir: dir, Prefix: "es_svc", Level: logger.DEBUG})
+	if err != nil {
+		t.Fatal(err)
+	}
+	l.Warn("Search failed", "index", "logs-*")
+	l.Close()
+
+	files, err := logger.CurrentFiles(dir)
+	if err != nil || len(files) != 1 {
+		t.Fatalf("Expected today's file, got %v (%v)", files, err)
+	}
+	if source := logger.FileSource(files[0]); source != "es_svc" {
+		t.Fatalf("Expected source es_svc, got %q", source)
+	}
+	content, err := os.ReadFile(files[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A partly written entry is left for the next read
+	partial := append(content, []byte("=== [ERROR] 2025-01-02 15:04:05.000 ===\nhalf")...)
+	entries, consumed := logger.ParseEntries(partial, "es_svc")
+	if consumed != len(content) {
+		t.Fatalf("Expected %d bytes consumed, got %d", len(content), consumed)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("Expected the init entry and the warning, got %d entries", len(entries))
+	}
+
+	warning := entries[1]
+	if warning.Level != logger.WARN || warning.Source != "es_svc" || warning.Summary() != "Search failed" {
+		t.Fatalf("Unexpected entry %+v", warning)
+	}
+	if !strings.Contains(warning.Message, `"index": "logs-*"`) {
+		t.Fatalf("Expected the arguments in the message, got %q", warning.Message)
+	}
+	if time.Since(warning.Time) > time.Minute {
+		t.Fatalf("Expected the entry's time to be now, got %v", warning.Time)
+	}
+}
@@ -28,20 +28,80 @@ func Authenticate(profile, region string) (awssdk.Config, error) {
 }
 
 func validateCredentials(cfg awssdk.Config) error {
-	stsClient := sts.NewFromConfig(cfg)
-	_, err := stsClient.GetCallerIdentity(context.TODO(), &sts.GetCallerIdentityInput{})
+	_, err := CallerIdentity(context.TODO(), cfg)
+	return err
+}
+
+// Identity is the effective principal a configuration authenticates as.
+type Identity struct {
+	Account string
+	ARN     string
+	UserID  string
+}
+
+type callerIdentityAPI interface {
+	GetCallerIdentity(ctx context.Context, params *sts.GetCallerIdentityInput, optFns ...func(*sts.Options)) (*sts.GetCallerIdentityOutput, error)
+}
+
+// CallerIdentity resolves cfg's credentials and returns who they belong to.
+func CallerIdentity(ctx context.Context, cfg awssdk.Config) (*Identity, error) {
+	return callerIdentity(ctx, sts.NewFromConfig(cfg))
+}
+
+func callerIdentity(ctx context.Context, client callerIdentityAPI) (*Identity, error) {
+	out, err := client.GetCallerIdentity(ctx, &sts.GetCallerIdentityInput{})
 	if err != nil {
-		return handleAuthError(err)
+		return nil, handleAuthError(err)
+	}
+	return &Identity{
+		Account: awssdk.ToString(out.Account),
+		ARN:     awssdk.ToString(out.Arn),
+		UserID:  awssdk.ToString(out.UserId),
+	}, nil
+}
+
+// expiredTokenCodes are the error codes services return once temporary credentials have expired.
+var expiredTokenCodes = map[string]bool{
+	"ExpiredToken":          true,
+	"ExpiredTokenException": true,
+}
+
+// IsExpiredTokenError reports whether err is an AWS API error caused by expired credentials.
+func IsExpiredTokenError(err error) bool {
+	var apiErr smithy.APIError
+	return errors.As(err, &apiErr) && expiredTokenCodes[apiErr.ErrorCode()]
+}
+
+// CredentialsRefresher is implemented by credential providers that can re-authenticate on demand.
+type CredentialsRefresher interface {
+	RefreshCredentials(ctx context.Context) error
+}
+
+// RetryOnExpiredToken runs op, and if it fails because creds have expired and creds can be
+// refreshed, refreshes them and runs op once more.
+func RetryOnExpiredToken(ctx context.Context, creds awssdk.CredentialsProvider, op func() error) error {
+	err := op()
+	if err == nil || !IsExpiredTokenError(err) {
+		return err
 	}
-	return nil
+
+	refresher, ok := creds.(CredentialsRefresher)
+	if !ok {
+		return err
+	}
+	if refreshErr := refresher.RefreshCredentials(ctx); refreshErr != nil {
+		return fmt.Errorf("%w (refreshing credentials failed: %v)", err, refreshErr)
+	}
+	return op()
 }
 
 func handleAuthError(err error) error {
 	var apiErr smithy.APIError
 	if errors.As(err, &apiErr) {
-		switch apiErr.ErrorCode() {
-		case "ExpiredToken":
+		if expiredTokenCodes[apiErr.ErrorCode()] {
 			return fmt.Errorf("authentication failed: your AWS token has expired, please refresh it")
+		}
+		switch apiErr.ErrorCode() {
 		case "InvalidClientTokenId", "UnrecognizedClientException", "AccessDeniedException":
 			return fmt.Errorf("authentication failed: invalid AWS credentials or insufficient permissions")
 		default:
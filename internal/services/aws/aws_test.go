@@ -3,6 +3,7 @@ package aws
 import (
 	"context"
 	"errors"
+	"fmt"
 	"testing"
 
 	awssdk "github.com/aws/aws-sdk-go-v2/aws"
@@ -58,6 +59,11 @@ func TestHandleAuthError(t *testing.T) {
 			inputError:  MockAPIError{Code: "ExpiredToken", Message: "Token expired"},
 			expectedMsg: "authentication failed: your AWS token has expired, please refresh it",
 		},
+		{
+			name:        "ExpiredTokenException error",
+			inputError:  MockAPIError{Code: "ExpiredTokenException", Message: "Token expired"},
+			expectedMsg: "authentication failed: your AWS token has expired, please refresh it",
+		},
 		{
 			name:        "InvalidClientTokenId error",
 			inputError:  MockAPIError{Code: "InvalidClientTokenId", Message: "Invalid token"},
@@ -289,6 +295,35 @@ func TestAuthenticationErrorCategories(t *testing.T) {
 	})
 }
 
+func TestCallerIdentity(t *testing.T) {
+	t.Run("returns the effective identity", func(t *testing.T) {
+		client := new(MockSTSClient)
+		client.On("GetCallerIdentity", mock.Anything, mock.Anything).Return(&sts.GetCallerIdentityOutput{
+			Account: awssdk.String("123456789012"),
+			Arn:     awssdk.String("arn:aws:sts::123456789012:assumed-role/Admin/cloudcutter"),
+			UserId:  awssdk.String("AROACKCEVSQ6C2EXAMPLE:cloudcutter"),
+		}, nil)
+
+		identity, err := callerIdentity(context.Background(), client)
+		assert.NoError(t, err)
+		assert.Equal(t, &Identity{
+			Account: "123456789012",
+			ARN:     "arn:aws:sts::123456789012:assumed-role/Admin/cloudcutter",
+			UserID:  "AROACKCEVSQ6C2EXAMPLE:cloudcutter",
+		}, identity)
+		client.AssertExpectations(t)
+	})
+
+	t.Run("maps authentication errors", func(t *testing.T) {
+		client := new(MockSTSClient)
+		client.On("GetCallerIdentity", mock.Anything, mock.Anything).Return(nil, MockAPIError{Code: "ExpiredToken", Message: "Token expired"})
+
+		identity, err := callerIdentity(context.Background(), client)
+		assert.Nil(t, identity)
+		assert.EqualError(t, err, "authentication failed: your AWS token has expired, please refresh it")
+	})
+}
+
 // Integration test placeholder - would require actual AWS credentials
 func TestAuthenticateIntegration(t *testing.T) {
 	t.Skip("Integration test - requires AWS credentials and should be run separately")
@@ -307,4 +342,71 @@ func BenchmarkHandleAuthError(b *testing.B) {
 	for i := 0; i < b.N; i++ {
 		_ = handleAuthError(err)
 	}
-}
\ No newline at end of file
+}
+// refreshingProvider counts refreshes requested after an expired token.
+type refreshingProvider struct {
+	awssdk.AnonymousCredentials
+	refreshes int
+	err       error
+}
+
+func (p *refreshingProvider) RefreshCredentials(context.Context) error {
+	p.refreshes++
+	return p.err
+}
+
+func TestIsExpiredTokenError(t *testing.T) {
+	assert.True(t, IsExpiredTokenError(MockAPIError{Code: "ExpiredToken"}))
+	assert.True(t, IsExpiredTokenError(fmt.Errorf("scan failed: %w", MockAPIError{Code: "ExpiredTokenException"})))
+	assert.False(t, IsExpiredTokenError(MockAPIError{Code: "AccessDeniedException"}))
+	assert.False(t, IsExpiredTokenError(errors.New("network timeout")))
+}
+
+func TestRetryOnExpiredToken(t *testing.T) {
+	expired := MockAPIError{Code: "ExpiredTokenException", Message: "The security token included in the request is expired"}
+
+	t.Run("retries once after refreshing", func(t *testing.T) {
+		provider := &refreshingProvider{}
+		calls := 0
+		err := RetryOnExpiredToken(context.Background(), provider, func() error {
+			calls++
+			if calls == 1 {
+				return expired
+			}
+			return nil
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, 2, calls)
+		assert.Equal(t, 1, provider.refreshes)
+	})
+
+	t.Run("other errors are returned as is", func(t *testing.T) {
+		provider := &refreshingProvider{}
+		denied := MockAPIError{Code: "AccessDeniedException"}
+		err := RetryOnExpiredToken(context.Background(), provider, func() error { return denied })
+		assert.Equal(t, denied, err)
+		assert.Equal(t, 0, provider.refreshes)
+	})
+
+	t.Run("failed refresh keeps the original error", func(t *testing.T) {
+		provider := &refreshingProvider{err: errors.New("MFA prompt cancelled")}
+		calls := 0
+		err := RetryOnExpiredToken(context.Background(), provider, func() error {
+			calls++
+			return expired
+		})
+		assert.True(t, IsExpiredTokenError(err))
+		assert.Contains(t, err.Error(), "MFA prompt cancelled")
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("providers that cannot refresh are not retried", func(t *testing.T) {
+		calls := 0
+		err := RetryOnExpiredToken(context.Background(), awssdk.AnonymousCredentials{}, func() error {
+			calls++
+			return expired
+		})
+		assert.Equal(t, expired, err)
+		assert.Equal(t, 1, calls)
+	})
+}
@@ -0,0 +1,851 @@
+// Package ddbfake is an in-memory DynamoDB for tests and demo mode. Its Client implements the
+// calls the dynamodb service makes, and the writes that seed it, the way DynamoDB answers them:
+// key schemas, secondary indexes, pagination with LastEvaluatedKey, key conditions, filter,
+// condition and update expressions, and DynamoDB's errors.
+//
+// Tables are seeded with CreateTable and PutItem, from JSON fixtures with LoadFixtures, or with
+// generated data with LoadDemo. A client is safe for concurrent use.
+package ddbfake
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"maps"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsdynamodb "github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/aws/smithy-go"
+	"github.com/tpelletiersophos/cloudcutter/internal/services/aws/dynamodb"
+)
+
+// maxPageBytes is the size of the items a Scan or Query reads before it stops and returns a page.
+const maxPageBytes = 1 << 20
+
+// Client is an in-memory DynamoDB.
+type Client struct {
+	mu     sync.Mutex
+	tables map[string]*table
+}
+
+var _ dynamodb.Client = (*Client)(nil)
+
+type table struct {
+	desc  types.TableDescription
+	items map[string]item // by primary key
+}
+
+// keySchema names the partition key and the sort key, which may be empty, of a table or index.
+type keySchema struct {
+	hash, rng string
+}
+
+func schemaOf(elements []types.KeySchemaElement) keySchema {
+	var schema keySchema
+	for _, e := range elements {
+		if e.KeyType == types.KeyTypeHash {
+			schema.hash = aws.ToString(e.AttributeName)
+		} else {
+			schema.rng = aws.ToString(e.AttributeName)
+		}
+	}
+	return schema
+}
+
+func (k keySchema) attributes() []string {
+	if k.rng == "" {
+		return []string{k.hash}
+	}
+	return []string{k.hash, k.rng}
+}
+
+// New returns a client without tables.
+func New() *Client {
+	return &Client{tables: map[string]*table{}}
+}
+
+func validation(format string, args ...any) error {
+	return &smithy.GenericAPIError{Code: "ValidationException", Message: fmt.Sprintf(format, args...), Fault: smithy.FaultClient}
+}
+
+func (c *Client) table(name *string) (*table, error) {
+	t, ok := c.tables[aws.ToString(name)]
+	if !ok {
+		return nil, &types.ResourceNotFoundException{Message: aws.String("Requested resource not found")}
+	}
+	return t, nil
+}
+
+func (t *table) schema() keySchema { return schemaOf(t.desc.KeySchema) }
+
+func (t *table) attributeType(name string) string {
+	for _, def := range t.desc.AttributeDefinitions {
+		if aws.ToString(def.AttributeName) == name {
+			return string(def.AttributeType)
+		}
+	}
+	return ""
+}
+
+// keyString identifies an item by its primary key.
+func (t *table) keyString(it item) string {
+	var b strings.Builder
+	for _, name := range t.schema().attributes() {
+		switch v := it[name].(type) {
+		case *types.AttributeValueMemberS:
+			b.WriteString("S:" + v.Value)
+		case *types.AttributeValueMemberN:
+			n, _ := number(v.Value)
+			b.WriteString("N:" + formatNumber(n))
+		case *types.AttributeValueMemberB:
+			b.WriteString("B:" + base64.StdEncoding.EncodeToString(v.Value))
+		}
+		b.WriteByte(0)
+	}
+	return b.String()
+}
+
+// checkKey fails unless key has exactly the primary key attributes, of their types.
+func (t *table) checkKey(key item) error {
+	names := t.schema().attributes()
+	if len(key) != len(names) {
+		return validation("The provided key element does not match the schema")
+	}
+	for _, name := range names {
+		if v, ok := key[name]; !ok || typeOf(v) != t.attributeType(name) {
+			return validation("The provided key element does not match the schema")
+		}
+	}
+	return nil
+}
+
+// checkItem fails unless it has the primary key attributes, and every key attribute of the table
+// and its indexes it has is of its defined type and not empty.
+func (t *table) checkItem(it item) error {
+	for _, name := range t.schema().attributes() {
+		if _, ok := it[name]; !ok {
+			return validation("One or more parameter values were invalid: Missing the key %s in the item", name)
+		}
+	}
+	for _, def := range t.desc.AttributeDefinitions {
+		name := aws.ToString(def.AttributeName)
+		v, ok := it[name]
+		if !ok {
+			continue
+		}
+		if typeOf(v) != string(def.AttributeType) {
+			return validation("One or more parameter values were invalid: Type mismatch for key %s expected: %s actual: %s", name, def.AttributeType, typeOf(v))
+		}
+		if valueSize(v) == 0 {
+			return validation("One or more parameter values are not valid. The AttributeValue for a key attribute cannot contain an empty string value. Key: %s", name)
+		}
+	}
+	return nil
+}
+
+// CreateTable creates an active table with its secondary indexes.
+func (c *Client) CreateTable(ctx context.Context, params *awsdynamodb.CreateTableInput, _ ...func(*awsdynamodb.Options)) (*awsdynamodb.CreateTableOutput, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	name := aws.ToString(params.TableName)
+	if name == "" {
+		return nil, validation("TableName must not be empty")
+	}
+	if _, ok := c.tables[name]; ok {
+		return nil, &types.ResourceInUseException{Message: aws.String("Table already exists: " + name)}
+	}
+
+	defined := map[string]bool{}
+	for _, def := range params.AttributeDefinitions {
+		switch def.AttributeType {
+		case types.ScalarAttributeTypeS, types.ScalarAttributeTypeN, types.ScalarAttributeTypeB:
+		default:
+			return nil, validation("1 validation error detected: Value '%s' at 'attributeDefinitions.attributeType' failed to satisfy constraint: Member must satisfy enum value set: [B, N, S]", def.AttributeType)
+		}
+		defined[aws.ToString(def.AttributeName)] = true
+	}
+	used := map[string]bool{}
+	checkSchema := func(elements []types.KeySchemaElement) error {
+		if len(elements) == 0 || len(elements) > 2 || elements[0].KeyType != types.KeyTypeHash ||
+			len(elements) == 2 && elements[1].KeyType != types.KeyTypeRange {
+			return validation("Invalid KeySchema: The first KeySchemaElement is not a HASH key type")
+		}
+		for _, e := range elements {
+			attr := aws.ToString(e.AttributeName)
+			if !defined[attr] {
+				return validation("One or more parameter values were invalid: Some index key attributes are not defined in AttributeDefinitions. Keys: [%s]", attr)
+			}
+			used[attr] = true
+		}
+		return nil
+	}
+	if err := checkSchema(params.KeySchema); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	arn := "arn:aws:dynamodb:local:000000000000:table/" + name
+	desc := types.TableDescription{
+		TableName:                 aws.String(name),
+		TableArn:                  aws.String(arn),
+		TableId:                   aws.String(fmt.Sprintf("%08x-0000-0000-0000-000000000000", len(c.tables)+1)),
+		TableStatus:               types.TableStatusActive,
+		CreationDateTime:          &now,
+		KeySchema:                 slices.Clone(params.KeySchema),
+		AttributeDefinitions:      slices.Clone(params.AttributeDefinitions),
+		ItemCount:                 aws.Int64(0),
+		TableSizeBytes:            aws.Int64(0),
+		DeletionProtectionEnabled: params.DeletionProtectionEnabled,
+	}
+	if params.BillingMode != "" {
+		desc.BillingModeSummary = &types.BillingModeSummary{BillingMode: params.BillingMode}
+	}
+	if params.ProvisionedThroughput != nil {
+		desc.ProvisionedThroughput = &types.ProvisionedThroughputDescription{
+			ReadCapacityUnits:  params.ProvisionedThroughput.ReadCapacityUnits,
+			WriteCapacityUnits: params.ProvisionedThroughput.WriteCapacityUnits,
+		}
+	}
+
+	indexNames := map[string]bool{}
+	checkIndex := func(name *string, elements []types.KeySchemaElement, projection *types.Projection) error {
+		if indexNames[aws.ToString(name)] || aws.ToString(name) == "" {
+			return validation("One or more parameter values were invalid: Duplicate index name: %s", aws.ToString(name))
+		}
+		indexNames[aws.ToString(name)] = true
+		if projection == nil {
+			return validation("One or more parameter values were invalid: Projection must be specified for index %s", aws.ToString(name))
+		}
+		return checkSchema(elements)
+	}
+	for _, gsi := range params.GlobalSecondaryIndexes {
+		if err := checkIndex(gsi.IndexName, gsi.KeySchema, gsi.Projection); err != nil {
+			return nil, err
+		}
+		desc.GlobalSecondaryIndexes = append(desc.GlobalSecondaryIndexes, types.GlobalSecondaryIndexDescription{
+			IndexName:   gsi.IndexName,
+			IndexArn:    aws.String(arn + "/index/" + aws.ToString(gsi.IndexName)),
+			IndexStatus: types.IndexStatusActive,
+			KeySchema:   slices.Clone(gsi.KeySchema),
+			Projection:  gsi.Projection,
+		})
+	}
+	for _, lsi := range params.LocalSecondaryIndexes {
+		if err := checkIndex(lsi.IndexName, lsi.KeySchema, lsi.Projection); err != nil {
+			return nil, err
+		}
+		if len(lsi.KeySchema) != 2 || schemaOf(lsi.KeySchema).hash != schemaOf(params.KeySchema).hash {
+			return nil, validation("One or more parameter values were invalid: Index KeySchema does not have the same leading hash key as table KeySchema for index: %s", aws.ToString(lsi.IndexName))
+		}
+		desc.LocalSecondaryIndexes = append(desc.LocalSecondaryIndexes, types.LocalSecondaryIndexDescription{
+			IndexName:  lsi.IndexName,
+			IndexArn:   aws.String(arn + "/index/" + aws.ToString(lsi.IndexName)),
+			KeySchema:  slices.Clone(lsi.KeySchema),
+			Projection: lsi.Projection,
+		})
+	}
+	for attr := range defined {
+		if !used[attr] {
+			return nil, validation("One or more parameter values were invalid: Some AttributeDefinitions are not used. AttributeDefinitions: [%s], keys used: [%s]", attr, strings.Join(slices.Sorted(maps.Keys(used)), ", "))
+		}
+	}
+
+	t := &table{desc: desc, items: map[string]item{}}
+	c.tables[name] = t
+	return &awsdynamodb.CreateTableOutput{TableDescription: t.describe()}, nil
+}
+
+// describe returns the description of the table with its current item counts and sizes.
+func (t *table) describe() *types.TableDescription {
+	desc := t.desc
+	var size int64
+	for _, it := range t.items {
+		size += itemSize(it)
+	}
+	desc.ItemCount = aws.Int64(int64(len(t.items)))
+	desc.TableSizeBytes = aws.Int64(size)
+
+	desc.GlobalSecondaryIndexes = slices.Clone(desc.GlobalSecondaryIndexes)
+	for i := range desc.GlobalSecondaryIndexes {
+		gsi := &desc.GlobalSecondaryIndexes[i]
+		items := t.indexItems(&index{name: aws.ToString(gsi.IndexName), schema: schemaOf(gsi.KeySchema), projection: gsi.Projection})
+		gsi.ItemCount, gsi.IndexSizeBytes = aws.Int64(int64(len(items))), aws.Int64(itemsSize(items))
+	}
+	desc.LocalSecondaryIndexes = slices.Clone(desc.LocalSecondaryIndexes)
+	for i := range desc.LocalSecondaryIndexes {
+		lsi := &desc.LocalSecondaryIndexes[i]
+		items := t.indexItems(&index{name: aws.ToString(lsi.IndexName), schema: schemaOf(lsi.KeySchema), projection: lsi.Projection})
+		lsi.ItemCount, lsi.IndexSizeBytes = aws.Int64(int64(len(items))), aws.Int64(itemsSize(items))
+	}
+	return &desc
+}
+
+func itemsSize(items []item) int64 {
+	var size int64
+	for _, it := range items {
+		size += itemSize(it)
+	}
+	return size
+}
+
+// DeleteTable deletes a table and its items.
+func (c *Client) DeleteTable(ctx context.Context, params *awsdynamodb.DeleteTableInput, _ ...func(*awsdynamodb.Options)) (*awsdynamodb.DeleteTableOutput, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	t, err := c.table(params.TableName)
+	if err != nil {
+		return nil, err
+	}
+	delete(c.tables, aws.ToString(params.TableName))
+	desc := t.describe()
+	desc.TableStatus = types.TableStatusDeleting
+	return &awsdynamodb.DeleteTableOutput{TableDescription: desc}, nil
+}
+
+// ListTables lists table names in order, up to Limit (100 by default) a page.
+func (c *Client) ListTables(ctx context.Context, params *awsdynamodb.ListTablesInput, _ ...func(*awsdynamodb.Options)) (*awsdynamodb.ListTablesOutput, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	limit := int(aws.ToInt32(params.Limit))
+	if params.Limit != nil && (limit < 1 || limit > 100) {
+		return nil, validation("1 validation error detected: Value '%d' at 'limit' failed to satisfy constraint: Member must have value between 1 and 100", limit)
+	}
+	if limit == 0 {
+		limit = 100
+	}
+
+	names := slices.Sorted(maps.Keys(c.tables))
+	start := aws.ToString(params.ExclusiveStartTableName)
+	names = slices.DeleteFunc(names, func(name string) bool { return name <= start })
+	output := &awsdynamodb.ListTablesOutput{TableNames: names}
+	if len(names) > limit {
+		output.TableNames = names[:limit]
+		output.LastEvaluatedTableName = aws.String(names[limit-1])
+	}
+	return output, nil
+}
+
+// DescribeTable describes a table, counting its items now rather than every six hours.
+func (c *Client) DescribeTable(ctx context.Context, params *awsdynamodb.DescribeTableInput, _ ...func(*awsdynamodb.Options)) (*awsdynamodb.DescribeTableOutput, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	t, err := c.table(params.TableName)
+	if err != nil {
+		return nil, err
+	}
+	return &awsdynamodb.DescribeTableOutput{Table: t.describe()}, nil
+}
+
+// GetItem reads the item with the given primary key.
+func (c *Client) GetItem(ctx context.Context, params *awsdynamodb.GetItemInput, _ ...func(*awsdynamodb.Options)) (*awsdynamodb.GetItemOutput, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	t, err := c.table(params.TableName)
+	if err != nil {
+		return nil, err
+	}
+	ph := newPlaceholders(params.ExpressionAttributeNames, nil)
+	var paths []path
+	if params.ProjectionExpression != nil {
+		if paths, err = parseProjection(*params.ProjectionExpression, ph); err != nil {
+			return nil, err
+		}
+	}
+	if err := ph.unused(); err != nil {
+		return nil, err
+	}
+	if err := t.checkKey(params.Key); err != nil {
+		return nil, err
+	}
+
+	it, ok := t.items[t.keyString(params.Key)]
+	if !ok {
+		return &awsdynamodb.GetItemOutput{}, nil
+	}
+	if paths != nil {
+		return &awsdynamodb.GetItemOutput{Item: project(it, paths)}, nil
+	}
+	return &awsdynamodb.GetItemOutput{Item: cloneItem(it)}, nil
+}
+
+// write is a checked change of one item: the placeholders and condition of the request.
+type write struct {
+	ph        *placeholders
+	condition condition
+}
+
+func newWrite(condition *string, names map[string]string, values map[string]types.AttributeValue) (*write, error) {
+	w := &write{ph: newPlaceholders(names, values)}
+	if condition != nil {
+		var err error
+		if w.condition, err = parseCondition("ConditionExpression", *condition, w.ph); err != nil {
+			return nil, err
+		}
+	}
+	return w, nil
+}
+
+// check fails with ConditionalCheckFailedException when the condition does not hold for old,
+// which is nil when there is no item, carrying old when returnOld is ALL_OLD.
+func (w *write) check(old item, returnOld types.ReturnValuesOnConditionCheckFailure) error {
+	if w.condition == nil || w.condition.test(old) {
+		return nil
+	}
+	err := &types.ConditionalCheckFailedException{Message: aws.String("The conditional request failed")}
+	if returnOld == types.ReturnValuesOnConditionCheckFailureAllOld && old != nil {
+		err.Item = cloneItem(old)
+	}
+	return err
+}
+
+// PutItem stores an item, replacing the item with its primary key.
+func (c *Client) PutItem(ctx context.Context, params *awsdynamodb.PutItemInput, _ ...func(*awsdynamodb.Options)) (*awsdynamodb.PutItemOutput, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	t, err := c.table(params.TableName)
+	if err != nil {
+		return nil, err
+	}
+	w, err := newWrite(params.ConditionExpression, params.ExpressionAttributeNames, params.ExpressionAttributeValues)
+	if err != nil {
+		return nil, err
+	}
+	if err := w.ph.unused(); err != nil {
+		return nil, err
+	}
+	if params.ReturnValues != "" && params.ReturnValues != types.ReturnValueNone && params.ReturnValues != types.ReturnValueAllOld {
+		return nil, validation("ReturnValues can only be ALL_OLD or NONE")
+	}
+	if err := t.checkItem(params.Item); err != nil {
+		return nil, err
+	}
+
+	key := t.keyString(params.Item)
+	old := t.items[key]
+	if err := w.check(old, params.ReturnValuesOnConditionCheckFailure); err != nil {
+		return nil, err
+	}
+	t.items[key] = cloneItem(params.Item)
+
+	output := &awsdynamodb.PutItemOutput{}
+	if params.ReturnValues == types.ReturnValueAllOld {
+		output.Attributes = cloneItem(old)
+	}
+	return output, nil
+}
+
+// DeleteItem deletes the item with the given primary key, if there is one.
+func (c *Client) DeleteItem(ctx context.Context, params *awsdynamodb.DeleteItemInput, _ ...func(*awsdynamodb.Options)) (*awsdynamodb.DeleteItemOutput, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	t, err := c.table(params.TableName)
+	if err != nil {
+		return nil, err
+	}
+	w, err := newWrite(params.ConditionExpression, params.ExpressionAttributeNames, params.ExpressionAttributeValues)
+	if err != nil {
+		return nil, err
+	}
+	if err := w.ph.unused(); err != nil {
+		return nil, err
+	}
+	if params.ReturnValues != "" && params.ReturnValues != types.ReturnValueNone && params.ReturnValues != types.ReturnValueAllOld {
+		return nil, validation("ReturnValues can only be ALL_OLD or NONE")
+	}
+	if err := t.checkKey(params.Key); err != nil {
+		return nil, err
+	}
+
+	key := t.keyString(params.Key)
+	old := t.items[key]
+	if err := w.check(old, params.ReturnValuesOnConditionCheckFailure); err != nil {
+		return nil, err
+	}
+	delete(t.items, key)
+
+	output := &awsdynamodb.DeleteItemOutput{}
+	if params.ReturnValues == types.ReturnValueAllOld {
+		output.Attributes = old
+	}
+	return output, nil
+}
+
+// UpdateItem changes the item with the given primary key by an update expression, creating it
+// when there is none.
+func (c *Client) UpdateItem(ctx context.Context, params *awsdynamodb.UpdateItemInput, _ ...func(*awsdynamodb.Options)) (*awsdynamodb.UpdateItemOutput, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	t, err := c.table(params.TableName)
+	if err != nil {
+		return nil, err
+	}
+	w, err := newWrite(params.ConditionExpression, params.ExpressionAttributeNames, params.ExpressionAttributeValues)
+	if err != nil {
+		return nil, err
+	}
+	var u *update
+	if params.UpdateExpression != nil {
+		if u, err = parseUpdate(*params.UpdateExpression, w.ph); err != nil {
+			return nil, err
+		}
+	}
+	if err := w.ph.unused(); err != nil {
+		return nil, err
+	}
+	if err := t.checkKey(params.Key); err != nil {
+		return nil, err
+	}
+	if u != nil {
+		for _, pth := range u.paths() {
+			if slices.Contains(t.schema().attributes(), pth[0].name) {
+				return nil, validation("One or more parameter values were invalid: Cannot update attribute %s. This attribute is part of the key", pth[0].name)
+			}
+		}
+	}
+
+	key := t.keyString(params.Key)
+	old := t.items[key]
+	if err := w.check(old, params.ReturnValuesOnConditionCheckFailure); err != nil {
+		return nil, err
+	}
+	base := old
+	if base == nil {
+		base = cloneItem(params.Key)
+	}
+	next := cloneItem(base)
+	if u != nil {
+		if next, err = u.apply(base); err != nil {
+			return nil, err
+		}
+	}
+	if err := t.checkItem(next); err != nil {
+		return nil, err
+	}
+	t.items[key] = next
+
+	output := &awsdynamodb.UpdateItemOutput{}
+	switch params.ReturnValues {
+	case "", types.ReturnValueNone:
+	case types.ReturnValueAllOld:
+		output.Attributes = cloneItem(old)
+	case types.ReturnValueAllNew:
+		output.Attributes = cloneItem(next)
+	case types.ReturnValueUpdatedOld, types.ReturnValueUpdatedNew:
+		var paths []path
+		if u != nil {
+			paths = u.paths()
+		}
+		if params.ReturnValues == types.ReturnValueUpdatedOld && old != nil {
+			output.Attributes = project(old, paths)
+		} else if params.ReturnValues == types.ReturnValueUpdatedNew {
+			output.Attributes = project(next, paths)
+		}
+	default:
+		return nil, validation("Invalid ReturnValues: %s", params.ReturnValues)
+	}
+	return output, nil
+}
+
+// index is what a Scan or Query reads: the table itself, or one of its secondary indexes.
+type index struct {
+	name       string // empty for the table
+	global     bool
+	schema     keySchema
+	projection *types.Projection
+}
+
+func (t *table) index(name *string) (*index, error) {
+	if name == nil {
+		return &index{schema: t.schema()}, nil
+	}
+	for _, gsi := range t.desc.GlobalSecondaryIndexes {
+		if aws.ToString(gsi.IndexName) == *name {
+			return &index{name: *name, global: true, schema: schemaOf(gsi.KeySchema), projection: gsi.Projection}, nil
+		}
+	}
+	for _, lsi := range t.desc.LocalSecondaryIndexes {
+		if aws.ToString(lsi.IndexName) == *name {
+			return &index{name: *name, schema: schemaOf(lsi.KeySchema), projection: lsi.Projection}, nil
+		}
+	}
+	return nil, validation("The table does not have the specified index: %s", *name)
+}
+
+// order is the attributes an index sorts its items by: its keys, then the table's.
+func (t *table) order(ix *index) []string {
+	attrs := ix.schema.attributes()
+	for _, name := range t.schema().attributes() {
+		if !slices.Contains(attrs, name) {
+			attrs = append(attrs, name)
+		}
+	}
+	return attrs
+}
+
+// indexItems returns the items of an index in its order, projected. Items without the index's
+// key attributes are not in a secondary index.
+func (t *table) indexItems(ix *index) []item {
+	order := t.order(ix)
+	var items []item
+	for _, it := range t.items {
+		if ix.name != "" && slices.ContainsFunc(ix.schema.attributes(), func(name string) bool { return it[name] == nil }) {
+			continue
+		}
+		items = append(items, it)
+	}
+	sort.Slice(items, func(i, j int) bool { return compareKeys(items[i], items[j], order) < 0 })
+
+	if ix.name == "" || ix.projection == nil || ix.projection.ProjectionType == types.ProjectionTypeAll {
+		return items
+	}
+	keep := append(order, ix.projection.NonKeyAttributes...)
+	projected := make([]item, len(items))
+	for i, it := range items {
+		projected[i] = item{}
+		for _, name := range keep {
+			if v, ok := it[name]; ok {
+				projected[i][name] = v
+			}
+		}
+	}
+	return projected
+}
+
+// compareKeys orders items by the given key attributes in turn.
+func compareKeys(a, b item, attrs []string) int {
+	for _, name := range attrs {
+		if n, _ := compare(a[name], b[name]); n != 0 {
+			return n
+		}
+	}
+	return 0
+}
+
+// read is the part of a Scan or Query request both share.
+type read struct {
+	limit             *int32
+	exclusiveStartKey item
+	consistentRead    *bool
+	selectAttributes  types.Select
+	projection        *string
+	filter            *string
+}
+
+// page is what a Scan or Query returns.
+type page struct {
+	items            []item
+	count, scanned   int32
+	lastEvaluatedKey item
+}
+
+// readPage reads a page of an index: the items matching match from after the exclusive start key,
+// until Limit items or a megabyte were read, keeping those that pass the filter.
+func (t *table) readPage(r read, ix *index, ph *placeholders, match condition, forward bool) (*page, error) {
+	if r.limit != nil && *r.limit < 1 {
+		return nil, validation("1 validation error detected: Value '%d' at 'limit' failed to satisfy constraint: Member must have value greater than or equal to 1", *r.limit)
+	}
+	if ix.global && aws.ToBool(r.consistentRead) {
+		return nil, validation("Consistent reads are not supported on global secondary indexes")
+	}
+
+	var filter condition
+	var paths []path
+	var err error
+	if r.filter != nil {
+		if filter, err = parseCondition("FilterExpression", *r.filter, ph); err != nil {
+			return nil, err
+		}
+	}
+	if r.projection != nil {
+		if paths, err = parseProjection(*r.projection, ph); err != nil {
+			return nil, err
+		}
+	}
+	if err := ph.unused(); err != nil {
+		return nil, err
+	}
+	switch r.selectAttributes {
+	case "", types.SelectAllAttributes, types.SelectAllProjectedAttributes, types.SelectCount:
+		if paths != nil && r.selectAttributes != "" {
+			return nil, validation("Cannot specify the ProjectionExpression when choosing to get %s", r.selectAttributes)
+		}
+	case types.SelectSpecificAttributes:
+		if paths == nil {
+			return nil, validation("SPECIFIC_ATTRIBUTES requires a ProjectionExpression")
+		}
+	default:
+		return nil, validation("Invalid Select: %s", r.selectAttributes)
+	}
+
+	order := t.order(ix)
+	if r.exclusiveStartKey != nil {
+		if len(r.exclusiveStartKey) != len(order) ||
+			slices.ContainsFunc(order, func(name string) bool { return typeOf(r.exclusiveStartKey[name]) != t.attributeType(name) }) {
+			return nil, validation("The provided starting key is invalid: The provided key element does not match the schema")
+		}
+	}
+
+	items := t.indexItems(ix)
+	if !forward {
+		slices.Reverse(items)
+	}
+	if r.exclusiveStartKey != nil {
+		items = slices.DeleteFunc(items, func(it item) bool {
+			n := compareKeys(it, r.exclusiveStartKey, order)
+			return forward && n <= 0 || !forward && n >= 0
+		})
+	}
+
+	p := &page{}
+	var size int64
+	for _, it := range items {
+		if match != nil && !match.test(it) {
+			continue
+		}
+		p.scanned++
+		size += itemSize(it)
+		if filter == nil || filter.test(it) {
+			p.count++
+			if r.selectAttributes != types.SelectCount {
+				if paths != nil {
+					p.items = append(p.items, project(it, paths))
+				} else {
+					p.items = append(p.items, cloneItem(it))
+				}
+			}
+		}
+		// Like DynamoDB, a page that stops at the limit has a LastEvaluatedKey even when no
+		// items follow, and the next page is empty
+		if r.limit != nil && p.scanned == *r.limit || size >= maxPageBytes {
+			p.lastEvaluatedKey = item{}
+			for _, name := range order {
+				p.lastEvaluatedKey[name] = clone(it[name])
+			}
+			break
+		}
+	}
+	if p.items == nil && r.selectAttributes != types.SelectCount {
+		p.items = []item{}
+	}
+	return p, nil
+}
+
+// Scan reads the items of a table or index in key order.
+func (c *Client) Scan(ctx context.Context, params *awsdynamodb.ScanInput, _ ...func(*awsdynamodb.Options)) (*awsdynamodb.ScanOutput, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	t, err := c.table(params.TableName)
+	if err != nil {
+		return nil, err
+	}
+	ix, err := t.index(params.IndexName)
+	if err != nil {
+		return nil, err
+	}
+	p, err := t.readPage(read{
+		limit:             params.Limit,
+		exclusiveStartKey: params.ExclusiveStartKey,
+		consistentRead:    params.ConsistentRead,
+		selectAttributes:  params.Select,
+		projection:        params.ProjectionExpression,
+		filter:            params.FilterExpression,
+	}, ix, newPlaceholders(params.ExpressionAttributeNames, params.ExpressionAttributeValues), nil, true)
+	if err != nil {
+		return nil, err
+	}
+	return &awsdynamodb.ScanOutput{
+		Items:            p.items,
+		Count:            p.count,
+		ScannedCount:     p.scanned,
+		LastEvaluatedKey: p.lastEvaluatedKey,
+	}, nil
+}
+
+// Query reads the items of a table or index with one partition key, in sort key order.
+func (c *Client) Query(ctx context.Context, params *awsdynamodb.QueryInput, _ ...func(*awsdynamodb.Options)) (*awsdynamodb.QueryOutput, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	t, err := c.table(params.TableName)
+	if err != nil {
+		return nil, err
+	}
+	ix, err := t.index(params.IndexName)
+	if err != nil {
+		return nil, err
+	}
+	if params.KeyConditionExpression == nil {
+		return nil, validation("Either the KeyConditions or KeyConditionExpression parameter must be specified in the request.")
+	}
+	ph := newPlaceholders(params.ExpressionAttributeNames, params.ExpressionAttributeValues)
+	match, err := parseCondition("KeyConditionExpression", *params.KeyConditionExpression, ph)
+	if err != nil {
+		return nil, err
+	}
+	if err := keyConditionTerms(match, ix.schema.hash, ix.schema.rng); err != nil {
+		return nil, err
+	}
+
+	p, err := t.readPage(read{
+		limit:             params.Limit,
+		exclusiveStartKey: params.ExclusiveStartKey,
+		consistentRead:    params.ConsistentRead,
+		selectAttributes:  params.Select,
+		projection:        params.ProjectionExpression,
+		filter:            params.FilterExpression,
+	}, ix, ph, match, params.ScanIndexForward == nil || *params.ScanIndexForward)
+	if err != nil {
+		return nil, err
+	}
+	return &awsdynamodb.QueryOutput{
+		Items:            p.items,
+		Count:            p.count,
+		ScannedCount:     p.scanned,
+		LastEvaluatedKey: p.lastEvaluatedKey,
+	}, nil
+}
@@ -0,0 +1,585 @@
+package ddbfake
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsdynamodb "github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/aws/smithy-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tpelletiersophos/cloudcutter/internal/services/aws/dynamodb"
+)
+
+func s(v string) types.AttributeValue { return &types.AttributeValueMemberS{Value: v} }
+func n(v string) types.AttributeValue { return &types.AttributeValueMemberN{Value: v} }
+
+// newClient returns a client with an orders table keyed by customer and order number, with a
+// by-status index that leaves out orders without a status.
+func newClient(t *testing.T) *Client {
+	t.Helper()
+	c := New()
+	_, err := c.CreateTable(context.Background(), &awsdynamodb.CreateTableInput{
+		TableName: aws.String("orders"),
+		KeySchema: []types.KeySchemaElement{
+			{AttributeName: aws.String("customer"), KeyType: types.KeyTypeHash},
+			{AttributeName: aws.String("order"), KeyType: types.KeyTypeRange},
+		},
+		AttributeDefinitions: []types.AttributeDefinition{
+			{AttributeName: aws.String("customer"), AttributeType: types.ScalarAttributeTypeS},
+			{AttributeName: aws.String("order"), AttributeType: types.ScalarAttributeTypeN},
+			{AttributeName: aws.String("status"), AttributeType: types.ScalarAttributeTypeS},
+		},
+		GlobalSecondaryIndexes: []types.GlobalSecondaryIndex{{
+			IndexName:  aws.String("by-status"),
+			KeySchema:  []types.KeySchemaElement{{AttributeName: aws.String("status"), KeyType: types.KeyTypeHash}},
+			Projection: &types.Projection{ProjectionType: types.ProjectionTypeInclude, NonKeyAttributes: []string{"total"}},
+		}},
+	})
+	require.NoError(t, err)
+
+	items := []item{
+		{"customer": s("ann"), "order": n("1"), "status": s("paid"), "total": n("20"), "note": s("gift wrap")},
+		{"customer": s("ann"), "order": n("2"), "status": s("shipped"), "total": n("5.5")},
+		{"customer": s("ann"), "order": n("10"), "total": n("99"), "tags": &types.AttributeValueMemberSS{Value: []string{"bulk", "rush"}}},
+		{"customer": s("bob"), "order": n("1"), "status": s("paid"), "total": n("42")},
+		{"customer": s("bob"), "order": n("3"), "status": s("cancelled"), "total": n("0"), "note": s("duplicate")},
+	}
+	for _, it := range items {
+		_, err := c.PutItem(context.Background(), &awsdynamodb.PutItemInput{TableName: aws.String("orders"), Item: it})
+		require.NoError(t, err)
+	}
+	return c
+}
+
+// keys returns "customer/order" of each item, in order.
+func keys(items []item) []string {
+	out := []string{}
+	for _, it := range items {
+		out = append(out, it["customer"].(*types.AttributeValueMemberS).Value+"/"+it["order"].(*types.AttributeValueMemberN).Value)
+	}
+	return out
+}
+
+func requireValidation(t *testing.T, err error, message string) {
+	t.Helper()
+	var apiErr smithy.APIError
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, "ValidationException", apiErr.ErrorCode())
+	assert.Contains(t, apiErr.ErrorMessage(), message)
+}
+
+func TestKeySchema(t *testing.T) {
+	c := newClient(t)
+	ctx := context.Background()
+	table := aws.String("orders")
+
+	out, err := c.GetItem(ctx, &awsdynamodb.GetItemInput{TableName: table, Key: item{"customer": s("ann"), "order": n("1.0")}})
+	require.NoError(t, err)
+	assert.Equal(t, s("gift wrap"), out.Item["note"])
+
+	out, err = c.GetItem(ctx, &awsdynamodb.GetItemInput{TableName: table, Key: item{"customer": s("ann"), "order": n("3")}})
+	require.NoError(t, err)
+	assert.Nil(t, out.Item)
+
+	_, err = c.GetItem(ctx, &awsdynamodb.GetItemInput{TableName: table, Key: item{"customer": s("ann")}})
+	requireValidation(t, err, "The provided key element does not match the schema")
+	_, err = c.GetItem(ctx, &awsdynamodb.GetItemInput{TableName: table, Key: item{"customer": s("ann"), "order": s("1")}})
+	requireValidation(t, err, "The provided key element does not match the schema")
+
+	_, err = c.PutItem(ctx, &awsdynamodb.PutItemInput{TableName: table, Item: item{"customer": s("ann")}})
+	requireValidation(t, err, "Missing the key order in the item")
+	_, err = c.PutItem(ctx, &awsdynamodb.PutItemInput{TableName: table, Item: item{"customer": s("ann"), "order": n("4"), "status": n("1")}})
+	requireValidation(t, err, "Type mismatch for key status expected: S actual: N")
+	_, err = c.PutItem(ctx, &awsdynamodb.PutItemInput{TableName: table, Item: item{"customer": s(""), "order": n("4")}})
+	requireValidation(t, err, "cannot contain an empty string value")
+
+	_, err = c.GetItem(ctx, &awsdynamodb.GetItemInput{TableName: aws.String("missing"), Key: item{"id": s("1")}})
+	var notFound *types.ResourceNotFoundException
+	assert.ErrorAs(t, err, &notFound)
+
+	_, err = c.CreateTable(ctx, &awsdynamodb.CreateTableInput{
+		TableName:            table,
+		KeySchema:            []types.KeySchemaElement{{AttributeName: aws.String("id"), KeyType: types.KeyTypeHash}},
+		AttributeDefinitions: []types.AttributeDefinition{{AttributeName: aws.String("id"), AttributeType: types.ScalarAttributeTypeS}},
+	})
+	var inUse *types.ResourceInUseException
+	assert.ErrorAs(t, err, &inUse)
+
+	_, err = c.CreateTable(ctx, &awsdynamodb.CreateTableInput{
+		TableName: aws.String("bad"),
+		KeySchema: []types.KeySchemaElement{{AttributeName: aws.String("id"), KeyType: types.KeyTypeHash}},
+		AttributeDefinitions: []types.AttributeDefinition{
+			{AttributeName: aws.String("id"), AttributeType: types.ScalarAttributeTypeS},
+			{AttributeName: aws.String("extra"), AttributeType: types.ScalarAttributeTypeS},
+		},
+	})
+	requireValidation(t, err, "Some AttributeDefinitions are not used")
+
+	desc, err := c.DescribeTable(ctx, &awsdynamodb.DescribeTableInput{TableName: table})
+	require.NoError(t, err)
+	assert.Equal(t, types.TableStatusActive, desc.Table.TableStatus)
+	assert.EqualValues(t, 5, aws.ToInt64(desc.Table.ItemCount))
+	assert.Positive(t, aws.ToInt64(desc.Table.TableSizeBytes))
+	assert.EqualValues(t, 4, aws.ToInt64(desc.Table.GlobalSecondaryIndexes[0].ItemCount))
+}
+
+func TestPagination(t *testing.T) {
+	c := newClient(t)
+	ctx := context.Background()
+
+	var pages [][]string
+	var start item
+	for {
+		out, err := c.Scan(ctx, &awsdynamodb.ScanInput{TableName: aws.String("orders"), Limit: aws.Int32(2), ExclusiveStartKey: start})
+		require.NoError(t, err)
+		pages = append(pages, keys(out.Items))
+		if out.LastEvaluatedKey == nil {
+			break
+		}
+		start = out.LastEvaluatedKey
+	}
+	assert.Equal(t, [][]string{{"ann/1", "ann/2"}, {"ann/10", "bob/1"}, {"bob/3"}}, pages)
+
+	// A page that ends on the last item still has a LastEvaluatedKey, and the next is empty
+	out, err := c.Scan(ctx, &awsdynamodb.ScanInput{TableName: aws.String("orders"), Limit: aws.Int32(5)})
+	require.NoError(t, err)
+	assert.Equal(t, item{"customer": s("bob"), "order": n("3")}, out.LastEvaluatedKey)
+	out, err = c.Scan(ctx, &awsdynamodb.ScanInput{TableName: aws.String("orders"), ExclusiveStartKey: out.LastEvaluatedKey})
+	require.NoError(t, err)
+	assert.Empty(t, out.Items)
+	assert.Nil(t, out.LastEvaluatedKey)
+
+	_, err = c.Scan(ctx, &awsdynamodb.ScanInput{TableName: aws.String("orders"), ExclusiveStartKey: item{"customer": s("ann")}})
+	requireValidation(t, err, "The provided starting key is invalid")
+
+	// The service pages through the fake with the SDK's paginators
+	for i := 0; i < 120; i++ {
+		_, err := c.CreateTable(ctx, &awsdynamodb.CreateTableInput{
+			TableName:            aws.String(fmt.Sprintf("t%03d", i)),
+			KeySchema:            []types.KeySchemaElement{{AttributeName: aws.String("id"), KeyType: types.KeyTypeHash}},
+			AttributeDefinitions: []types.AttributeDefinition{{AttributeName: aws.String("id"), AttributeType: types.ScalarAttributeTypeN}},
+		})
+		require.NoError(t, err)
+		if i < 3 {
+			for id := 0; id < 150; id++ {
+				_, err := c.PutItem(ctx, &awsdynamodb.PutItemInput{TableName: aws.String(fmt.Sprintf("t%03d", i)), Item: item{"id": n(fmt.Sprint(id))}})
+				require.NoError(t, err)
+			}
+		}
+	}
+	svc := dynamodb.NewServiceWithClient(c)
+	tables, err := svc.ListTables(ctx)
+	require.NoError(t, err)
+	assert.Len(t, tables, 121)
+	assert.Equal(t, "orders", tables[0])
+
+	all, err := svc.ScanTable(ctx, "t000")
+	require.NoError(t, err)
+	assert.Len(t, all, 150)
+	some, err := svc.Scan(ctx, "t000", 7)
+	require.NoError(t, err)
+	require.Len(t, some, 7)
+	assert.Equal(t, n("6"), some[6]["id"])
+}
+
+func TestQuery(t *testing.T) {
+	c := newClient(t)
+	ctx := context.Background()
+
+	query := func(t *testing.T, input *awsdynamodb.QueryInput) []string {
+		t.Helper()
+		input.TableName = aws.String("orders")
+		out, err := c.Query(ctx, input)
+		require.NoError(t, err)
+		return keys(out.Items)
+	}
+	values := func(kv ...any) map[string]types.AttributeValue {
+		m := map[string]types.AttributeValue{}
+		for i := 0; i < len(kv); i += 2 {
+			m[kv[i].(string)] = kv[i+1].(types.AttributeValue)
+		}
+		return m
+	}
+
+	assert.Equal(t, []string{"ann/1", "ann/2", "ann/10"}, query(t, &awsdynamodb.QueryInput{
+		KeyConditionExpression:    aws.String("customer = :c"),
+		ExpressionAttributeValues: values(":c", s("ann")),
+	}))
+	assert.Equal(t, []string{"ann/10", "ann/2"}, query(t, &awsdynamodb.QueryInput{
+		KeyConditionExpression:    aws.String("#c = :c AND #o BETWEEN :lo AND :hi"),
+		ExpressionAttributeNames:  map[string]string{"#c": "customer", "#o": "order"},
+		ExpressionAttributeValues: values(":c", s("ann"), ":lo", n("2"), ":hi", n("10")),
+		ScanIndexForward:          aws.Bool(false),
+	}))
+	assert.Equal(t, []string{"ann/10"}, query(t, &awsdynamodb.QueryInput{
+		KeyConditionExpression:    aws.String("customer = :c and #o > :o"),
+		ExpressionAttributeNames:  map[string]string{"#o": "order"},
+		ExpressionAttributeValues: values(":c", s("ann"), ":o", n("2")),
+	}))
+
+	out, err := c.Query(ctx, &awsdynamodb.QueryInput{
+		TableName:                 aws.String("orders"),
+		IndexName:                 aws.String("by-status"),
+		KeyConditionExpression:    aws.String("#s = :s"),
+		ExpressionAttributeNames:  map[string]string{"#s": "status"},
+		ExpressionAttributeValues: values(":s", s("paid")),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"ann/1", "bob/1"}, keys(out.Items))
+	assert.Equal(t, item{"customer": s("ann"), "order": n("1"), "status": s("paid"), "total": n("20")}, out.Items[0], "the index projects keys and total only")
+
+	out, err = c.Query(ctx, &awsdynamodb.QueryInput{
+		TableName:                 aws.String("orders"),
+		IndexName:                 aws.String("by-status"),
+		KeyConditionExpression:    aws.String("#s = :s"),
+		ExpressionAttributeNames:  map[string]string{"#s": "status"},
+		ExpressionAttributeValues: values(":s", s("paid")),
+		Limit:                     aws.Int32(1),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, item{"status": s("paid"), "customer": s("ann"), "order": n("1")}, out.LastEvaluatedKey)
+
+	for _, tc := range []struct {
+		condition string
+		error     string
+	}{
+		{"#o = :o", "Query condition missed key schema element: customer"},
+		{"customer > :c", "Query key condition not supported"},
+		{"customer = :c OR customer = :c", "Query key condition not supported"},
+		{"customer = :c AND total = :o", "Query condition missed key schema element"},
+		{"customer = :c AND", "Syntax error; token: <EOF>"},
+	} {
+		_, err := c.Query(ctx, &awsdynamodb.QueryInput{
+			TableName:                 aws.String("orders"),
+			KeyConditionExpression:    aws.String(tc.condition),
+			ExpressionAttributeNames:  map[string]string{"#o": "order"},
+			ExpressionAttributeValues: values(":c", s("ann"), ":o", n("1")),
+		})
+		requireValidation(t, err, tc.error)
+	}
+
+	_, err = c.Query(ctx, &awsdynamodb.QueryInput{
+		TableName:                 aws.String("orders"),
+		IndexName:                 aws.String("by-status"),
+		KeyConditionExpression:    aws.String("#s = :s"),
+		ExpressionAttributeNames:  map[string]string{"#s": "status"},
+		ExpressionAttributeValues: values(":s", s("paid")),
+		ConsistentRead:            aws.Bool(true),
+	})
+	requireValidation(t, err, "Consistent reads are not supported on global secondary indexes")
+
+	svc := dynamodb.NewServiceWithClient(c)
+	items, err := svc.Query(ctx, "orders", "by-status", map[string]types.AttributeValue{"status": s("paid")}, 0)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"ann/1", "bob/1"}, keys(items))
+	items, err = svc.Query(ctx, "orders", "", map[string]types.AttributeValue{"customer": s("bob"), "order": n("3")}, 0)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"bob/3"}, keys(items))
+}
+
+func TestFilterExpressions(t *testing.T) {
+	c := newClient(t)
+	values := map[string]types.AttributeValue{
+		":paid": s("paid"), ":ten": n("10"), ":fifty": n("50"), ":gift": s("gift"),
+		":rush": s("rush"), ":S": s("S"), ":two": n("2"), ":shipped": s("shipped"),
+	}
+
+	tests := []struct {
+		filter string
+		want   []string
+	}{
+		{"#st = :paid", []string{"ann/1", "bob/1"}},
+		{"#st <> :paid", []string{"ann/2", "ann/10", "bob/3"}},
+		{"total BETWEEN :ten AND :fifty", []string{"ann/1", "bob/1"}},
+		{"NOT total < :ten AND attribute_exists(#st)", []string{"ann/1", "bob/1"}},
+		{"#st IN (:paid, :shipped) OR contains(tags, :rush)", []string{"ann/1", "ann/2", "ann/10", "bob/1"}},
+		{"attribute_not_exists(#st)", []string{"ann/10"}},
+		{"begins_with(note, :gift)", []string{"ann/1"}},
+		{"attribute_type(note, :S) AND total < :ten", []string{"bob/3"}},
+		{"size(tags) = :two", []string{"ann/10"}},
+		{"(#st = :paid OR #st = :shipped) AND total > :ten", []string{"ann/1", "bob/1"}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.filter, func(t *testing.T) {
+			used := map[string]types.AttributeValue{}
+			for name, v := range values {
+				if containsWord(tc.filter, name) {
+					used[name] = v
+				}
+			}
+			input := &awsdynamodb.ScanInput{
+				TableName:                 aws.String("orders"),
+				FilterExpression:          aws.String(tc.filter),
+				ExpressionAttributeValues: used,
+			}
+			if containsWord(tc.filter, "#st") {
+				input.ExpressionAttributeNames = map[string]string{"#st": "status"}
+			}
+			out, err := c.Scan(context.Background(), input)
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, keys(out.Items))
+			assert.EqualValues(t, len(tc.want), out.Count)
+			assert.EqualValues(t, 5, out.ScannedCount)
+		})
+	}
+
+	// Limit counts the items read, not the items the filter keeps
+	out, err := c.Scan(context.Background(), &awsdynamodb.ScanInput{
+		TableName:                 aws.String("orders"),
+		FilterExpression:          aws.String("total > :ten"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{":ten": n("10")},
+		Limit:                     aws.Int32(2),
+		Select:                    types.SelectCount,
+	})
+	require.NoError(t, err)
+	assert.Nil(t, out.Items)
+	assert.EqualValues(t, 1, out.Count)
+	assert.EqualValues(t, 2, out.ScannedCount)
+
+	out, err = c.Scan(context.Background(), &awsdynamodb.ScanInput{
+		TableName:                aws.String("orders"),
+		ProjectionExpression:     aws.String("#o, customer, tags"),
+		ExpressionAttributeNames: map[string]string{"#o": "order"},
+		Limit:                    aws.Int32(3),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, item{"customer": s("ann"), "order": n("10"), "tags": &types.AttributeValueMemberSS{Value: []string{"bulk", "rush"}}}, out.Items[2])
+	assert.Equal(t, item{"customer": s("ann"), "order": n("1")}, out.Items[0])
+}
+
+// containsWord reports whether a placeholder appears in an expression.
+func containsWord(expr, word string) bool {
+	for i := 0; i+len(word) <= len(expr); i++ {
+		if expr[i:i+len(word)] == word && (i+len(word) == len(expr) || !isWordRune(expr[i+len(word)])) {
+			return true
+		}
+	}
+	return false
+}
+
+func TestExpressionErrors(t *testing.T) {
+	c := newClient(t)
+	tests := []struct {
+		filter string
+		names  map[string]string
+		values map[string]types.AttributeValue
+		error  string
+	}{
+		{"total > :missing", nil, nil, "An expression attribute value used in expression is not defined; attribute value: :missing"},
+		{"#missing > :v", nil, map[string]types.AttributeValue{":v": n("1")}, "attribute name: #missing"},
+		{"total > :v", nil, map[string]types.AttributeValue{":v": n("1"), ":unused": n("2")}, "unused in expressions: keys: {:unused}"},
+		{"total > :v", map[string]string{"#unused": "x"}, map[string]types.AttributeValue{":v": n("1")}, "ExpressionAttributeNames unused in expressions: keys: {#unused}"},
+		{"total >", nil, nil, "Invalid FilterExpression: Syntax error; token: <EOF>"},
+		{"total ! :v", nil, map[string]types.AttributeValue{":v": n("1")}, `Syntax error; token: "!"`},
+		{"", nil, nil, "The expression can not be empty"},
+	}
+	for _, tc := range tests {
+		_, err := c.Scan(context.Background(), &awsdynamodb.ScanInput{
+			TableName:                 aws.String("orders"),
+			FilterExpression:          aws.String(tc.filter),
+			ExpressionAttributeNames:  tc.names,
+			ExpressionAttributeValues: tc.values,
+		})
+		requireValidation(t, err, tc.error)
+	}
+}
+
+func TestConditionalWrites(t *testing.T) {
+	c := newClient(t)
+	ctx := context.Background()
+
+	put := &awsdynamodb.PutItemInput{
+		TableName:                           aws.String("orders"),
+		Item:                                item{"customer": s("cat"), "order": n("1"), "total": n("7")},
+		ConditionExpression:                 aws.String("attribute_not_exists(customer)"),
+		ReturnValuesOnConditionCheckFailure: types.ReturnValuesOnConditionCheckFailureAllOld,
+	}
+	_, err := c.PutItem(ctx, put)
+	require.NoError(t, err)
+
+	put.Item = item{"customer": s("cat"), "order": n("1"), "total": n("8")}
+	_, err = c.PutItem(ctx, put)
+	var failed *types.ConditionalCheckFailedException
+	require.ErrorAs(t, err, &failed)
+	assert.Equal(t, "The conditional request failed", failed.ErrorMessage())
+	assert.Equal(t, n("7"), failed.Item["total"])
+
+	_, err = c.DeleteItem(ctx, &awsdynamodb.DeleteItemInput{
+		TableName:                 aws.String("orders"),
+		Key:                       item{"customer": s("cat"), "order": n("1")},
+		ConditionExpression:       aws.String("total > :limit"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{":limit": n("10")},
+	})
+	require.ErrorAs(t, err, &failed)
+	assert.Nil(t, failed.Item, "the old item is only returned when asked for")
+
+	deleted, err := c.DeleteItem(ctx, &awsdynamodb.DeleteItemInput{
+		TableName:                 aws.String("orders"),
+		Key:                       item{"customer": s("cat"), "order": n("1")},
+		ConditionExpression:       aws.String("total < :limit"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{":limit": n("10")},
+		ReturnValues:              types.ReturnValueAllOld,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, n("7"), deleted.Attributes["total"])
+
+	got, err := c.GetItem(ctx, &awsdynamodb.GetItemInput{TableName: aws.String("orders"), Key: item{"customer": s("cat"), "order": n("1")}})
+	require.NoError(t, err)
+	assert.Nil(t, got.Item)
+}
+
+func TestUpdateItem(t *testing.T) {
+	c := newClient(t)
+	ctx := context.Background()
+	key := item{"customer": s("ann"), "order": n("1")}
+
+	update := func(t *testing.T, expr string, values map[string]types.AttributeValue) item {
+		t.Helper()
+		out, err := c.UpdateItem(ctx, &awsdynamodb.UpdateItemInput{
+			TableName:                 aws.String("orders"),
+			Key:                       key,
+			UpdateExpression:          aws.String(expr),
+			ExpressionAttributeValues: values,
+			ReturnValues:              types.ReturnValueAllNew,
+		})
+		require.NoError(t, err)
+		return out.Attributes
+	}
+
+	got := update(t, "SET total = total + :inc, visits = if_not_exists(visits, :zero) + :inc REMOVE note", map[string]types.AttributeValue{":inc": n("1.5"), ":zero": n("0")})
+	assert.Equal(t, n("21.5"), got["total"])
+	assert.Equal(t, n("1.5"), got["visits"])
+	assert.NotContains(t, got, "note")
+
+	got = update(t, "SET history = list_append(if_not_exists(history, :empty), :entry)",
+		map[string]types.AttributeValue{
+			":empty": &types.AttributeValueMemberL{},
+			":entry": &types.AttributeValueMemberL{Value: []types.AttributeValue{s("paid")}},
+		})
+	assert.Equal(t, &types.AttributeValueMemberL{Value: []types.AttributeValue{s("paid")}}, got["history"])
+
+	got = update(t, "ADD visits :one, tags :tags", map[string]types.AttributeValue{":one": n("1"), ":tags": &types.AttributeValueMemberSS{Value: []string{"vip", "new"}}})
+	assert.Equal(t, n("2.5"), got["visits"])
+	assert.ElementsMatch(t, []string{"vip", "new"}, got["tags"].(*types.AttributeValueMemberSS).Value)
+
+	got = update(t, "DELETE tags :new SET history[0] = :shipped", map[string]types.AttributeValue{":new": &types.AttributeValueMemberSS{Value: []string{"new"}}, ":shipped": s("shipped")})
+	assert.Equal(t, &types.AttributeValueMemberSS{Value: []string{"vip"}}, got["tags"])
+	assert.Equal(t, &types.AttributeValueMemberL{Value: []types.AttributeValue{s("shipped")}}, got["history"])
+
+	out, err := c.UpdateItem(ctx, &awsdynamodb.UpdateItemInput{
+		TableName:                 aws.String("orders"),
+		Key:                       item{"customer": s("dan"), "order": n("1")},
+		UpdateExpression:          aws.String("SET #st = :s"),
+		ConditionExpression:       aws.String("attribute_not_exists(customer)"),
+		ExpressionAttributeNames:  map[string]string{"#st": "status"},
+		ExpressionAttributeValues: map[string]types.AttributeValue{":s": s("pending")},
+		ReturnValues:              types.ReturnValueUpdatedNew,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, item{"status": s("pending")}, out.Attributes, "updating a missing item creates it")
+
+	for _, tc := range []struct {
+		expr   string
+		values map[string]types.AttributeValue
+		error  string
+	}{
+		{"SET customer = :v", map[string]types.AttributeValue{":v": s("x")}, "Cannot update attribute customer. This attribute is part of the key"},
+		{"SET total = note + :v", map[string]types.AttributeValue{":v": n("1")}, "refers to an attribute that does not exist in the item"},
+		{"SET total = #st + :v", map[string]types.AttributeValue{":v": n("1")}, "An operand in the update expression has an incorrect data type"},
+		{"SET #st = :v", map[string]types.AttributeValue{":v": n("1")}, "Type mismatch for key status expected: S actual: N"},
+		{"SET address.city = :v", map[string]types.AttributeValue{":v": s("Oslo")}, "The document path provided in the update expression is invalid for update"},
+		{"SET total = :v SET visits = :v", map[string]types.AttributeValue{":v": n("1")}, "Syntax error"},
+	} {
+		input := &awsdynamodb.UpdateItemInput{
+			TableName:                 aws.String("orders"),
+			Key:                       key,
+			UpdateExpression:          aws.String(tc.expr),
+			ExpressionAttributeValues: tc.values,
+		}
+		if containsWord(tc.expr, "#st") {
+			input.ExpressionAttributeNames = map[string]string{"#st": "status"}
+		}
+		_, err := c.UpdateItem(ctx, input)
+		requireValidation(t, err, tc.error)
+	}
+}
+
+func TestLoadFixtureFile(t *testing.T) {
+	c := New()
+	require.NoError(t, c.LoadFixtureFile(filepath.Join("..", "..", "..", "..", "..", "deployments", "local", "data", "dynamodb-tables.json")))
+
+	ctx := context.Background()
+	desc, err := c.DescribeTable(ctx, &awsdynamodb.DescribeTableInput{TableName: aws.String("sample-devices")})
+	require.NoError(t, err)
+	assert.EqualValues(t, 4, aws.ToInt64(desc.Table.ItemCount))
+
+	out, err := c.Query(ctx, &awsdynamodb.QueryInput{
+		TableName:                 aws.String("sample-devices"),
+		IndexName:                 aws.String("by-last-seen"),
+		KeyConditionExpression:    aws.String("tenantId = :t AND lastSeen >= :since"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{":t": s("acme"), ":since": n("1705300000")},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []item{
+		{"tenantId": s("acme"), "deviceId": s("laptop-001"), "lastSeen": n("1705312800")},
+		{"tenantId": s("acme"), "deviceId": s("server-001"), "lastSeen": n("1705316400")},
+	}, out.Items)
+
+	err = c.LoadFixtureFile(filepath.Join("..", "..", "..", "..", "..", "deployments", "local", "data", "dynamodb-tables.json"))
+	var inUse *types.ResourceInUseException
+	assert.ErrorAs(t, err, &inUse)
+
+	for _, tc := range []struct{ fixture, error string }{
+		{`{}`, "invalid fixture"},
+		{`[{"TableName": "x", "Keys": []}]`, `unknown field "Keys"`},
+		{`[{"TableName": "x", "KeySchema": [{"AttributeName": "id", "KeyType": "HASH"}], "AttributeDefinitions": [{"AttributeName": "id", "AttributeType": "S"}], "Items": [{"id": {"X": "1"}}]}]`, `table x: item 0: id: unknown type "X"`},
+	} {
+		err := New().LoadFixtures(strings.NewReader(tc.fixture))
+		assert.ErrorContains(t, err, tc.error)
+	}
+}
+
+func TestLoadDemo(t *testing.T) {
+	now := time.Date(2024, 1, 16, 12, 0, 0, 0, time.UTC)
+	c := New()
+	require.NoError(t, c.LoadDemo(now))
+
+	ctx := context.Background()
+	svc := dynamodb.NewServiceWithClient(c)
+	tables, err := svc.ListTables(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, DemoTables, tables)
+
+	users, err := svc.ScanTable(ctx, "demo-users")
+	require.NoError(t, err)
+	assert.Len(t, users, len(demoNames))
+
+	orders, err := svc.ScanTable(ctx, "demo-orders")
+	require.NoError(t, err)
+	paid, err := svc.Query(ctx, "demo-orders", "by-status", map[string]types.AttributeValue{"status": s("paid")}, 0)
+	require.NoError(t, err)
+	assert.NotEmpty(t, paid)
+	assert.Less(t, len(paid), len(orders))
+	for _, order := range orders {
+		created, err := time.Parse(time.RFC3339, order["createdAt"].(*types.AttributeValueMemberS).Value)
+		require.NoError(t, err)
+		assert.False(t, created.After(now))
+	}
+
+	again := New()
+	require.NoError(t, again.LoadDemo(now))
+	same, err := dynamodb.NewServiceWithClient(again).ScanTable(ctx, "demo-orders")
+	require.NoError(t, err)
+	assert.Equal(t, orders, same)
+
+	var inUse *types.ResourceInUseException
+	assert.True(t, errors.As(c.LoadDemo(now), &inUse))
+}
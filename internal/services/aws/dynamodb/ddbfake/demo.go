@@ -0,0 +1,133 @@
+package ddbfake
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"math/rand"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsdynamodb "github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// DemoTables are the tables LoadDemo creates.
+var DemoTables = []string{"demo-orders", "demo-users"}
+
+var (
+	demoNames    = []string{"ada", "grace", "linus", "margaret", "ken", "barbara", "dennis", "frances", "alan", "radia"}
+	demoPlans    = []string{"free", "team", "enterprise"}
+	demoStatuses = []string{"pending", "paid", "shipped", "delivered", "cancelled"}
+	demoProducts = []struct {
+		sku   string
+		price string
+	}{
+		{"KB-101", "49.90"},
+		{"MS-220", "19.50"},
+		{"MN-270", "229"},
+		{"HD-045", "89.99"},
+		{"CB-003", "7.25"},
+	}
+)
+
+// LoadDemo creates made-up tables of users and their orders up to now: demo-users, keyed by
+// email, and demo-orders, keyed by customer and order and indexed by status and creation time in
+// the by-status index. The same now writes the same items.
+func (c *Client) LoadDemo(now time.Time) error {
+	ctx := context.Background()
+	rng := rand.New(rand.NewSource(now.Unix()))
+
+	_, err := c.CreateTable(ctx, &awsdynamodb.CreateTableInput{
+		TableName:            aws.String("demo-users"),
+		KeySchema:            []types.KeySchemaElement{{AttributeName: aws.String("email"), KeyType: types.KeyTypeHash}},
+		AttributeDefinitions: []types.AttributeDefinition{{AttributeName: aws.String("email"), AttributeType: types.ScalarAttributeTypeS}},
+		BillingMode:          types.BillingModePayPerRequest,
+	})
+	if err != nil {
+		return err
+	}
+	_, err = c.CreateTable(ctx, &awsdynamodb.CreateTableInput{
+		TableName: aws.String("demo-orders"),
+		KeySchema: []types.KeySchemaElement{
+			{AttributeName: aws.String("customer"), KeyType: types.KeyTypeHash},
+			{AttributeName: aws.String("orderId"), KeyType: types.KeyTypeRange},
+		},
+		AttributeDefinitions: []types.AttributeDefinition{
+			{AttributeName: aws.String("customer"), AttributeType: types.ScalarAttributeTypeS},
+			{AttributeName: aws.String("orderId"), AttributeType: types.ScalarAttributeTypeS},
+			{AttributeName: aws.String("status"), AttributeType: types.ScalarAttributeTypeS},
+			{AttributeName: aws.String("createdAt"), AttributeType: types.ScalarAttributeTypeS},
+		},
+		GlobalSecondaryIndexes: []types.GlobalSecondaryIndex{{
+			IndexName: aws.String("by-status"),
+			KeySchema: []types.KeySchemaElement{
+				{AttributeName: aws.String("status"), KeyType: types.KeyTypeHash},
+				{AttributeName: aws.String("createdAt"), KeyType: types.KeyTypeRange},
+			},
+			Projection: &types.Projection{ProjectionType: types.ProjectionTypeAll},
+		}},
+		BillingMode: types.BillingModePayPerRequest,
+	})
+	if err != nil {
+		return err
+	}
+
+	put := func(table string, it item) error {
+		_, err := c.PutItem(ctx, &awsdynamodb.PutItemInput{TableName: aws.String(table), Item: it})
+		return err
+	}
+	order := 0
+	for i, name := range demoNames {
+		email := name + "@example.com"
+		signedUp := now.Add(-time.Duration(30+rng.Intn(300)) * 24 * time.Hour)
+		user := item{
+			"email":    &types.AttributeValueMemberS{Value: email},
+			"name":     &types.AttributeValueMemberS{Value: name},
+			"plan":     &types.AttributeValueMemberS{Value: demoPlans[rng.Intn(len(demoPlans))]},
+			"signedUp": &types.AttributeValueMemberS{Value: signedUp.UTC().Format(time.RFC3339)},
+			"logins":   &types.AttributeValueMemberN{Value: strconv.Itoa(rng.Intn(200))},
+			"verified": &types.AttributeValueMemberBOOL{Value: i%4 != 3},
+			"address": &types.AttributeValueMemberM{Value: item{
+				"city":    &types.AttributeValueMemberS{Value: []string{"Oxford", "Lyon", "Austin", "Kyoto"}[rng.Intn(4)]},
+				"country": &types.AttributeValueMemberS{Value: []string{"GB", "FR", "US", "JP"}[rng.Intn(4)]},
+			}},
+		}
+		if i%3 == 0 {
+			user["roles"] = &types.AttributeValueMemberSS{Value: []string{"admin", "billing"}}
+		}
+		if err := put("demo-users", user); err != nil {
+			return err
+		}
+
+		for n := 2 + rng.Intn(12); n > 0; n-- {
+			order++
+			created := now.Add(-time.Duration(rng.Intn(60*24)) * time.Minute * 30)
+			product := demoProducts[rng.Intn(len(demoProducts))]
+			quantity := 1 + rng.Intn(3)
+			price, _ := number(product.price)
+			total := new(big.Rat).Mul(price, big.NewRat(int64(quantity), 1))
+			it := item{
+				"customer":  &types.AttributeValueMemberS{Value: email},
+				"orderId":   &types.AttributeValueMemberS{Value: fmt.Sprintf("ord-%05d", order)},
+				"status":    &types.AttributeValueMemberS{Value: demoStatuses[rng.Intn(len(demoStatuses))]},
+				"createdAt": &types.AttributeValueMemberS{Value: created.UTC().Format(time.RFC3339)},
+				"total":     &types.AttributeValueMemberN{Value: formatNumber(total)},
+				"lines": &types.AttributeValueMemberL{Value: []types.AttributeValue{
+					&types.AttributeValueMemberM{Value: item{
+						"sku":      &types.AttributeValueMemberS{Value: product.sku},
+						"quantity": &types.AttributeValueMemberN{Value: strconv.Itoa(quantity)},
+					}},
+				}},
+			}
+			if rng.Intn(4) == 0 {
+				it["giftNote"] = &types.AttributeValueMemberS{Value: "Happy birthday!"}
+			}
+			if err := put("demo-orders", it); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
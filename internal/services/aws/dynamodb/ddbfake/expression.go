@@ -0,0 +1,1022 @@
+package ddbfake
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+type item = map[string]types.AttributeValue
+
+type tokenKind int
+
+const (
+	tokEnd    tokenKind = iota
+	tokIdent            // attribute names, keywords and functions
+	tokName             // #placeholder, kept with its #
+	tokValue            // :placeholder, kept with its :
+	tokNumber           // list indexes
+	tokSymbol           // = <> < <= > >= ( ) , . [ ] + -
+)
+
+type token struct {
+	kind tokenKind
+	text string
+}
+
+func isWordRune(r byte) bool {
+	return r == '_' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9'
+}
+
+func tokenize(expr string) ([]token, error) {
+	var tokens []token
+	for i := 0; i < len(expr); {
+		c := expr[i]
+		word := func(start int) string {
+			j := start
+			for j < len(expr) && isWordRune(expr[j]) {
+				j++
+			}
+			return expr[i:j]
+		}
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '#' || c == ':':
+			text := word(i + 1)
+			if len(text) == 1 {
+				return nil, fmt.Errorf("Syntax error; token: %q", string(c))
+			}
+			kind := tokName
+			if c == ':' {
+				kind = tokValue
+			}
+			tokens = append(tokens, token{kind, text})
+			i += len(text)
+		case c >= '0' && c <= '9':
+			text := word(i)
+			tokens = append(tokens, token{tokNumber, text})
+			i += len(text)
+		case isWordRune(c):
+			text := word(i)
+			tokens = append(tokens, token{tokIdent, text})
+			i += len(text)
+		case c == '<' && i+1 < len(expr) && (expr[i+1] == '>' || expr[i+1] == '='),
+			c == '>' && i+1 < len(expr) && expr[i+1] == '=':
+			tokens = append(tokens, token{tokSymbol, expr[i : i+2]})
+			i += 2
+		case strings.IndexByte("=<>(),.[]+-", c) >= 0:
+			tokens = append(tokens, token{tokSymbol, string(c)})
+			i++
+		default:
+			r, _ := utf8.DecodeRuneInString(expr[i:])
+			return nil, fmt.Errorf("Syntax error; token: %q", string(r))
+		}
+	}
+	return append(tokens, token{kind: tokEnd}), nil
+}
+
+// placeholders are the ExpressionAttributeNames and ExpressionAttributeValues of a request, which
+// its expressions share and must use up.
+type placeholders struct {
+	names  map[string]string
+	values map[string]types.AttributeValue
+	used   map[string]bool
+}
+
+func newPlaceholders(names map[string]string, values map[string]types.AttributeValue) *placeholders {
+	return &placeholders{names: names, values: values, used: map[string]bool{}}
+}
+
+// unused fails when a name or value was given that no expression refers to.
+func (ph *placeholders) unused() error {
+	var names, values []string
+	for name := range ph.names {
+		if !ph.used[name] {
+			names = append(names, name)
+		}
+	}
+	for name := range ph.values {
+		if !ph.used[name] {
+			values = append(values, name)
+		}
+	}
+	slices.Sort(names)
+	slices.Sort(values)
+	switch {
+	case len(names) > 0:
+		return validation("Value provided in ExpressionAttributeNames unused in expressions: keys: {%s}", strings.Join(names, ", "))
+	case len(values) > 0:
+		return validation("Value provided in ExpressionAttributeValues unused in expressions: keys: {%s}", strings.Join(values, ", "))
+	}
+	return nil
+}
+
+// parser reads one expression, resolving its #name and :value placeholders.
+type parser struct {
+	what   string // the request parameter, for errors
+	tokens []token
+	pos    int
+	ph     *placeholders
+}
+
+func newParser(what, expr string, ph *placeholders) (*parser, error) {
+	if strings.TrimSpace(expr) == "" {
+		return nil, validation("Invalid %s: The expression can not be empty;", what)
+	}
+	tokens, err := tokenize(expr)
+	if err != nil {
+		return nil, validation("Invalid %s: %v", what, err)
+	}
+	return &parser{what: what, tokens: tokens, ph: ph}, nil
+}
+
+// peek returns the next token, or the end once past it.
+func (p *parser) peek() token { return p.tokens[min(p.pos, len(p.tokens)-1)] }
+
+func (p *parser) next() token {
+	t := p.peek()
+	p.pos++
+	return t
+}
+
+// keyword consumes the next token if it is word, in any case.
+func (p *parser) keyword(word string) bool {
+	if t := p.peek(); t.kind == tokIdent && strings.EqualFold(t.text, word) {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+// isFunction reports whether a call of the named function comes next.
+func (p *parser) isFunction(name string) bool {
+	t := p.peek()
+	return t.kind == tokIdent && t.text == name && p.tokens[p.pos+1].kind == tokSymbol && p.tokens[p.pos+1].text == "("
+}
+
+func (p *parser) symbol(s string) bool {
+	if t := p.peek(); t.kind == tokSymbol && t.text == s {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *parser) expect(s string) error {
+	if !p.symbol(s) {
+		return p.syntaxError()
+	}
+	return nil
+}
+
+func (p *parser) syntaxError() error {
+	t := p.peek()
+	if t.kind == tokEnd {
+		return validation("Invalid %s: Syntax error; token: <EOF>", p.what)
+	}
+	return validation("Invalid %s: Syntax error; token: %q", p.what, t.text)
+}
+
+func (p *parser) end() error {
+	if p.peek().kind != tokEnd {
+		return p.syntaxError()
+	}
+	return nil
+}
+
+// pathElem is a step of a document path: an attribute name, or a list index when index >= 0.
+type pathElem struct {
+	name  string
+	index int
+}
+
+type path []pathElem
+
+func (p path) String() string {
+	var b strings.Builder
+	for i, e := range p {
+		switch {
+		case e.index >= 0:
+			fmt.Fprintf(&b, "[%d]", e.index)
+		case i > 0:
+			b.WriteString("." + e.name)
+		default:
+			b.WriteString(e.name)
+		}
+	}
+	return b.String()
+}
+
+// get returns the value at the path, or nil when there is none.
+func (p path) get(it item) types.AttributeValue {
+	var cur types.AttributeValue = &types.AttributeValueMemberM{Value: it}
+	for _, e := range p {
+		switch v := cur.(type) {
+		case *types.AttributeValueMemberM:
+			if e.index >= 0 {
+				return nil
+			}
+			cur = v.Value[e.name]
+		case *types.AttributeValueMemberL:
+			if e.index < 0 || e.index >= len(v.Value) {
+				return nil
+			}
+			cur = v.Value[e.index]
+		default:
+			return nil
+		}
+		if cur == nil {
+			return nil
+		}
+	}
+	return cur
+}
+
+func (p *parser) parsePath() (path, error) {
+	var pth path
+	for {
+		t := p.next()
+		switch t.kind {
+		case tokIdent:
+			pth = append(pth, pathElem{name: t.text, index: -1})
+		case tokName:
+			name, ok := p.ph.names[t.text]
+			p.ph.used[t.text] = true
+			if !ok {
+				return nil, validation("Invalid %s: An expression attribute name used in the document path is not defined; attribute name: %s", p.what, t.text)
+			}
+			pth = append(pth, pathElem{name: name, index: -1})
+		default:
+			p.pos--
+			return nil, p.syntaxError()
+		}
+		for p.symbol("[") {
+			t := p.next()
+			n, err := strconv.Atoi(t.text)
+			if t.kind != tokNumber || err != nil {
+				p.pos--
+				return nil, p.syntaxError()
+			}
+			pth = append(pth, pathElem{index: n})
+			if err := p.expect("]"); err != nil {
+				return nil, err
+			}
+		}
+		if !p.symbol(".") {
+			return pth, nil
+		}
+	}
+}
+
+// operand is a value in an expression. eval returns nil for a path the item does not have.
+type operand interface {
+	eval(it item) (types.AttributeValue, error)
+}
+
+type pathOperand path
+
+func (o pathOperand) eval(it item) (types.AttributeValue, error) { return path(o).get(it), nil }
+
+type valueOperand struct{ v types.AttributeValue }
+
+func (o valueOperand) eval(item) (types.AttributeValue, error) { return o.v, nil }
+
+type sizeOperand path
+
+func (o sizeOperand) eval(it item) (types.AttributeValue, error) {
+	var n int
+	switch v := path(o).get(it).(type) {
+	case nil:
+		return nil, nil
+	case *types.AttributeValueMemberS:
+		n = utf8.RuneCountInString(v.Value)
+	case *types.AttributeValueMemberB:
+		n = len(v.Value)
+	case *types.AttributeValueMemberL:
+		n = len(v.Value)
+	case *types.AttributeValueMemberM:
+		n = len(v.Value)
+	case *types.AttributeValueMemberSS, *types.AttributeValueMemberNS, *types.AttributeValueMemberBS:
+		n = len(setMembers(v))
+	default:
+		return nil, nil
+	}
+	return &types.AttributeValueMemberN{Value: strconv.Itoa(n)}, nil
+}
+
+func (p *parser) parseOperand() (operand, error) {
+	if t := p.peek(); t.kind == tokValue {
+		p.pos++
+		v, ok := p.ph.values[t.text]
+		p.ph.used[t.text] = true
+		if !ok {
+			return nil, validation("Invalid %s: An expression attribute value used in expression is not defined; attribute value: %s", p.what, t.text)
+		}
+		return valueOperand{v}, nil
+	}
+	if p.isFunction("size") {
+		p.pos += 2
+		pth, err := p.parsePath()
+		if err != nil {
+			return nil, err
+		}
+		return sizeOperand(pth), p.expect(")")
+	}
+	pth, err := p.parsePath()
+	return pathOperand(pth), err
+}
+
+// condition is a condition, filter or key condition expression.
+type condition interface {
+	test(it item) bool
+}
+
+type andCond struct{ a, b condition }
+
+func (c andCond) test(it item) bool { return c.a.test(it) && c.b.test(it) }
+
+type orCond struct{ a, b condition }
+
+func (c orCond) test(it item) bool { return c.a.test(it) || c.b.test(it) }
+
+type notCond struct{ c condition }
+
+func (c notCond) test(it item) bool { return !c.c.test(it) }
+
+type compareCond struct {
+	op          string
+	left, right operand
+}
+
+func (c compareCond) test(it item) bool {
+	a, _ := c.left.eval(it)
+	b, _ := c.right.eval(it)
+	if a == nil || b == nil {
+		// An attribute the item lacks differs from every value
+		return c.op == "<>"
+	}
+	switch c.op {
+	case "=":
+		return equal(a, b)
+	case "<>":
+		return !equal(a, b)
+	}
+	n, ok := compare(a, b)
+	if !ok {
+		return false
+	}
+	switch c.op {
+	case "<":
+		return n < 0
+	case "<=":
+		return n <= 0
+	case ">":
+		return n > 0
+	default:
+		return n >= 0
+	}
+}
+
+type betweenCond struct{ v, lo, hi operand }
+
+func (c betweenCond) test(it item) bool {
+	return compareCond{">=", c.v, c.lo}.test(it) && compareCond{"<=", c.v, c.hi}.test(it)
+}
+
+type inCond struct {
+	v    operand
+	list []operand
+}
+
+func (c inCond) test(it item) bool {
+	for _, o := range c.list {
+		if (compareCond{"=", c.v, o}).test(it) {
+			return true
+		}
+	}
+	return false
+}
+
+type funcCond struct {
+	name string
+	path path
+	arg  operand
+}
+
+func (c funcCond) test(it item) bool {
+	v := c.path.get(it)
+	var arg types.AttributeValue
+	if c.arg != nil {
+		arg, _ = c.arg.eval(it)
+	}
+	switch c.name {
+	case "attribute_exists":
+		return v != nil
+	case "attribute_not_exists":
+		return v == nil
+	case "attribute_type":
+		kind, ok := arg.(*types.AttributeValueMemberS)
+		return v != nil && ok && typeOf(v) == kind.Value
+	case "begins_with":
+		switch v := v.(type) {
+		case *types.AttributeValueMemberS:
+			prefix, ok := arg.(*types.AttributeValueMemberS)
+			return ok && strings.HasPrefix(v.Value, prefix.Value)
+		case *types.AttributeValueMemberB:
+			prefix, ok := arg.(*types.AttributeValueMemberB)
+			return ok && bytes.HasPrefix(v.Value, prefix.Value)
+		}
+	case "contains":
+		switch v := v.(type) {
+		case *types.AttributeValueMemberS:
+			sub, ok := arg.(*types.AttributeValueMemberS)
+			return ok && strings.Contains(v.Value, sub.Value)
+		case *types.AttributeValueMemberB:
+			sub, ok := arg.(*types.AttributeValueMemberB)
+			return ok && bytes.Contains(v.Value, sub.Value)
+		case *types.AttributeValueMemberSS, *types.AttributeValueMemberNS, *types.AttributeValueMemberBS:
+			return arg != nil && setContains(setMembers(v), arg)
+		case *types.AttributeValueMemberL:
+			for _, elem := range v.Value {
+				if arg != nil && equal(elem, arg) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// parseCondition parses a condition, filter or key condition expression.
+func parseCondition(what, expr string, ph *placeholders) (condition, error) {
+	p, err := newParser(what, expr, ph)
+	if err != nil {
+		return nil, err
+	}
+	c, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	return c, p.end()
+}
+
+func (p *parser) parseOr() (condition, error) {
+	c, err := p.parseAnd()
+	for err == nil && p.keyword("OR") {
+		var right condition
+		right, err = p.parseAnd()
+		c = orCond{c, right}
+	}
+	return c, err
+}
+
+func (p *parser) parseAnd() (condition, error) {
+	c, err := p.parseNot()
+	for err == nil && p.keyword("AND") {
+		var right condition
+		right, err = p.parseNot()
+		c = andCond{c, right}
+	}
+	return c, err
+}
+
+func (p *parser) parseNot() (condition, error) {
+	if p.keyword("NOT") {
+		c, err := p.parseNot()
+		return notCond{c}, err
+	}
+	return p.parseComparison()
+}
+
+var conditionFunctions = []string{"attribute_exists", "attribute_not_exists", "attribute_type", "begins_with", "contains"}
+
+func (p *parser) parseComparison() (condition, error) {
+	if p.symbol("(") {
+		c, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		return c, p.expect(")")
+	}
+	for _, name := range conditionFunctions {
+		if p.isFunction(name) {
+			p.pos += 2
+			return p.parseFunction(name)
+		}
+	}
+
+	left, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+	switch t := p.peek(); {
+	case t.kind == tokSymbol && (t.text == "=" || t.text == "<>" || t.text == "<" || t.text == "<=" || t.text == ">" || t.text == ">="):
+		p.pos++
+		right, err := p.parseOperand()
+		return compareCond{t.text, left, right}, err
+	case p.keyword("BETWEEN"):
+		lo, err := p.parseOperand()
+		if err != nil {
+			return nil, err
+		}
+		if !p.keyword("AND") {
+			return nil, p.syntaxError()
+		}
+		hi, err := p.parseOperand()
+		return betweenCond{left, lo, hi}, err
+	case p.keyword("IN"):
+		if err := p.expect("("); err != nil {
+			return nil, err
+		}
+		c := inCond{v: left}
+		for {
+			o, err := p.parseOperand()
+			if err != nil {
+				return nil, err
+			}
+			c.list = append(c.list, o)
+			if !p.symbol(",") {
+				break
+			}
+		}
+		return c, p.expect(")")
+	}
+	return nil, p.syntaxError()
+}
+
+func (p *parser) parseFunction(name string) (condition, error) {
+	pth, err := p.parsePath()
+	if err != nil {
+		return nil, err
+	}
+	c := funcCond{name: name, path: pth}
+	if name != "attribute_exists" && name != "attribute_not_exists" {
+		if err := p.expect(","); err != nil {
+			return nil, err
+		}
+		if c.arg, err = p.parseOperand(); err != nil {
+			return nil, err
+		}
+	}
+	return c, p.expect(")")
+}
+
+// keyConditionTerms checks a key condition names the partition key with = and at most the sort
+// key besides, as Query requires.
+func keyConditionTerms(c condition, hashKey, rangeKey string) error {
+	var terms []condition
+	var flatten func(c condition)
+	flatten = func(c condition) {
+		if and, ok := c.(andCond); ok {
+			flatten(and.a)
+			flatten(and.b)
+			return
+		}
+		terms = append(terms, c)
+	}
+	flatten(c)
+
+	keyOf := func(o operand) string {
+		if p, ok := o.(pathOperand); ok && len(p) == 1 && p[0].index < 0 {
+			return p[0].name
+		}
+		return ""
+	}
+	hashSeen, rangeSeen := false, false
+	for _, term := range terms {
+		var attr string
+		var ok bool
+		switch t := term.(type) {
+		case compareCond:
+			_, valueRight := t.right.(valueOperand)
+			attr, ok = keyOf(t.left), valueRight && t.op != "<>"
+			if attr == hashKey && t.op != "=" {
+				ok = false
+			}
+		case betweenCond:
+			_, lo := t.lo.(valueOperand)
+			_, hi := t.hi.(valueOperand)
+			attr, ok = keyOf(t.v), lo && hi && keyOf(t.v) != hashKey
+		case funcCond:
+			_, valueArg := t.arg.(valueOperand)
+			attr, ok = keyOf(pathOperand(t.path)), t.name == "begins_with" && valueArg && len(t.path) == 1
+			if attr == hashKey {
+				ok = false
+			}
+		}
+		switch {
+		case !ok:
+			return validation("Query key condition not supported")
+		case attr == hashKey && !hashSeen:
+			hashSeen = true
+		case attr == rangeKey && rangeKey != "" && !rangeSeen:
+			rangeSeen = true
+		default:
+			return validation("Query condition missed key schema element: %s", hashKey)
+		}
+	}
+	if !hashSeen {
+		return validation("Query condition missed key schema element: %s", hashKey)
+	}
+	return nil
+}
+
+// parseProjection parses a projection expression: paths separated by commas.
+func parseProjection(expr string, ph *placeholders) ([]path, error) {
+	p, err := newParser("ProjectionExpression", expr, ph)
+	if err != nil {
+		return nil, err
+	}
+	var paths []path
+	for {
+		pth, err := p.parsePath()
+		if err != nil {
+			return nil, err
+		}
+		paths = append(paths, pth)
+		if !p.symbol(",") {
+			return paths, p.end()
+		}
+	}
+}
+
+// project returns the attributes of it at paths. List elements keep their order but not their
+// positions, as in DynamoDB.
+func project(it item, paths []path) item {
+	out := item{}
+	for _, pth := range paths {
+		v := pth.get(it)
+		if v == nil {
+			continue
+		}
+		var parent types.AttributeValue = &types.AttributeValueMemberM{Value: out}
+		for i, e := range pth {
+			last := i == len(pth)-1
+			var child types.AttributeValue
+			if !last {
+				child = &types.AttributeValueMemberM{Value: item{}}
+				if pth[i+1].index >= 0 {
+					child = &types.AttributeValueMemberL{}
+				}
+			} else {
+				child = clone(v)
+			}
+			switch pv := parent.(type) {
+			case *types.AttributeValueMemberM:
+				if existing, ok := pv.Value[e.name]; ok && !last {
+					child = existing
+				} else {
+					pv.Value[e.name] = child
+				}
+			case *types.AttributeValueMemberL:
+				pv.Value = append(pv.Value, child)
+			}
+			parent = child
+		}
+	}
+	return out
+}
+
+// update is a parsed update expression.
+type update struct {
+	set    []assignment
+	remove []path
+	add    []assignment
+	delete []assignment
+}
+
+type assignment struct {
+	path  path
+	value operand
+}
+
+type arithOperand struct {
+	op          string
+	left, right operand
+}
+
+func (o arithOperand) eval(it item) (types.AttributeValue, error) {
+	a, err := evalExisting(o.left, it)
+	if err != nil {
+		return nil, err
+	}
+	b, err := evalExisting(o.right, it)
+	if err != nil {
+		return nil, err
+	}
+	x, okA := a.(*types.AttributeValueMemberN)
+	y, okB := b.(*types.AttributeValueMemberN)
+	if !okA || !okB {
+		return nil, validation("An operand in the update expression has an incorrect data type")
+	}
+	m, _ := number(x.Value)
+	n, _ := number(y.Value)
+	if o.op == "+" {
+		return &types.AttributeValueMemberN{Value: formatNumber(new(big.Rat).Add(m, n))}, nil
+	}
+	return &types.AttributeValueMemberN{Value: formatNumber(new(big.Rat).Sub(m, n))}, nil
+}
+
+type ifNotExistsOperand struct {
+	path     path
+	fallback operand
+}
+
+func (o ifNotExistsOperand) eval(it item) (types.AttributeValue, error) {
+	if v := o.path.get(it); v != nil {
+		return v, nil
+	}
+	return evalExisting(o.fallback, it)
+}
+
+type listAppendOperand struct{ a, b operand }
+
+func (o listAppendOperand) eval(it item) (types.AttributeValue, error) {
+	a, err := evalExisting(o.a, it)
+	if err != nil {
+		return nil, err
+	}
+	b, err := evalExisting(o.b, it)
+	if err != nil {
+		return nil, err
+	}
+	x, okA := a.(*types.AttributeValueMemberL)
+	y, okB := b.(*types.AttributeValueMemberL)
+	if !okA || !okB {
+		return nil, validation("An operand in the update expression has an incorrect data type")
+	}
+	return &types.AttributeValueMemberL{Value: append(append([]types.AttributeValue{}, x.Value...), y.Value...)}, nil
+}
+
+// evalExisting evaluates an operand of an update, which must exist.
+func evalExisting(o operand, it item) (types.AttributeValue, error) {
+	v, err := o.eval(it)
+	if err == nil && v == nil {
+		err = validation("The provided expression refers to an attribute that does not exist in the item")
+	}
+	return v, err
+}
+
+// parseUpdate parses an update expression: SET, REMOVE, ADD and DELETE clauses, each at most once.
+func parseUpdate(expr string, ph *placeholders) (*update, error) {
+	p, err := newParser("UpdateExpression", expr, ph)
+	if err != nil {
+		return nil, err
+	}
+	u := &update{}
+	seen := map[string]bool{}
+	for p.peek().kind != tokEnd {
+		t := p.next()
+		clause := strings.ToUpper(t.text)
+		if t.kind != tokIdent || seen[clause] {
+			p.pos--
+			return nil, p.syntaxError()
+		}
+		seen[clause] = true
+		for {
+			switch clause {
+			case "SET":
+				pth, err := p.parsePath()
+				if err != nil {
+					return nil, err
+				}
+				if err := p.expect("="); err != nil {
+					return nil, err
+				}
+				value, err := p.parseSetValue()
+				if err != nil {
+					return nil, err
+				}
+				u.set = append(u.set, assignment{pth, value})
+			case "REMOVE":
+				pth, err := p.parsePath()
+				if err != nil {
+					return nil, err
+				}
+				u.remove = append(u.remove, pth)
+			case "ADD", "DELETE":
+				pth, err := p.parsePath()
+				if err != nil {
+					return nil, err
+				}
+				value, err := p.parseOperand()
+				if err != nil {
+					return nil, err
+				}
+				if _, ok := value.(valueOperand); !ok {
+					return nil, p.syntaxError()
+				}
+				if clause == "ADD" {
+					u.add = append(u.add, assignment{pth, value})
+				} else {
+					u.delete = append(u.delete, assignment{pth, value})
+				}
+			default:
+				p.pos--
+				return nil, p.syntaxError()
+			}
+			if !p.symbol(",") {
+				break
+			}
+		}
+	}
+	return u, nil
+}
+
+func (p *parser) parseSetValue() (operand, error) {
+	left, err := p.parseSetTerm()
+	if err != nil {
+		return nil, err
+	}
+	for _, op := range []string{"+", "-"} {
+		if p.symbol(op) {
+			right, err := p.parseSetTerm()
+			return arithOperand{op, left, right}, err
+		}
+	}
+	return left, nil
+}
+
+func (p *parser) parseSetTerm() (operand, error) {
+	switch {
+	case p.isFunction("if_not_exists"):
+		p.pos += 2
+		pth, err := p.parsePath()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(","); err != nil {
+			return nil, err
+		}
+		fallback, err := p.parseSetValue()
+		if err != nil {
+			return nil, err
+		}
+		return ifNotExistsOperand{pth, fallback}, p.expect(")")
+	case p.isFunction("list_append"):
+		p.pos += 2
+		a, err := p.parseSetValue()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(","); err != nil {
+			return nil, err
+		}
+		b, err := p.parseSetValue()
+		if err != nil {
+			return nil, err
+		}
+		return listAppendOperand{a, b}, p.expect(")")
+	}
+	return p.parseOperand()
+}
+
+// paths returns every path the update changes.
+func (u *update) paths() []path {
+	var paths []path
+	for _, a := range u.set {
+		paths = append(paths, a.path)
+	}
+	paths = append(paths, u.remove...)
+	for _, a := range u.add {
+		paths = append(paths, a.path)
+	}
+	for _, a := range u.delete {
+		paths = append(paths, a.path)
+	}
+	return paths
+}
+
+// apply returns it changed by the update. Every operand reads it as it was before the update.
+func (u *update) apply(it item) (item, error) {
+	out := cloneItem(it)
+	for _, a := range u.set {
+		v, err := evalExisting(a.value, it)
+		if err != nil {
+			return nil, err
+		}
+		if err := setPath(out, a.path, clone(v)); err != nil {
+			return nil, err
+		}
+	}
+	for _, pth := range u.remove {
+		removePath(out, pth)
+	}
+	for _, a := range u.add {
+		v, _ := a.value.eval(it)
+		cur := a.path.get(it)
+		var next types.AttributeValue
+		switch {
+		case typeOf(v) == "N" && cur == nil:
+			next = v
+		case typeOf(v) == "N" && typeOf(cur) == "N":
+			var err error
+			if next, err = (arithOperand{"+", valueOperand{cur}, valueOperand{v}}).eval(it); err != nil {
+				return nil, err
+			}
+		case strings.HasSuffix(typeOf(v), "S") && cur == nil:
+			next = v
+		case strings.HasSuffix(typeOf(v), "S") && typeOf(cur) == typeOf(v):
+			members := setMembers(cur)
+			for _, m := range setMembers(v) {
+				if !setContains(members, m) {
+					members = append(members, m)
+				}
+			}
+			next = makeSet(typeOf(v), members)
+		default:
+			return nil, validation("An operand in the update expression has an incorrect data type")
+		}
+		if err := setPath(out, a.path, clone(next)); err != nil {
+			return nil, err
+		}
+	}
+	for _, a := range u.delete {
+		v, _ := a.value.eval(it)
+		cur := a.path.get(it)
+		if cur == nil {
+			continue
+		}
+		if !strings.HasSuffix(typeOf(v), "S") || typeOf(cur) != typeOf(v) {
+			return nil, validation("An operand in the update expression has an incorrect data type")
+		}
+		removed := setMembers(v)
+		var members []types.AttributeValue
+		for _, m := range setMembers(cur) {
+			if !setContains(removed, m) {
+				members = append(members, m)
+			}
+		}
+		if len(members) == 0 {
+			removePath(out, a.path)
+			continue
+		}
+		if err := setPath(out, a.path, makeSet(typeOf(v), members)); err != nil {
+			return nil, err
+		}
+	}
+	return out, nil
+}
+
+// setPath stores v at the path. Maps and lists on the way must exist; an index past the end of a
+// list appends.
+func setPath(it item, pth path, v types.AttributeValue) error {
+	invalid := validation("The document path provided in the update expression is invalid for update")
+	var parent types.AttributeValue = &types.AttributeValueMemberM{Value: it}
+	for i, e := range pth {
+		last := i == len(pth)-1
+		switch pv := parent.(type) {
+		case *types.AttributeValueMemberM:
+			if e.index >= 0 {
+				return invalid
+			}
+			if last {
+				pv.Value[e.name] = v
+				return nil
+			}
+			parent = pv.Value[e.name]
+		case *types.AttributeValueMemberL:
+			if e.index < 0 {
+				return invalid
+			}
+			if last {
+				if e.index < len(pv.Value) {
+					pv.Value[e.index] = v
+				} else {
+					pv.Value = append(pv.Value, v)
+				}
+				return nil
+			}
+			if e.index >= len(pv.Value) {
+				return invalid
+			}
+			parent = pv.Value[e.index]
+		default:
+			return invalid
+		}
+	}
+	return invalid
+}
+
+func removePath(it item, pth path) {
+	parent := path(pth[:len(pth)-1]).get(it)
+	if len(pth) == 1 {
+		parent = &types.AttributeValueMemberM{Value: it}
+	}
+	last := pth[len(pth)-1]
+	switch pv := parent.(type) {
+	case *types.AttributeValueMemberM:
+		delete(pv.Value, last.name)
+	case *types.AttributeValueMemberL:
+		if last.index >= 0 && last.index < len(pv.Value) {
+			pv.Value = append(pv.Value[:last.index], pv.Value[last.index+1:]...)
+		}
+	}
+}
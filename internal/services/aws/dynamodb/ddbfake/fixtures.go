@@ -0,0 +1,87 @@
+package ddbfake
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsdynamodb "github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// fixtureTable is a table of a fixture file: the fields of a CreateTable request and the table's
+// items in DynamoDB JSON.
+type fixtureTable struct {
+	TableName              string
+	KeySchema              []types.KeySchemaElement
+	AttributeDefinitions   []types.AttributeDefinition
+	GlobalSecondaryIndexes []types.GlobalSecondaryIndex
+	LocalSecondaryIndexes  []types.LocalSecondaryIndex
+	BillingMode            types.BillingMode
+	ProvisionedThroughput  *types.ProvisionedThroughput
+	Items                  []json.RawMessage
+}
+
+// LoadFixtures creates the tables of a fixture and puts their items. A fixture is a JSON array of
+// tables, each with the fields of `aws dynamodb create-table --cli-input-json` and its items as
+// `aws dynamodb scan` prints them:
+//
+//	[{
+//	  "TableName": "users",
+//	  "KeySchema": [{"AttributeName": "email", "KeyType": "HASH"}],
+//	  "AttributeDefinitions": [{"AttributeName": "email", "AttributeType": "S"}],
+//	  "Items": [{"email": {"S": "ada@example.com"}, "logins": {"N": "3"}}]
+//	}]
+func (c *Client) LoadFixtures(r io.Reader) error {
+	var tables []fixtureTable
+	dec := json.NewDecoder(r)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&tables); err != nil {
+		return fmt.Errorf("invalid fixture: %w", err)
+	}
+
+	ctx := context.Background()
+	for _, fixture := range tables {
+		if fixture.BillingMode == "" && fixture.ProvisionedThroughput == nil {
+			fixture.BillingMode = types.BillingModePayPerRequest
+		}
+		_, err := c.CreateTable(ctx, &awsdynamodb.CreateTableInput{
+			TableName:              aws.String(fixture.TableName),
+			KeySchema:              fixture.KeySchema,
+			AttributeDefinitions:   fixture.AttributeDefinitions,
+			GlobalSecondaryIndexes: fixture.GlobalSecondaryIndexes,
+			LocalSecondaryIndexes:  fixture.LocalSecondaryIndexes,
+			BillingMode:            fixture.BillingMode,
+			ProvisionedThroughput:  fixture.ProvisionedThroughput,
+		})
+		if err != nil {
+			return fmt.Errorf("table %s: %w", fixture.TableName, err)
+		}
+		for i, raw := range fixture.Items {
+			it, err := decodeItem(raw)
+			if err != nil {
+				return fmt.Errorf("table %s: item %d: %w", fixture.TableName, i, err)
+			}
+			if _, err := c.PutItem(ctx, &awsdynamodb.PutItemInput{TableName: aws.String(fixture.TableName), Item: it}); err != nil {
+				return fmt.Errorf("table %s: item %d: %w", fixture.TableName, i, err)
+			}
+		}
+	}
+	return nil
+}
+
+// LoadFixtureFile loads the fixture saved at path, such as deployments/local/data/dynamodb-tables.json.
+func (c *Client) LoadFixtureFile(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if err := c.LoadFixtures(f); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	return nil
+}
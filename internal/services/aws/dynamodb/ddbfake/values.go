@@ -0,0 +1,340 @@
+package ddbfake
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"slices"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// typeOf is the DynamoDB type name of a value: S, N, B, BOOL, NULL, SS, NS, BS, L or M.
+func typeOf(av types.AttributeValue) string {
+	switch av.(type) {
+	case *types.AttributeValueMemberS:
+		return "S"
+	case *types.AttributeValueMemberN:
+		return "N"
+	case *types.AttributeValueMemberB:
+		return "B"
+	case *types.AttributeValueMemberBOOL:
+		return "BOOL"
+	case *types.AttributeValueMemberNULL:
+		return "NULL"
+	case *types.AttributeValueMemberSS:
+		return "SS"
+	case *types.AttributeValueMemberNS:
+		return "NS"
+	case *types.AttributeValueMemberBS:
+		return "BS"
+	case *types.AttributeValueMemberL:
+		return "L"
+	case *types.AttributeValueMemberM:
+		return "M"
+	}
+	return ""
+}
+
+// number parses a DynamoDB number, which has up to 38 digits of precision.
+func number(s string) (*big.Rat, bool) {
+	return new(big.Rat).SetString(s)
+}
+
+// formatNumber writes a number as DynamoDB does: without an exponent or trailing zeros.
+func formatNumber(r *big.Rat) string {
+	if r.IsInt() {
+		return r.Num().String()
+	}
+	s := strings.TrimRight(r.FloatString(38), "0")
+	return strings.TrimSuffix(s, ".")
+}
+
+// compare orders two scalars of the same type: strings and binaries by their bytes, numbers by
+// value. It reports false for other types, which only compare equal or not.
+func compare(a, b types.AttributeValue) (int, bool) {
+	switch a := a.(type) {
+	case *types.AttributeValueMemberS:
+		if b, ok := b.(*types.AttributeValueMemberS); ok {
+			return strings.Compare(a.Value, b.Value), true
+		}
+	case *types.AttributeValueMemberN:
+		if b, ok := b.(*types.AttributeValueMemberN); ok {
+			x, okA := number(a.Value)
+			y, okB := number(b.Value)
+			if okA && okB {
+				return x.Cmp(y), true
+			}
+		}
+	case *types.AttributeValueMemberB:
+		if b, ok := b.(*types.AttributeValueMemberB); ok {
+			return bytes.Compare(a.Value, b.Value), true
+		}
+	}
+	return 0, false
+}
+
+// equal reports whether two values are the same: of one type, numbers by value, sets regardless
+// of order, lists and maps element by element.
+func equal(a, b types.AttributeValue) bool {
+	if c, ok := compare(a, b); ok {
+		return c == 0
+	}
+	switch a := a.(type) {
+	case *types.AttributeValueMemberBOOL:
+		b, ok := b.(*types.AttributeValueMemberBOOL)
+		return ok && a.Value == b.Value
+	case *types.AttributeValueMemberNULL:
+		_, ok := b.(*types.AttributeValueMemberNULL)
+		return ok
+	case *types.AttributeValueMemberSS, *types.AttributeValueMemberNS, *types.AttributeValueMemberBS:
+		if typeOf(a) != typeOf(b) {
+			return false
+		}
+		x, y := setMembers(a), setMembers(b)
+		return len(x) == len(y) && !slices.ContainsFunc(x, func(m types.AttributeValue) bool { return !setContains(y, m) })
+	case *types.AttributeValueMemberL:
+		b, ok := b.(*types.AttributeValueMemberL)
+		return ok && slices.EqualFunc(a.Value, b.Value, equal)
+	case *types.AttributeValueMemberM:
+		b, ok := b.(*types.AttributeValueMemberM)
+		if !ok || len(a.Value) != len(b.Value) {
+			return false
+		}
+		for name, v := range a.Value {
+			if w, ok := b.Value[name]; !ok || !equal(v, w) {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// setMembers returns the members of a set as scalars.
+func setMembers(av types.AttributeValue) []types.AttributeValue {
+	var members []types.AttributeValue
+	switch v := av.(type) {
+	case *types.AttributeValueMemberSS:
+		for _, s := range v.Value {
+			members = append(members, &types.AttributeValueMemberS{Value: s})
+		}
+	case *types.AttributeValueMemberNS:
+		for _, n := range v.Value {
+			members = append(members, &types.AttributeValueMemberN{Value: n})
+		}
+	case *types.AttributeValueMemberBS:
+		for _, b := range v.Value {
+			members = append(members, &types.AttributeValueMemberB{Value: b})
+		}
+	}
+	return members
+}
+
+func setContains(members []types.AttributeValue, m types.AttributeValue) bool {
+	return slices.ContainsFunc(members, func(x types.AttributeValue) bool { return equal(x, m) })
+}
+
+// makeSet builds a set of the given type (SS, NS or BS) from scalars.
+func makeSet(kind string, members []types.AttributeValue) types.AttributeValue {
+	switch kind {
+	case "SS":
+		set := &types.AttributeValueMemberSS{}
+		for _, m := range members {
+			set.Value = append(set.Value, m.(*types.AttributeValueMemberS).Value)
+		}
+		return set
+	case "NS":
+		set := &types.AttributeValueMemberNS{}
+		for _, m := range members {
+			set.Value = append(set.Value, m.(*types.AttributeValueMemberN).Value)
+		}
+		return set
+	default:
+		set := &types.AttributeValueMemberBS{}
+		for _, m := range members {
+			set.Value = append(set.Value, m.(*types.AttributeValueMemberB).Value)
+		}
+		return set
+	}
+}
+
+// clone copies a value deeply, so that items handed out and items stored never share memory.
+func clone(av types.AttributeValue) types.AttributeValue {
+	switch v := av.(type) {
+	case *types.AttributeValueMemberS:
+		return &types.AttributeValueMemberS{Value: v.Value}
+	case *types.AttributeValueMemberN:
+		return &types.AttributeValueMemberN{Value: v.Value}
+	case *types.AttributeValueMemberB:
+		return &types.AttributeValueMemberB{Value: bytes.Clone(v.Value)}
+	case *types.AttributeValueMemberBOOL:
+		return &types.AttributeValueMemberBOOL{Value: v.Value}
+	case *types.AttributeValueMemberNULL:
+		return &types.AttributeValueMemberNULL{Value: v.Value}
+	case *types.AttributeValueMemberSS:
+		return &types.AttributeValueMemberSS{Value: slices.Clone(v.Value)}
+	case *types.AttributeValueMemberNS:
+		return &types.AttributeValueMemberNS{Value: slices.Clone(v.Value)}
+	case *types.AttributeValueMemberBS:
+		set := &types.AttributeValueMemberBS{Value: make([][]byte, len(v.Value))}
+		for i, b := range v.Value {
+			set.Value[i] = bytes.Clone(b)
+		}
+		return set
+	case *types.AttributeValueMemberL:
+		list := &types.AttributeValueMemberL{Value: make([]types.AttributeValue, len(v.Value))}
+		for i, elem := range v.Value {
+			list.Value[i] = clone(elem)
+		}
+		return list
+	case *types.AttributeValueMemberM:
+		return &types.AttributeValueMemberM{Value: cloneItem(v.Value)}
+	}
+	return av
+}
+
+func cloneItem(item map[string]types.AttributeValue) map[string]types.AttributeValue {
+	if item == nil {
+		return nil
+	}
+	out := make(map[string]types.AttributeValue, len(item))
+	for name, v := range item {
+		out[name] = clone(v)
+	}
+	return out
+}
+
+// itemSize approximates the stored size of an item: its names and values.
+func itemSize(item map[string]types.AttributeValue) int64 {
+	var size int64
+	for name, v := range item {
+		size += int64(len(name)) + valueSize(v)
+	}
+	return size
+}
+
+func valueSize(av types.AttributeValue) int64 {
+	switch v := av.(type) {
+	case *types.AttributeValueMemberS:
+		return int64(len(v.Value))
+	case *types.AttributeValueMemberN:
+		return int64(len(v.Value)/2 + 1)
+	case *types.AttributeValueMemberB:
+		return int64(len(v.Value))
+	case *types.AttributeValueMemberL:
+		size := int64(3)
+		for _, elem := range v.Value {
+			size += 1 + valueSize(elem)
+		}
+		return size
+	case *types.AttributeValueMemberM:
+		return 3 + itemSize(v.Value)
+	default:
+		var size int64 = 1
+		for _, m := range setMembers(av) {
+			size += valueSize(m)
+		}
+		return size
+	}
+}
+
+// decodeItem reads an item written in DynamoDB JSON, as the AWS CLI prints and takes it:
+// {"id": {"S": "a-1"}, "total": {"N": "12.5"}}.
+func decodeItem(data json.RawMessage) (map[string]types.AttributeValue, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	item := make(map[string]types.AttributeValue, len(fields))
+	for name, raw := range fields {
+		v, err := decodeValue(raw)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		item[name] = v
+	}
+	return item, nil
+}
+
+func decodeValue(data json.RawMessage) (types.AttributeValue, error) {
+	var typed map[string]json.RawMessage
+	if err := json.Unmarshal(data, &typed); err != nil {
+		return nil, err
+	}
+	if len(typed) != 1 {
+		return nil, fmt.Errorf("a value must have exactly one type, such as {\"S\": \"text\"}")
+	}
+	for kind, raw := range typed {
+		switch kind {
+		case "S":
+			var s string
+			err := json.Unmarshal(raw, &s)
+			return &types.AttributeValueMemberS{Value: s}, err
+		case "N":
+			var n string
+			if err := json.Unmarshal(raw, &n); err != nil {
+				return nil, err
+			}
+			if _, ok := number(n); !ok {
+				return nil, fmt.Errorf("%q is not a number", n)
+			}
+			return &types.AttributeValueMemberN{Value: n}, nil
+		case "B":
+			var b []byte // base64, as encoding/json reads []byte
+			err := json.Unmarshal(raw, &b)
+			return &types.AttributeValueMemberB{Value: b}, err
+		case "BOOL":
+			var b bool
+			err := json.Unmarshal(raw, &b)
+			return &types.AttributeValueMemberBOOL{Value: b}, err
+		case "NULL":
+			return &types.AttributeValueMemberNULL{Value: true}, nil
+		case "SS":
+			var ss []string
+			err := json.Unmarshal(raw, &ss)
+			return &types.AttributeValueMemberSS{Value: ss}, err
+		case "NS":
+			var ns []string
+			err := json.Unmarshal(raw, &ns)
+			return &types.AttributeValueMemberNS{Value: ns}, err
+		case "BS":
+			var encoded []string
+			if err := json.Unmarshal(raw, &encoded); err != nil {
+				return nil, err
+			}
+			set := &types.AttributeValueMemberBS{}
+			for _, e := range encoded {
+				b, err := base64.StdEncoding.DecodeString(e)
+				if err != nil {
+					return nil, err
+				}
+				set.Value = append(set.Value, b)
+			}
+			return set, nil
+		case "L":
+			var elems []json.RawMessage
+			if err := json.Unmarshal(raw, &elems); err != nil {
+				return nil, err
+			}
+			list := &types.AttributeValueMemberL{Value: make([]types.AttributeValue, len(elems))}
+			for i, elem := range elems {
+				v, err := decodeValue(elem)
+				if err != nil {
+					return nil, fmt.Errorf("[%d]: %w", i, err)
+				}
+				list.Value[i] = v
+			}
+			return list, nil
+		case "M":
+			m, err := decodeItem(raw)
+			return &types.AttributeValueMemberM{Value: m}, err
+		default:
+			return nil, fmt.Errorf("unknown type %q", kind)
+		}
+	}
+	panic("unreachable")
+}
@@ -2,34 +2,92 @@ package dynamodb
 
 import (
 	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	awsdynamodb "github.com/aws/aws-sdk-go-v2/service/dynamodb"
 	dynamodbtypes "github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/tpelletiersophos/cloudcutter/internal/diagnostics"
+	awsservice "github.com/tpelletiersophos/cloudcutter/internal/services/aws"
 )
 
 type Interface interface {
 	ListTables(ctx context.Context) ([]string, error)
 	DescribeTable(ctx context.Context, tableName string) (*dynamodbtypes.TableDescription, error)
 	ScanTable(ctx context.Context, tableName string) ([]map[string]dynamodbtypes.AttributeValue, error)
+	Scan(ctx context.Context, tableName string, limit int) ([]map[string]dynamodbtypes.AttributeValue, error)
+	Query(ctx context.Context, tableName, indexName string, key map[string]dynamodbtypes.AttributeValue, limit int) ([]map[string]dynamodbtypes.AttributeValue, error)
+	GetItem(ctx context.Context, tableName string, key map[string]dynamodbtypes.AttributeValue) (map[string]dynamodbtypes.AttributeValue, error)
+}
+
+// Client is the part of the DynamoDB API the service calls. *dynamodb.Client implements it, and so
+// does the in-memory ddbfake.Client.
+type Client interface {
+	ListTables(ctx context.Context, params *awsdynamodb.ListTablesInput, optFns ...func(*awsdynamodb.Options)) (*awsdynamodb.ListTablesOutput, error)
+	DescribeTable(ctx context.Context, params *awsdynamodb.DescribeTableInput, optFns ...func(*awsdynamodb.Options)) (*awsdynamodb.DescribeTableOutput, error)
+	Scan(ctx context.Context, params *awsdynamodb.ScanInput, optFns ...func(*awsdynamodb.Options)) (*awsdynamodb.ScanOutput, error)
+	Query(ctx context.Context, params *awsdynamodb.QueryInput, optFns ...func(*awsdynamodb.Options)) (*awsdynamodb.QueryOutput, error)
+	GetItem(ctx context.Context, params *awsdynamodb.GetItemInput, optFns ...func(*awsdynamodb.Options)) (*awsdynamodb.GetItemOutput, error)
 }
 
 type Service struct {
-	client *awsdynamodb.Client
+	client      Client
+	credentials aws.CredentialsProvider
+}
+
+var (
+	localMu     sync.RWMutex
+	localClient Client
+)
+
+// UseLocalClient makes every service created from now on for the local region use client, such
+// as an in-memory fake, instead of calling DynamoDB. A nil client restores DynamoDB.
+func UseLocalClient(client Client) {
+	localMu.Lock()
+	defer localMu.Unlock()
+	localClient = client
 }
 
 func NewService(cfg aws.Config) Interface {
+	if cfg.Region == "local" {
+		localMu.RLock()
+		client := localClient
+		localMu.RUnlock()
+		if client != nil {
+			return NewServiceWithClient(client)
+		}
+	}
 	return &Service{
-		client: awsdynamodb.NewFromConfig(cfg),
+		client: awsdynamodb.NewFromConfig(cfg, func(o *awsdynamodb.Options) {
+			o.HTTPClient = diagnostics.WrapClient("dynamodb", o.HTTPClient)
+		}),
+		credentials: cfg.Credentials,
 	}
 }
 
+// NewServiceWithClient returns a service that calls client as it is, such as an in-memory fake.
+func NewServiceWithClient(client Client) Interface {
+	return &Service{client: client}
+}
+
+// call runs op, re-authenticating and retrying it once if the session's credentials expired.
+func (s *Service) call(ctx context.Context, op func() error) error {
+	return awsservice.RetryOnExpiredToken(ctx, s.credentials, op)
+}
+
 func (s *Service) ListTables(ctx context.Context) ([]string, error) {
 	var tableNames []string
 	paginator := awsdynamodb.NewListTablesPaginator(s.client, &awsdynamodb.ListTablesInput{})
 
 	for paginator.HasMorePages() {
-		output, err := paginator.NextPage(ctx)
+		var output *awsdynamodb.ListTablesOutput
+		err := s.call(ctx, func() (err error) {
+			output, err = paginator.NextPage(ctx)
+			return err
+		})
 		if err != nil {
 			return nil, err
 		}
@@ -39,8 +97,12 @@ func (s *Service) ListTables(ctx context.Context) ([]string, error) {
 }
 
 func (s *Service) DescribeTable(ctx context.Context, tableName string) (*dynamodbtypes.TableDescription, error) {
-	output, err := s.client.DescribeTable(ctx, &awsdynamodb.DescribeTableInput{
-		TableName: aws.String(tableName),
+	var output *awsdynamodb.DescribeTableOutput
+	err := s.call(ctx, func() (err error) {
+		output, err = s.client.DescribeTable(ctx, &awsdynamodb.DescribeTableInput{
+			TableName: aws.String(tableName),
+		})
+		return err
 	})
 	if err != nil {
 		return nil, err
@@ -57,7 +119,11 @@ func (s *Service) ScanTable(ctx context.Context, tableName string) ([]map[string
 	paginator := awsdynamodb.NewScanPaginator(s.client, input)
 
 	for paginator.HasMorePages() {
-		output, err := paginator.NextPage(ctx)
+		var output *awsdynamodb.ScanOutput
+		err := s.call(ctx, func() (err error) {
+			output, err = paginator.NextPage(ctx)
+			return err
+		})
 		if err != nil {
 			return nil, err
 		}
@@ -65,3 +131,97 @@ func (s *Service) ScanTable(ctx context.Context, tableName string) ([]map[string
 	}
 	return items, nil
 }
+
+// Scan reads up to limit items from the table, or every item when limit is zero.
+func (s *Service) Scan(ctx context.Context, tableName string, limit int) ([]map[string]dynamodbtypes.AttributeValue, error) {
+	input := &awsdynamodb.ScanInput{TableName: aws.String(tableName)}
+	if limit > 0 {
+		input.Limit = aws.Int32(int32(min(limit, 1000)))
+	}
+	paginator := awsdynamodb.NewScanPaginator(s.client, input)
+
+	var items []map[string]dynamodbtypes.AttributeValue
+	for paginator.HasMorePages() && (limit == 0 || len(items) < limit) {
+		var output *awsdynamodb.ScanOutput
+		err := s.call(ctx, func() (err error) {
+			output, err = paginator.NextPage(ctx)
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, output.Items...)
+	}
+	return truncateItems(items, limit), nil
+}
+
+// Query reads up to limit items whose key attributes equal key, from the table or from one of
+// its secondary indexes when indexName is set. key holds the partition key and optionally the sort key.
+func (s *Service) Query(ctx context.Context, tableName, indexName string, key map[string]dynamodbtypes.AttributeValue, limit int) ([]map[string]dynamodbtypes.AttributeValue, error) {
+	if len(key) == 0 {
+		return nil, fmt.Errorf("query needs a partition key value")
+	}
+
+	names := make([]string, 0, len(key))
+	for name := range key {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	input := &awsdynamodb.QueryInput{
+		TableName:                 aws.String(tableName),
+		ExpressionAttributeNames:  make(map[string]string, len(key)),
+		ExpressionAttributeValues: make(map[string]dynamodbtypes.AttributeValue, len(key)),
+	}
+	conditions := make([]string, len(names))
+	for i, name := range names {
+		input.ExpressionAttributeNames[fmt.Sprintf("#k%d", i)] = name
+		input.ExpressionAttributeValues[fmt.Sprintf(":v%d", i)] = key[name]
+		conditions[i] = fmt.Sprintf("#k%d = :v%d", i, i)
+	}
+	input.KeyConditionExpression = aws.String(strings.Join(conditions, " AND "))
+	if indexName != "" {
+		input.IndexName = aws.String(indexName)
+	}
+	if limit > 0 {
+		input.Limit = aws.Int32(int32(min(limit, 1000)))
+	}
+	paginator := awsdynamodb.NewQueryPaginator(s.client, input)
+
+	var items []map[string]dynamodbtypes.AttributeValue
+	for paginator.HasMorePages() && (limit == 0 || len(items) < limit) {
+		var output *awsdynamodb.QueryOutput
+		err := s.call(ctx, func() (err error) {
+			output, err = paginator.NextPage(ctx)
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, output.Items...)
+	}
+	return truncateItems(items, limit), nil
+}
+
+// GetItem reads the item with the given primary key, or nil when there is none.
+func (s *Service) GetItem(ctx context.Context, tableName string, key map[string]dynamodbtypes.AttributeValue) (map[string]dynamodbtypes.AttributeValue, error) {
+	var output *awsdynamodb.GetItemOutput
+	err := s.call(ctx, func() (err error) {
+		output, err = s.client.GetItem(ctx, &awsdynamodb.GetItemInput{
+			TableName: aws.String(tableName),
+			Key:       key,
+		})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return output.Item, nil
+}
+
+func truncateItems(items []map[string]dynamodbtypes.AttributeValue, limit int) []map[string]dynamodbtypes.AttributeValue {
+	if limit > 0 && len(items) > limit {
+		return items[:limit]
+	}
+	return items
+}
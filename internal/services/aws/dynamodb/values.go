@@ -0,0 +1,84 @@
+package dynamodb
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	dynamodbtypes "github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// KeyFromStrings turns key attribute values given as text into attribute values, typed by the
+// table's attribute definitions. Binary values are base64.
+func KeyFromStrings(table *dynamodbtypes.TableDescription, values map[string]string) (map[string]dynamodbtypes.AttributeValue, error) {
+	types := make(map[string]dynamodbtypes.ScalarAttributeType, len(table.AttributeDefinitions))
+	for _, def := range table.AttributeDefinitions {
+		types[*def.AttributeName] = def.AttributeType
+	}
+
+	key := make(map[string]dynamodbtypes.AttributeValue, len(values))
+	for name, value := range values {
+		switch types[name] {
+		case dynamodbtypes.ScalarAttributeTypeS:
+			key[name] = &dynamodbtypes.AttributeValueMemberS{Value: value}
+		case dynamodbtypes.ScalarAttributeTypeN:
+			if _, err := strconv.ParseFloat(value, 64); err != nil {
+				return nil, fmt.Errorf("key %s must be a number, got %q", name, value)
+			}
+			key[name] = &dynamodbtypes.AttributeValueMemberN{Value: value}
+		case dynamodbtypes.ScalarAttributeTypeB:
+			data, err := base64.StdEncoding.DecodeString(value)
+			if err != nil {
+				return nil, fmt.Errorf("key %s must be base64: %w", name, err)
+			}
+			key[name] = &dynamodbtypes.AttributeValueMemberB{Value: data}
+		default:
+			return nil, fmt.Errorf("%s is not a key attribute of %s", name, *table.TableName)
+		}
+	}
+	return key, nil
+}
+
+// ItemToMap converts an item into plain values that encode naturally as JSON. Numbers stay
+// json.Number so they keep their precision.
+func ItemToMap(item map[string]dynamodbtypes.AttributeValue) map[string]any {
+	out := make(map[string]any, len(item))
+	for name, value := range item {
+		out[name] = attributeToAny(value)
+	}
+	return out
+}
+
+func attributeToAny(av dynamodbtypes.AttributeValue) any {
+	switch v := av.(type) {
+	case *dynamodbtypes.AttributeValueMemberS:
+		return v.Value
+	case *dynamodbtypes.AttributeValueMemberN:
+		return json.Number(v.Value)
+	case *dynamodbtypes.AttributeValueMemberBOOL:
+		return v.Value
+	case *dynamodbtypes.AttributeValueMemberB:
+		return v.Value
+	case *dynamodbtypes.AttributeValueMemberSS:
+		return v.Value
+	case *dynamodbtypes.AttributeValueMemberNS:
+		numbers := make([]json.Number, len(v.Value))
+		for i, n := range v.Value {
+			numbers[i] = json.Number(n)
+		}
+		return numbers
+	case *dynamodbtypes.AttributeValueMemberBS:
+		return v.Value
+	case *dynamodbtypes.AttributeValueMemberM:
+		return ItemToMap(v.Value)
+	case *dynamodbtypes.AttributeValueMemberL:
+		list := make([]any, len(v.Value))
+		for i, elem := range v.Value {
+			list[i] = attributeToAny(elem)
+		}
+		return list
+	default:
+		return nil
+	}
+}
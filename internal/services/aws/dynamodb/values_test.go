@@ -0,0 +1,63 @@
+package dynamodb
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	dynamodbtypes "github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestItemToMap(t *testing.T) {
+	item := map[string]dynamodbtypes.AttributeValue{
+		"id":      &dynamodbtypes.AttributeValueMemberS{Value: "a"},
+		"count":   &dynamodbtypes.AttributeValueMemberN{Value: "12345678901234567890"},
+		"active":  &dynamodbtypes.AttributeValueMemberBOOL{Value: true},
+		"deleted": &dynamodbtypes.AttributeValueMemberNULL{Value: true},
+		"tags":    &dynamodbtypes.AttributeValueMemberSS{Value: []string{"x", "y"}},
+		"scores":  &dynamodbtypes.AttributeValueMemberNS{Value: []string{"1", "2.5"}},
+		"address": &dynamodbtypes.AttributeValueMemberM{Value: map[string]dynamodbtypes.AttributeValue{
+			"city": &dynamodbtypes.AttributeValueMemberS{Value: "Ottawa"},
+		}},
+		"history": &dynamodbtypes.AttributeValueMemberL{Value: []dynamodbtypes.AttributeValue{
+			&dynamodbtypes.AttributeValueMemberN{Value: "1"},
+			&dynamodbtypes.AttributeValueMemberS{Value: "two"},
+		}},
+	}
+
+	data, err := json.Marshal(ItemToMap(item))
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{
+		"id": "a", "count": 12345678901234567890, "active": true, "deleted": null,
+		"tags": ["x", "y"], "scores": [1, 2.5], "address": {"city": "Ottawa"}, "history": [1, "two"]
+	}`, string(data))
+}
+
+func TestKeyFromStrings(t *testing.T) {
+	table := &dynamodbtypes.TableDescription{
+		TableName: aws.String("files"),
+		AttributeDefinitions: []dynamodbtypes.AttributeDefinition{
+			{AttributeName: aws.String("owner"), AttributeType: dynamodbtypes.ScalarAttributeTypeS},
+			{AttributeName: aws.String("version"), AttributeType: dynamodbtypes.ScalarAttributeTypeN},
+			{AttributeName: aws.String("hash"), AttributeType: dynamodbtypes.ScalarAttributeTypeB},
+		},
+	}
+
+	key, err := KeyFromStrings(table, map[string]string{"owner": "me", "version": "3", "hash": "aGk="})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]dynamodbtypes.AttributeValue{
+		"owner":   &dynamodbtypes.AttributeValueMemberS{Value: "me"},
+		"version": &dynamodbtypes.AttributeValueMemberN{Value: "3"},
+		"hash":    &dynamodbtypes.AttributeValueMemberB{Value: []byte("hi")},
+	}, key)
+
+	for _, values := range []map[string]string{
+		{"version": "three"},
+		{"hash": "not base64!"},
+		{"size": "10"},
+	} {
+		_, err := KeyFromStrings(table, values)
+		assert.Error(t, err, "%v", values)
+	}
+}
@@ -0,0 +1,70 @@
+package elastic
+
+import (
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// ClusterConfig says where the Elasticsearch cluster for each region and profile lives.
+type ClusterConfig struct {
+	Endpoint      string   `yaml:"endpoint"`       // {env} and {region} are filled in per session
+	LocalEndpoint string   `yaml:"local_endpoint"` // used for the local region
+	ProdProfiles  []string `yaml:"prod_profiles"`  // profiles whose {env} is prod; every other is dev
+}
+
+var (
+	clusterMu sync.RWMutex
+	clusters  = DefaultClusterConfig()
+)
+
+// DefaultClusterConfig returns the primary clusters.
+func DefaultClusterConfig() ClusterConfig {
+	return ClusterConfig{
+		Endpoint:      "https://{env}-{region}-primary-es.darkbytes.io",
+		LocalEndpoint: "http://localhost:9200",
+		ProdProfiles:  []string{"opal_prod"},
+	}
+}
+
+// Validate checks both endpoints are URLs and the template names the region.
+func (c ClusterConfig) Validate() error {
+	if !strings.Contains(c.Endpoint, "{region}") {
+		return fmt.Errorf("endpoint %q must contain {region}", c.Endpoint)
+	}
+	for name, endpoint := range map[string]string{"endpoint": c.Endpoint, "local_endpoint": c.LocalEndpoint} {
+		u, err := url.Parse(strings.NewReplacer("{env}", "dev", "{region}", "local").Replace(endpoint))
+		if err != nil || u.Scheme == "" || u.Host == "" {
+			return fmt.Errorf("%s %q is not an http(s) URL", name, endpoint)
+		}
+	}
+	return nil
+}
+
+// UseClusterConfig validates c and makes every client created from now on use it.
+func UseClusterConfig(c ClusterConfig) error {
+	if err := c.Validate(); err != nil {
+		return err
+	}
+	clusterMu.Lock()
+	defer clusterMu.Unlock()
+	clusters = c
+	return nil
+}
+
+// clusterEndpoint returns the address of the cluster serving region for profile.
+func clusterEndpoint(region, profile string) string {
+	clusterMu.RLock()
+	defer clusterMu.RUnlock()
+
+	if region == "local" {
+		return clusters.LocalEndpoint
+	}
+	env := "dev"
+	if slices.Contains(clusters.ProdProfiles, profile) {
+		env = "prod"
+	}
+	return strings.NewReplacer("{env}", env, "{region}", region).Replace(clusters.Endpoint)
+}
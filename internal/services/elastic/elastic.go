@@ -15,7 +15,9 @@ import (
 	"time"
 
 	"github.com/spf13/viper"
+	"github.com/tpelletiersophos/cloudcutter/internal/diagnostics"
 	"github.com/tpelletiersophos/cloudcutter/internal/logger"
+	awsservice "github.com/tpelletiersophos/cloudcutter/internal/services/aws"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
@@ -64,7 +66,8 @@ func NewService(cfg aws.Config) (*Service, error) {
 	if cfg.Region == "local" {
 		l.Debug("Configuring local elasticsearch connection")
 		esConfig = elasticsearch.Config{
-			Addresses: []string{"http://localhost:9200"},
+			Addresses: []string{clusterEndpoint(cfg.Region, "")},
+			Transport: diagnostics.WrapTransport("elasticsearch", nil),
 		}
 	} else {
 		l.Debug("Configuring AWS elasticsearch connection for region: %s", "region", cfg.Region)
@@ -74,10 +77,10 @@ func NewService(cfg aws.Config) (*Service, error) {
 			region: cfg.Region,
 		}
 
-		esEndpoint := fmt.Sprintf("https://dev-%s-primary-es.darkbytes.io", cfg.Region)
+		esEndpoint := clusterEndpoint(cfg.Region, "")
 		esConfig = elasticsearch.Config{
 			Addresses:     []string{esEndpoint},
-			Transport:     transport,
+			Transport:     diagnostics.WrapTransport("elasticsearch", transport),
 			EnableMetrics: true,
 		}
 	}
@@ -106,12 +109,35 @@ func NewService(cfg aws.Config) (*Service, error) {
 	return s, nil
 }
 
+// NewServiceWithClient returns a service that uses client as it is, such as a client of a test
+// server, and logs to log.
+func NewServiceWithClient(client *elasticsearch.Client, log *logger.Logger) *Service {
+	return &Service{
+		Client: client,
+		log:    log,
+		cache:  make(map[string]*IndexStats),
+	}
+}
+
 func (s *Service) Reinitialize(cfg aws.Config, profile string) error {
+	newClient, err := NewClient(cfg, profile)
+	if err != nil {
+		return fmt.Errorf("error reinitializing Elasticsearch client: %s", err)
+	}
+
+	s.Client = newClient
+	return nil
+}
+
+// NewClient creates a client for the cluster serving cfg.Region under the given profile,
+// without touching the service's own client or cache.
+func NewClient(cfg aws.Config, profile string) (*elasticsearch.Client, error) {
 	var esConfig elasticsearch.Config
 
 	if cfg.Region == "local" {
 		esConfig = elasticsearch.Config{
-			Addresses: []string{"http://localhost:9200"},
+			Addresses: []string{clusterEndpoint(cfg.Region, profile)},
+			Transport: diagnostics.WrapTransport("elasticsearch", nil),
 		}
 	} else {
 		transport := &awsTransport{
@@ -120,26 +146,15 @@ func (s *Service) Reinitialize(cfg aws.Config, profile string) error {
 			region: cfg.Region,
 		}
 
-		endpointPrefix := "dev"
-		if profile == "opal_prod" {
-			endpointPrefix = "prod"
-		}
-
-		esEndpoint := fmt.Sprintf("https://%s-%s-primary-es.darkbytes.io", endpointPrefix, cfg.Region)
+		esEndpoint := clusterEndpoint(cfg.Region, profile)
 		esConfig = elasticsearch.Config{
 			Addresses:     []string{esEndpoint},
-			Transport:     transport,
+			Transport:     diagnostics.WrapTransport("elasticsearch", transport),
 			EnableMetrics: true,
 		}
 	}
 
-	newClient, err := elasticsearch.NewClient(esConfig)
-	if err != nil {
-		return fmt.Errorf("error reinitializing Elasticsearch client: %s", err)
-	}
-
-	s.Client = newClient
-	return nil
+	return elasticsearch.NewClient(esConfig)
 }
 
 func (t *awsTransport) RoundTrip(req *http.Request) (*http.Response, error) {
@@ -151,9 +166,23 @@ func (t *awsTransport) RoundTrip(req *http.Request) (*http.Response, error) {
 			return nil, err
 		}
 		req.Body.Close()
-		req.Body = io.NopCloser(bytes.NewReader(body))
 	}
 
+	res, err := t.send(req, body)
+	if err != nil || !isExpiredTokenResponse(res) {
+		return res, err
+	}
+
+	// Re-authenticate and replay the request once; the caller sees the original 403 otherwise
+	refresher, ok := t.cfg.Credentials.(awsservice.CredentialsRefresher)
+	if !ok || refresher.RefreshCredentials(req.Context()) != nil {
+		return res, nil
+	}
+	res.Body.Close()
+	return t.send(req.Clone(req.Context()), body)
+}
+
+func (t *awsTransport) send(req *http.Request, body []byte) (*http.Response, error) {
 	credentials, err := t.cfg.Credentials.Retrieve(req.Context())
 	if err != nil {
 		return nil, err
@@ -162,6 +191,9 @@ func (t *awsTransport) RoundTrip(req *http.Request) (*http.Response, error) {
 	req.Header.Set("Host", req.Host)
 	payloadHash := hashPayload(body)
 	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+	if body != nil {
+		req.Body = io.NopCloser(bytes.NewReader(body))
+	}
 
 	signer := v4.NewSigner()
 	err = signer.SignHTTP(req.Context(), credentials, req, payloadHash, "es", t.region, time.Now())
@@ -176,6 +208,23 @@ func (t *awsTransport) RoundTrip(req *http.Request) (*http.Response, error) {
 	return t.client.Do(req)
 }
 
+// isExpiredTokenResponse reports whether the cluster rejected a request because its signing
+// credentials have expired. The body is left readable for the caller.
+func isExpiredTokenResponse(res *http.Response) bool {
+	if res.StatusCode != http.StatusForbidden || res.Body == nil {
+		return false
+	}
+	body, err := io.ReadAll(res.Body)
+	res.Body.Close()
+	res.Body = io.NopCloser(bytes.NewReader(body))
+	if err != nil {
+		return false
+	}
+	message := string(body)
+	return strings.Contains(message, "ExpiredToken") ||
+		strings.Contains(message, "security token included in the request is expired")
+}
+
 func hashPayload(b []byte) string {
 	if b == nil {
 		return "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
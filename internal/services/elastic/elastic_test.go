@@ -0,0 +1,50 @@
+package elastic
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tpelletiersophos/cloudcutter/internal/logger"
+	"github.com/tpelletiersophos/cloudcutter/internal/services/elastic/esfake"
+)
+
+func newFakeService(t *testing.T) *Service {
+	t.Helper()
+	server := esfake.NewServer()
+	t.Cleanup(server.Close)
+	for _, name := range []string{"logs-2024.01.15", "logs-2024.01.16", "metrics-2024.01.16"} {
+		require.NoError(t, server.Index(name, "1", map[string]any{"message": "hello"}))
+	}
+	require.NoError(t, server.Index("logs-2024.01.16", "2", map[string]any{"message": "again"}))
+
+	client, err := server.Client()
+	require.NoError(t, err)
+	log, err := logger.New(logger.Config{LogDir: t.TempDir(), Prefix: "test", Level: logger.ERROR})
+	require.NoError(t, err)
+	t.Cleanup(func() { log.Close() })
+	return NewServiceWithClient(client, log)
+}
+
+func TestListIndices(t *testing.T) {
+	s := newFakeService(t)
+
+	names, err := s.ListIndices(context.Background(), "logs-*")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"logs-2024.01.16", "logs-2024.01.15"}, names, "newest first")
+
+	names, err = s.ListIndices(context.Background(), "")
+	require.NoError(t, err)
+	assert.Len(t, names, 3)
+}
+
+func TestPreloadIndexStats(t *testing.T) {
+	s := newFakeService(t)
+
+	require.NoError(t, s.PreloadIndexStats(context.Background()))
+	require.Contains(t, s.cache, "logs-2024.01.16")
+	assert.Equal(t, "2", s.cache["logs-2024.01.16"].DocsCount)
+	assert.Equal(t, "green", s.cache["logs-2024.01.16"].Health)
+}
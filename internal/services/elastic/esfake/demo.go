@@ -0,0 +1,56 @@
+package esfake
+
+import (
+	"fmt"
+	"math/rand"
+	"time"
+)
+
+// DemoIndexPattern matches the indices LoadDemo writes.
+const DemoIndexPattern = "demo-logs-*"
+
+var (
+	demoServices = []string{"api-gateway", "billing", "auth", "search", "scheduler"}
+	demoHosts    = []string{"ip-10-0-1-17", "ip-10-0-1-42", "ip-10-0-2-8"}
+	demoEvents   = []struct {
+		level, message string
+		severity       int
+	}{
+		{"info", "request completed", 1},
+		{"info", "user signed in", 1},
+		{"info", "cache refreshed", 1},
+		{"debug", "retrying upstream call", 0},
+		{"warn", "slow response from upstream", 3},
+		{"warn", "rate limit almost reached", 4},
+		{"error", "connection reset by peer", 7},
+		{"error", "payment declined by provider", 8},
+	}
+)
+
+// LoadDemo writes a day of made-up application logs up to now, one index per UTC day
+// (demo-logs-2006.01.02), with unixTime in seconds as the views expect. The same now writes the
+// same documents.
+func (s *Server) LoadDemo(now time.Time) error {
+	rng := rand.New(rand.NewSource(now.Unix()))
+	const count = 500
+	for i := 0; i < count; i++ {
+		at := now.Add(-time.Duration(count-i) * 24 * time.Hour / count).Add(-time.Duration(rng.Intn(60)) * time.Second)
+		event := demoEvents[rng.Intn(len(demoEvents))]
+		doc := map[string]any{
+			"unixTime":  at.Unix(),
+			"timestamp": at.UTC().Format(time.RFC3339),
+			"level":     event.level,
+			"severity":  event.severity,
+			"message":   event.message,
+			"service":   demoServices[rng.Intn(len(demoServices))],
+			"host":      demoHosts[rng.Intn(len(demoHosts))],
+			"latencyMs": 5 + rng.Intn(900),
+			"requestId": fmt.Sprintf("req-%06d", i+1),
+		}
+		name := "demo-logs-" + at.UTC().Format("2006.01.02")
+		if err := s.Index(name, fmt.Sprintf("%06d", i+1), doc); err != nil {
+			return err
+		}
+	}
+	return nil
+}
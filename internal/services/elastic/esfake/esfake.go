@@ -0,0 +1,636 @@
+// Package esfake is an Elasticsearch 6 cluster that runs in the process and keeps its documents in
+// memory, so that tests and the demo mode work without Docker. It answers the requests cloudcutter
+// makes: searches with match, term, wildcard, range, bool, exists and ids queries, sorting and
+// scrolling, field capabilities, index listings, explaining a document's match, and reading, writing
+// and deleting documents.
+package esfake
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/elastic/go-elasticsearch/v6"
+)
+
+// Version is the Elasticsearch version the fake reports.
+const Version = "6.8.23"
+
+// Server is a fake cluster listening on a local port. Its zero value is not usable; call NewServer.
+type Server struct {
+	*httptest.Server
+
+	mu         sync.Mutex
+	indices    map[string]*index
+	scrolls    map[string]*scroll
+	nextID     int   // of generated document ids
+	nextScroll int   // of scroll ids
+	seqNo      int64 // of the last write
+}
+
+type index struct {
+	docs []*document // in the order they were first written
+}
+
+type document struct {
+	id      string
+	source  json.RawMessage
+	fields  map[string]any // source decoded with json.Number for numbers
+	version int64
+	seqNo   int64
+}
+
+// NewServer starts an empty cluster. Close it when done.
+func NewServer() *Server {
+	s := &Server{
+		indices: make(map[string]*index),
+		scrolls: make(map[string]*scroll),
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+// Client returns a client of the cluster.
+func (s *Server) Client() (*elasticsearch.Client, error) {
+	return elasticsearch.NewClient(elasticsearch.Config{Addresses: []string{s.URL}})
+}
+
+// Index writes doc, anything that encodes to a JSON object, to the named index, creating the index
+// if needed. An empty id is generated.
+func (s *Server) Index(name, id string, doc any) error {
+	source, err := json.Marshal(doc)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, _, err = s.put(name, id, source)
+	return err
+}
+
+// LoadBulk writes the documents of an Elasticsearch bulk request body: newline-delimited index,
+// create, update and delete actions, each but delete followed by its document.
+func (s *Server) LoadBulk(r io.Reader) error {
+	_, err := s.bulk(r, "")
+	return err
+}
+
+// LoadBulkFile writes the documents of a bulk request body saved at path, such as
+// deployments/local/data/sample-data.json.
+func (s *Server) LoadBulkFile(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if err := s.LoadBulk(f); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	return nil
+}
+
+// Indices returns the names of the indices, sorted.
+func (s *Server) Indices() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.matching("*")
+}
+
+// put writes a document and reports whether it was created. s.mu must be held.
+func (s *Server) put(name, id string, source json.RawMessage) (*document, bool, error) {
+	if name == "" || strings.ContainsAny(name, `*?,/\ "`) || strings.ToLower(name) != name {
+		return nil, false, fmt.Errorf("invalid index name [%s]", name)
+	}
+	dec := json.NewDecoder(bytes.NewReader(source))
+	dec.UseNumber()
+	var fields map[string]any
+	if err := dec.Decode(&fields); err != nil || fields == nil {
+		return nil, false, fmt.Errorf("document is not a JSON object")
+	}
+
+	idx := s.indices[name]
+	if idx == nil {
+		idx = &index{}
+		s.indices[name] = idx
+	}
+	if id == "" {
+		s.nextID++
+		id = fmt.Sprintf("fake-%d", s.nextID)
+	}
+	s.seqNo++
+	if doc := idx.get(id); doc != nil {
+		doc.source, doc.fields = source, fields
+		doc.version++
+		doc.seqNo = s.seqNo
+		return doc, false, nil
+	}
+	doc := &document{id: id, source: source, fields: fields, version: 1, seqNo: s.seqNo}
+	idx.docs = append(idx.docs, doc)
+	return doc, true, nil
+}
+
+func (idx *index) get(id string) *document {
+	for _, doc := range idx.docs {
+		if doc.id == id {
+			return doc
+		}
+	}
+	return nil
+}
+
+func (idx *index) delete(id string) *document {
+	for i, doc := range idx.docs {
+		if doc.id == id {
+			idx.docs = append(idx.docs[:i], idx.docs[i+1:]...)
+			return doc
+		}
+	}
+	return nil
+}
+
+// matching returns the indices named by a comma-separated list of names and wildcard patterns,
+// sorted. s.mu must be held.
+func (s *Server) matching(expr string) []string {
+	if expr == "" || expr == "_all" {
+		expr = "*"
+	}
+	seen := make(map[string]bool)
+	for _, pattern := range strings.Split(expr, ",") {
+		for name := range s.indices {
+			if ok, _ := path.Match(pattern, name); ok {
+				seen[name] = true
+			}
+		}
+	}
+	names := make([]string, 0, len(seen))
+	for name := range seen {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// resolve is matching, failing as Elasticsearch does when a name without wildcards does not exist.
+func (s *Server) resolve(expr string) ([]string, error) {
+	for _, name := range strings.Split(expr, ",") {
+		if name != "" && name != "_all" && !strings.ContainsAny(name, "*?") && s.indices[name] == nil {
+			return nil, &esError{status: http.StatusNotFound, kind: "index_not_found_exception", reason: "no such index", index: name}
+		}
+	}
+	return s.matching(expr), nil
+}
+
+// bulk applies a bulk request body, writing to defaultIndex where an action names no index.
+func (s *Server) bulk(r io.Reader, defaultIndex string) ([]map[string]any, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64<<10), 16<<20)
+	next := func() ([]byte, bool) {
+		for scanner.Scan() {
+			if line := bytes.TrimSpace(scanner.Bytes()); len(line) > 0 {
+				return line, true
+			}
+		}
+		return nil, false
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var items []map[string]any
+	for n := 1; ; n++ {
+		line, ok := next()
+		if !ok {
+			break
+		}
+		var action map[string]struct {
+			Index string `json:"_index"`
+			ID    string `json:"_id"`
+		}
+		if err := json.Unmarshal(line, &action); err != nil || len(action) != 1 {
+			return items, fmt.Errorf("action %d: malformed", n)
+		}
+		for op, meta := range action {
+			name := meta.Index
+			if name == "" {
+				name = defaultIndex
+			}
+			item := map[string]any{"_index": name, "_type": "_doc", "_id": meta.ID}
+			switch op {
+			case "index", "create", "update":
+				source, ok := next()
+				if !ok {
+					return items, fmt.Errorf("action %d: %s without a document", n, op)
+				}
+				if op == "update" {
+					var update struct {
+						Doc json.RawMessage `json:"doc"`
+					}
+					if err := json.Unmarshal(source, &update); err != nil || update.Doc == nil {
+						return items, fmt.Errorf("action %d: update without a doc", n)
+					}
+					merged, err := s.merge(name, meta.ID, update.Doc)
+					if err != nil {
+						return items, fmt.Errorf("action %d: %w", n, err)
+					}
+					source = merged
+				}
+				doc, created, err := s.put(name, meta.ID, json.RawMessage(bytes.Clone(source)))
+				if err != nil {
+					return items, fmt.Errorf("action %d: %w", n, err)
+				}
+				item["_id"], item["_version"], item["result"] = doc.id, doc.version, writeResult(created)
+				item["status"] = http.StatusOK
+				if created {
+					item["status"] = http.StatusCreated
+				}
+			case "delete":
+				item["result"], item["status"] = "not_found", http.StatusNotFound
+				if idx := s.indices[name]; idx != nil && idx.delete(meta.ID) != nil {
+					item["result"], item["status"] = "deleted", http.StatusOK
+				}
+			default:
+				return items, fmt.Errorf("action %d: unknown operation %q", n, op)
+			}
+			items = append(items, map[string]any{op: item})
+		}
+	}
+	return items, scanner.Err()
+}
+
+// merge applies a partial document to the stored one. s.mu must be held.
+func (s *Server) merge(name, id string, partial json.RawMessage) (json.RawMessage, error) {
+	var doc *document
+	if idx := s.indices[name]; idx != nil {
+		doc = idx.get(id)
+	}
+	if doc == nil {
+		return nil, &esError{status: http.StatusNotFound, kind: "document_missing_exception", reason: fmt.Sprintf("[_doc][%s]: document missing", id), index: name}
+	}
+	var source, changes map[string]json.RawMessage
+	if err := json.Unmarshal(doc.source, &source); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(partial, &changes); err != nil {
+		return nil, err
+	}
+	for field, value := range changes {
+		source[field] = value
+	}
+	return json.Marshal(source)
+}
+
+func writeResult(created bool) string {
+	if created {
+		return "created"
+	}
+	return "updated"
+}
+
+// serveHTTP routes a request by its path, as the cluster's REST API does.
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		writeError(w, badRequest("failed to read the request body: %v", err))
+		return
+	}
+
+	// Split the escaped path so that an escaped '/' in a document id stays within its part
+	parts := strings.Split(strings.Trim(r.URL.EscapedPath(), "/"), "/")
+	for i, part := range parts {
+		if parts[i], err = url.PathUnescape(part); err != nil {
+			writeError(w, badRequest("invalid path [%s]: %v", r.URL.EscapedPath(), err))
+			return
+		}
+	}
+	var res any
+	switch {
+	case r.URL.Path == "/":
+		res = map[string]any{
+			"name":         "esfake",
+			"cluster_name": "esfake",
+			"version":      map[string]any{"number": Version},
+			"tagline":      "You Know, for Search",
+		}
+	case parts[0] == "_cat" && len(parts) <= 3 && len(parts) > 1 && parts[1] == "indices":
+		res, err = s.catIndices(strings.Join(parts[2:], ""), r)
+	case parts[0] == "_search" && len(parts) >= 2 && parts[1] == "scroll":
+		if r.Method == http.MethodDelete {
+			res, err = s.clearScroll(strings.Join(parts[2:], ""), body)
+		} else {
+			res, err = s.scroll(strings.Join(parts[2:], ""), r, body)
+		}
+	case len(parts) == 1 && parts[0] == "_bulk", len(parts) == 2 && parts[1] == "_bulk":
+		res, err = s.bulkRequest(strings.Join(parts[:len(parts)-1], ""), body)
+	case len(parts) == 1 && parts[0] == "_search", len(parts) == 2 && parts[1] == "_search":
+		res, err = s.search(strings.Join(parts[:len(parts)-1], ""), r, body)
+	case len(parts) == 1 && parts[0] == "_count", len(parts) == 2 && parts[1] == "_count":
+		res, err = s.count(strings.Join(parts[:len(parts)-1], ""), body)
+	case len(parts) == 1 && parts[0] == "_field_caps", len(parts) == 2 && parts[1] == "_field_caps":
+		res, err = s.fieldCaps(strings.Join(parts[:len(parts)-1], ""))
+	case len(parts) == 2 && !strings.HasPrefix(parts[0], "_") && r.Method == http.MethodPost:
+		res, err = s.writeDocument(parts[0], "", r, body)
+	case len(parts) == 4 && !strings.HasPrefix(parts[0], "_") && parts[3] == "_explain":
+		res, err = s.explain(parts[0], parts[1], parts[2], body)
+	case len(parts) == 3 && !strings.HasPrefix(parts[0], "_"):
+		res, err = s.document(parts[0], parts[2], r, body)
+	default:
+		err = &esError{status: http.StatusBadRequest, kind: "illegal_argument_exception",
+			reason: fmt.Sprintf("no handler found for uri [%s] and method [%s]", r.URL.Path, r.Method)}
+	}
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	status := http.StatusOK
+	if sb, ok := res.(statusBody); ok {
+		status, res = sb.status, sb.body
+	}
+	writeJSON(w, status, res)
+}
+
+// statusBody is a response body sent with a status other than 200 OK.
+type statusBody struct {
+	status int
+	body   any
+}
+
+func (s *Server) catIndices(expr string, r *http.Request) (any, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	names, err := s.resolve(expr)
+	if err != nil {
+		return nil, err
+	}
+	if r.URL.Query().Get("s") == "index:desc" {
+		sort.Sort(sort.Reverse(sort.StringSlice(names)))
+	}
+	var columns []string
+	if h := r.URL.Query().Get("h"); h != "" {
+		columns = strings.Split(h, ",")
+	}
+	rows := make([]map[string]string, 0, len(names))
+	for _, name := range names {
+		size := 0
+		for _, doc := range s.indices[name].docs {
+			size += len(doc.source)
+		}
+		row := map[string]string{
+			"health":         "green",
+			"status":         "open",
+			"index":          name,
+			"uuid":           name,
+			"pri":            "1",
+			"rep":            "0",
+			"docs.count":     strconv.Itoa(len(s.indices[name].docs)),
+			"docs.deleted":   "0",
+			"store.size":     fmt.Sprintf("%db", size),
+			"pri.store.size": fmt.Sprintf("%db", size),
+		}
+		if columns != nil {
+			// h picks the columns
+			picked := make(map[string]string, len(columns))
+			for _, column := range columns {
+				picked[column] = row[column]
+			}
+			row = picked
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+func (s *Server) bulkRequest(defaultIndex string, body []byte) (any, error) {
+	items, err := s.bulk(bytes.NewReader(body), defaultIndex)
+	if err != nil {
+		return nil, badRequest("%v", err)
+	}
+	return map[string]any{"took": 1, "errors": false, "items": items}, nil
+}
+
+// document reads, writes or deletes the document id of the named index.
+func (s *Server) document(name, id string, r *http.Request, body []byte) (any, error) {
+	method := r.Method
+	switch method {
+	case http.MethodPut, http.MethodPost:
+		return s.writeDocument(name, id, r, body)
+	case http.MethodGet, http.MethodDelete:
+	default:
+		return nil, badRequest("method [%s] is not supported for documents", method)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	res := map[string]any{"_index": name, "_type": "_doc", "_id": id}
+	idx := s.indices[name]
+	if idx == nil {
+		return nil, &esError{status: http.StatusNotFound, kind: "index_not_found_exception", reason: "no such index", index: name}
+	}
+	if method == http.MethodDelete {
+		doc := idx.get(id)
+		if doc == nil {
+			res["result"] = "not_found"
+			return statusBody{http.StatusNotFound, res}, nil
+		}
+		if err := checkVersion(name, doc, r); err != nil {
+			return nil, err
+		}
+		idx.delete(id)
+		s.seqNo++
+		res["result"], res["_version"], res["_seq_no"], res["_primary_term"] = "deleted", doc.version+1, s.seqNo, 1
+		return res, nil
+	}
+	doc := idx.get(id)
+	if doc == nil {
+		res["found"] = false
+		return statusBody{http.StatusNotFound, res}, nil
+	}
+	res["found"], res["_version"], res["_seq_no"], res["_primary_term"] = true, doc.version, doc.seqNo, 1
+	res["_source"] = doc.source
+	return res, nil
+}
+
+func (s *Server) writeDocument(name, id string, r *http.Request, body []byte) (any, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if idx := s.indices[name]; idx != nil && id != "" {
+		if doc := idx.get(id); doc != nil {
+			if err := checkVersion(name, doc, r); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	doc, created, err := s.put(name, id, json.RawMessage(body))
+	if err != nil {
+		return nil, badRequest("%v", err)
+	}
+	res := map[string]any{
+		"_index":        name,
+		"_type":         "_doc",
+		"_id":           doc.id,
+		"_version":      doc.version,
+		"_seq_no":       doc.seqNo,
+		"_primary_term": 1,
+		"result":        writeResult(created),
+	}
+	if created {
+		return statusBody{http.StatusCreated, res}, nil
+	}
+	return res, nil
+}
+
+// checkVersion fails with a conflict when the request expects another version of doc, by
+// if_seq_no and if_primary_term or by version.
+func checkVersion(name string, doc *document, r *http.Request) error {
+	params := r.URL.Query()
+	var expected, current string
+	switch {
+	case params.Get("if_seq_no") != "":
+		expected = fmt.Sprintf("seqNo [%s] and primary term [%s]", params.Get("if_seq_no"), params.Get("if_primary_term"))
+		current = fmt.Sprintf("seqNo [%d] and primary term [1]", doc.seqNo)
+	case params.Get("version") != "":
+		expected = fmt.Sprintf("version [%s]", params.Get("version"))
+		current = fmt.Sprintf("version [%d]", doc.version)
+	default:
+		return nil
+	}
+	if expected == current {
+		return nil
+	}
+	return &esError{status: http.StatusConflict, kind: "version_conflict_engine_exception", index: name,
+		reason: fmt.Sprintf("[_doc][%s]: version conflict, required %s, current document has %s", doc.id, expected, current)}
+}
+
+// fieldCaps reports the type of every field of the documents in the named indices, as dynamic
+// mapping would have set it: strings are text with a keyword sub-field unless they hold dates.
+func (s *Server) fieldCaps(expr string) (any, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	names, err := s.resolve(expr)
+	if err != nil {
+		return nil, err
+	}
+	types := make(map[string]map[string]bool)
+	add := func(field, fieldType string) {
+		if types[field] == nil {
+			types[field] = make(map[string]bool)
+		}
+		types[field][fieldType] = true
+	}
+	var walk func(prefix string, fields map[string]any)
+	walk = func(prefix string, fields map[string]any) {
+		for name, value := range fields {
+			field := prefix + name
+			for _, v := range flatten(value) {
+				if object, ok := v.(map[string]any); ok {
+					add(field, "object")
+					walk(field+".", object)
+					continue
+				}
+				fieldType := mappingType(v)
+				if fieldType == "" {
+					continue
+				}
+				add(field, fieldType)
+				if fieldType == "text" {
+					add(field+".keyword", "keyword")
+				}
+			}
+		}
+	}
+	for _, name := range names {
+		for _, doc := range s.indices[name].docs {
+			walk("", doc.fields)
+		}
+	}
+
+	fields := make(map[string]any, len(types))
+	for field, set := range types {
+		caps := make(map[string]any, len(set))
+		for fieldType := range set {
+			searchable := fieldType != "object"
+			caps[fieldType] = map[string]any{
+				"type":         fieldType,
+				"searchable":   searchable,
+				"aggregatable": searchable && fieldType != "text",
+			}
+		}
+		fields[field] = caps
+	}
+	return map[string]any{"indices": names, "fields": fields}, nil
+}
+
+// mappingType is the type dynamic mapping gives a JSON value, or "" for null.
+func mappingType(value any) string {
+	switch v := value.(type) {
+	case bool:
+		return "boolean"
+	case json.Number:
+		if _, err := v.Int64(); err == nil {
+			return "long"
+		}
+		return "float"
+	case string:
+		if _, ok := parseTime(v); ok {
+			return "date"
+		}
+		return "text"
+	}
+	return ""
+}
+
+// esError is an error response as Elasticsearch writes it.
+type esError struct {
+	status int
+	kind   string
+	reason string
+	index  string
+}
+
+func (e *esError) Error() string {
+	return fmt.Sprintf("%s: %s", e.kind, e.reason)
+}
+
+func badRequest(format string, args ...any) error {
+	return &esError{status: http.StatusBadRequest, kind: "parsing_exception", reason: fmt.Sprintf(format, args...)}
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	e, ok := err.(*esError)
+	if !ok {
+		e = &esError{status: http.StatusInternalServerError, kind: "exception", reason: err.Error()}
+	}
+	cause := map[string]any{"type": e.kind, "reason": e.reason}
+	if e.index != "" {
+		cause["index"] = e.index
+	}
+	writeJSON(w, e.status, map[string]any{
+		"error": map[string]any{
+			"root_cause": []any{cause},
+			"type":       e.kind,
+			"reason":     e.reason,
+		},
+		"status": e.status,
+	})
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
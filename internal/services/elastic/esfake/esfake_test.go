@@ -0,0 +1,309 @@
+package esfake
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/elastic/go-elasticsearch/v6"
+	"github.com/elastic/go-elasticsearch/v6/esapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newServer(t *testing.T) (*Server, *elasticsearch.Client) {
+	t.Helper()
+	s := NewServer()
+	t.Cleanup(s.Close)
+	client, err := s.Client()
+	require.NoError(t, err)
+
+	docs := []map[string]any{
+		{"level": "error", "message": "connection refused by upstream", "service": "api", "latency": 120, "timestamp": "2024-01-15T10:00:00Z", "tags": []any{"net", "retry"}},
+		{"level": "info", "message": "request completed", "service": "api", "latency": 15, "timestamp": "2024-01-15T11:00:00Z"},
+		{"level": "warn", "message": "slow upstream response", "service": "billing", "latency": 900, "timestamp": "2024-01-16T09:30:00Z", "user": map[string]any{"name": "ann"}},
+		{"level": "error", "message": "payment declined", "service": "billing", "latency": 40, "timestamp": "2024-01-16T12:00:00Z"},
+	}
+	for i, doc := range docs {
+		name := "logs-2024.01.15"
+		if i >= 2 {
+			name = "logs-2024.01.16"
+		}
+		require.NoError(t, s.Index(name, string(rune('1'+i)), doc))
+	}
+	return s, client
+}
+
+// decode returns a reader of a client call's status and body, which fails t on transport errors.
+func decode(t *testing.T) func(res *esapi.Response, err error) (int, map[string]any) {
+	return func(res *esapi.Response, err error) (int, map[string]any) {
+		t.Helper()
+		require.NoError(t, err)
+		defer res.Body.Close()
+		var body map[string]any
+		require.NoError(t, json.NewDecoder(res.Body).Decode(&body))
+		return res.StatusCode, body
+	}
+}
+
+// searchIDs runs query against logs-* and returns the ids of the hits in order.
+func searchIDs(t *testing.T, client *elasticsearch.Client, query string) []string {
+	t.Helper()
+	status, body := decode(t)(client.Search(
+		client.Search.WithIndex("logs-*"),
+		client.Search.WithBody(strings.NewReader(query)),
+	))
+	require.Equal(t, http.StatusOK, status, "%v", body)
+	return hitIDs(body)
+}
+
+func hitIDs(body map[string]any) []string {
+	ids := []string{}
+	for _, h := range body["hits"].(map[string]any)["hits"].([]any) {
+		ids = append(ids, h.(map[string]any)["_id"].(string))
+	}
+	return ids
+}
+
+func TestSearchQueries(t *testing.T) {
+	_, client := newServer(t)
+
+	tests := []struct {
+		name  string
+		query string
+		want  []string
+	}{
+		{"match all", `{}`, []string{"1", "2", "3", "4"}},
+		{"match word", `{"query":{"match":{"message":"Upstream"}}}`, []string{"1", "3"}},
+		{"match and", `{"query":{"match":{"message":{"query":"slow upstream","operator":"and"}}}}`, []string{"3"}},
+		{"match phrase", `{"query":{"match_phrase":{"message":"request completed"}}}`, []string{"2"}},
+		{"term", `{"query":{"term":{"level":"error"}}}`, []string{"1", "4"}},
+		{"term keyword", `{"query":{"term":{"service.keyword":"billing"}}}`, []string{"3", "4"}},
+		{"term number", `{"query":{"term":{"latency":15}}}`, []string{"2"}},
+		{"term array", `{"query":{"term":{"tags":"retry"}}}`, []string{"1"}},
+		{"terms", `{"query":{"terms":{"level":["warn","info"]}}}`, []string{"2", "3"}},
+		{"wildcard", `{"query":{"wildcard":{"message":"*upstream*"}}}`, []string{"1", "3"}},
+		{"prefix", `{"query":{"prefix":{"service":"bil"}}}`, []string{"3", "4"}},
+		{"range numbers", `{"query":{"range":{"latency":{"gte":40,"lt":900}}}}`, []string{"1", "4"}},
+		{"range dates", `{"query":{"range":{"timestamp":{"gt":"2024-01-15T10:00:00Z","lte":"2024-01-16"}}}}`, []string{"2"}},
+		{"exists", `{"query":{"exists":{"field":"user.name"}}}`, []string{"3"}},
+		{"ids", `{"query":{"ids":{"values":["4","2","9"]}}}`, []string{"2", "4"}},
+		{"bool", `{"query":{"bool":{"must":[{"term":{"service":"api"}}],"must_not":{"term":{"level":"info"}}}}}`, []string{"1"}},
+		{"bool should", `{"query":{"bool":{"should":[{"term":{"level":"warn"}},{"term":{"latency":15}}]}}}`, []string{"2", "3"}},
+		{"bool minimum should", `{"query":{"bool":{"should":[{"term":{"level":"error"}},{"term":{"service":"api"}}],"minimum_should_match":2}}}`, []string{"1"}},
+		{"missing field", `{"query":{"bool":{"must_not":{"exists":{"field":"tags"}}}}}`, []string{"2", "3", "4"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, searchIDs(t, client, tt.query))
+		})
+	}
+}
+
+func TestSearchSortAndPage(t *testing.T) {
+	_, client := newServer(t)
+
+	assert.Equal(t, []string{"3", "1", "4", "2"}, searchIDs(t, client, `{"sort":[{"latency":{"order":"desc"}}]}`))
+	assert.Equal(t, []string{"4", "3", "2", "1"}, searchIDs(t, client, `{"sort":[{"timestamp":"desc"}]}`))
+	assert.Equal(t, []string{"1", "4", "2", "3"}, searchIDs(t, client, `{"sort":["level","_id"]}`))
+	assert.Equal(t, []string{"2", "3"}, searchIDs(t, client, `{"from":1,"size":2}`))
+
+	status, body := decode(t)(client.Search(
+		client.Search.WithIndex("logs-*"),
+		client.Search.WithSize(1),
+		client.Search.WithBody(strings.NewReader(`{"query":{"match_all":{}}}`)),
+	))
+	require.Equal(t, http.StatusOK, status)
+	assert.Equal(t, float64(4), body["hits"].(map[string]any)["total"], "the total counts every match")
+}
+
+func TestScroll(t *testing.T) {
+	_, client := newServer(t)
+
+	_, body := decode(t)(client.Search(
+		client.Search.WithIndex("logs-*"),
+		client.Search.WithSize(3),
+		client.Search.WithScroll(time.Minute),
+		client.Search.WithSort("_id:desc"),
+	))
+	ids := hitIDs(body)
+	scrollID := body["_scroll_id"].(string)
+	for {
+		_, page := decode(t)(client.Scroll(client.Scroll.WithScrollID(scrollID), client.Scroll.WithScroll(time.Minute)))
+		more := hitIDs(page)
+		if len(more) == 0 {
+			break
+		}
+		ids = append(ids, more...)
+	}
+	assert.Equal(t, []string{"4", "3", "2", "1"}, ids)
+
+	status, cleared := decode(t)(client.ClearScroll(client.ClearScroll.WithScrollID(scrollID)))
+	require.Equal(t, http.StatusOK, status)
+	assert.Equal(t, float64(1), cleared["num_freed"])
+
+	status, _ = decode(t)(client.Scroll(client.Scroll.WithScrollID(scrollID)))
+	assert.Equal(t, http.StatusNotFound, status)
+}
+
+func TestCatIndices(t *testing.T) {
+	_, client := newServer(t)
+
+	res, err := client.Cat.Indices(
+		client.Cat.Indices.WithFormat("json"),
+		client.Cat.Indices.WithS("index:desc"),
+	)
+	require.NoError(t, err)
+	defer res.Body.Close()
+	var rows []map[string]string
+	require.NoError(t, json.NewDecoder(res.Body).Decode(&rows))
+
+	require.Len(t, rows, 2)
+	assert.Equal(t, "logs-2024.01.16", rows[0]["index"])
+	assert.Equal(t, "2", rows[0]["docs.count"])
+	assert.Equal(t, "logs-2024.01.15", rows[1]["index"])
+}
+
+func TestFieldCaps(t *testing.T) {
+	_, client := newServer(t)
+
+	status, body := decode(t)(client.FieldCaps(
+		client.FieldCaps.WithIndex("logs-*"),
+		client.FieldCaps.WithFields("*"),
+	))
+	require.Equal(t, http.StatusOK, status)
+	fields := body["fields"].(map[string]any)
+
+	typeOf := func(field string) string {
+		caps, ok := fields[field].(map[string]any)
+		if !ok {
+			return ""
+		}
+		for kind := range caps {
+			return kind
+		}
+		return ""
+	}
+	assert.Equal(t, "text", typeOf("message"))
+	assert.Equal(t, "keyword", typeOf("message.keyword"))
+	assert.Equal(t, "long", typeOf("latency"))
+	assert.Equal(t, "date", typeOf("timestamp"))
+	assert.Equal(t, "text", typeOf("user.name"))
+}
+
+func TestDocuments(t *testing.T) {
+	_, client := newServer(t)
+
+	status, body := decode(t)(client.Get("logs-2024.01.16", "3"))
+	require.Equal(t, http.StatusOK, status)
+	assert.Equal(t, true, body["found"])
+	assert.Equal(t, "slow upstream response", body["_source"].(map[string]any)["message"])
+
+	status, body = decode(t)(client.Get("logs-2024.01.16", "1"))
+	assert.Equal(t, http.StatusNotFound, status)
+	assert.Equal(t, false, body["found"])
+
+	status, body = decode(t)(client.Index("logs-2024.01.16", strings.NewReader(`{"level":"info"}`),
+		client.Index.WithDocumentID("3"), client.Index.WithVersion(1)))
+	require.Equal(t, http.StatusOK, status, "%v", body)
+	assert.Equal(t, "updated", body["result"])
+	assert.Equal(t, float64(2), body["_version"])
+
+	status, body = decode(t)(client.Index("logs-2024.01.16", strings.NewReader(`{"level":"debug"}`),
+		client.Index.WithDocumentID("3"), client.Index.WithVersion(1)))
+	assert.Equal(t, http.StatusConflict, status, "a stale version is refused")
+	assert.Equal(t, "version_conflict_engine_exception", body["error"].(map[string]any)["type"])
+
+	status, _ = decode(t)(client.Delete("logs-2024.01.16", "3"))
+	require.Equal(t, http.StatusOK, status)
+	assert.Equal(t, []string{"1", "2", "4"}, searchIDs(t, client, `{}`))
+}
+
+func TestExplain(t *testing.T) {
+	s, client := newServer(t)
+	require.NoError(t, s.Index("logs-2024.01.16", "a/b", map[string]any{"level": "error"}))
+
+	status, body := decode(t)(client.Explain("logs-2024.01.16", url.PathEscape("a/b"),
+		client.Explain.WithBody(strings.NewReader(`{"query":{"term":{"level":"error"}}}`))))
+	require.Equal(t, http.StatusOK, status, "%v", body)
+	assert.Equal(t, "a/b", body["_id"])
+	assert.Equal(t, true, body["matched"])
+
+	status, body = decode(t)(client.Explain("logs-2024.01.16", "3",
+		client.Explain.WithBody(strings.NewReader(`{"query":{"term":{"level":"error"}}}`))))
+	require.Equal(t, http.StatusOK, status, "%v", body)
+	assert.Equal(t, false, body["matched"])
+
+	status, body = decode(t)(client.Explain("logs-2024.01.16", "3", client.Explain.WithDocumentType("log")))
+	assert.Equal(t, http.StatusNotFound, status, "the fake's documents are all of type _doc")
+	assert.Equal(t, false, body["matched"])
+}
+
+func TestErrors(t *testing.T) {
+	_, client := newServer(t)
+
+	status, body := decode(t)(client.Search(client.Search.WithIndex("missing")))
+	assert.Equal(t, http.StatusNotFound, status)
+	assert.Equal(t, "index_not_found_exception", body["error"].(map[string]any)["type"])
+
+	status, body = decode(t)(client.Search(
+		client.Search.WithIndex("logs-*"),
+		client.Search.WithBody(strings.NewReader(`{"query":{"fuzzy":{"message":"upstram"}}}`)),
+	))
+	assert.Equal(t, http.StatusBadRequest, status)
+	assert.Contains(t, body["error"].(map[string]any)["reason"], "no [query] registered for [fuzzy]")
+}
+
+func TestLoadBulkFile(t *testing.T) {
+	s := NewServer()
+	t.Cleanup(s.Close)
+
+	require.NoError(t, s.LoadBulkFile(filepath.Join("..", "..", "..", "..", "deployments", "local", "data", "sample-data.json")))
+	assert.Equal(t, []string{"sample-index"}, s.Indices())
+
+	client, err := s.Client()
+	require.NoError(t, err)
+	status, body := decode(t)(client.Search(
+		client.Search.WithIndex("sample-index"),
+		client.Search.WithBody(strings.NewReader(`{"query":{"match":{"title":"second"}}}`)),
+	))
+	require.Equal(t, http.StatusOK, status)
+	assert.Equal(t, []string{"2"}, hitIDs(body))
+
+	err = s.LoadBulk(strings.NewReader(`{"index":{"_index":"sample-index"}}` + "\n"))
+	assert.EqualError(t, err, "action 1: index without a document")
+}
+
+func TestLoadDemo(t *testing.T) {
+	s := NewServer()
+	t.Cleanup(s.Close)
+	now := time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+	require.NoError(t, s.LoadDemo(now))
+
+	assert.Equal(t, []string{"demo-logs-2024.03.09", "demo-logs-2024.03.10"}, s.Indices())
+
+	client, err := s.Client()
+	require.NoError(t, err)
+	res, err := client.Count(
+		client.Count.WithIndex(DemoIndexPattern),
+		client.Count.WithBody(strings.NewReader(`{"query":{"range":{"unixTime":{"gte":`+
+			jsonNumber(now.Add(-time.Hour).Unix())+`}}}}`)),
+	)
+	require.NoError(t, err)
+	defer res.Body.Close()
+	data, err := io.ReadAll(res.Body)
+	require.NoError(t, err)
+	var count struct{ Count int }
+	require.NoError(t, json.Unmarshal(data, &count))
+	assert.InDelta(t, 500/24, count.Count, 3, "documents are spread over the day")
+}
+
+func jsonNumber(n int64) string {
+	data, _ := json.Marshal(n)
+	return string(data)
+}
@@ -0,0 +1,481 @@
+package esfake
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// matcher reports whether a query matches a document of the named index.
+type matcher func(index string, doc *document) bool
+
+func matchAll(string, *document) bool { return true }
+
+// compileQuery turns a query clause into a matcher. Text is not analyzed as Elasticsearch would:
+// match compares lowercased words and everything else compares values exactly.
+func compileQuery(clause map[string]any) (matcher, error) {
+	if clause == nil {
+		return matchAll, nil
+	}
+	if len(clause) != 1 {
+		return nil, badRequest("a query clause must have exactly one key, got %d", len(clause))
+	}
+	for kind, body := range clause {
+		switch kind {
+		case "match_all":
+			return matchAll, nil
+		case "match_none":
+			return func(string, *document) bool { return false }, nil
+		case "bool":
+			return compileBool(body)
+		case "ids":
+			return compileIDs(body)
+		case "exists":
+			options, ok := body.(map[string]any)
+			field, _ := options["field"].(string)
+			if !ok || field == "" {
+				return nil, badRequest("[exists] must name a field")
+			}
+			return func(_ string, doc *document) bool {
+				return slices.ContainsFunc(lookup(doc.fields, field), func(v any) bool { return v != nil })
+			}, nil
+		case "term", "match", "match_phrase", "wildcard", "prefix":
+			return compileFieldQuery(kind, body)
+		case "terms":
+			return compileTerms(body)
+		case "range":
+			return compileRange(body)
+		default:
+			return nil, badRequest("no [query] registered for [%s]", kind)
+		}
+	}
+	panic("unreachable")
+}
+
+// clauses reads a bool occurrence, either one clause or a list of them.
+func clauses(occurrence string, value any) ([]matcher, error) {
+	var list []any
+	switch v := value.(type) {
+	case nil:
+		return nil, nil
+	case []any:
+		list = v
+	case map[string]any:
+		list = []any{v}
+	default:
+		return nil, badRequest("[bool] malformed [%s]", occurrence)
+	}
+	matchers := make([]matcher, 0, len(list))
+	for _, item := range list {
+		clause, ok := item.(map[string]any)
+		if !ok {
+			return nil, badRequest("[bool] malformed [%s]", occurrence)
+		}
+		m, err := compileQuery(clause)
+		if err != nil {
+			return nil, err
+		}
+		matchers = append(matchers, m)
+	}
+	return matchers, nil
+}
+
+func compileBool(body any) (matcher, error) {
+	options, ok := body.(map[string]any)
+	if !ok {
+		return nil, badRequest("[bool] malformed query")
+	}
+	var must, should, mustNot []matcher
+	for occurrence, value := range options {
+		if occurrence == "minimum_should_match" || occurrence == "boost" {
+			continue
+		}
+		list, err := clauses(occurrence, value)
+		if err != nil {
+			return nil, err
+		}
+		switch occurrence {
+		case "must", "filter":
+			must = append(must, list...)
+		case "should":
+			should = list
+		case "must_not":
+			mustNot = list
+		default:
+			return nil, badRequest("[bool] query does not support [%s]", occurrence)
+		}
+	}
+
+	// Without must or filter clauses, one should clause has to match
+	minimumShould := 0
+	if len(must) == 0 && len(should) > 0 {
+		minimumShould = 1
+	}
+	if v, ok := options["minimum_should_match"]; ok {
+		n, err := strconv.Atoi(fmt.Sprint(v))
+		if err != nil {
+			return nil, badRequest("[bool] minimum_should_match [%v] is not supported", v)
+		}
+		minimumShould = n
+	}
+
+	return func(index string, doc *document) bool {
+		for _, m := range must {
+			if !m(index, doc) {
+				return false
+			}
+		}
+		for _, m := range mustNot {
+			if m(index, doc) {
+				return false
+			}
+		}
+		matched := 0
+		for _, m := range should {
+			if m(index, doc) {
+				matched++
+			}
+		}
+		return matched >= minimumShould
+	}, nil
+}
+
+func compileIDs(body any) (matcher, error) {
+	options, _ := body.(map[string]any)
+	values, ok := options["values"].([]any)
+	if !ok {
+		return nil, badRequest("[ids] must list values")
+	}
+	ids := make([]string, len(values))
+	for i, v := range values {
+		ids[i] = fmt.Sprint(v)
+	}
+	return func(_ string, doc *document) bool {
+		return slices.Contains(ids, doc.id)
+	}, nil
+}
+
+// fieldQuery reads the body of a single-field query: {"field": value} or
+// {"field": {"<valueKey>": value, ...options}}.
+func fieldQuery(kind, valueKey string, body any) (string, any, map[string]any, error) {
+	options, ok := body.(map[string]any)
+	if !ok || len(options) != 1 {
+		return "", nil, nil, badRequest("[%s] query must target exactly one field", kind)
+	}
+	for field, value := range options {
+		if nested, ok := value.(map[string]any); ok {
+			return field, nested[valueKey], nested, nil
+		}
+		return field, value, nil, nil
+	}
+	panic("unreachable")
+}
+
+func compileFieldQuery(kind string, body any) (matcher, error) {
+	valueKey := "value"
+	if kind == "match" || kind == "match_phrase" {
+		valueKey = "query"
+	}
+	field, value, options, err := fieldQuery(kind, valueKey, body)
+	if err != nil {
+		return nil, err
+	}
+	if value == nil {
+		return nil, badRequest("[%s] query for [%s] has no %s", kind, field, valueKey)
+	}
+
+	var test func(v any) bool
+	switch kind {
+	case "term":
+		test = func(v any) bool { return equal(v, value) }
+	case "match":
+		want := words(fmt.Sprint(value))
+		all := strings.EqualFold(fmt.Sprint(options["operator"]), "and")
+		test = func(v any) bool {
+			s, ok := v.(string)
+			if !ok {
+				return equal(v, value)
+			}
+			have := words(s)
+			for _, w := range want {
+				found := slices.Contains(have, w)
+				if found && !all {
+					return true
+				}
+				if !found && all {
+					return false
+				}
+			}
+			return all && len(want) > 0
+		}
+	case "match_phrase":
+		phrase := strings.Join(words(fmt.Sprint(value)), " ")
+		test = func(v any) bool {
+			s, ok := v.(string)
+			return ok && strings.Contains(" "+strings.Join(words(s), " ")+" ", " "+phrase+" ")
+		}
+	case "wildcard", "prefix":
+		pattern := fmt.Sprint(value)
+		if kind == "prefix" {
+			pattern = strings.NewReplacer(`\`, `\\`, `*`, `\*`, `?`, `\?`).Replace(pattern) + "*"
+		}
+		re := wildcardRegexp(pattern)
+		test = func(v any) bool {
+			s, ok := v.(string)
+			return ok && re.MatchString(s)
+		}
+	}
+	return anyValue(field, test), nil
+}
+
+func compileTerms(body any) (matcher, error) {
+	options, ok := body.(map[string]any)
+	if !ok || len(options) != 1 {
+		return nil, badRequest("[terms] query must target exactly one field")
+	}
+	for field, value := range options {
+		values, ok := value.([]any)
+		if !ok {
+			return nil, badRequest("[terms] query for [%s] must list values", field)
+		}
+		return anyValue(field, func(v any) bool {
+			return slices.ContainsFunc(values, func(want any) bool { return equal(v, want) })
+		}), nil
+	}
+	panic("unreachable")
+}
+
+func compileRange(body any) (matcher, error) {
+	options, ok := body.(map[string]any)
+	if !ok || len(options) != 1 {
+		return nil, badRequest("[range] query must target exactly one field")
+	}
+	for field, value := range options {
+		bounds, ok := value.(map[string]any)
+		if !ok {
+			return nil, badRequest("[range] query for [%s] is malformed", field)
+		}
+		type bound struct {
+			value any
+			ok    func(c int) bool
+		}
+		var checks []bound
+		for op, limit := range bounds {
+			switch op {
+			case "gt":
+				checks = append(checks, bound{limit, func(c int) bool { return c > 0 }})
+			case "gte", "from":
+				checks = append(checks, bound{limit, func(c int) bool { return c >= 0 }})
+			case "lt":
+				checks = append(checks, bound{limit, func(c int) bool { return c < 0 }})
+			case "lte", "to":
+				checks = append(checks, bound{limit, func(c int) bool { return c <= 0 }})
+			case "format", "time_zone", "boost", "include_lower", "include_upper":
+			default:
+				return nil, badRequest("[range] query does not support [%s]", op)
+			}
+		}
+		return anyValue(field, func(v any) bool {
+			for _, check := range checks {
+				if check.value == nil {
+					continue
+				}
+				c, ok := compareValues(v, check.value)
+				if !ok || !check.ok(c) {
+					return false
+				}
+			}
+			return true
+		}), nil
+	}
+	panic("unreachable")
+}
+
+// anyValue matches documents where test holds for one of the field's values.
+func anyValue(field string, test func(v any) bool) matcher {
+	return func(_ string, doc *document) bool {
+		for _, v := range lookup(doc.fields, field) {
+			if v != nil && test(v) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// lookup returns the values of a dotted field path, looking into objects and the objects of arrays.
+// A .keyword sub-field, which dynamic mapping adds to text, reads the text itself.
+func lookup(fields map[string]any, field string) []any {
+	values := lookupPath(fields, strings.Split(field, "."))
+	if len(values) == 0 && strings.HasSuffix(field, ".keyword") {
+		values = lookupPath(fields, strings.Split(strings.TrimSuffix(field, ".keyword"), "."))
+	}
+	return values
+}
+
+func lookupPath(fields map[string]any, path []string) []any {
+	// Field names may contain dots themselves, so try the longest names first
+	for n := len(path); n > 0; n-- {
+		value, ok := fields[strings.Join(path[:n], ".")]
+		if !ok {
+			continue
+		}
+		if n == len(path) {
+			return flatten(value)
+		}
+		var values []any
+		for _, v := range flatten(value) {
+			if object, ok := v.(map[string]any); ok {
+				values = append(values, lookupPath(object, path[n:])...)
+			}
+		}
+		return values
+	}
+	return nil
+}
+
+// flatten returns the elements of arrays, nested or not, and any other value on its own.
+func flatten(value any) []any {
+	list, ok := value.([]any)
+	if !ok {
+		return []any{value}
+	}
+	var values []any
+	for _, v := range list {
+		values = append(values, flatten(v)...)
+	}
+	return values
+}
+
+// equal compares a document value with a query value, as a term query on a field of the document
+// value's type would: numbers by value, and dates at the same instant.
+func equal(docValue, queryValue any) bool {
+	if c, ok := compareValues(docValue, queryValue); ok {
+		return c == 0
+	}
+	return fmt.Sprint(docValue) == fmt.Sprint(queryValue)
+}
+
+// compareValues orders a against b when they are comparable: both numbers, both dates (strings in a
+// date format, or numbers as epoch milliseconds against a date), both strings or both booleans.
+func compareValues(a, b any) (int, bool) {
+	an, aNum := toNumber(a)
+	bn, bNum := toNumber(b)
+	as, aStr := a.(string)
+	bs, bStr := b.(string)
+	switch {
+	case aNum && bNum:
+		return compareFloat(an, bn), true
+	case aNum && bStr:
+		if t, ok := parseTime(bs); ok {
+			return compareFloat(an, float64(t.UnixMilli())), true
+		}
+		if n, err := strconv.ParseFloat(bs, 64); err == nil {
+			return compareFloat(an, n), true
+		}
+	case aStr && bNum:
+		if t, ok := parseTime(as); ok {
+			return compareFloat(float64(t.UnixMilli()), bn), true
+		}
+		if n, err := strconv.ParseFloat(as, 64); err == nil {
+			return compareFloat(n, bn), true
+		}
+	case aStr && bStr:
+		if at, ok := parseTime(as); ok {
+			if bt, ok := parseTime(bs); ok {
+				return at.Compare(bt), true
+			}
+		}
+		return strings.Compare(as, bs), true
+	}
+	ab, aBool := a.(bool)
+	bb, bBool := b.(bool)
+	if aBool && bBool {
+		switch {
+		case ab == bb:
+			return 0, true
+		case bb:
+			return -1, true
+		default:
+			return 1, true
+		}
+	}
+	return 0, false
+}
+
+func compareFloat(a, b float64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+func toNumber(v any) (float64, bool) {
+	switch n := v.(type) {
+	case json.Number:
+		f, err := n.Float64()
+		return f, err == nil
+	case float64:
+		return n, true
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	}
+	return 0, false
+}
+
+// dateLayouts are the formats of Elasticsearch's default date detection.
+var dateLayouts = []string{time.RFC3339Nano, "2006-01-02T15:04:05.999999999", "2006-01-02 15:04:05", "2006-01-02"}
+
+func parseTime(s string) (time.Time, bool) {
+	if len(s) < len("2006-01-02") || s[4] != '-' {
+		return time.Time{}, false
+	}
+	for _, layout := range dateLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// words splits text into lowercase words, roughly as the standard analyzer does.
+func words(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+}
+
+// wildcardRegexp translates a wildcard pattern, where * matches any text, ? any character and \
+// escapes either, to a regular expression matching whole values.
+func wildcardRegexp(pattern string) *regexp.Regexp {
+	var b strings.Builder
+	b.WriteString("^")
+	escaped := false
+	for _, r := range pattern {
+		switch {
+		case escaped:
+			b.WriteString(regexp.QuoteMeta(string(r)))
+			escaped = false
+		case r == '\\':
+			escaped = true
+		case r == '*':
+			b.WriteString(".*")
+		case r == '?':
+			b.WriteString(".")
+		default:
+			b.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	b.WriteString("$")
+	return regexp.MustCompile(b.String())
+}
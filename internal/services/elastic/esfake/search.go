@@ -0,0 +1,413 @@
+package esfake
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// defaultSize is how many hits a search returns when it does not say.
+const defaultSize = 10
+
+// searchBody is the part of a search request body the fake understands.
+type searchBody struct {
+	Query        map[string]any  `json:"query"`
+	Size         *int            `json:"size"`
+	From         int             `json:"from"`
+	Sort         any             `json:"sort"`
+	Version      bool            `json:"version"`
+	SeqNo        bool            `json:"seq_no_primary_term"`
+	Aggs         json.RawMessage `json:"aggs"`
+	Aggregations json.RawMessage `json:"aggregations"`
+}
+
+type hit struct {
+	index string
+	doc   *document
+	sort  []any // the values sorted on, when the search sorts
+}
+
+// scroll is a search being read a page at a time.
+type scroll struct {
+	hits    []hit // not read yet
+	total   int
+	size    int
+	version bool
+	seqNo   bool
+}
+
+func parseSearchBody(data []byte) (*searchBody, error) {
+	body := &searchBody{}
+	if len(bytes.TrimSpace(data)) == 0 {
+		return body, nil
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(body); err != nil {
+		return nil, badRequest("failed to parse search body: %v", err)
+	}
+	if body.Aggs != nil || body.Aggregations != nil {
+		return nil, badRequest("aggregations are not supported by esfake")
+	}
+	return body, nil
+}
+
+// find returns the documents of the named indices that body's query matches, in body's order.
+// s.mu must be held.
+func (s *Server) find(expr string, body *searchBody) ([]hit, error) {
+	names, err := s.resolve(expr)
+	if err != nil {
+		return nil, err
+	}
+	match, err := compileQuery(body.Query)
+	if err != nil {
+		return nil, err
+	}
+	keys, err := parseSort(body.Sort)
+	if err != nil {
+		return nil, err
+	}
+
+	var hits []hit
+	for _, name := range names {
+		for _, doc := range s.indices[name].docs {
+			if match(name, doc) {
+				hits = append(hits, hit{index: name, doc: doc})
+			}
+		}
+	}
+	if len(keys) > 0 {
+		for i := range hits {
+			for _, key := range keys {
+				hits[i].sort = append(hits[i].sort, key.value(hits[i]))
+			}
+		}
+		sort.SliceStable(hits, func(i, j int) bool {
+			for k, key := range keys {
+				if c := key.compare(hits[i].sort[k], hits[j].sort[k]); c != 0 {
+					return c < 0
+				}
+			}
+			return false
+		})
+	}
+	return hits, nil
+}
+
+func (s *Server) search(expr string, r *http.Request, data []byte) (any, error) {
+	body, err := parseSearchBody(data)
+	if err != nil {
+		return nil, err
+	}
+	params := r.URL.Query()
+	size := defaultSize
+	if body.Size != nil {
+		size = *body.Size
+	}
+	if v := params.Get("size"); v != "" {
+		if size, err = strconv.Atoi(v); err != nil {
+			return nil, badRequest("failed to parse size [%s]", v)
+		}
+	}
+	from := body.From
+	if v := params.Get("from"); v != "" {
+		if from, err = strconv.Atoi(v); err != nil {
+			return nil, badRequest("failed to parse from [%s]", v)
+		}
+	}
+	if v := params.Get("sort"); v != "" {
+		// field:order pairs, comma-separated
+		var keys []any
+		for _, item := range strings.Split(v, ",") {
+			field, order, ok := strings.Cut(item, ":")
+			if !ok {
+				keys = append(keys, field)
+				continue
+			}
+			keys = append(keys, map[string]any{field: order})
+		}
+		body.Sort = keys
+	}
+	if size < 0 || from < 0 {
+		return nil, badRequest("[from] and [size] must not be negative")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	hits, err := s.find(expr, body)
+	if err != nil {
+		return nil, err
+	}
+	if params.Get("scroll") == "" {
+		return searchResponse(hits[min(from, len(hits)):min(from+size, len(hits))], len(hits), "", body.Version, body.SeqNo), nil
+	}
+
+	s.nextScroll++
+	id := fmt.Sprintf("esfake-scroll-%d", s.nextScroll)
+	page := hits[:min(size, len(hits))]
+	s.scrolls[id] = &scroll{hits: hits[len(page):], total: len(hits), size: size, version: body.Version, seqNo: body.SeqNo}
+	return searchResponse(page, len(hits), id, body.Version, body.SeqNo), nil
+}
+
+// scroll returns the next page of a scrolled search, by the id in the path, query or body.
+func (s *Server) scroll(id string, r *http.Request, data []byte) (any, error) {
+	if id == "" {
+		id = r.URL.Query().Get("scroll_id")
+	}
+	if id == "" && len(data) > 0 {
+		var body struct {
+			ScrollID string `json:"scroll_id"`
+		}
+		if err := json.Unmarshal(data, &body); err != nil {
+			return nil, badRequest("failed to parse scroll body: %v", err)
+		}
+		id = body.ScrollID
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sc := s.scrolls[id]
+	if sc == nil {
+		return nil, &esError{status: http.StatusNotFound, kind: "search_context_missing_exception", reason: fmt.Sprintf("No search context found for id [%s]", id)}
+	}
+	page := sc.hits[:min(sc.size, len(sc.hits))]
+	sc.hits = sc.hits[len(page):]
+	return searchResponse(page, sc.total, id, sc.version, sc.seqNo), nil
+}
+
+// clearScroll forgets the scrolls of a comma-separated list of ids in the path or body, or
+// all of them for _all.
+func (s *Server) clearScroll(ids string, data []byte) (any, error) {
+	list := strings.Split(ids, ",")
+	if ids == "" && len(data) > 0 {
+		var body struct {
+			ScrollID any `json:"scroll_id"`
+		}
+		if err := json.Unmarshal(data, &body); err != nil {
+			return nil, badRequest("failed to parse clear scroll body: %v", err)
+		}
+		switch v := body.ScrollID.(type) {
+		case string:
+			list = []string{v}
+		case []any:
+			list = nil
+			for _, id := range v {
+				list = append(list, fmt.Sprint(id))
+			}
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	freed := 0
+	for _, id := range list {
+		if id == "_all" {
+			freed += len(s.scrolls)
+			clear(s.scrolls)
+			continue
+		}
+		if _, ok := s.scrolls[id]; ok {
+			delete(s.scrolls, id)
+			freed++
+		}
+	}
+	return map[string]any{"succeeded": true, "num_freed": freed}, nil
+}
+
+func (s *Server) count(expr string, data []byte) (any, error) {
+	body, err := parseSearchBody(data)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	hits, err := s.find(expr, body)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]any{"count": len(hits), "_shards": shards()}, nil
+}
+
+// explain reports whether the query in data matches the document id of the named index. The fake
+// does not score, so a match explains as a constant score of 1.
+func (s *Server) explain(name, docType, id string, data []byte) (any, error) {
+	body, err := parseSearchBody(data)
+	if err != nil {
+		return nil, err
+	}
+	match, err := compileQuery(body.Query)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	idx := s.indices[name]
+	if idx == nil {
+		return nil, &esError{status: http.StatusNotFound, kind: "index_not_found_exception", reason: "no such index", index: name}
+	}
+	res := map[string]any{"_index": name, "_type": docType, "_id": id, "matched": false}
+	doc := idx.get(id)
+	if doc == nil || docType != "_doc" {
+		return statusBody{http.StatusNotFound, res}, nil
+	}
+	if match(name, doc) {
+		res["matched"] = true
+		res["explanation"] = map[string]any{"value": 1.0, "description": "ConstantScore(esfake)", "details": []any{}}
+	} else {
+		res["explanation"] = map[string]any{"value": 0.0, "description": "no matching clause", "details": []any{}}
+	}
+	return res, nil
+}
+
+func searchResponse(hits []hit, total int, scrollID string, version, seqNo bool) map[string]any {
+	var maxScore any
+	list := make([]map[string]any, 0, len(hits))
+	for _, h := range hits {
+		item := map[string]any{
+			"_index":  h.index,
+			"_type":   "_doc",
+			"_id":     h.doc.id,
+			"_score":  1.0,
+			"_source": h.doc.source,
+		}
+		if h.sort != nil {
+			item["_score"] = nil
+			item["sort"] = h.sort
+		} else {
+			maxScore = 1.0
+		}
+		if version {
+			item["_version"] = h.doc.version
+		}
+		if seqNo {
+			item["_seq_no"], item["_primary_term"] = h.doc.seqNo, 1
+		}
+		list = append(list, item)
+	}
+
+	res := map[string]any{
+		"took":      1,
+		"timed_out": false,
+		"_shards":   shards(),
+		"hits":      map[string]any{"total": total, "max_score": maxScore, "hits": list},
+	}
+	if scrollID != "" {
+		res["_scroll_id"] = scrollID
+	}
+	return res
+}
+
+func shards() map[string]int {
+	return map[string]int{"total": 1, "successful": 1, "skipped": 0, "failed": 0}
+}
+
+// sortKey is one of the fields a search sorts on.
+type sortKey struct {
+	field string
+	desc  bool
+}
+
+// parseSort reads a sort in any of its forms: "field", {"field": "desc"},
+// {"field": {"order": "desc"}}, or a list of those.
+func parseSort(spec any) ([]sortKey, error) {
+	var items []any
+	switch v := spec.(type) {
+	case nil:
+		return nil, nil
+	case []any:
+		items = v
+	default:
+		items = []any{v}
+	}
+
+	var keys []sortKey
+	for _, item := range items {
+		switch v := item.(type) {
+		case string:
+			keys = append(keys, sortKey{field: v, desc: v == "_score"})
+		case map[string]any:
+			for field, order := range v {
+				key := sortKey{field: field, desc: field == "_score"}
+				if options, ok := order.(map[string]any); ok {
+					order = options["order"]
+				}
+				switch order {
+				case nil:
+				case "asc":
+					key.desc = false
+				case "desc":
+					key.desc = true
+				default:
+					return nil, badRequest("unknown sort order [%v] for [%s]", order, field)
+				}
+				keys = append(keys, key)
+			}
+		default:
+			return nil, badRequest("malformed sort [%v]", item)
+		}
+	}
+	return keys, nil
+}
+
+// value is what h sorts by: the field's smallest value ascending and largest descending, as
+// Elasticsearch does for fields with several.
+func (k sortKey) value(h hit) any {
+	switch k.field {
+	case "_id":
+		return h.doc.id
+	case "_index":
+		return h.index
+	case "_score":
+		return 1.0
+	case "_doc":
+		return h.doc.seqNo
+	}
+	var best any
+	for _, v := range lookup(h.doc.fields, k.field) {
+		if v == nil {
+			continue
+		}
+		if best == nil {
+			best = v
+			continue
+		}
+		if c, ok := compareValues(v, best); ok && (c < 0) != k.desc && c != 0 {
+			best = v
+		}
+	}
+	if n, ok := toNumber(best); ok {
+		return n
+	}
+	return best
+}
+
+// compare orders two sort values; documents without one come last either way.
+func (k sortKey) compare(a, b any) int {
+	switch {
+	case a == nil && b == nil:
+		return 0
+	case a == nil:
+		return 1
+	case b == nil:
+		return -1
+	}
+	c, ok := compareValues(a, b)
+	if !ok {
+		c = strings.Compare(fmt.Sprint(a), fmt.Sprint(b))
+	}
+	if k.desc {
+		return -c
+	}
+	return c
+}
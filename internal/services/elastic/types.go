@@ -7,10 +7,22 @@ import (
 
 // ESSearchResult represents the response from Elasticsearch
 type ESSearchResult struct {
-	Took     int          `json:"took"`
-	TimedOut bool         `json:"timed_out"`
-	Hits     ESSearchHits `json:"hits"`
-	ScrollID string       `json:"_scroll_id,omitempty"`
+	Took         int             `json:"took"`
+	TimedOut     bool            `json:"timed_out"`
+	Hits         ESSearchHits    `json:"hits"`
+	Shards       ESShards        `json:"_shards"`
+	ScrollID     string          `json:"_scroll_id,omitempty"`
+	Aggregations json.RawMessage `json:"aggregations,omitempty"`
+	Profile      json.RawMessage `json:"profile,omitempty"`
+}
+
+// ESShards contains the shard summary of a search response
+type ESShards struct {
+	Total      int               `json:"total"`
+	Successful int               `json:"successful"`
+	Skipped    int               `json:"skipped"`
+	Failed     int               `json:"failed"`
+	Failures   []json.RawMessage `json:"failures,omitempty"`
 }
 
 // ESSearchHits contains the hits part of the response
@@ -28,12 +40,13 @@ type ESTotal struct {
 
 // ESSearchHit represents a single hit in the search results
 type ESSearchHit struct {
-	Index   string          `json:"_index"`
-	Type    string          `json:"_type"`
-	ID      string          `json:"_id"`
-	Score   *float64        `json:"_score"`
-	Source  json.RawMessage `json:"_source"`
-	Version *int64          `json:"_version,omitempty"`
+	Index     string              `json:"_index"`
+	Type      string              `json:"_type"`
+	ID        string              `json:"_id"`
+	Score     *float64            `json:"_score"`
+	Source    json.RawMessage     `json:"_source"`
+	Version   *int64              `json:"_version,omitempty"`
+	Highlight map[string][]string `json:"highlight,omitempty"`
 }
 
 func (t *ESTotal) UnmarshalJSON(data []byte) error {
@@ -0,0 +1,144 @@
+// Package session saves what each profile was looking at to ~/.cloudcutter/state.json, so the next
+// start can offer to pick up where it left off.
+package session
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// State is what a profile's session showed when it was saved: the region and view it was in, and
+// each view's query and layout.
+type State struct {
+	Region   string         `json:"region,omitempty"`
+	View     string         `json:"view,omitempty"`
+	Elastic  *ElasticState  `json:"elastic,omitempty"`
+	DynamoDB *DynamoDBState `json:"dynamodb,omitempty"`
+	SavedAt  time.Time      `json:"saved_at"`
+}
+
+// ElasticState is the Elastic view's search and layout.
+type ElasticState struct {
+	Index          string   `json:"index,omitempty"`
+	Filters        []string `json:"filters,omitempty"`
+	Timeframe      string   `json:"timeframe,omitempty"`
+	SelectedFields []string `json:"selected_fields,omitempty"` // in column order
+	NumResults     int      `json:"num_results,omitempty"`
+	RowNumbers     bool     `json:"row_numbers"`
+	FieldList      bool     `json:"field_list"` // whether the field lists are shown
+}
+
+// DynamoDBState is the DynamoDB view's table and layout.
+type DynamoDBState struct {
+	Table      string `json:"table,omitempty"`
+	RowNumbers bool   `json:"row_numbers"`
+}
+
+// stateFile is the contents of state.json.
+type stateFile struct {
+	Profiles map[string]State `json:"profiles"`
+}
+
+// Path returns the state file path.
+func Path() string {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(homeDir, ".cloudcutter", "state.json")
+}
+
+// Store holds the saved state of every profile, and writes the file whenever one changes.
+type Store struct {
+	path     string
+	mu       sync.Mutex
+	profiles map[string]State
+}
+
+// Open reads the state file at path. A missing file is an empty store; an unreadable one is an
+// error, alongside an empty store that overwrites it on the next save.
+func Open(path string) (*Store, error) {
+	s := &Store{path: path, profiles: make(map[string]State)}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return s, err
+	}
+
+	var file stateFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return s, fmt.Errorf("invalid session state %s: %w", path, err)
+	}
+	for profile, state := range file.Profiles {
+		s.profiles[profile] = state
+	}
+	return s, nil
+}
+
+// Get returns the state saved for profile.
+func (s *Store) Get(profile string) (State, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	state, ok := s.profiles[profile]
+	return state, ok
+}
+
+// Put saves state for profile, stamping the time, and writes the file.
+func (s *Store) Put(profile string, state State) error {
+	if profile == "" {
+		return errors.New("a session is saved per profile")
+	}
+	state.SavedAt = time.Now().UTC().Truncate(time.Second)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.profiles[profile] = state
+	return s.write()
+}
+
+// Delete forgets the state saved for profile.
+func (s *Store) Delete(profile string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.profiles[profile]; !ok {
+		return nil
+	}
+	delete(s.profiles, profile)
+	return s.write()
+}
+
+// write replaces the file through a temporary one, so a crash mid-write leaves the old state.
+func (s *Store) write() error {
+	if s.path == "" {
+		return errors.New("no home directory to save the session in")
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o700); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(stateFile{Profiles: s.profiles}, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), ".state-*.json")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), s.path)
+}
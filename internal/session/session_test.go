@@ -0,0 +1,69 @@
+package session
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".cloudcutter", "state.json")
+
+	store, err := Open(path)
+	assert.NoError(t, err, "a missing file is an empty store")
+	_, ok := store.Get("opal_dev")
+	assert.False(t, ok)
+
+	saved := State{
+		Region: "eu-west-1",
+		View:   "elastic",
+		Elastic: &ElasticState{
+			Index:          "logs-*",
+			Filters:        []string{"level=error"},
+			Timeframe:      "1h",
+			SelectedFields: []string{"message", "host"},
+			NumResults:     500,
+			RowNumbers:     true,
+		},
+	}
+	assert.NoError(t, store.Put("opal_dev", saved))
+	assert.NoError(t, store.Put("local", State{View: "dynamodb", DynamoDB: &DynamoDBState{Table: "users"}}))
+
+	reopened, err := Open(path)
+	assert.NoError(t, err)
+	state, ok := reopened.Get("opal_dev")
+	if assert.True(t, ok) {
+		assert.False(t, state.SavedAt.IsZero(), "saving stamps the time")
+		state.SavedAt = saved.SavedAt
+		assert.Equal(t, saved, state)
+	}
+
+	assert.NoError(t, reopened.Delete("opal_dev"))
+	reopened, _ = Open(path)
+	_, ok = reopened.Get("opal_dev")
+	assert.False(t, ok)
+	_, ok = reopened.Get("local")
+	assert.True(t, ok, "other profiles are kept")
+
+	assert.EqualError(t, store.Put("", State{}), "a session is saved per profile")
+}
+
+func TestOpenInvalid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	if err := os.WriteFile(path, []byte("{not json"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	store, err := Open(path)
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "invalid session state")
+	}
+	assert.NoError(t, store.Put("local", State{View: "elastic"}), "the store still saves over a broken file")
+
+	store, err = Open(path)
+	assert.NoError(t, err)
+	_, ok := store.Get("local")
+	assert.True(t, ok)
+}
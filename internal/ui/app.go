@@ -23,13 +23,12 @@ func NewApp() *App {
 	return app
 }
 
+// QueueUpdateDraw runs f on the event loop and redraws. The draw happens in the same update:
+// Draw queues another update and waits for it, which deadlocks from the event loop.
 func (app *App) QueueUpdateDraw(f func()) {
-	app.QueueUpdate(func() {
-		f()
-		app.Draw()
-	})
+	app.Application.QueueUpdateDraw(f)
 }
 
 func (app *App) Suspend(f func()) {
-	app.Suspend(f)
+	app.Application.Suspend(f)
 }
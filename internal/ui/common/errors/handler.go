@@ -6,6 +6,8 @@ import (
 	"runtime"
 	"strings"
 	"time"
+
+	"github.com/tpelletiersophos/cloudcutter/internal/diagnostics"
 )
 
 // ErrorHandlerConfig contains configuration for error handling behavior
@@ -400,8 +402,7 @@ func (eh *ErrorHandler) logError(err *ViewError) {
 
 // recordMetrics records error metrics for monitoring
 func (eh *ErrorHandler) recordMetrics(err *ViewError) {
-	// TODO: Implement metrics recording
-	// This could integrate with Prometheus, StatsD, or other metrics systems
+	diagnostics.RecordError(string(err.Code))
 }
 
 // attemptRecovery attempts to recover from recoverable errors
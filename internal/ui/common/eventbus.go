@@ -12,6 +12,8 @@ type EventBus struct {
 	logger          Logger
 	globalHandler   GlobalShortcutHandler
 	componentMapper ComponentMapper
+	keys            *KeyResolver
+	keyContexts     map[ComponentType]string
 }
 
 type EventBusConfig struct {
@@ -19,6 +21,8 @@ type EventBusConfig struct {
 	ErrorHandler  ErrorHandler
 	Logger        Logger
 	GlobalHandler GlobalShortcutHandler
+	// KeyContexts names the keymap context of each component; others use the view's name.
+	KeyContexts map[ComponentType]string
 }
 
 func NewEventBus(config *EventBusConfig) *EventBus {
@@ -29,6 +33,8 @@ func NewEventBus(config *EventBusConfig) *EventBus {
 		logger:          config.Logger,
 		globalHandler:   config.GlobalHandler,
 		componentMapper: config.View.GetComponentMapper(),
+		keys:            NewKeyResolver(),
+		keyContexts:     config.KeyContexts,
 	}
 }
 
@@ -40,8 +46,13 @@ func (eb *EventBus) RegisterHandler(componentType ComponentType, handler Compone
 }
 
 func (eb *EventBus) ProcessEvent(event *tcell.EventKey, currentFocus tview.Primitive) *tcell.EventKey {
-	// Try component handlers first
 	componentType := eb.componentMapper.GetComponentType(currentFocus)
+	action, pending := eb.keys.Resolve(eb.keyContext(componentType), event)
+	if pending {
+		return nil
+	}
+
+	// Try component handlers first
 	if componentType != nil {
 		if handler, exists := eb.handlers[*componentType]; exists {
 			if handler.CanHandle(event, currentFocus) {
@@ -49,6 +60,7 @@ func (eb *EventBus) ProcessEvent(event *tcell.EventKey, currentFocus tview.Primi
 					View:          eb.view,
 					Component:     currentFocus,
 					ComponentType: componentType,
+					Action:        action,
 					ErrorHandler:  eb.errorHandler,
 					Logger:        eb.logger,
 				}
@@ -67,19 +79,34 @@ func (eb *EventBus) ProcessEvent(event *tcell.EventKey, currentFocus tview.Primi
 	}
 
 	// Handle common shortcuts (Tab navigation)
-	if result := eb.handleCommonShortcuts(event, currentFocus); result == nil {
+	if result := eb.handleCommonShortcuts(event, action, currentFocus); result == nil {
 		return nil
 	}
 
 	return event
 }
 
-func (eb *EventBus) handleCommonShortcuts(event *tcell.EventKey, currentFocus tview.Primitive) *tcell.EventKey {
-	switch event.Key() {
-	case tcell.KeyTab:
+// KeyContext is the keymap context of the component focus is on.
+func (eb *EventBus) KeyContext(focus tview.Primitive) string {
+	return eb.keyContext(eb.componentMapper.GetComponentType(focus))
+}
+
+// keyContext is the keymap context of the focused component.
+func (eb *EventBus) keyContext(componentType *ComponentType) string {
+	if componentType != nil {
+		if context, ok := eb.keyContexts[*componentType]; ok {
+			return context
+		}
+	}
+	return eb.view.GetName()
+}
+
+func (eb *EventBus) handleCommonShortcuts(event *tcell.EventKey, action string, currentFocus tview.Primitive) *tcell.EventKey {
+	switch action {
+	case "focus_next":
 		eb.handleBasicNavigation(currentFocus, true)
 		return nil
-	case tcell.KeyBacktab:
+	case "focus_prev":
 		eb.handleBasicNavigation(currentFocus, false)
 		return nil
 	}
@@ -1,8 +1,12 @@
 package common
 
 import (
+	"errors"
+
 	"github.com/gdamore/tcell/v2"
 	"github.com/rivo/tview"
+	"github.com/tpelletiersophos/cloudcutter/internal/diagnostics"
+	commonErrors "github.com/tpelletiersophos/cloudcutter/internal/ui/common/errors"
 )
 
 type BaseHandler struct {
@@ -44,25 +48,33 @@ func (h *DefaultGlobalShortcutHandler) HandleGlobalShortcut(event *tcell.EventKe
 
 type SimpleErrorHandler struct {
 	manager ManagerInterface
+	source  string // the view whose errors it reports
 	logger  Logger
 }
 
-func NewSimpleErrorHandler(mgr ManagerInterface, logger Logger) *SimpleErrorHandler {
-	return &SimpleErrorHandler{manager: mgr, logger: logger}
+func NewSimpleErrorHandler(mgr ManagerInterface, source string, logger Logger) *SimpleErrorHandler {
+	return &SimpleErrorHandler{manager: mgr, source: source, logger: logger}
 }
 
 func (h *SimpleErrorHandler) HandleError(err error) {
+	code := commonErrors.ErrorCodeUnknownError
+	var viewErr *commonErrors.ViewError
+	if errors.As(err, &viewErr) {
+		code = viewErr.Code
+	}
+	diagnostics.RecordError(string(code))
+
 	if h.logger != nil {
 		h.logger.Error("Error occurred", "error", err)
 	}
 	if h.manager != nil {
-		h.manager.UpdateStatusBar("Error: " + err.Error())
+		h.manager.NotifyError(h.source, "Error: "+err.Error())
 	}
 }
 
 func (h *SimpleErrorHandler) UpdateStatus(message string) {
 	if h.manager != nil {
-		h.manager.UpdateStatusBar(message)
+		h.manager.NotifyInfo(h.source, message)
 	}
 }
 
@@ -28,6 +28,7 @@ type HandlerContext struct {
 	View          ViewInterface
 	Component     tview.Primitive
 	ComponentType *ComponentType
+	Action        string // the keymap action the event completes, if any
 	ErrorHandler  ErrorHandler
 	Logger        Logger
 }
@@ -48,7 +49,8 @@ type GlobalShortcutHandler interface {
 }
 
 type ManagerInterface interface {
-	UpdateStatusBar(message string)
+	NotifyInfo(source, message string)
+	NotifyError(source, message string)
 	HideAllModals()
 	SetFocus(primitive tview.Primitive)
 	Stop()
@@ -0,0 +1,464 @@
+package common
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// KeymapGlobal is the context of the bindings the manager handles in every view. Other contexts
+// are a view's name, with its panes below it, e.g. "elastic.results"; a binding in a context is
+// live wherever the focus is in that context or below it.
+const KeymapGlobal = "global"
+
+// ChordTimeout is how long a chord such as `g g` waits for its next key.
+const ChordTimeout = time.Second
+
+// Key is a single key press: a rune, or a special key such as tcell.KeyEnter, with its modifiers.
+type Key struct {
+	Key  tcell.Key
+	Rune rune
+	Mod  tcell.ModMask
+}
+
+// KeySequence is one key, or a chord of keys pressed one after another.
+type KeySequence []Key
+
+// Binding binds an action in a context to one or more key sequences.
+type Binding struct {
+	Context     string
+	Action      string
+	Description string
+	Keys        []KeySequence
+	Custom      bool // set by config.yaml rather than the defaults
+}
+
+// KeysString lists the binding's keys as they are written in config.yaml.
+func (b Binding) KeysString() string {
+	keys := make([]string, len(b.Keys))
+	for i, seq := range b.Keys {
+		keys[i] = seq.String()
+	}
+	return strings.Join(keys, ", ")
+}
+
+// namedKeys are the special keys a key spec can name, besides single characters.
+var namedKeys = map[string]Key{
+	"enter":     {Key: tcell.KeyEnter},
+	"esc":       {Key: tcell.KeyEsc},
+	"escape":    {Key: tcell.KeyEsc},
+	"tab":       {Key: tcell.KeyTab},
+	"backtab":   {Key: tcell.KeyBacktab},
+	"backspace": {Key: tcell.KeyBackspace2},
+	"delete":    {Key: tcell.KeyDelete},
+	"insert":    {Key: tcell.KeyInsert},
+	"up":        {Key: tcell.KeyUp},
+	"down":      {Key: tcell.KeyDown},
+	"left":      {Key: tcell.KeyLeft},
+	"right":     {Key: tcell.KeyRight},
+	"home":      {Key: tcell.KeyHome},
+	"end":       {Key: tcell.KeyEnd},
+	"pgup":      {Key: tcell.KeyPgUp},
+	"pgdn":      {Key: tcell.KeyPgDn},
+	"space":     {Key: tcell.KeyRune, Rune: ' '},
+	"comma":     {Key: tcell.KeyRune, Rune: ','},
+}
+
+// ParseKey parses a key spec such as "g", "G", "enter", "ctrl+r", "alt+1" or "shift+tab".
+func ParseKey(spec string) (Key, error) {
+	parts := strings.Split(spec, "+")
+	name := parts[len(parts)-1]
+	if name == "" && len(parts) > 1 {
+		// "ctrl++" binds the plus key
+		name, parts = "+", parts[:len(parts)-2]
+	} else {
+		parts = parts[:len(parts)-1]
+	}
+
+	var mod tcell.ModMask
+	for _, modifier := range parts {
+		switch strings.ToLower(modifier) {
+		case "ctrl":
+			mod |= tcell.ModCtrl
+		case "alt":
+			mod |= tcell.ModAlt
+		case "shift":
+			mod |= tcell.ModShift
+		default:
+			return Key{}, fmt.Errorf("unknown modifier %q in %q", modifier, spec)
+		}
+	}
+
+	var key Key
+	if utf8.RuneCountInString(name) == 1 {
+		key = Key{Key: tcell.KeyRune, Rune: []rune(name)[0]}
+	} else if named, ok := namedKeys[strings.ToLower(name)]; ok {
+		key = named
+	} else if n, ok := functionKeyNumber(name); ok {
+		key = Key{Key: tcell.KeyF1 + tcell.Key(n-1)}
+	} else {
+		return Key{}, fmt.Errorf("unknown key %q in %q", name, spec)
+	}
+
+	switch {
+	case mod&tcell.ModShift != 0 && key.Key == tcell.KeyTab:
+		key.Key, mod = tcell.KeyBacktab, mod&^tcell.ModShift
+	case mod&tcell.ModShift != 0 && key.Key == tcell.KeyRune:
+		key.Rune, mod = []rune(strings.ToUpper(string(key.Rune)))[0], mod&^tcell.ModShift
+	}
+	if mod&tcell.ModCtrl != 0 && key.Key == tcell.KeyRune {
+		letter := []rune(strings.ToLower(string(key.Rune)))[0]
+		switch {
+		case letter == 'h' || letter == 'i' || letter == 'm':
+			return Key{}, fmt.Errorf("%q cannot be told apart from backspace, tab or enter", spec)
+		case letter < 'a' || letter > 'z':
+			return Key{}, fmt.Errorf("%q: ctrl only combines with letters and special keys", spec)
+		}
+		key = Key{Key: tcell.KeyCtrlA + tcell.Key(letter-'a')}
+	}
+	key.Mod = mod
+	return key, nil
+}
+
+func functionKeyNumber(name string) (int, bool) {
+	lower := strings.ToLower(name)
+	if !strings.HasPrefix(lower, "f") {
+		return 0, false
+	}
+	n, err := strconv.Atoi(lower[1:])
+	return n, err == nil && n >= 1 && n <= 12
+}
+
+// ParseKeySequence parses a key, or a chord of keys separated by spaces such as "g g".
+func ParseKeySequence(spec string) (KeySequence, error) {
+	fields := strings.Fields(spec)
+	if len(fields) == 0 {
+		return nil, errors.New("must name a key")
+	}
+	seq := make(KeySequence, len(fields))
+	for i, field := range fields {
+		key, err := ParseKey(field)
+		if err != nil {
+			return nil, err
+		}
+		seq[i] = key
+	}
+	return seq, nil
+}
+
+// parseKeys parses a comma-separated list of key sequences, e.g. "n, pgdn".
+func parseKeys(spec string) ([]KeySequence, error) {
+	var keys []KeySequence
+	for _, part := range strings.Split(spec, ",") {
+		seq, err := ParseKeySequence(part)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, seq)
+	}
+	return keys, nil
+}
+
+// KeyOfEvent returns the key an event reports, normalized the way ParseKey writes it.
+func KeyOfEvent(event *tcell.EventKey) Key {
+	key, mod := event.Key(), event.Modifiers()
+	switch {
+	case key == tcell.KeyRune:
+		// Shift is already in the rune
+		return Key{Key: tcell.KeyRune, Rune: event.Rune(), Mod: mod &^ tcell.ModShift}
+	case key == tcell.KeyBackspace && mod&tcell.ModCtrl == 0:
+		key = tcell.KeyBackspace2
+	case key == tcell.KeyBacktab:
+		mod &^= tcell.ModShift
+	case key >= tcell.KeyCtrlA && key <= tcell.KeyCtrlZ && key != tcell.KeyTab && key != tcell.KeyEnter:
+		mod |= tcell.ModCtrl
+	}
+	return Key{Key: key, Mod: mod}
+}
+
+// String writes the key the way ParseKey reads it.
+func (k Key) String() string {
+	var prefix string
+	if k.Mod&tcell.ModCtrl != 0 {
+		prefix += "ctrl+"
+	}
+	if k.Mod&tcell.ModAlt != 0 {
+		prefix += "alt+"
+	}
+	if k.Mod&tcell.ModShift != 0 {
+		prefix += "shift+"
+	}
+
+	switch {
+	case k.Key == tcell.KeyRune && k.Rune == ' ':
+		return prefix + "space"
+	case k.Key == tcell.KeyRune && k.Rune == ',':
+		return prefix + "comma"
+	case k.Key == tcell.KeyRune:
+		return prefix + string(k.Rune)
+	case k.Key == tcell.KeyBacktab:
+		return prefix + "shift+tab"
+	case k.Key >= tcell.KeyCtrlA && k.Key <= tcell.KeyCtrlZ && k.Key != tcell.KeyTab && k.Key != tcell.KeyEnter:
+		return prefix + string(rune('a'+k.Key-tcell.KeyCtrlA))
+	case k.Key >= tcell.KeyF1 && k.Key <= tcell.KeyF12:
+		return fmt.Sprintf("%sf%d", prefix, k.Key-tcell.KeyF1+1)
+	}
+	for name, named := range namedKeys {
+		if named.Key == k.Key && named.Rune == 0 && name != "escape" {
+			return prefix + name
+		}
+	}
+	return prefix + strings.ToLower(tcell.KeyNames[k.Key])
+}
+
+// String writes the sequence the way ParseKeySequence reads it.
+func (s KeySequence) String() string {
+	keys := make([]string, len(s))
+	for i, key := range s {
+		keys[i] = key.String()
+	}
+	return strings.Join(keys, " ")
+}
+
+// hasPrefix reports whether s starts with prefix.
+func (s KeySequence) hasPrefix(prefix KeySequence) bool {
+	return len(prefix) <= len(s) && slices.Equal(s[:len(prefix)], prefix)
+}
+
+// Keymap holds the key bindings of the manager and every view.
+type Keymap struct {
+	bindings []Binding
+}
+
+// NewKeymap returns the default keymap with overrides applied. Overrides map a context, then an
+// action, to its new keys, e.g. {"elastic.results": {"next_page": "n, pgdn"}}; "none" unbinds the
+// action. Unknown contexts or actions, unparsable keys and conflicting bindings are errors.
+func NewKeymap(overrides map[string]map[string]string) (*Keymap, error) {
+	km := &Keymap{bindings: make([]Binding, len(defaultKeymap))}
+	for i, d := range defaultKeymap {
+		keys, err := parseKeys(d.keys)
+		if err != nil {
+			panic(fmt.Sprintf("default keys for %s.%s: %v", d.context, d.action, err))
+		}
+		km.bindings[i] = Binding{Context: d.context, Action: d.action, Description: d.description, Keys: keys}
+	}
+
+	contexts := make([]string, 0, len(overrides))
+	for context := range overrides {
+		contexts = append(contexts, context)
+	}
+	sort.Strings(contexts)
+	for _, context := range contexts {
+		actions := make([]string, 0, len(overrides[context]))
+		for action := range overrides[context] {
+			actions = append(actions, action)
+		}
+		sort.Strings(actions)
+		for _, action := range actions {
+			if err := km.override(context, action, overrides[context][action]); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if err := km.conflicts(); err != nil {
+		return nil, err
+	}
+	return km, nil
+}
+
+func (km *Keymap) override(context, action, spec string) error {
+	if strings.TrimSpace(spec) == "" {
+		return fmt.Errorf("%s.%s must name a key", context, action)
+	}
+	i := slices.IndexFunc(km.bindings, func(b Binding) bool { return b.Context == context && b.Action == action })
+	if i < 0 {
+		if !slices.ContainsFunc(km.bindings, func(b Binding) bool { return b.Context == context }) {
+			return fmt.Errorf("%s: unknown context", context)
+		}
+		return fmt.Errorf("%s.%s: unknown action", context, action)
+	}
+	km.bindings[i].Custom = true
+	if strings.TrimSpace(spec) == "none" {
+		km.bindings[i].Keys = nil
+		return nil
+	}
+	keys, err := parseKeys(spec)
+	if err != nil {
+		return fmt.Errorf("%s.%s: %w", context, action, err)
+	}
+	km.bindings[i].Keys = keys
+	return nil
+}
+
+// conflicts reports bindings that are live at the same time and share a key sequence, or where one
+// sequence starts another so the longer one could never be typed.
+func (km *Keymap) conflicts() error {
+	var conflicts []string
+	for i, a := range km.bindings {
+		for _, b := range km.bindings[i+1:] {
+			if !overlaps(a.Context, b.Context) {
+				continue
+			}
+			for _, keysA := range a.Keys {
+				for _, keysB := range b.Keys {
+					if keysA.hasPrefix(keysB) || keysB.hasPrefix(keysA) {
+						conflicts = append(conflicts, fmt.Sprintf("%s.%s (%s) conflicts with %s.%s (%s)",
+							a.Context, a.Action, keysA, b.Context, b.Action, keysB))
+					}
+				}
+			}
+		}
+	}
+	if len(conflicts) > 0 {
+		return errors.New(strings.Join(conflicts, "; "))
+	}
+	return nil
+}
+
+// overlaps reports whether bindings in contexts a and b can be live at the same time.
+func overlaps(a, b string) bool {
+	return a == KeymapGlobal || b == KeymapGlobal || a == b ||
+		strings.HasPrefix(a, b+".") || strings.HasPrefix(b, a+".")
+}
+
+// Bindings returns the bindings of context, or of every context when context is "".
+func (km *Keymap) Bindings(context string) []Binding {
+	var bindings []Binding
+	for _, b := range km.bindings {
+		if context == "" || b.Context == context {
+			bindings = append(bindings, b)
+		}
+	}
+	return bindings
+}
+
+// Keys returns the keys bound to action in context, or in the nearest context above it that binds
+// the action, e.g. "ctrl+r"; "" when it is unbound.
+func (km *Keymap) Keys(context, action string) string {
+	for _, ctx := range chain(context) {
+		for _, b := range km.bindings {
+			if b.Context == ctx && b.Action == action {
+				return b.KeysString()
+			}
+		}
+	}
+	return ""
+}
+
+// chain lists context and the contexts above it, nearest first. Global bindings belong to the
+// manager, so a view's chain stops below them.
+func chain(context string) []string {
+	contexts := []string{context}
+	for {
+		i := strings.LastIndex(context, ".")
+		if i < 0 {
+			return contexts
+		}
+		context = context[:i]
+		contexts = append(contexts, context)
+	}
+}
+
+var (
+	keymapMu      sync.RWMutex
+	currentKeymap *Keymap
+)
+
+// UseKeymap makes km the keymap every KeyResolver reads.
+func UseKeymap(km *Keymap) {
+	keymapMu.Lock()
+	defer keymapMu.Unlock()
+	currentKeymap = km
+}
+
+// CurrentKeymap returns the keymap in use, the defaults until UseKeymap is called.
+func CurrentKeymap() *Keymap {
+	keymapMu.RLock()
+	km := currentKeymap
+	keymapMu.RUnlock()
+	if km != nil {
+		return km
+	}
+
+	km, err := NewKeymap(nil)
+	if err != nil {
+		panic(fmt.Sprintf("default keymap: %v", err))
+	}
+	keymapMu.Lock()
+	defer keymapMu.Unlock()
+	if currentKeymap == nil {
+		currentKeymap = km
+	}
+	return currentKeymap
+}
+
+// KeyResolver turns key events into the actions of the current keymap, remembering the keys of a
+// chord typed so far. Each input handler keeps its own.
+type KeyResolver struct {
+	pending     KeySequence
+	pendingAt   time.Time
+	pendingCtxt string
+}
+
+// NewKeyResolver returns a resolver with no chord in progress.
+func NewKeyResolver() *KeyResolver {
+	return &KeyResolver{}
+}
+
+// Resolve returns the action event completes in context. It returns pending true, and no action,
+// when the event starts or continues a chord; the caller should swallow the event then.
+func (r *KeyResolver) Resolve(context string, event *tcell.EventKey) (action string, pending bool) {
+	km := CurrentKeymap()
+	key := KeyOfEvent(event)
+
+	seq := KeySequence{key}
+	if len(r.pending) > 0 && r.pendingCtxt == context && time.Since(r.pendingAt) < ChordTimeout {
+		seq = append(slices.Clone(r.pending), key)
+	}
+	r.pending = nil
+
+	for {
+		action, prefix := km.match(context, seq)
+		if action != "" {
+			return action, false
+		}
+		if prefix {
+			r.pending, r.pendingAt, r.pendingCtxt = seq, time.Now(), context
+			return "", true
+		}
+		if len(seq) == 1 {
+			return "", false
+		}
+		// The chord broke off; the last key may still mean something on its own
+		seq = KeySequence{key}
+	}
+}
+
+// match finds the action seq is bound to in context, or whether seq starts a longer binding.
+func (km *Keymap) match(context string, seq KeySequence) (action string, prefix bool) {
+	for _, ctx := range chain(context) {
+		for _, b := range km.bindings {
+			if b.Context != ctx {
+				continue
+			}
+			for _, keys := range b.Keys {
+				switch {
+				case slices.Equal(keys, seq):
+					return b.Action, false
+				case keys.hasPrefix(seq):
+					prefix = true
+				}
+			}
+		}
+	}
+	return "", prefix
+}
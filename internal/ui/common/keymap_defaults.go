@@ -0,0 +1,90 @@
+package common
+
+// defaultKeymap is the keymap before config.yaml overrides it. Keys are written as in config.yaml:
+// alternatives separated by commas, chords by spaces.
+var defaultKeymap = []struct {
+	context, action, keys, description string
+}{
+	{KeymapGlobal, "command_prompt", ":", "Open the command prompt"},
+	{KeymapGlobal, "help", "?", "Show help"},
+	{KeymapGlobal, "notifications", "alt+n", "Show the notifications"},
+	{KeymapGlobal, "new_tab", "ctrl+t", "Open a session tab"},
+	{KeymapGlobal, "next_tab", "alt+right", "Go to the next session tab"},
+	{KeymapGlobal, "prev_tab", "alt+left", "Go to the previous session tab"},
+	{KeymapGlobal, "tab_1", "alt+1", "Go to session tab 1"},
+	{KeymapGlobal, "tab_2", "alt+2", "Go to session tab 2"},
+	{KeymapGlobal, "tab_3", "alt+3", "Go to session tab 3"},
+	{KeymapGlobal, "tab_4", "alt+4", "Go to session tab 4"},
+	{KeymapGlobal, "tab_5", "alt+5", "Go to session tab 5"},
+	{KeymapGlobal, "tab_6", "alt+6", "Go to session tab 6"},
+	{KeymapGlobal, "tab_7", "alt+7", "Go to session tab 7"},
+	{KeymapGlobal, "tab_8", "alt+8", "Go to session tab 8"},
+	{KeymapGlobal, "tab_9", "alt+9", "Go to session tab 9"},
+
+	{"elastic", "focus_next", "tab", "Focus the next pane"},
+	{"elastic", "focus_prev", "shift+tab", "Focus the previous pane"},
+	{"elastic", "focus_fields", "ctrl+a", "Focus the available fields"},
+	{"elastic", "focus_selected", "ctrl+s", "Focus the selected fields"},
+	{"elastic", "focus_results", "ctrl+r", "Focus the results"},
+	{"elastic.filters", "delete_filter", "delete, backspace", "Delete the selected filter"},
+	{"elastic.fields", "down", "j", "Move down"},
+	{"elastic.fields", "up", "k", "Move up"},
+	{"elastic.fields", "field_stats", "enter", "Show the field's value statistics"},
+	{"elastic.fields", "toggle_field", "space", "Add the field as a column"},
+	{"elastic.fields", "focus_selected", "s", "Focus the selected fields"},
+	{"elastic.fields", "filter", "/", "Filter the fields"},
+	{"elastic.fields", "clear_filter", "backspace", "Clear the field filter"},
+	{"elastic.selected", "down", "j", "Move down"},
+	{"elastic.selected", "up", "k", "Move up"},
+	{"elastic.selected", "move_down", "J", "Move the field down the column order"},
+	{"elastic.selected", "move_up", "K", "Move the field up the column order"},
+	{"elastic.selected", "toggle_field", "enter", "Deselect the field"},
+	{"elastic.selected", "focus_fields", "a", "Focus the available fields"},
+	{"elastic.results", "open_document", "enter", "Show the full document"},
+	{"elastic.results", "first_row", "g g", "Go to the first row"},
+	{"elastic.results", "last_row", "G", "Go to the last row"},
+	{"elastic.results", "next_page", "n", "Next page"},
+	{"elastic.results", "previous_page", "p", "Previous page"},
+	{"elastic.results", "filter", "/", "Filter the results"},
+	{"elastic.results", "toggle_row_numbers", "r", "Show or hide row numbers"},
+	{"elastic.results", "toggle_fields", "f", "Show or hide the field lists"},
+	{"elastic.results", "toggle_highlighting", "H", "Turn search highlighting on or off"},
+	{"elastic.results", "inspect_query", "i", "Inspect the query and the document's score"},
+	{"elastic.results", "compare", "C", "Compare the search against another index or cluster"},
+	{"elastic.results", "delete_document", "D", "Delete the document"},
+	{"elastic.results", "update_by_query", "U", "Update every document the search matches"},
+	{"elastic.results", "delete_by_query", "X", "Delete every document the search matches"},
+	{"elastic.results", "focus_fields", "a", "Focus the available fields"},
+	{"elastic.results", "focus_selected", "s", "Focus the selected fields"},
+	{"elastic.local_filter", "filter", "/", "Filter the results"},
+	{"elastic.field_stats", "add_filter", "enter", "Filter on the selected value"},
+	{"elastic.field_stats", "close", "esc", "Close the field statistics"},
+	{"elastic.inspector", "validate", "v", "Validate the query"},
+	{"elastic.inspector", "explain", "e", "Explain the selected document's score"},
+	{"elastic.inspector", "explain_document", "E", "Explain the score of any index/_id"},
+	{"elastic.inspector", "profile", "p", "Run the query with profiling"},
+	{"elastic.inspector", "close", "q, esc", "Close the inspector"},
+	{"elastic.compare", "toggle_coverage", "c", "Switch between field coverage and the document diff"},
+	{"elastic.compare", "close", "q, esc", "Close the comparison"},
+
+	{"dynamodb", "focus_next", "tab", "Focus the next pane"},
+	{"dynamodb", "focus_prev", "shift+tab", "Focus the previous pane"},
+	{"dynamodb.tables", "open_table", "enter", "Show the table's items"},
+	{"dynamodb.tables", "filter", "/", "Filter the tables"},
+	{"dynamodb.tables", "clear_filter", "backspace", "Clear the table filter"},
+	{"dynamodb.items", "open_item", "enter", "Show the item"},
+	{"dynamodb.items", "first_row", "g g", "Go to the first row"},
+	{"dynamodb.items", "last_row", "G", "Go to the last row"},
+	{"dynamodb.items", "next_page", "n", "Next page"},
+	{"dynamodb.items", "previous_page", "p", "Previous page"},
+	{"dynamodb.items", "filter", "/", "Filter the items"},
+	{"dynamodb.items", "toggle_row_numbers", "r", "Show or hide row numbers"},
+
+	{"logs", "focus_next", "tab", "Switch between the entries and the entry"},
+	{"logs", "search", "/", "Search the entries"},
+	{"logs", "level", "l", "Cycle the lowest level shown"},
+	{"logs", "follow", "f", "Follow new entries or stop following"},
+	{"logs", "first_row", "g g", "Go to the first entry"},
+	{"logs", "last_row", "G", "Go to the last entry"},
+	{"logs", "diagnostics", "d", "Show the diagnostics panel"},
+}
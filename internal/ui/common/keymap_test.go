@@ -0,0 +1,145 @@
+package common
+
+import (
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseKey(t *testing.T) {
+	tests := []struct {
+		spec     string
+		expected Key
+		written  string
+	}{
+		{spec: "g", expected: Key{Key: tcell.KeyRune, Rune: 'g'}, written: "g"},
+		{spec: "shift+g", expected: Key{Key: tcell.KeyRune, Rune: 'G'}, written: "G"},
+		{spec: "ctrl+r", expected: Key{Key: tcell.KeyCtrlR, Mod: tcell.ModCtrl}, written: "ctrl+r"},
+		{spec: "Ctrl+R", expected: Key{Key: tcell.KeyCtrlR, Mod: tcell.ModCtrl}, written: "ctrl+r"},
+		{spec: "alt+1", expected: Key{Key: tcell.KeyRune, Rune: '1', Mod: tcell.ModAlt}, written: "alt+1"},
+		{spec: "alt+right", expected: Key{Key: tcell.KeyRight, Mod: tcell.ModAlt}, written: "alt+right"},
+		{spec: "shift+tab", expected: Key{Key: tcell.KeyBacktab}, written: "shift+tab"},
+		{spec: "enter", expected: Key{Key: tcell.KeyEnter}, written: "enter"},
+		{spec: "backspace", expected: Key{Key: tcell.KeyBackspace2}, written: "backspace"},
+		{spec: "f5", expected: Key{Key: tcell.KeyF5}, written: "f5"},
+		{spec: "space", expected: Key{Key: tcell.KeyRune, Rune: ' '}, written: "space"},
+		{spec: "alt++", expected: Key{Key: tcell.KeyRune, Rune: '+', Mod: tcell.ModAlt}, written: "alt++"},
+	}
+	for _, tt := range tests {
+		key, err := ParseKey(tt.spec)
+		if assert.NoError(t, err, tt.spec) {
+			assert.Equal(t, tt.expected, key, tt.spec)
+			assert.Equal(t, tt.written, key.String(), tt.spec)
+		}
+	}
+
+	for spec, expected := range map[string]string{
+		"hyper+a":  `unknown modifier "hyper" in "hyper+a"`,
+		"pagedown": `unknown key "pagedown" in "pagedown"`,
+		"ctrl+i":   `"ctrl+i" cannot be told apart from backspace, tab or enter`,
+		"ctrl+1":   `"ctrl+1": ctrl only combines with letters and special keys`,
+	} {
+		_, err := ParseKey(spec)
+		assert.EqualError(t, err, expected, spec)
+	}
+}
+
+func TestKeyOfEvent(t *testing.T) {
+	tests := []struct {
+		event *tcell.EventKey
+		spec  string
+	}{
+		{event: tcell.NewEventKey(tcell.KeyCtrlR, 0, tcell.ModCtrl), spec: "ctrl+r"},
+		{event: tcell.NewEventKey(tcell.KeyRune, 'J', tcell.ModShift), spec: "J"},
+		{event: tcell.NewEventKey(tcell.KeyBacktab, 0, tcell.ModShift), spec: "shift+tab"},
+		{event: tcell.NewEventKey(tcell.KeyBackspace, 0, tcell.ModNone), spec: "backspace"},
+		{event: tcell.NewEventKey(tcell.KeyTab, 0, tcell.ModNone), spec: "tab"},
+		{event: tcell.NewEventKey(tcell.KeyRune, '1', tcell.ModAlt), spec: "alt+1"},
+	}
+	for _, tt := range tests {
+		key, err := ParseKey(tt.spec)
+		assert.NoError(t, err)
+		assert.Equal(t, key, KeyOfEvent(tt.event), tt.spec)
+	}
+}
+
+func TestNewKeymap(t *testing.T) {
+	km, err := NewKeymap(nil)
+	assert.NoError(t, err, "the defaults must not conflict")
+	assert.Equal(t, "g g", km.Keys("elastic.results", "first_row"))
+
+	km, err = NewKeymap(map[string]map[string]string{
+		"elastic.results": {"next_page": "n, pgdn", "compare": "none"},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "n, pgdn", km.Keys("elastic.results", "next_page"))
+	assert.Equal(t, "", km.Keys("elastic.results", "compare"))
+	assert.Equal(t, "tab", km.Keys("elastic.compare", "focus_next"), "inherited from the view")
+	for _, b := range km.Bindings("elastic.results") {
+		assert.Equal(t, b.Action == "next_page" || b.Action == "compare", b.Custom, b.Action)
+	}
+
+	tests := []struct {
+		overrides map[string]map[string]string
+		expected  string
+	}{
+		{overrides: map[string]map[string]string{"s3": {"open": "o"}}, expected: "s3: unknown context"},
+		{overrides: map[string]map[string]string{"global": {"quit": "q"}}, expected: "global.quit: unknown action"},
+		{overrides: map[string]map[string]string{"global": {"help": "ctrl+?"}}, expected: `global.help: "ctrl+?": ctrl only combines with letters and special keys`},
+		{overrides: map[string]map[string]string{"global": {"help": " "}}, expected: "global.help must name a key"},
+		{
+			overrides: map[string]map[string]string{"dynamodb.items": {"filter": "n"}},
+			expected:  "dynamodb.items.next_page (n) conflicts with dynamodb.items.filter (n)",
+		},
+		{
+			overrides: map[string]map[string]string{"global": {"help": "r"}},
+			expected:  "global.help (r) conflicts with elastic.results.toggle_row_numbers (r); global.help (r) conflicts with dynamodb.items.toggle_row_numbers (r)",
+		},
+	}
+	for _, tt := range tests {
+		_, err := NewKeymap(tt.overrides)
+		assert.EqualError(t, err, tt.expected)
+	}
+
+	_, err = NewKeymap(map[string]map[string]string{"elastic.fields": {"filter": "n"}, "dynamodb.items": {"filter": "f"}})
+	assert.NoError(t, err, "panes of different views and sibling panes do not conflict")
+}
+
+func TestKeyResolver(t *testing.T) {
+	km, err := NewKeymap(map[string]map[string]string{"dynamodb.items": {"next_page": "ctrl+n, ] ]"}})
+	assert.NoError(t, err)
+	UseKeymap(km)
+	t.Cleanup(func() { UseKeymap(nil) })
+
+	r := NewKeyResolver()
+	typed := func(ch rune) *tcell.EventKey { return tcell.NewEventKey(tcell.KeyRune, ch, tcell.ModNone) }
+
+	action, pending := r.Resolve("dynamodb.items", tcell.NewEventKey(tcell.KeyCtrlN, 0, tcell.ModCtrl))
+	assert.Equal(t, "next_page", action)
+	assert.False(t, pending)
+
+	action, pending = r.Resolve("dynamodb.items", typed('n'))
+	assert.Equal(t, "", action, "overridden keys no longer apply")
+	assert.False(t, pending)
+
+	action, pending = r.Resolve("dynamodb.items", typed(']'))
+	assert.Equal(t, "", action)
+	assert.True(t, pending, "a chord waits for its next key")
+	action, _ = r.Resolve("dynamodb.items", typed(']'))
+	assert.Equal(t, "next_page", action)
+
+	r.Resolve("dynamodb.items", typed('g'))
+	action, pending = r.Resolve("dynamodb.items", typed('r'))
+	assert.Equal(t, "toggle_row_numbers", action, "a broken chord leaves the last key to act alone")
+	assert.False(t, pending)
+
+	action, _ = r.Resolve("dynamodb.items", tcell.NewEventKey(tcell.KeyTab, 0, tcell.ModNone))
+	assert.Equal(t, "focus_next", action, "a pane inherits its view's bindings")
+
+	action, _ = r.Resolve("dynamodb.tables", typed('r'))
+	assert.Equal(t, "", action, "a pane does not see its sibling's bindings")
+
+	action, _ = r.Resolve(KeymapGlobal, tcell.NewEventKey(tcell.KeyRune, '3', tcell.ModAlt))
+	assert.Equal(t, "tab_3", action)
+}
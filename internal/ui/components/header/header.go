@@ -33,6 +33,8 @@ type Header struct {
 	actions       []Action
 	envVarRowMap  map[string]int
 	viewCommands  []ViewCommands
+	banner        string
+	summary       []types.SummaryItem
 }
 
 func NewHeader() *Header {
@@ -50,10 +52,8 @@ func NewHeader() *Header {
 		},
 	}
 
-	header.SetTitle("[::b] Cloud Cutter ").
-		SetTitleAlign(tview.AlignCenter).
-		SetTitleColor(style.GruvboxMaterial.Yellow)
-	header.SetBorder(true).SetBorderColor(tcell.ColorMediumTurquoise)
+	header.SetTitleAlign(tview.AlignCenter)
+	header.SetBorder(true)
 	header.SetDirection(tview.FlexColumn).
 		AddItem(header.leftTable, 0, 1, false).
 		AddItem(header.leftMidTable, 0, 1, false).
@@ -62,12 +62,32 @@ func NewHeader() *Header {
 
 	header.UpdateEnvVar("Profile", "default")
 	header.UpdateEnvVar("Region", "us-west-2")
-	header.setupLeftTable()
-	header.setupLeftMidTable()
+	header.UpdateEnvVar("Identity", "-")
+	header.UpdateEnvVar("Expires", "-")
+	header.ApplyTheme(style.Current())
 
 	return header
 }
 
+// ApplyTheme recolors the header and everything it shows with t.
+func (h *Header) ApplyTheme(t *style.Theme) {
+	h.SetBackgroundColor(t.Color(style.Background))
+	h.SetTitleColor(t.Color(style.Title))
+	for _, table := range []*tview.Table{h.leftTable, h.leftMidTable, h.rightMidTable, h.rightTable} {
+		table.SetBackgroundColor(t.Color(style.Background))
+	}
+
+	h.SetEnvironmentBanner(h.banner)
+	h.setupLeftTable()
+	for key, value := range h.envVars {
+		h.UpdateEnvVar(key, value)
+	}
+	h.SetViewCommands(h.viewCommands)
+	if h.summary != nil {
+		h.UpdateSummary(h.summary)
+	}
+}
+
 type SummaryItem struct {
 	Key   string
 	Value string
@@ -78,7 +98,7 @@ func (h *Header) setupLeftTable() {
 	headers := []string{"  Environment Variables", "", "  Actions"}
 	for col, headerText := range headers {
 		cell := tview.NewTableCell(headerText).
-			SetTextColor(style.GruvboxMaterial.Yellow).
+			SetTextColor(style.Color(style.Title)).
 			SetAlign(tview.AlignLeft).
 			SetSelectable(false).
 			SetAttributes(tcell.AttrBold)
@@ -89,9 +109,9 @@ func (h *Header) setupLeftTable() {
 	}
 
 	for i, action := range h.actions {
-		actionText := fmt.Sprintf("[mediumturquoise]%-10s [beige]%s", action.Shortcut, action.Description)
+		actionText := fmt.Sprintf("%s%-10s %s%s", style.Tag(style.Label), action.Shortcut, style.Tag(style.Text), action.Description)
 		actionCell := tview.NewTableCell(actionText).
-			SetTextColor(tcell.ColorBeige).
+			SetTextColor(style.Color(style.Text)).
 			SetAlign(tview.AlignLeft).
 			SetSelectable(false)
 		h.leftTable.SetCell(i+1, 2, actionCell)
@@ -106,40 +126,59 @@ func (h *Header) UpdateEnvVar(key, value string) {
 		h.envVarRowMap[key] = row
 	}
 
-	envText := fmt.Sprintf("[mediumturquoise]%-10s: [beige]%s", key, value)
+	envText := fmt.Sprintf("%s%-10s: %s%s", style.Tag(style.Label), key, style.Tag(style.Text), value)
 	envCell := tview.NewTableCell(envText).
-		SetTextColor(tcell.ColorBeige).
+		SetTextColor(style.Color(style.Text)).
 		SetAlign(tview.AlignLeft).
 		SetSelectable(false)
 	h.leftTable.SetCell(row, 0, envCell)
 	h.leftTable.SetCell(row, 1, tview.NewTableCell("  ")) // Spacer
 }
 
+// EnvVar returns the value shown for key, or "" when it isn't shown.
+func (h *Header) EnvVar(key string) string {
+	return h.envVars[key]
+}
+
+// SetEnvironmentBanner marks the header with the name of a protected environment, shown in red in
+// the title and border. An empty name restores the normal header.
+func (h *Header) SetEnvironmentBanner(name string) {
+	h.banner = name
+	if name == "" {
+		h.SetTitle("[::b] Cloud Cutter ").SetBorderColor(style.Color(style.BorderFocus))
+		return
+	}
+	h.SetTitle(fmt.Sprintf("[::b] Cloud Cutter [%s:%s:b] %s [-:-:-] ",
+		style.Color(style.Background), style.Color(style.ProdWarning), tview.Escape(name))).
+		SetBorderColor(style.Color(style.ProdWarning))
+}
+
 func (h *Header) UpdateSummary(items []types.SummaryItem) {
+	h.summary = items
 	h.rightMidTable.Clear()
 	h.rightMidTable.SetTitle("Summary").SetTitleAlign(tview.AlignRight)
 
 	h.rightMidTable.SetCell(0, 0, tview.NewTableCell("   Summary").
-		SetTextColor(style.GruvboxMaterial.Yellow).
+		SetTextColor(style.Color(style.Title)).
 		SetAlign(tview.AlignCenter).
 		SetSelectable(false).
 		SetAttributes(tcell.AttrBold))
 
 	if len(items) == 0 {
 		h.rightMidTable.SetCell(0, 0, tview.NewTableCell("No Summary Available").
-			SetTextColor(tcell.ColorBeige).
+			SetTextColor(style.Color(style.Text)).
 			SetAlign(tview.AlignLeft).
 			SetSelectable(false))
 		return
 	}
 
 	for i, item := range items {
-		keyCell := tview.NewTableCell(fmt.Sprintf("[mediumturquoise::b]%s: ", item.Key)).
-			SetTextColor(tcell.ColorMediumTurquoise).
+		keyCell := tview.NewTableCell(fmt.Sprintf("[%s::b]%s: ", style.Color(style.Label), item.Key)).
+			SetTextColor(style.Color(style.Label)).
 			SetAlign(tview.AlignLeft).
 			SetSelectable(false)
 		valueCell := tview.NewTableCell(item.Value).
-			SetTextColor(tcell.ColorBeige).
+			SetTextColor(style.Color(style.Text)).
 			SetAlign(tview.AlignLeft).
 			SetSelectable(false)
 		h.rightMidTable.SetCell(i+1, 0, keyCell)
@@ -157,20 +196,21 @@ func (h *Header) GetHeight() int {
 
 func (h *Header) setupLeftMidTable() {
 	h.leftMidTable.SetCell(0, 0, tview.NewTableCell("  View Commands").
-		SetTextColor(style.GruvboxMaterial.Yellow).
+		SetTextColor(style.Color(style.Title)).
 		SetAlign(tview.AlignLeft).
 		SetSelectable(false).
 		SetAttributes(tcell.AttrBold))
 }
 
 func (h *Header) SetViewCommands(commands []ViewCommands) {
+	h.viewCommands = commands
 	h.leftMidTable.Clear()
 	h.setupLeftMidTable()
 
 	for i, cmd := range commands {
-		cmdText := fmt.Sprintf("[mediumturquoise]%-10s [beige]%s", cmd.View, cmd.Description)
+		cmdText := fmt.Sprintf("%s%-10s %s%s", style.Tag(style.Label), cmd.View, style.Tag(style.Text), cmd.Description)
 		cmdCell := tview.NewTableCell(cmdText).
-			SetTextColor(tcell.ColorBeige).
+			SetTextColor(style.Color(style.Text)).
 			SetAlign(tview.AlignLeft).
 			SetSelectable(false)
 		h.leftMidTable.SetCell(i+1, 0, cmdCell)
@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/tpelletiersophos/cloudcutter/internal/auth"
@@ -16,6 +18,7 @@ type Handler struct {
 	region      string
 	onLoadStart func(msg string)
 	onLoadEnd   func()
+	refreshing  atomic.Bool
 }
 
 func NewProfileHandler(statusChan chan<- string, onLoadStart func(string), onLoadEnd func()) (*Handler, error) {
@@ -66,6 +69,64 @@ func (ph *Handler) SwitchProfile(ctx context.Context, profile string, callback f
 	}()
 }
 
+// SetDeviceAuthHandler registers the callback that displays a pending SSO device login.
+func (ph *Handler) SetDeviceAuthHandler(fn func(auth.DeviceAuthorization)) {
+	ph.auth.SetDeviceAuthHandler(fn)
+}
+
+// CancelLogin aborts an SSO device login waiting for approval.
+func (ph *Handler) CancelLogin() {
+	ph.auth.CancelLogin()
+}
+
+// SetMFATokenHandler registers the blocking prompt used when an assumed role requires MFA.
+func (ph *Handler) SetMFATokenHandler(fn func(auth.MFARequest) (string, error)) {
+	ph.auth.SetMFATokenHandler(fn)
+}
+
+// GetCurrentIdentity returns the caller identity ARN of the current session, if known.
+func (ph *Handler) GetCurrentIdentity() string {
+	if session := ph.auth.Current(); session != nil && session.Identity != nil {
+		return session.Identity.ARN
+	}
+	return ""
+}
+
+// GetCurrentPolicy returns the environment policy protecting the current session, if any.
+func (ph *Handler) GetCurrentPolicy() *auth.EnvironmentPolicy {
+	if session := ph.auth.Current(); session != nil {
+		return session.Policy
+	}
+	return nil
+}
+
+// GetCurrentExpiry returns when the current session's credentials expire, if known.
+func (ph *Handler) GetCurrentExpiry() (time.Time, bool) {
+	if session := ph.auth.Current(); session != nil {
+		return session.Expires()
+	}
+	return time.Time{}, false
+}
+
+// RefreshIfExpiring re-authenticates in the background when the current credentials are about to
+// expire. Calls made while a refresh is running are ignored.
+func (ph *Handler) RefreshIfExpiring(ctx context.Context) {
+	if !ph.refreshing.CompareAndSwap(false, true) {
+		return
+	}
+
+	go func() {
+		defer ph.refreshing.Store(false)
+
+		attempted, err := ph.auth.RefreshIfExpiring(ctx)
+		if err != nil {
+			ph.sendStatus(fmt.Sprintf("Credential refresh failed: %v", err))
+		} else if attempted {
+			ph.sendStatus(fmt.Sprintf("Refreshed credentials for profile: %s", ph.GetCurrentProfile()))
+		}
+	}()
+}
+
 func (ph *Handler) GetCurrentProfile() string {
 	if session := ph.auth.Current(); session != nil {
 		return session.Profile
@@ -7,6 +7,8 @@ import (
 	"sort"
 	"strings"
 
+	"github.com/tpelletiersophos/cloudcutter/internal/auth"
+	"github.com/tpelletiersophos/cloudcutter/internal/logger"
 	"github.com/tpelletiersophos/cloudcutter/internal/ui/components/statusbar"
 	"github.com/tpelletiersophos/cloudcutter/internal/ui/style"
 	"gopkg.in/ini.v1"
@@ -18,6 +20,7 @@ import (
 
 type Manager interface {
 	Pages() *tview.Pages
+	Logger() *logger.Logger
 }
 
 type Selector struct {
@@ -27,6 +30,7 @@ type Selector struct {
 	ph        *Handler
 	statusBar *statusbar.StatusBar
 	profiles  []string
+	sso       map[string]auth.SSOProfile
 	manager   Manager
 }
 
@@ -41,27 +45,31 @@ func NewSelector(ph *Handler, onSelect func(profile string), onCancel func(), st
 	}
 
 	selector.
-		SetMainTextColor(style.GruvboxMaterial.Foreground).
+		SetMainTextColor(style.Color(style.Text)).
 		SetSelectedStyle(tcell.StyleDefault.
-			Foreground(tcell.ColorLightYellow).
-			Background(tcell.ColorDarkCyan)).
+			Foreground(style.Color(style.SelectionText)).
+			Background(style.Color(style.Selection))).
 		SetBorder(true).
 		SetTitle(" Select Environment ").
 		SetTitleAlign(tview.AlignCenter).
-		SetTitleColor(style.GruvboxMaterial.Foreground).
-		SetBorderColor(tcell.ColorMediumTurquoise)
+		SetTitleColor(style.Color(style.Title)).
+		SetBorderColor(style.Color(style.BorderFocus))
 
 	// Discover available profiles
 	selector.profiles = selector.discoverProfiles()
 
-	// Add all discovered profiles
+	// Add all discovered profiles, marking those that log in through IAM Identity Center
 	for _, profile := range selector.profiles {
-		selector.AddItem(profile, "", 0, nil)
+		label := profile
+		if _, isSSO := selector.sso[profile]; isSSO {
+			label += " (sso)"
+		}
+		selector.AddItem(label, "", 0, nil)
 	}
 
-	// Set selection handler to use profile name directly
+	// Resolve the profile by position since SSO labels differ from profile names
 	selector.SetSelectedFunc(func(index int, name string, secondName string, shortcut rune) {
-		selector.switchProfile(name)
+		selector.switchProfile(selector.profiles[index])
 	})
 
 	selector.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
@@ -107,9 +115,25 @@ func (ps *Selector) ShowSelector() (tview.Primitive, error) {
 }
 
 func (ps *Selector) discoverProfiles() []string {
+	profiles, ssoProfiles, err := discoverProfiles()
+	if err != nil {
+		ps.manager.Logger().Warn("Skipped SSO profiles", "error", err)
+	}
+	ps.sso = ssoProfiles
+	return profiles
+}
+
+// DiscoverProfiles lists the profiles in the shared credentials and config files, and local.
+func DiscoverProfiles() []string {
+	profiles, _, _ := discoverProfiles()
+	return profiles
+}
+
+// discoverProfiles also returns the SSO profiles, and why any of them were left out.
+func discoverProfiles() ([]string, map[string]auth.SSOProfile, error) {
 	homedir, err := os.UserHomeDir()
 	if err != nil {
-		return nil
+		return nil, nil, nil
 	}
 
 	profileMap := make(map[string]struct{})
@@ -126,6 +150,16 @@ func (ps *Selector) discoverProfiles() []string {
 		}
 	}
 
+	// Add SSO profiles, which only live in the shared config file
+	ssoProfiles, ssoErr := auth.LoadSSOProfiles(auth.AWSConfigPath())
+	if ssoProfiles == nil {
+		// The shared config file is missing or unreadable, which isn't worth reporting
+		ssoErr = nil
+	}
+	for name := range ssoProfiles {
+		profileMap[name] = struct{}{}
+	}
+
 	// add local profile to connect to local Docker instance
 	profileMap["local"] = struct{}{}
 	// Convert map to sorted slice
@@ -135,5 +169,5 @@ func (ps *Selector) discoverProfiles() []string {
 	}
 	sort.Strings(profiles)
 
-	return profiles
+	return profiles, ssoProfiles, ssoErr
 }
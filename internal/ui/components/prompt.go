@@ -3,12 +3,13 @@ package components
 import (
 	"github.com/gdamore/tcell/v2"
 	"github.com/rivo/tview"
+	"github.com/tpelletiersophos/cloudcutter/internal/ui/style"
 )
 
 type PromptOptions struct {
 	Title      string
 	Label      string
-	LabelColor tcell.Color
+	LabelColor style.Role
 	Width      int
 	Height     int
 	OnDone     func(text string)
@@ -26,22 +27,35 @@ func NewPrompt() *Prompt {
 		InputField: tview.NewInputField(),
 	}
 
-	p.InputField.SetFieldBackgroundColor(tcell.ColorBlack)
-	p.InputField.SetFieldTextColor(tcell.ColorBeige)
+	p.ApplyTheme(style.Current())
 
 	return p
 }
 
+// ApplyTheme colors the prompt with t. Configure applies the current theme, so prompts that are
+// configured each time they open follow theme changes by themselves.
+func (p *Prompt) ApplyTheme(t *style.Theme) {
+	p.SetBackgroundColor(t.Color(style.Background))
+	p.SetBorderColor(t.Color(style.BorderFocus))
+	p.SetTitleColor(t.Color(style.Title))
+	p.InputField.SetLabelColor(t.Color(p.options.LabelColor))
+	p.InputField.SetFieldBackgroundColor(t.Color(style.InputBackground))
+	p.InputField.SetFieldTextColor(t.Color(style.InputText))
+	p.InputField.SetAutocompleteStyles(
+		t.Color(style.InputBackground),
+		tcell.StyleDefault.Foreground(t.Color(style.InputText)),
+		tcell.StyleDefault.Background(t.Color(style.Selection)).Foreground(t.Color(style.SelectionText)))
+}
+
 func (p *Prompt) Configure(opts PromptOptions) *Prompt {
 	p.options = opts
 
 	p.SetTitle(opts.Title)
 	p.SetBorder(true)
 	p.SetTitleAlign(tview.AlignLeft)
-	p.SetBorderColor(tcell.ColorMediumTurquoise)
+	p.ApplyTheme(style.Current())
 
 	p.InputField.SetLabel(opts.Label)
-	p.InputField.SetLabelColor(opts.LabelColor)
 	p.InputField.SetFieldWidth(0)
 
 	if opts.OnDone != nil {
@@ -5,6 +5,7 @@ import (
 	"github.com/gdamore/tcell/v2"
 	"github.com/rivo/tview"
 	"github.com/tpelletiersophos/cloudcutter/internal/ui/components/statusbar"
+	"github.com/tpelletiersophos/cloudcutter/internal/ui/style"
 )
 
 type ManagerInterface interface {
@@ -22,19 +23,20 @@ type RegionSelector struct {
 	manager   ManagerInterface
 }
 
-func NewRegionSelector(onSelect func(string), onCancel func(), statusBar *statusbar.StatusBar, manager ManagerInterface) *RegionSelector {
-	regions := []string{
-		"us-east-1",      // US East (N. Virginia)
-		"us-east-2",      // US East (Ohio)
-		"us-west-1",      // US West (N. California)
-		"us-west-2",      // US West (Oregon)
-		"eu-west-1",      // Europe (Ireland)
-		"eu-central-1",   // Europe (Frankfurt)
-		"ap-northeast-1", // Asia Pacific (Tokyo)
-		"ap-southeast-1", // Asia Pacific (Singapore)
-		"ap-southeast-2", // Asia Pacific (Sydney)
-	}
+// DefaultRegions are offered when config.yaml does not list its own.
+var DefaultRegions = []string{
+	"us-east-1",      // US East (N. Virginia)
+	"us-east-2",      // US East (Ohio)
+	"us-west-1",      // US West (N. California)
+	"us-west-2",      // US West (Oregon)
+	"eu-west-1",      // Europe (Ireland)
+	"eu-central-1",   // Europe (Frankfurt)
+	"ap-northeast-1", // Asia Pacific (Tokyo)
+	"ap-southeast-1", // Asia Pacific (Singapore)
+	"ap-southeast-2", // Asia Pacific (Sydney)
+}
 
+func NewRegionSelector(regions []string, onSelect func(string), onCancel func(), statusBar *statusbar.StatusBar, manager ManagerInterface) *RegionSelector {
 	selector := &RegionSelector{
 		List:      tview.NewList().ShowSecondaryText(false),
 		onSelect:  onSelect,
@@ -46,7 +48,7 @@ func NewRegionSelector(onSelect func(string), onCancel func(), statusBar *status
 	selector.SetBorder(true)
 	selector.SetTitle(" Select Region ")
 	selector.SetTitleAlign(tview.AlignLeft)
-	selector.SetBorderColor(tcell.ColorMediumTurquoise)
+	selector.SetBorderColor(style.Color(style.BorderFocus))
 
 	for _, region := range regions {
 		selector.AddItem(region, "", 0, nil)
@@ -2,8 +2,8 @@ package components
 
 import (
 	"fmt"
-	"github.com/gdamore/tcell/v2"
 	"github.com/rivo/tview"
+	"github.com/tpelletiersophos/cloudcutter/internal/ui/style"
 	"time"
 )
 
@@ -24,10 +24,10 @@ func NewSpinner() *Spinner {
 	}
 
 	s.SetBorder(true).
-		SetBorderColor(tcell.ColorTeal).
+		SetBorderColor(style.Color(style.BorderFocus)).
 		SetTitle(" Loading ").
 		SetTitleAlign(tview.AlignLeft).
-		SetTitleColor(tcell.ColorTeal)
+		SetTitleColor(style.Color(style.Label))
 
 	return s
 }
@@ -7,6 +7,7 @@ import (
 
 	"github.com/gdamore/tcell/v2"
 	"github.com/rivo/tview"
+	"github.com/tpelletiersophos/cloudcutter/internal/ui/style"
 )
 
 type Spinner struct {
@@ -23,7 +24,7 @@ func NewSpinner(message string) *Spinner {
 	spinner := &Spinner{
 		TextView: tview.NewTextView().
 			SetTextAlign(tview.AlignCenter).
-			SetTextColor(tcell.ColorBeige).
+			SetTextColor(style.Color(style.Text)).
 			SetDynamicColors(true),
 		frames:  []string{"⣾", "⣽", "⣻", "⢿", "⡿", "⣟", "⣯", "⣷"},
 		current: 0,
@@ -82,10 +83,7 @@ func (s *Spinner) animate(app *tview.Application) {
 		case <-s.done:
 			return
 		case <-ticker.C:
-			app.QueueUpdateDraw(func() {
-				s.SetText(fmt.Sprintf("\n[yellow]%s[white] %s", s.message, s.frames[s.current]))
-			})
-			s.current = (s.current + 1) % len(s.frames)
+			app.QueueUpdateDraw(s.tick)
 		}
 	}
 }
@@ -97,19 +95,22 @@ func (s *Spinner) animateWithContext(ctx context.Context, app *tview.Application
 	for {
 		select {
 		case <-ctx.Done():
-			s.Stop()
+			// Whoever cancelled stops the spinner on the event loop
 			return
 		case <-s.done:
 			return
 		case <-ticker.C:
-			app.QueueUpdateDraw(func() {
-				s.SetText(fmt.Sprintf("\n[yellow]%s[white] %s", s.message, s.frames[s.current]))
-			})
-			s.current = (s.current + 1) % len(s.frames)
+			app.QueueUpdateDraw(s.tick)
 		}
 	}
 }
 
+// tick shows the next frame. It runs on the event loop, like the rest of the spinner's state.
+func (s *Spinner) tick() {
+	s.SetText(fmt.Sprintf("\n%s%s%s %s", style.Tag(style.Warning), s.message, style.Tag(style.Text), s.frames[s.current]))
+	s.current = (s.current + 1) % len(s.frames)
+}
+
 func CreateSpinnerModal(spinner *Spinner) tview.Primitive {
 	// Create a simple frame for the spinner with no background
 	frame := tview.NewFrame(spinner).
@@ -1,58 +1,151 @@
 package statusbar
 
 import (
-	"github.com/gdamore/tcell/v2"
+	"fmt"
+	"sync"
+
 	"github.com/rivo/tview"
+	"github.com/tpelletiersophos/cloudcutter/internal/ui/notify"
+	"github.com/tpelletiersophos/cloudcutter/internal/ui/style"
 )
 
+// StatusBar shows the newest notification of its center, with the count of unread warnings and
+// errors after it.
 type StatusBar struct {
 	*tview.TextView
-	messages    []string
-	maxMessages int
+	notifications *notify.Center
+	banner        string
+
+	mu      sync.Mutex
+	current *notify.Notification // shown instead of the newest, when replayed
 }
 
 func NewStatusBar() *StatusBar {
 	sb := &StatusBar{
-		TextView:    tview.NewTextView(),
-		maxMessages: 10,
-		messages:    make([]string, 0, 10),
+		TextView:      tview.NewTextView(),
+		notifications: notify.NewCenter(notify.MaxHistory),
 	}
 
 	sb.SetDynamicColors(true).
-		SetTextAlign(tview.AlignLeft).
-		SetTextColor(tcell.ColorMediumTurquoise)
+		SetTextAlign(tview.AlignLeft)
+	sb.ApplyTheme(style.Current())
+	sb.notifications.OnPost(func(notify.Notification) {
+		sb.mu.Lock()
+		sb.current = nil
+		sb.mu.Unlock()
+		sb.render()
+	})
 
 	return sb
 }
 
+// Notifications returns the center whose notifications the status bar shows.
+func (sb *StatusBar) Notifications() *notify.Center {
+	return sb.notifications
+}
+
+// ApplyTheme recolors the status bar with t; colors already in messages stay as they were.
+func (sb *StatusBar) ApplyTheme(t *style.Theme) {
+	sb.SetBackgroundColor(t.Color(style.Background))
+	sb.SetTextColor(t.Color(style.StatusText))
+	sb.render()
+}
+
+// SetText posts message as a notification, guessing its severity from its wording.
 func (sb *StatusBar) SetText(message string) {
-	sb.messages = append(sb.messages, message)
-	if len(sb.messages) > sb.maxMessages {
-		sb.messages = sb.messages[1:]
+	sb.notifications.Post(notify.Notification{Severity: notify.SeverityOf(message), Message: message})
+}
+
+// Show shows n again until the next notification is posted.
+func (sb *StatusBar) Show(n notify.Notification) {
+	sb.mu.Lock()
+	sb.current = &n
+	sb.mu.Unlock()
+	sb.render()
+}
+
+// Refresh redraws the unread count after the notifications were read.
+func (sb *StatusBar) Refresh() {
+	sb.render()
+}
+
+// SetBanner keeps text in front of every message, e.g. the name of a protected environment.
+// An empty text removes it.
+func (sb *StatusBar) SetBanner(text string) {
+	if text != "" {
+		text += " "
+	}
+	sb.mu.Lock()
+	sb.banner = text
+	sb.mu.Unlock()
+	sb.render()
+}
+
+func (sb *StatusBar) render() {
+	sb.mu.Lock()
+	banner, current := sb.banner, sb.current
+	sb.mu.Unlock()
+
+	n, ok := sb.notifications.Latest()
+	if current != nil {
+		n, ok = *current, true
 	}
+	text := banner
+	if ok {
+		if tag := severityTag(n.Severity); tag != "" {
+			text += tag + n.Message + "[-]"
+		} else {
+			text += n.Message
+		}
+		if n.Action != nil {
+			text += fmt.Sprintf(" %s%s[-]", style.Tag(style.Muted), tview.Escape("["+n.Action.Label+"]"))
+		}
+	}
+	if unread := sb.notifications.Unread(); unread > 0 {
+		text += fmt.Sprintf("  %s(%d unread)[-]", style.Tag(style.Warning), unread)
+	}
+	sb.TextView.SetText(text)
+}
 
-	sb.TextView.SetText(message)
+// severityTag colors a message by its severity; colors in the message itself take precedence.
+func severityTag(s notify.Severity) string {
+	switch s {
+	case notify.Error:
+		return style.Tag(style.StatusError)
+	case notify.Warning:
+		return style.Tag(style.Warning)
+	case notify.Success:
+		return style.Tag(style.Success)
+	default:
+		return ""
+	}
 }
 
 func (sb *StatusBar) ShowError(err error) {
 	if err != nil {
-		sb.SetText("[red]Error: " + err.Error())
+		sb.notifications.Post(notify.Notification{Severity: notify.Error, Message: "Error: " + err.Error()})
 	}
 }
 
 func (sb *StatusBar) ShowSuccess(message string) {
-	sb.SetText("[green]" + message)
+	sb.notifications.Post(notify.Notification{Severity: notify.Success, Message: message})
 }
 
 func (sb *StatusBar) ShowWarning(message string) {
-	sb.SetText("[yellow]" + message)
+	sb.notifications.Post(notify.Notification{Severity: notify.Warning, Message: message})
 }
 
 func (sb *StatusBar) Clear() {
-	sb.SetText("")
-	sb.messages = make([]string, 0, sb.maxMessages)
+	sb.notifications.Clear()
+	sb.render()
 }
 
+// GetHistory returns the messages posted, oldest first.
 func (sb *StatusBar) GetHistory() []string {
-	return append([]string{}, sb.messages...)
+	history := sb.notifications.History()
+	messages := make([]string, len(history))
+	for i, n := range history {
+		messages[len(history)-1-i] = n.Message
+	}
+	return messages
 }
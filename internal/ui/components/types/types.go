@@ -1,9 +1,8 @@
 package types
 
 import (
-	"github.com/gdamore/tcell/v2"
 	"github.com/rivo/tview"
-	"github.com/tpelletiersophos/cloudcutter/internal/ui/help"
+	"github.com/tpelletiersophos/cloudcutter/internal/ui/style"
 )
 
 const (
@@ -42,29 +41,31 @@ type SummaryItem struct {
 	Value string
 }
 
+// BaseStyle and the styles that embed it color components by theme role, so that they follow the
+// theme when it changes. FocusBorderColor replaces BorderColor while the component has focus.
 type BaseStyle struct {
-	Border          bool
-	BorderColor     tcell.Color
-	Title           string
-	TitleAlign      int
-	TitleColor      tcell.Color
-	BackgroundColor tcell.Color
-	TextColor       tcell.Color
+	Border           bool
+	BorderColor      style.Role
+	FocusBorderColor style.Role
+	Title            string
+	TitleAlign       int
+	TitleColor       style.Role
+	BackgroundColor  style.Role
+	TextColor        style.Role
 }
 
 type BaseProperties struct{}
 
 type ListStyle struct {
 	BaseStyle
-	SelectedTextColor       tcell.Color
-	SelectedBackgroundColor tcell.Color
+	SelectedTextColor       style.Role
+	SelectedBackgroundColor style.Role
 }
 
 type ListProperties struct {
 	BaseProperties
 	Items         []string
 	ShowSecondary bool
-	NewHelp       *help.HelpCategory
 	OnFocus       func(*tview.List)
 	OnBlur        func(*tview.List)
 	OnChanged     func(int, string, string, rune)
@@ -73,8 +74,8 @@ type ListProperties struct {
 
 type TableStyle struct {
 	BaseStyle
-	SelectedTextColor       tcell.Color
-	SelectedBackgroundColor tcell.Color
+	SelectedTextColor       style.Role
+	SelectedBackgroundColor style.Role
 }
 
 type TableProperties struct {
@@ -110,9 +111,9 @@ type FlexProperties struct {
 
 type InputFieldStyle struct {
 	BaseStyle
-	LabelColor           tcell.Color
-	FieldBackgroundColor tcell.Color
-	FieldTextColor       tcell.Color
+	LabelColor           style.Role
+	FieldBackgroundColor style.Role
+	FieldTextColor       style.Role
 }
 
 type InputFieldProperties struct {
@@ -140,7 +141,5 @@ type Component struct {
 	Style      any
 	Properties any
 	Children   []Component
-	Help       []help.Command
-	HelpProps  *help.HelpProperties
 	OnCreate   func(p tview.Primitive)
 }
@@ -0,0 +1,137 @@
+package vtable
+
+import (
+	"sync"
+)
+
+// LoadFunc fetches up to limit rows from offset.
+type LoadFunc[T any] func(offset, limit int) ([]T, error)
+
+// Pages holds rows in fixed-size pages that are loaded the first time one of their rows is read.
+// Only the most recently read pages are kept, so memory stays bounded however many rows there are.
+// A page that fails to load is not tried again, so a failing source isn't hit on every draw.
+type Pages[T any] struct {
+	mu      sync.Mutex
+	total   int
+	size    int
+	keep    int
+	load    LoadFunc[T]
+	pages   map[int][]T
+	recent  []int // page numbers, least recently read first
+	failed  map[int]bool
+	lastErr error
+}
+
+// DefaultKeep is how many pages Pages holds unless told otherwise.
+const DefaultKeep = 16
+
+// NewPages returns total rows read through load in pages of size rows.
+func NewPages[T any](total, size int, load LoadFunc[T]) *Pages[T] {
+	if size < 1 {
+		size = 1
+	}
+	return &Pages[T]{
+		total:  max(total, 0),
+		size:   size,
+		keep:   DefaultKeep,
+		load:   load,
+		pages:  make(map[int][]T),
+		failed: make(map[int]bool),
+	}
+}
+
+// SetKeep sets how many pages are held in memory.
+func (p *Pages[T]) SetKeep(pages int) *Pages[T] {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.keep = max(pages, 1)
+	p.evict()
+	return p
+}
+
+// Len returns the number of rows.
+func (p *Pages[T]) Len() int {
+	return p.total
+}
+
+// Err returns the error of the last load that failed, if any.
+func (p *Pages[T]) Err() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.lastErr
+}
+
+// Get returns row i, loading its page if needed. It reports false when i is out of range or the
+// page failed to load.
+func (p *Pages[T]) Get(i int) (T, bool) {
+	var zero T
+	if i < 0 || i >= p.total {
+		return zero, false
+	}
+	page, offset := i/p.size, i%p.size
+
+	p.mu.Lock()
+	rows, ok := p.pages[page]
+	if ok {
+		p.touch(page)
+		p.mu.Unlock()
+		return at(rows, offset)
+	}
+	if p.failed[page] {
+		p.mu.Unlock()
+		return zero, false
+	}
+	p.mu.Unlock()
+
+	if !p.fetch(page) {
+		return zero, false
+	}
+	p.mu.Lock()
+	rows = p.pages[page]
+	p.mu.Unlock()
+	return at(rows, offset)
+}
+
+// fetch loads page and stores it, reporting whether it loaded.
+func (p *Pages[T]) fetch(page int) bool {
+	rows, err := p.load(page*p.size, min(p.size, p.total-page*p.size))
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.lastErr = err
+	if err != nil {
+		p.failed[page] = true
+		return false
+	}
+	p.pages[page] = rows
+	p.touch(page)
+	p.evict()
+	return true
+}
+
+// touch marks page as the most recently read.
+func (p *Pages[T]) touch(page int) {
+	for i, n := range p.recent {
+		if n == page {
+			p.recent = append(p.recent[:i], p.recent[i+1:]...)
+			break
+		}
+	}
+	p.recent = append(p.recent, page)
+}
+
+// evict drops the least recently read pages beyond keep.
+func (p *Pages[T]) evict() {
+	for len(p.recent) > p.keep {
+		delete(p.pages, p.recent[0])
+		p.recent = p.recent[1:]
+	}
+}
+
+func at[T any](rows []T, i int) (T, bool) {
+	if i >= len(rows) {
+		var zero T
+		return zero, false
+	}
+	return rows[i], true
+}
@@ -0,0 +1,108 @@
+// Package vtable renders tables virtually: a tview.Table backed by a Content asks its Source only
+// for the cells it draws, so a result set of any size costs no more to show than a screen of rows.
+package vtable
+
+import (
+	"github.com/rivo/tview"
+)
+
+// Source supplies the cells of a virtual table. Rows count data rows from 0; the header is not one
+// of them.
+type Source interface {
+	RowCount() int
+	ColumnCount() int
+	Header(column int) *tview.TableCell
+	// Cell returns the cell at row and column, or nil while it is not available.
+	Cell(row, column int) *tview.TableCell
+}
+
+// Content is a tview.TableContent that draws its source's header as row 0 and data rows below it.
+// Writes through the table are ignored, and Table.Clear empties it.
+type Content struct {
+	tview.TableContentReadOnly
+	source Source
+}
+
+var _ tview.TableContent = (*Content)(nil)
+
+// New returns content for source, which may be nil for an empty table.
+func New(source Source) *Content {
+	return &Content{source: source}
+}
+
+// SetSource replaces the rows shown by the table.
+func (c *Content) SetSource(source Source) {
+	c.source = source
+}
+
+// Source returns the rows shown by the table, or nil.
+func (c *Content) Source() Source {
+	return c.source
+}
+
+func (c *Content) GetCell(row, column int) *tview.TableCell {
+	if c.source == nil || row < 0 || column < 0 || column >= c.source.ColumnCount() {
+		return nil
+	}
+	if row == 0 {
+		return c.source.Header(column)
+	}
+	if row > c.source.RowCount() {
+		return nil
+	}
+	return c.source.Cell(row-1, column)
+}
+
+func (c *Content) GetRowCount() int {
+	if c.source == nil || c.source.ColumnCount() == 0 {
+		return 0
+	}
+	return c.source.RowCount() + 1
+}
+
+func (c *Content) GetColumnCount() int {
+	if c.source == nil {
+		return 0
+	}
+	return c.source.ColumnCount()
+}
+
+// Clear drops the source, so Table.Clear empties a virtual table like any other.
+func (c *Content) Clear() {
+	c.source = nil
+}
+
+// Rows is a Source over a window of rendered rows held in pages, so only the rows the table draws
+// are ever rendered.
+type Rows struct {
+	header []*tview.TableCell
+	pages  *Pages[[]*tview.TableCell]
+	start  int
+	count  int
+}
+
+// NewRows shows count rows of pages from start, under header.
+func NewRows(header []*tview.TableCell, pages *Pages[[]*tview.TableCell], start, count int) *Rows {
+	if start < 0 {
+		start = 0
+	}
+	if rest := pages.Len() - start; count > rest {
+		count = max(rest, 0)
+	}
+	return &Rows{header: header, pages: pages, start: start, count: count}
+}
+
+func (r *Rows) RowCount() int    { return r.count }
+func (r *Rows) ColumnCount() int { return len(r.header) }
+
+func (r *Rows) Header(column int) *tview.TableCell {
+	return r.header[column]
+}
+
+func (r *Rows) Cell(row, column int) *tview.TableCell {
+	cells, ok := r.pages.Get(r.start + row)
+	if !ok || column >= len(cells) {
+		return nil
+	}
+	return cells[column]
+}
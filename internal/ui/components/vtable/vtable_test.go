@@ -0,0 +1,121 @@
+package vtable
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"github.com/stretchr/testify/assert"
+)
+
+// renderRows renders rows as "r<row>c<column>" cells and counts the rows it was asked for.
+func renderRows(columns int, rendered *int) LoadFunc[[]*tview.TableCell] {
+	return func(offset, limit int) ([][]*tview.TableCell, error) {
+		rows := make([][]*tview.TableCell, 0, limit)
+		for row := offset; row < offset+limit; row++ {
+			cells := make([]*tview.TableCell, columns)
+			for column := range cells {
+				cells[column] = tview.NewTableCell(fmt.Sprintf("r%dc%d", row, column))
+			}
+			rows = append(rows, cells)
+		}
+		*rendered += limit
+		return rows, nil
+	}
+}
+
+func header(titles ...string) []*tview.TableCell {
+	cells := make([]*tview.TableCell, len(titles))
+	for i, title := range titles {
+		cells[i] = tview.NewTableCell(title)
+	}
+	return cells
+}
+
+func TestContent(t *testing.T) {
+	rendered := 0
+	pages := NewPages(100000, 50, renderRows(2, &rendered))
+	content := New(NewRows(header("id", "name"), pages, 500, 1000))
+
+	assert.Equal(t, 1001, content.GetRowCount(), "the header plus the window")
+	assert.Equal(t, 2, content.GetColumnCount())
+	assert.Equal(t, "name", content.GetCell(0, 1).Text)
+	assert.Equal(t, "r500c0", content.GetCell(1, 0).Text)
+	assert.Equal(t, "r1499c1", content.GetCell(1000, 1).Text)
+	assert.Nil(t, content.GetCell(1001, 0))
+	assert.Nil(t, content.GetCell(1, 2))
+	assert.Equal(t, 100, rendered, "only the pages of the rows read are rendered")
+
+	table := tview.NewTable().SetContent(content)
+	table.SetRect(0, 0, 40, 10)
+	screen := tcell.NewSimulationScreen("")
+	if assert.NoError(t, screen.Init()) {
+		table.Draw(screen)
+		assert.Equal(t, 100, rendered, "drawing reads only the visible rows")
+	}
+
+	table.Clear()
+	assert.Equal(t, 0, content.GetRowCount(), "clearing the table empties the content")
+	assert.Nil(t, content.GetCell(0, 0))
+
+	window := NewRows(header("id"), NewPages(10, 5, renderRows(1, &rendered)), 8, 5)
+	assert.Equal(t, 2, window.RowCount(), "a window is cut at the last row")
+}
+
+func TestPages(t *testing.T) {
+	var offsets []int
+	pages := NewPages(10, 3, func(offset, limit int) ([]int, error) {
+		offsets = append(offsets, offset)
+		rows := make([]int, limit)
+		for i := range rows {
+			rows[i] = offset + i
+		}
+		return rows, nil
+	}).SetKeep(2)
+
+	row, ok := pages.Get(9)
+	assert.True(t, ok)
+	assert.Equal(t, 9, row, "the last page is short")
+	pages.Get(0)
+	pages.Get(1)
+	pages.Get(4)
+	pages.Get(0)
+	assert.Equal(t, []int{9, 0, 3}, offsets, "pages are loaded once while kept")
+	pages.Get(9)
+	assert.Equal(t, []int{9, 0, 3, 9}, offsets, "the least recently read page is dropped")
+
+	_, ok = pages.Get(10)
+	assert.False(t, ok)
+	_, ok = pages.Get(-1)
+	assert.False(t, ok)
+
+	loads := 0
+	failing := NewPages(5, 5, func(int, int) ([]int, error) {
+		loads++
+		return nil, errors.New("throttled")
+	})
+	_, ok = failing.Get(0)
+	assert.False(t, ok)
+	assert.EqualError(t, failing.Err(), "throttled")
+	_, ok = failing.Get(1)
+	assert.False(t, ok)
+	assert.Equal(t, 1, loads, "a failed page is not loaded again")
+}
+
+func TestEstimateWidths(t *testing.T) {
+	var read []int
+	widths := EstimateWidths([]string{"id", "message"}, 100000, 5, 30, func(row, column int) string {
+		if column == 0 {
+			read = append(read, row)
+			return fmt.Sprintf("[red]%d[-]", row)
+		}
+		return "a message far longer than the limit of thirty cells"
+	})
+
+	assert.Equal(t, []int{5, 30}, widths, "tags take no width and widths are capped")
+	assert.Equal(t, []int{0, 24999, 49999, 74999, 99999}, read, "rows are sampled evenly, first and last included")
+
+	assert.Equal(t, []int{4}, EstimateWidths([]string{"name"}, 0, 5, 0, nil), "no rows leaves the header width")
+}
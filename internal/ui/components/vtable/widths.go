@@ -0,0 +1,56 @@
+package vtable
+
+import (
+	"github.com/rivo/tview"
+)
+
+// DefaultSamples is how many rows EstimateWidths reads unless told otherwise.
+const DefaultSamples = 200
+
+// EstimateWidths estimates the width of each column from the headers and up to samples evenly
+// spaced rows, always including the first and last, instead of reading every row. Widths are
+// capped at limit when it is positive. text returns the text of a cell, with style tags allowed.
+func EstimateWidths(headers []string, rows, samples, limit int, text func(row, column int) string) []int {
+	widths := make([]int, len(headers))
+	for column, header := range headers {
+		widths[column] = tview.TaggedStringWidth(header)
+	}
+
+	for _, row := range sampleRows(rows, samples) {
+		for column := range headers {
+			if width := tview.TaggedStringWidth(text(row, column)); width > widths[column] {
+				widths[column] = width
+			}
+		}
+	}
+
+	if limit > 0 {
+		for column, width := range widths {
+			widths[column] = min(width, limit)
+		}
+	}
+	return widths
+}
+
+// sampleRows picks up to samples evenly spaced row indexes out of rows.
+func sampleRows(rows, samples int) []int {
+	if samples < 1 {
+		samples = DefaultSamples
+	}
+	if rows <= samples {
+		picked := make([]int, rows)
+		for i := range picked {
+			picked[i] = i
+		}
+		return picked
+	}
+	if samples == 1 {
+		return []int{0}
+	}
+
+	picked := make([]int, 0, samples)
+	for i := 0; i < samples; i++ {
+		picked = append(picked, i*(rows-1)/(samples-1))
+	}
+	return picked
+}
@@ -2,6 +2,8 @@ package help
 
 import (
 	"fmt"
+	"strings"
+
 	"github.com/gdamore/tcell/v2"
 	"github.com/rivo/tview"
 	"github.com/tpelletiersophos/cloudcutter/internal/ui/style"
@@ -11,111 +13,133 @@ const (
 	ModalHelp = "modalHelp"
 )
 
+// Command is a row of the help: the keys or command line, and what it does.
 type Command struct {
 	Key         string
 	Description string
+	Custom      bool // bound in config.yaml rather than by default
 }
 
+// HelpCategory is a titled group of rows, such as the bindings of one pane.
 type HelpCategory struct {
 	Title    string
 	Commands []Command
 }
 
-type HelpProperties struct {
-	Commands []Command
-}
-
+// Help is the help modal: a search field over the categories it was last given. The manager
+// generates the categories from the keymap and the commands when the help opens.
 type Help struct {
 	*tview.Flex
-	globalCategories []HelpCategory
-	contextCategory  *HelpCategory
-	table            *tview.Table
-	selectedCommand  *Command
-	isVisible        bool
-	Selectable       bool
-	Props            *HelpProperties
+	search     *tview.InputField
+	table      *tview.Table
+	categories []HelpCategory
+	isVisible  bool
+	onDone     func()
 }
 
-func (h *Help) getCommandForRow(row int) *Command {
-	// Map table row back to Command
-	if h.contextCategory != nil {
-		if row > 0 && row <= len(h.contextCategory.Commands) {
-			return &h.contextCategory.Commands[row-1]
-		}
-	}
-	return nil
-}
 func NewHelp() *Help {
 	help := &Help{
-		Flex:       tview.NewFlex(),
-		table:      tview.NewTable(),
-		Props:      &HelpProperties{},
-		Selectable: false,
-		globalCategories: []HelpCategory{
-			{
-				Title: "General Commands",
-				Commands: []Command{
-					{Key: "Enter", Description: "Execute command"},
-					{Key: "Esc", Description: "Close prompt/Go back"},
-					{Key: "?", Description: "Show help"},
-					{Key: "/", Description: "Filter"},
-					{Key: ":", Description: "Command prompt"},
-					{Key: "Tab", Description: "Cycle through fields"},
-					{Key: "Shift+Tab", Description: "Cycle through fields (reverse)"},
-					{Key: ":region", Description: "Change AWS region"},
-					{Key: ":profile", Description: "Change Profile"},
-				},
-			},
-			{
-				Title: "Views",
-				Commands: []Command{
-					{Key: ":dynamodb", Description: "Switch to DynamoDB view"},
-					{Key: ":elastic", Description: "Switch to Elastic view"},
-				},
-			},
-		},
+		Flex:   tview.NewFlex(),
+		search: tview.NewInputField(),
+		table:  tview.NewTable(),
 	}
 
 	help.table.SetBorders(false)
-	help.setupLayout()
+	help.search.SetLabel(" / ").
+		SetPlaceholder("type to search keys and commands").
+		SetChangedFunc(func(string) { help.render() })
+	// The search keeps the focus, so the keys that scroll go to the table
+	help.search.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Key() {
+		case tcell.KeyUp, tcell.KeyDown, tcell.KeyPgUp, tcell.KeyPgDn:
+			help.table.InputHandler()(event, func(tview.Primitive) {})
+			return nil
+		}
+		return event
+	})
+
+	help.SetDirection(tview.FlexRow).
+		AddItem(help.search, 1, 0, true).
+		AddItem(help.table, 0, 1, false)
 
 	return help
 }
 
-func (h *Help) setupLayout() {
-	h.Clear()
-	h.SetDirection(tview.FlexColumn)
-	h.AddItem(h.table, 0, 1, true)
+// SetCategories replaces what the help lists.
+func (h *Help) SetCategories(categories []HelpCategory) {
+	h.categories = categories
+	h.render()
+}
+
+// Filter returns the categories and rows that match every word of query, ignoring case. A word
+// matches a row's keys or description, or the title of its category.
+func (h *Help) Filter(query string) []HelpCategory {
+	words := strings.Fields(strings.ToLower(query))
+	if len(words) == 0 {
+		return h.categories
+	}
 
+	var matches []HelpCategory
+	for _, category := range h.categories {
+		var commands []Command
+		for _, cmd := range category.Commands {
+			text := strings.ToLower(strings.Join([]string{category.Title, cmd.Key, cmd.Description}, " "))
+			if containsAll(text, words) {
+				commands = append(commands, cmd)
+			}
+		}
+		if len(commands) > 0 {
+			matches = append(matches, HelpCategory{Title: category.Title, Commands: commands})
+		}
+	}
+	return matches
+}
+
+func containsAll(text string, words []string) bool {
+	for _, word := range words {
+		if !strings.Contains(text, word) {
+			return false
+		}
+	}
+	return true
+}
+
+func (h *Help) render() {
 	h.table.Clear()
+	h.table.SetBackgroundColor(style.Color(style.Background))
 	row := 0
 
-	if h.contextCategory != nil {
-		h.addCategoryToTable(h.contextCategory, &row)
-	} else {
-		for _, category := range h.globalCategories {
-			h.addCategoryToTable(&category, &row)
-		}
+	categories := h.Filter(h.search.GetText())
+	for _, category := range categories {
+		h.addCategoryToTable(&category, &row)
+	}
+	if len(categories) == 0 {
+		h.table.SetCell(0, 0, tview.NewTableCell("  No matching keys or commands").
+			SetTextColor(style.Color(style.Muted)))
 	}
-	h.table.SetSelectable(h.Selectable, false)
+	h.table.ScrollToBeginning()
 }
 
 func (h *Help) addCategoryToTable(category *HelpCategory, row *int) {
 	h.table.SetCell(*row, 0,
-		tview.NewTableCell(fmt.Sprintf("[::b]%s", category.Title)).
-			SetTextColor(style.GruvboxMaterial.Yellow).
-			SetAlign(tview.AlignLeft).
-			SetExpansion(1))
+		tview.NewTableCell(fmt.Sprintf("[::b]%s", tview.Escape(category.Title))).
+			SetTextColor(style.Color(style.Title)).
+			SetAlign(tview.AlignLeft))
 	*row++
 
 	for _, cmd := range category.Commands {
-		keyCell := tview.NewTableCell(fmt.Sprintf("  [mediumturquoise]%s", cmd.Key)).
-			SetTextColor(tcell.ColorMediumTurquoise).
+		keyCell := tview.NewTableCell(fmt.Sprintf("  %s%s", style.Tag(style.Label), tview.Escape(cmd.Key))).
+			SetTextColor(style.Color(style.Label)).
 			SetAlign(tview.AlignLeft)
 
-		descCell := tview.NewTableCell(fmt.Sprintf("  [beige]%s", cmd.Description)).
-			SetTextColor(tcell.ColorBeige).
-			SetAlign(tview.AlignLeft)
+		description := tview.Escape(cmd.Description)
+		if cmd.Custom {
+			description += fmt.Sprintf(" %s(config.yaml)", style.Tag(style.Muted))
+		}
+		descCell := tview.NewTableCell(fmt.Sprintf("  %s%s", style.Tag(style.Text), description)).
+			SetTextColor(style.Color(style.Text)).
+			SetAlign(tview.AlignLeft).
+			SetExpansion(1)
 
 		h.table.SetCell(*row, 0, keyCell)
 		h.table.SetCell(*row, 1, descCell)
@@ -126,17 +150,26 @@ func (h *Help) addCategoryToTable(category *HelpCategory, row *int) {
 	*row++
 }
 
+// Show opens the help with an empty search. onDone runs when Hide closes it.
 func (h *Help) Show(pages *tview.Pages, onDone func()) {
 	pages.RemovePage(ModalHelp)
 
 	h.isVisible = true
-	h.setupLayout()
-
+	h.onDone = onDone
+	h.search.SetText("")
+	h.render()
+
+	h.SetBackgroundColor(style.Color(style.Background))
+	h.search.SetLabelColor(style.Color(style.Label)).
+		SetFieldBackgroundColor(style.Color(style.InputBackground)).
+		SetFieldTextColor(style.Color(style.InputText)).
+		SetPlaceholderTextColor(style.Color(style.Muted)).
+		SetBackgroundColor(style.Color(style.Background))
 	h.SetBorder(true).
 		SetTitle(" Help ").
 		SetTitleAlign(tview.AlignCenter).
-		SetTitleColor(tcell.ColorMediumTurquoise).
-		SetBorderColor(tcell.ColorMediumTurquoise)
+		SetTitleColor(style.Color(style.Title)).
+		SetBorderColor(style.Color(style.BorderFocus))
 
 	modal := tview.NewFlex().
 		SetDirection(tview.FlexRow).
@@ -154,60 +187,18 @@ func (h *Help) Show(pages *tview.Pages, onDone func()) {
 
 func (h *Help) Hide(pages *tview.Pages) {
 	h.isVisible = false
-	h.contextCategory = nil
-	h.setupLayout()
 	pages.RemovePage(ModalHelp)
-}
-
-func (h *Help) SetContextHelp(category *HelpCategory) {
-	h.contextCategory = category
-	h.setupLayout()
-}
-
-func (h *Help) ClearContextHelp() {
-	h.contextCategory = nil
-	h.setupLayout()
+	if onDone := h.onDone; onDone != nil {
+		h.onDone = nil
+		onDone()
+	}
 }
 
 func (h *Help) IsVisible() bool {
 	return h.isVisible
 }
 
-func (h *Help) SetCommands(commands []Command) {
-	h.contextCategory = &HelpCategory{
-		Title:    "Component Help",
-		Commands: commands,
-	}
-	h.setupLayout()
-}
-
-func (h *Help) SetFocusFunc(handler func(*Help)) {
-	h.table.SetFocusFunc(func() {
-		handler(h)
-	})
-}
-
-func (h *Help) SetBlurFunc(handler func(*Help)) {
-	h.table.SetBlurFunc(func() {
-		handler(h)
-	})
-}
-
-func (h *Help) SetProperties(props HelpProperties) {
-	if h.contextCategory == nil {
-		h.contextCategory = &HelpCategory{
-			Title:    "Component Help",
-			Commands: props.Commands,
-		}
-	}
-	h.setupLayout()
-}
-
-func (h *Help) SetSelectable(b bool) {
-	h.Selectable = b
-	h.setupLayout()
-}
-
-func (h *Help) GetContextHelp() *HelpCategory {
-	return h.contextCategory
+// Search returns the field that searches the help; it has the focus while the help is open.
+func (h *Help) Search() *tview.InputField {
+	return h.search
 }
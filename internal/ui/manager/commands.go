@@ -0,0 +1,404 @@
+package manager
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/rivo/tview"
+	"github.com/tpelletiersophos/cloudcutter/internal/ui/components/profile"
+	"github.com/tpelletiersophos/cloudcutter/internal/ui/style"
+	"github.com/tpelletiersophos/cloudcutter/internal/ui/views"
+)
+
+// maxCompletions bounds the autocomplete list, which can otherwise hold every index in a cluster.
+const maxCompletions = 50
+
+// commandAliases expand to the command line they stand for.
+var commandAliases = map[string]string{
+	"tabnew":   "tab new",
+	"tabclose": "tab close",
+	"tabnext":  "tab next",
+	"tabprev":  "tab prev",
+	"quit":     "exit",
+}
+
+// setupCommands registers the commands available in every view.
+func (vm *Manager) setupCommands() {
+	noArgs := func(run func() (tview.Primitive, error)) func([]string) (tview.Primitive, error) {
+		return func(args []string) (tview.Primitive, error) {
+			if len(args) > 0 {
+				return nil, views.ErrUsage
+			}
+			return run()
+		}
+	}
+
+	vm.RegisterCommand(
+		&views.Command{
+			Name:        "profile",
+			Args:        "[name]",
+			Description: "Sign in to a profile; without a name, choose from a list",
+			Run: func(args []string) (tview.Primitive, error) {
+				switch len(args) {
+				case 0:
+					return vm.ShowProfileSelector()
+				case 1:
+					vm.NotifyInfo(SourceApp, fmt.Sprintf("Switching to %s profile...", args[0]))
+					vm.switchProfile(args[0])
+					return nil, nil
+				}
+				return nil, views.ErrUsage
+			},
+			Complete: func(args []string) []string {
+				if len(args) == 1 {
+					return profile.DiscoverProfiles()
+				}
+				return nil
+			},
+		},
+		&views.Command{
+			Name:        "region",
+			Args:        "[name]",
+			Description: "Switch region; without a name, choose from a list",
+			Run: func(args []string) (tview.Primitive, error) {
+				switch len(args) {
+				case 0:
+					return vm.showRegionSelector()
+				case 1:
+					vm.NotifyInfo(SourceApp, fmt.Sprintf("Switching to region %s...", args[0]))
+					go func() {
+						vm.switchRegion(args[0])
+						vm.app.Draw()
+					}()
+					return nil, nil
+				}
+				return nil, views.ErrUsage
+			},
+			Complete: func(args []string) []string {
+				if len(args) == 1 {
+					return vm.availableRegions()
+				}
+				return nil
+			},
+		},
+		&views.Command{
+			Name:        "elastic",
+			Description: "Open the Elasticsearch view",
+			Run:         noArgs(func() (tview.Primitive, error) { return nil, vm.SwitchToView(ViewElastic) }),
+		},
+		&views.Command{
+			Name:        "dynamodb",
+			Description: "Open the DynamoDB view",
+			Run:         noArgs(func() (tview.Primitive, error) { return nil, vm.SwitchToView(ViewDynamoDB) }),
+		},
+		&views.Command{
+			Name:        "logs",
+			Description: "Open the log viewer",
+			Run:         noArgs(func() (tview.Primitive, error) { return nil, vm.SwitchToView(ViewLogs) }),
+		},
+		&views.Command{
+			Name:        "diagnostics",
+			Description: "Show event and error metrics and the last failed requests",
+			Run:         noArgs(func() (tview.Primitive, error) { return vm.ShowDiagnostics(), nil }),
+		},
+		&views.Command{
+			Name:        "notifications",
+			Description: "Show the notifications posted so far and run their actions",
+			Run:         noArgs(func() (tview.Primitive, error) { return vm.ShowNotifications(), nil }),
+		},
+		&views.Command{
+			Name:        "tab",
+			Description: "Open, close or move between session tabs",
+			Subcommands: []*views.Command{
+				{Name: "new", Description: "Open a session tab and pick its profile", Run: noArgs(vm.NewTab)},
+				{Name: "close", Description: "Close the current session tab", Run: noArgs(func() (tview.Primitive, error) { return nil, vm.CloseTab() })},
+				{Name: "next", Description: "Go to the next session tab", Run: noArgs(func() (tview.Primitive, error) { vm.CycleTab(1); return nil, nil })},
+				{Name: "prev", Description: "Go to the previous session tab", Run: noArgs(func() (tview.Primitive, error) { vm.CycleTab(-1); return nil, nil })},
+			},
+		},
+		&views.Command{
+			Name:        "theme",
+			Args:        "[name|path]",
+			Description: "Switch the color theme; without a name, list the themes",
+			Run: func(args []string) (tview.Primitive, error) {
+				switch len(args) {
+				case 0:
+					vm.NotifyInfo(SourceApp, themeStatus())
+					return nil, nil
+				case 1:
+					return nil, vm.SetTheme(args[0])
+				}
+				return nil, views.ErrUsage
+			},
+			Complete: func(args []string) []string {
+				if len(args) == 1 {
+					return style.ThemeNames()
+				}
+				return nil
+			},
+		},
+		&views.Command{
+			Name:        "link",
+			Description: "Copy a link to what the current tab shows",
+			Run:         noArgs(func() (tview.Primitive, error) { vm.copyLink(); return nil, nil }),
+		},
+		&views.Command{
+			Name:        "help",
+			Args:        "[command]",
+			Description: "List the commands, or show how to use one",
+			Run: func(args []string) (tview.Primitive, error) {
+				vm.NotifyInfo(SourceApp, vm.commandHelp(args))
+				return nil, nil
+			},
+			Complete: func(args []string) []string {
+				return vm.completeCommandPath(args)
+			},
+		},
+		&views.Command{
+			Name:        "exit",
+			Description: "Quit CloudCutter",
+			Run:         noArgs(func() (tview.Primitive, error) { vm.app.Stop(); return nil, nil }),
+		},
+	)
+}
+
+// RegisterCommand adds commands available in every view. A later command replaces one of the same name.
+func (vm *Manager) RegisterCommand(commands ...*views.Command) {
+	for _, cmd := range commands {
+		vm.commands = slices.DeleteFunc(vm.commands, func(c *views.Command) bool { return c.Name == cmd.Name })
+		vm.commands = append(vm.commands, cmd)
+	}
+}
+
+// availableCommands are the global commands and those of the active view, which take precedence.
+func (vm *Manager) availableCommands() []*views.Command {
+	var viewCommands []*views.Command
+	if commander, ok := vm.tab.activeView.(views.Commander); ok {
+		viewCommands = commander.Commands()
+	}
+
+	commands := slices.Clone(viewCommands)
+	for _, cmd := range vm.commands {
+		if !slices.ContainsFunc(viewCommands, func(c *views.Command) bool { return c.Name == cmd.Name }) {
+			commands = append(commands, cmd)
+		}
+	}
+	sort.Slice(commands, func(i, j int) bool { return commands[i].Name < commands[j].Name })
+	return commands
+}
+
+func (vm *Manager) findCommand(name string) *views.Command {
+	for _, cmd := range vm.availableCommands() {
+		if cmd.Name == name {
+			return cmd
+		}
+	}
+	return nil
+}
+
+// resolveCommand finds the command args names, following aliases and subcommands. It returns the
+// command, its own arguments and the words that named it.
+func (vm *Manager) resolveCommand(args []string) (*views.Command, []string, []string) {
+	if len(args) == 0 {
+		return nil, nil, nil
+	}
+	if alias, ok := commandAliases[args[0]]; ok {
+		args = append(strings.Fields(alias), args[1:]...)
+	}
+
+	cmd := vm.findCommand(args[0])
+	if cmd == nil {
+		return nil, nil, nil
+	}
+	path, args := args[:1], args[1:]
+	for len(cmd.Subcommands) > 0 && len(args) > 0 {
+		sub := cmd.Subcommand(args[0])
+		if sub == nil {
+			break
+		}
+		cmd, path, args = sub, append(path, args[0]), args[1:]
+	}
+	return cmd, args, path
+}
+
+// handleCommand runs a command line such as `region eu-west-1`.
+func (vm *Manager) handleCommand(line string) (newFocus tview.Primitive) {
+	args, err := splitCommandLine(line)
+	if err != nil {
+		vm.NotifyError(SourceApp, fmt.Sprintf("Error executing command: %s", err))
+		return nil
+	}
+	if len(args) == 0 {
+		return nil
+	}
+
+	cmd, cmdArgs, path := vm.resolveCommand(args)
+	if cmd == nil {
+		vm.NotifyError(SourceApp, fmt.Sprintf("Unknown command: %s", args[0]))
+		return nil
+	}
+	if cmd.Run == nil {
+		vm.NotifyInfo(SourceApp, usageText(cmd, path))
+		return nil
+	}
+
+	primitive, err := cmd.Run(cmdArgs)
+	switch {
+	case errors.Is(err, views.ErrUsage):
+		vm.NotifyInfo(SourceApp, usageText(cmd, path))
+	case err != nil:
+		vm.NotifyError(SourceApp, fmt.Sprintf("Error executing command: %s", err))
+	default:
+		return primitive
+	}
+	return nil
+}
+
+// usageText shows how to run cmd, which path named.
+func usageText(cmd *views.Command, path []string) string {
+	usage := strings.Join(append(path[:len(path)-1:len(path)-1], cmd.Usage()), " ")
+	return fmt.Sprintf("Usage: :%s — %s", usage, cmd.Description)
+}
+
+// commandHelp lists the available commands, or gives the usage of the one args names.
+func (vm *Manager) commandHelp(args []string) string {
+	if len(args) == 0 {
+		var names []string
+		for _, cmd := range vm.availableCommands() {
+			names = append(names, cmd.Name)
+		}
+		return fmt.Sprintf("Commands: %s — :help <command> for usage", strings.Join(names, ", "))
+	}
+
+	cmd, rest, path := vm.resolveCommand(args)
+	if cmd == nil || len(rest) > 0 {
+		return fmt.Sprintf("Unknown command: %s", strings.Join(args, " "))
+	}
+	return usageText(cmd, path)
+}
+
+// completeCommand completes the word being typed in a command line: a command name first, then
+// subcommands and arguments from the command's completer.
+func (vm *Manager) completeCommand(input string) []string {
+	if strings.TrimSpace(input) == "" {
+		return nil
+	}
+	args, err := splitCommandLine(input)
+	if err != nil {
+		return nil
+	}
+	if strings.HasSuffix(input, " ") {
+		args = append(args, "")
+	}
+
+	typed, partial := args[:len(args)-1], args[len(args)-1]
+	var candidates []string
+	if len(typed) == 0 {
+		candidates = vm.completeCommandPath(args)
+	} else if cmd, cmdArgs, _ := vm.resolveCommand(typed); cmd != nil {
+		switch {
+		case len(cmd.Subcommands) > 0 && len(cmdArgs) == 0:
+			for _, sub := range cmd.Subcommands {
+				candidates = append(candidates, sub.Name)
+			}
+		case cmd.Complete != nil:
+			candidates = cmd.Complete(append(cmdArgs, partial))
+		}
+	}
+
+	var matches []string
+	for _, candidate := range candidates {
+		if !strings.HasPrefix(strings.ToLower(candidate), strings.ToLower(partial)) {
+			continue
+		}
+		matches = append(matches, strings.Join(append(quoteArgs(typed), quoteArg(candidate)), " "))
+		if len(matches) == maxCompletions {
+			break
+		}
+	}
+	return matches
+}
+
+// completeCommandPath returns the candidates for the last of args when args name a command: the
+// command names and aliases, then subcommand names.
+func (vm *Manager) completeCommandPath(args []string) []string {
+	var candidates []string
+	if len(args) <= 1 {
+		for _, cmd := range vm.availableCommands() {
+			candidates = append(candidates, cmd.Name)
+		}
+		for alias := range commandAliases {
+			candidates = append(candidates, alias)
+		}
+		sort.Strings(candidates)
+		return candidates
+	}
+
+	cmd, rest, _ := vm.resolveCommand(args[:len(args)-1])
+	if cmd != nil && len(rest) == 0 {
+		for _, sub := range cmd.Subcommands {
+			candidates = append(candidates, sub.Name)
+		}
+	}
+	return candidates
+}
+
+// splitCommandLine splits a command line into words. Single or double quotes keep spaces in a word.
+func splitCommandLine(line string) ([]string, error) {
+	var (
+		args    []string
+		current strings.Builder
+		inWord  bool
+		quote   rune
+	)
+	for _, r := range line {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else {
+				current.WriteRune(r)
+			}
+		case r == '"' || r == '\'':
+			quote, inWord = r, true
+		case r == ' ' || r == '\t':
+			if inWord {
+				args = append(args, current.String())
+				current.Reset()
+				inWord = false
+			}
+		default:
+			current.WriteRune(r)
+			inWord = true
+		}
+	}
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated %c quote", quote)
+	}
+	if inWord {
+		args = append(args, current.String())
+	}
+	return args, nil
+}
+
+func quoteArgs(args []string) []string {
+	quoted := make([]string, len(args))
+	for i, arg := range args {
+		quoted[i] = quoteArg(arg)
+	}
+	return quoted
+}
+
+// quoteArg quotes arg when splitCommandLine would otherwise split or unquote it.
+func quoteArg(arg string) string {
+	if arg != "" && !strings.ContainsAny(arg, " \t\"'") {
+		return arg
+	}
+	if strings.Contains(arg, `"`) {
+		return "'" + arg + "'"
+	}
+	return `"` + arg + `"`
+}
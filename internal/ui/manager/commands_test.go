@@ -0,0 +1,115 @@
+package manager
+
+import (
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"github.com/stretchr/testify/assert"
+	"github.com/tpelletiersophos/cloudcutter/internal/ui/views"
+)
+
+// commandView is a view that adds a :table command completing from a fixed list.
+type commandView struct {
+	tables []string
+	opened []string
+}
+
+func (v *commandView) Name() string                                              { return "commandView" }
+func (v *commandView) Content() tview.Primitive                                  { return tview.NewBox() }
+func (v *commandView) Show()                                                     {}
+func (v *commandView) Hide()                                                     {}
+func (v *commandView) InputHandler() func(event *tcell.EventKey) *tcell.EventKey { return nil }
+
+func (v *commandView) Commands() []*views.Command {
+	return []*views.Command{{
+		Name:        "table",
+		Args:        "<name>",
+		Description: "Show a table's items",
+		Run: func(args []string) (tview.Primitive, error) {
+			if len(args) != 1 {
+				return nil, views.ErrUsage
+			}
+			v.opened = append(v.opened, args[0])
+			return nil, nil
+		},
+		Complete: func(args []string) []string { return v.tables },
+	}}
+}
+
+func TestSplitCommandLine(t *testing.T) {
+	tests := []struct {
+		line     string
+		expected []string
+	}{
+		{line: "region eu-west-1", expected: []string{"region", "eu-west-1"}},
+		{line: "  index   logs-*  ", expected: []string{"index", "logs-*"}},
+		{line: `table "My Table"`, expected: []string{"table", "My Table"}},
+		{line: `filter 'msg="a b"'`, expected: []string{"filter", `msg="a b"`}},
+		{line: `table ""`, expected: []string{"table", ""}},
+		{line: "", expected: nil},
+	}
+	for _, tt := range tests {
+		args, err := splitCommandLine(tt.line)
+		assert.NoError(t, err, tt.line)
+		assert.Equal(t, tt.expected, args, tt.line)
+	}
+
+	_, err := splitCommandLine(`table "Orders`)
+	assert.EqualError(t, err, `unterminated " quote`)
+}
+
+func TestCompleteCommand(t *testing.T) {
+	vm := newTestManager(t)
+	vm.SetRegions([]string{"eu-west-1", "eu-central-1", "us-east-1"})
+	vm.tab.activeView = &commandView{tables: []string{"Orders", "Order Items", "Users"}}
+
+	tests := []struct {
+		input    string
+		expected []string
+	}{
+		{input: "", expected: nil},
+		{input: "ta", expected: []string{"tab", "tabclose", "table", "tabnew", "tabnext", "tabprev"}},
+		{input: "region eu", expected: []string{"region eu-west-1", "region eu-central-1"}},
+		{input: "region ", expected: []string{"region eu-west-1", "region eu-central-1", "region us-east-1"}},
+		{input: "table ord", expected: []string{"table Orders", `table "Order Items"`}},
+		{input: "tab n", expected: []string{"tab new", "tab next"}},
+		{input: "help tab c", expected: []string{"help tab close"}},
+		{input: "link ", expected: nil},
+		{input: "nosuch ", expected: nil},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, vm.completeCommand(tt.input), tt.input)
+	}
+}
+
+func TestHandleCommand(t *testing.T) {
+	vm := newTestManager(t)
+	view := &commandView{}
+	vm.tab.activeView = view
+
+	vm.handleCommand(`table "Order Items"`)
+	assert.Equal(t, []string{"Order Items"}, view.opened)
+
+	vm.handleCommand("table")
+	assert.Equal(t, "Usage: :table <name> — Show a table's items", vm.statusBar.GetText(true))
+
+	vm.handleCommand("tab")
+	assert.Equal(t, "Usage: :tab new|close|next|prev — Open, close or move between session tabs", vm.statusBar.GetText(true))
+
+	vm.handleCommand("nosuch arg")
+	assert.Equal(t, "Unknown command: nosuch  (1 unread)", vm.statusBar.GetText(true), "errors count as unread")
+
+	vm.handleCommand("tabnew")
+	assert.Len(t, vm.tabs, 2, ":tabnew is :tab new")
+}
+
+func TestCommandHelp(t *testing.T) {
+	vm := newTestManager(t)
+
+	assert.Equal(t, "Usage: :region [name] — Switch region; without a name, choose from a list", vm.commandHelp([]string{"region"}))
+	assert.Equal(t, "Usage: :tab close — Close the current session tab", vm.commandHelp([]string{"tab", "close"}))
+	assert.Equal(t, "Usage: :tab close — Close the current session tab", vm.commandHelp([]string{"tabclose"}))
+	assert.Equal(t, "Unknown command: table", vm.commandHelp([]string{"table"}), "view commands need their view")
+	assert.Contains(t, vm.commandHelp(nil), "Commands: diagnostics, dynamodb, elastic, exit, help, link, logs, notifications, profile, region, tab")
+}
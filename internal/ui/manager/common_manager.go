@@ -2,6 +2,8 @@ package manager
 
 import (
 	"context"
+	"runtime"
+	"sync"
 	"time"
 
 	"github.com/tpelletiersophos/cloudcutter/internal/ui/common"
@@ -18,6 +20,8 @@ type CommonSystemsManager struct {
 	globalErrorHandler *commonErrors.ErrorHandler
 	globalEventConfig  *commonEvents.EventManagerConfig
 	systemMetrics      *SystemMetrics
+	metricsMu          sync.Mutex
+	startTime          time.Time
 	factory            *common.SystemsFactory
 
 	// Configuration for common systems
@@ -93,6 +97,7 @@ func NewCommonSystemsManager(ctx context.Context, baseManager *Manager) *CommonS
 			EventCounts:  make(map[string]int64),
 			LastActivity: time.Now(),
 		},
+		startTime: time.Now(),
 	}
 
 	// Initialize global error handler
@@ -186,7 +191,7 @@ func (csm *CommonSystemsManager) HandleGlobalError(viewName string, err error) {
 	csm.globalErrorHandler.HandleError(wrappedErr)
 
 	// Update status bar with user-friendly message
-	csm.UpdateStatusBar(wrappedErr.UserMessage)
+	csm.NotifyError(viewName, wrappedErr.UserMessage)
 
 	// Check if we need to take any global actions
 	csm.evaluateSystemHealth()
@@ -210,8 +215,35 @@ func (csm *CommonSystemsManager) GetCommonEventConfig() *commonEvents.EventManag
 	return &config
 }
 
+// viewShown records that the manager switched the current tab to the named view, which holds
+// viewCount views.
+func (csm *CommonSystemsManager) viewShown(name string, viewCount int) {
+	csm.metricsMu.Lock()
+	defer csm.metricsMu.Unlock()
+
+	csm.systemMetrics.ViewCount = viewCount
+	csm.systemMetrics.ViewSwitchCount++
+	csm.systemMetrics.ActiveView = name
+	csm.systemMetrics.LastActivity = time.Now()
+}
+
+// activeView names the view last shown, for anything that needs it off the UI goroutine.
+func (csm *CommonSystemsManager) activeView() string {
+	csm.metricsMu.Lock()
+	defer csm.metricsMu.Unlock()
+	return csm.systemMetrics.ActiveView
+}
+
 // GetSystemMetrics returns current system health and performance metrics
 func (csm *CommonSystemsManager) GetSystemMetrics() *SystemMetrics {
+	csm.metricsMu.Lock()
+	defer csm.metricsMu.Unlock()
+
+	var memory runtime.MemStats
+	runtime.ReadMemStats(&memory)
+	csm.systemMetrics.SystemUptime = time.Since(csm.startTime)
+	csm.systemMetrics.MemoryUsage = int64(memory.HeapAlloc)
+
 	// Return a copy to prevent external modification
 	metrics := *csm.systemMetrics
 	metrics.ErrorCounts = make(map[string]int64)
@@ -239,14 +271,14 @@ func (csm *CommonSystemsManager) startSystemMonitoring(ctx context.Context) {
 	ticker := time.NewTicker(30 * time.Second)
 	defer ticker.Stop()
 
-	startTime := time.Now()
-
 	for {
 		select {
 		case <-ctx.Done():
 			return
 		case <-ticker.C:
-			csm.systemMetrics.SystemUptime = time.Since(startTime)
+			csm.metricsMu.Lock()
+			csm.systemMetrics.SystemUptime = time.Since(csm.startTime)
+			csm.metricsMu.Unlock()
 			csm.evaluateSystemHealth()
 		}
 	}
@@ -0,0 +1,124 @@
+package manager
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/rivo/tview"
+	"github.com/tpelletiersophos/cloudcutter/internal/diagnostics"
+	commonEvents "github.com/tpelletiersophos/cloudcutter/internal/ui/common/events"
+	"github.com/tpelletiersophos/cloudcutter/internal/ui/style"
+)
+
+// ModalDiagnostics shows event and error metrics and the last failed requests.
+const ModalDiagnostics = "modalDiagnostics"
+
+// topKeys is how many of the most pressed keys the diagnostics list.
+const topKeys = 5
+
+// ShowDiagnostics opens the diagnostics panel over the current view and returns it for focus.
+func (vm *Manager) ShowDiagnostics() tview.Primitive {
+	panel := tview.NewTextView().
+		SetDynamicColors(true).
+		SetScrollable(true).
+		SetWrap(true).
+		SetText(vm.diagnosticsReport())
+	panel.SetBorder(true).
+		SetTitle(" Diagnostics (Esc to close) ").
+		SetBorderColor(style.Color(style.BorderFocus)).
+		SetBackgroundColor(style.Color(style.Background))
+
+	vm.tab.pages.RemovePage(ModalDiagnostics)
+	vm.showModal(ModalDiagnostics, panel, 110, 34)
+	vm.app.SetFocus(panel)
+	return panel
+}
+
+// diagnosticsReport renders the key event metrics, the system metrics, the handled errors by code
+// and the failed requests, newest first.
+func (vm *Manager) diagnosticsReport() string {
+	var b strings.Builder
+	section := func(title string) {
+		if b.Len() > 0 {
+			b.WriteString("\n")
+		}
+		fmt.Fprintf(&b, "[%s::b]%s[-::-]\n", style.Color(style.Title), title)
+	}
+	muted := func(text string) string {
+		return fmt.Sprintf("[%s]%s[-]", style.Color(style.Muted), text)
+	}
+
+	section("Key events")
+	b.WriteString(tview.Escape(vm.events.FormatMetricsSummary()))
+	if keys := mostPressedKeys(vm.events.GetMetrics().KeyMetrics, topKeys); keys != "" {
+		fmt.Fprintf(&b, "Most pressed: %s\n", tview.Escape(keys))
+	}
+
+	section("System")
+	system := vm.systems.GetSystemMetrics()
+	fmt.Fprintf(&b, "Active view: %s · %d view(s) in this tab · %d view switches\n",
+		valueOr(system.ActiveView, "none"), system.ViewCount, system.ViewSwitchCount)
+	fmt.Fprintf(&b, "Uptime: %s · heap %.1f MB · last activity %s\n",
+		system.SystemUptime.Round(time.Second), float64(system.MemoryUsage)/(1<<20),
+		system.LastActivity.Format("15:04:05"))
+
+	section("Errors by code")
+	counts := diagnostics.Default.ErrorCounts()
+	if len(counts) == 0 {
+		b.WriteString(muted("No errors handled") + "\n")
+	}
+	for _, count := range counts {
+		fmt.Fprintf(&b, "%-22s %d\n", count.Code, count.Count)
+	}
+
+	section(fmt.Sprintf("Failed requests (last %d)", diagnostics.MaxFailures))
+	failures := diagnostics.Default.Failures()
+	if len(failures) == 0 {
+		b.WriteString(muted("No failed requests") + "\n")
+	}
+	for _, failure := range failures {
+		outcome := failure.Error
+		if failure.Status != 0 {
+			outcome = fmt.Sprintf("%d %s", failure.Status, outcome)
+		}
+		fmt.Fprintf(&b, "[%s]%s[-] %s %s %s → %s\n", style.Color(style.StatusError),
+			failure.Time.Format("15:04:05"), failure.Service, failure.Method,
+			tview.Escape(failure.URL), tview.Escape(strings.TrimSpace(outcome)))
+		if failure.Request != "" {
+			fmt.Fprintf(&b, "  %s %s\n", muted("request:"), tview.Escape(failure.Request))
+		}
+		if failure.Response != "" {
+			fmt.Fprintf(&b, "  %s %s\n", muted("response:"), tview.Escape(failure.Response))
+		}
+	}
+	return b.String()
+}
+
+// mostPressedKeys lists up to n keys by how often they were pressed, e.g. "'j' ×12, Enter ×3".
+func mostPressedKeys(keys map[string]*commonEvents.KeyMetrics, n int) string {
+	names := make([]string, 0, len(keys))
+	for name := range keys {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if keys[names[i]].PressCount != keys[names[j]].PressCount {
+			return keys[names[i]].PressCount > keys[names[j]].PressCount
+		}
+		return names[i] < names[j]
+	})
+
+	pressed := make([]string, 0, n)
+	for _, name := range names[:min(n, len(names))] {
+		pressed = append(pressed, fmt.Sprintf("%s ×%d", name, keys[name].PressCount))
+	}
+	return strings.Join(pressed, ", ")
+}
+
+func valueOr(value, fallback string) string {
+	if value == "" {
+		return fallback
+	}
+	return value
+}
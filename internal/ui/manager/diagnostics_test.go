@@ -0,0 +1,47 @@
+package manager
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/tpelletiersophos/cloudcutter/internal/diagnostics"
+)
+
+func TestDiagnostics(t *testing.T) {
+	vm := newTestManager(t)
+	vm.tab.activeView = &commandView{}
+
+	saved := diagnostics.Default
+	diagnostics.Default = diagnostics.NewRecorder(diagnostics.MaxFailures)
+	t.Cleanup(func() { diagnostics.Default = saved })
+	diagnostics.RecordError("TIMEOUT")
+	diagnostics.Default.RecordFailure(diagnostics.FailedRequest{
+		Time:     time.Now(),
+		Service:  "elasticsearch",
+		Method:   http.MethodPost,
+		URL:      "http://localhost:9200/logs-*/_search",
+		Status:   http.StatusBadRequest,
+		Request:  `{"query":{"match":{"level":"error"}}}`,
+		Response: `{"error":"parsing_exception"}`,
+	})
+
+	vm.globalInputHandler(tcell.NewEventKey(tcell.KeyRune, 'x', tcell.ModNone))
+	vm.globalInputHandler(tcell.NewEventKey(tcell.KeyRune, 'x', tcell.ModNone))
+	assert.EqualValues(t, 2, vm.events.GetMetrics().TotalEvents, "keys the active view gets are measured")
+
+	report := vm.diagnosticsReport()
+	assert.Contains(t, report, "Total Events: 2")
+	assert.Contains(t, report, "Most pressed: 'x' ×2")
+	assert.Contains(t, report, "TIMEOUT")
+	assert.Contains(t, report, "elasticsearch POST http://localhost:9200/logs-*/_search → 400")
+	assert.Contains(t, report, `{"error":"parsing_exception"}`)
+
+	vm.ShowDiagnostics()
+	assert.True(t, vm.tab.pages.HasPage(ModalDiagnostics))
+	assert.True(t, vm.IsModalVisible())
+	vm.globalInputHandler(tcell.NewEventKey(tcell.KeyEsc, 0, tcell.ModNone))
+	assert.False(t, vm.tab.pages.HasPage(ModalDiagnostics), "Esc closes the panel")
+}
@@ -0,0 +1,90 @@
+package manager
+
+import (
+	"fmt"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"github.com/tpelletiersophos/cloudcutter/internal/auth"
+	"github.com/tpelletiersophos/cloudcutter/internal/ui/style"
+)
+
+// GuardMutation runs proceed once the current session's environment policy allows operation.
+// Read-only sessions refuse it, and confirm sessions first ask for the profile name to be typed.
+// Unprotected sessions run proceed straight away. Views call this before every mutating operation.
+func (vm *Manager) GuardMutation(operation string, proceed func()) {
+	vm.guardMutation(vm.tab.policy(), vm.tab.profile(), operation, proceed)
+}
+
+func (vm *Manager) guardMutation(policy *auth.EnvironmentPolicy, profileName, operation string, proceed func()) {
+	switch {
+	case policy == nil:
+		proceed()
+	case policy.ReadOnly():
+		vm.NotifyError(SourceApp, fmt.Sprintf("%s is not allowed: %s sessions are read-only", operation, policy.Name))
+	default:
+		vm.showMutationConfirm(policy, profileName, operation, proceed)
+	}
+}
+
+// showMutationConfirm asks for the profile name to be typed exactly before running proceed.
+func (vm *Manager) showMutationConfirm(policy *auth.EnvironmentPolicy, profileName, operation string, proceed func()) {
+	input := tview.NewInputField().
+		SetLabel(" Profile name: ").
+		SetFieldBackgroundColor(style.Color(style.InputBackground)).
+		SetFieldTextColor(style.Color(style.InputText)).
+		SetLabelColor(style.Color(style.Label))
+
+	input.SetDoneFunc(func(key tcell.Key) {
+		switch key {
+		case tcell.KeyEnter:
+			if input.GetText() != profileName {
+				vm.NotifyWarning(SourceApp, "Profile name does not match; nothing was changed")
+				return
+			}
+			vm.HideModal(ModalGuard)
+			proceed()
+		case tcell.KeyEscape:
+			vm.HideModal(ModalGuard)
+		}
+	})
+
+	text := tview.NewTextView().
+		SetDynamicColors(true).
+		SetTextAlign(tview.AlignCenter).
+		SetText(fmt.Sprintf("%s[::b]%s[-::-] in [%s:%s:b] %s [-:-:-]\nType %s%s[-] to continue",
+			style.Tag(style.ProdWarning), tview.Escape(operation),
+			style.Color(style.Background), style.Color(style.ProdWarning), tview.Escape(policy.Name),
+			style.Tag(style.Warning), tview.Escape(profileName)))
+
+	content := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(text, 0, 1, false).
+		AddItem(input, 1, 0, true)
+	content.SetBorder(true).
+		SetTitle(" Protected Environment ").
+		SetTitleColor(style.Color(style.ProdWarning)).
+		SetBorderColor(style.Color(style.ProdWarning))
+
+	vm.tab.pages.RemovePage(ModalGuard)
+	vm.showModal(ModalGuard, content, 80, 6)
+	vm.app.SetFocus(input)
+}
+
+// updateEnvironmentBanner shows the current tab's protected environment in the header and status bar.
+func (vm *Manager) updateEnvironmentBanner() {
+	name := ""
+	if policy := vm.tab.policy(); policy != nil {
+		name = policy.Name
+		if policy.ReadOnly() {
+			name += " (read-only)"
+		}
+	}
+	vm.header.SetEnvironmentBanner(name)
+
+	banner := ""
+	if name != "" {
+		banner = fmt.Sprintf("[%s:%s:b] %s [-:-:-]",
+			style.Color(style.Background), style.Color(style.ProdWarning), tview.Escape(name))
+	}
+	vm.statusBar.SetBanner(banner)
+}
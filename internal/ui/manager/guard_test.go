@@ -0,0 +1,55 @@
+package manager
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"github.com/stretchr/testify/assert"
+	"github.com/tpelletiersophos/cloudcutter/internal/auth"
+)
+
+func TestGuardMutation(t *testing.T) {
+	vm := newTestManager(t)
+	ran := 0
+	proceed := func() { ran++ }
+
+	vm.guardMutation(nil, "dev", "Delete document", proceed)
+	assert.Equal(t, 1, ran, "unprotected sessions run straight away")
+
+	readOnly := &auth.EnvironmentPolicy{Name: "PROD", Protection: auth.ProtectionReadOnly}
+	vm.guardMutation(readOnly, "opal_prod", "Delete document", proceed)
+	assert.Equal(t, 1, ran)
+	assert.True(t, strings.Contains(vm.statusBar.GetText(false), "read-only"))
+
+	confirm := &auth.EnvironmentPolicy{Name: "PROD", Protection: auth.ProtectionConfirm}
+	vm.guardMutation(confirm, "opal_prod", "Delete document", proceed)
+	assert.Equal(t, 1, ran, "confirm sessions wait for the profile name")
+	assert.True(t, vm.IsModalVisible())
+
+	input, ok := vm.app.GetFocus().(*tview.InputField)
+	assert.True(t, ok)
+	input.SetText("opal_dev")
+	input.InputHandler()(tcell.NewEventKey(tcell.KeyEnter, 0, tcell.ModNone), nil)
+	assert.Equal(t, 1, ran, "a mismatched name changes nothing")
+	assert.True(t, vm.tab.pages.HasPage(ModalGuard))
+
+	input.SetText("opal_prod")
+	input.InputHandler()(tcell.NewEventKey(tcell.KeyEnter, 0, tcell.ModNone), nil)
+	assert.Equal(t, 2, ran)
+	assert.False(t, vm.tab.pages.HasPage(ModalGuard))
+}
+
+func TestStatusBarBanner(t *testing.T) {
+	vm := newTestManager(t)
+	vm.UpdateStatusBar("Loaded 10 documents")
+	vm.statusBar.SetBanner("PROD")
+	assert.Equal(t, "PROD Loaded 10 documents", vm.statusBar.GetText(false))
+
+	vm.UpdateStatusBar("Saved")
+	assert.Equal(t, "PROD Saved", vm.statusBar.GetText(false), "the banner stays in front of new messages")
+
+	vm.statusBar.SetBanner("")
+	assert.Equal(t, "Saved", vm.statusBar.GetText(false))
+}
@@ -0,0 +1,84 @@
+package manager
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/rivo/tview"
+	"github.com/tpelletiersophos/cloudcutter/internal/ui/common"
+	"github.com/tpelletiersophos/cloudcutter/internal/ui/help"
+	"github.com/tpelletiersophos/cloudcutter/internal/ui/views"
+)
+
+// showHelp opens the help for what focus is on, and gives focus back when it closes.
+func (vm *Manager) showHelp(focus tview.Primitive) {
+	vm.help.SetCategories(vm.helpCategories(focus))
+	vm.help.Show(vm.tab.pages, func() {
+		if vm.tab.activeView != nil {
+			vm.app.SetFocus(focus)
+		}
+	})
+	vm.app.SetFocus(vm.help)
+}
+
+// helpCategories lists the bindings of the current keymap that are live where focus is: the
+// focused pane's first, then the rest of the active view's and the global ones, followed by the
+// commands the prompt accepts.
+func (vm *Manager) helpCategories(focus tview.Primitive) []help.HelpCategory {
+	km := common.CurrentKeymap()
+
+	var contexts []string
+	if view := vm.tab.activeView; view != nil {
+		focused := view.Name()
+		if contexter, ok := view.(views.KeyContexter); ok {
+			focused = contexter.KeyContext(focus)
+		}
+		for context := focused; ; {
+			contexts = append(contexts, context)
+			i := strings.LastIndex(context, ".")
+			if i < 0 {
+				break
+			}
+			context = context[:i]
+		}
+		for _, b := range km.Bindings("") {
+			if (b.Context == view.Name() || strings.HasPrefix(b.Context, view.Name()+".")) &&
+				!slices.Contains(contexts, b.Context) {
+				contexts = append(contexts, b.Context)
+			}
+		}
+	}
+	contexts = append(contexts, common.KeymapGlobal)
+
+	var categories []help.HelpCategory
+	for i, context := range contexts {
+		category := help.HelpCategory{Title: context}
+		if i == 0 && context != common.KeymapGlobal {
+			category.Title += " (focused)"
+		}
+		for _, b := range km.Bindings(context) {
+			keys := b.KeysString()
+			if keys == "" {
+				keys = "none"
+			}
+			category.Commands = append(category.Commands, help.Command{
+				Key:         keys,
+				Description: b.Description,
+				Custom:      b.Custom,
+			})
+		}
+		if len(category.Commands) > 0 {
+			categories = append(categories, category)
+		}
+	}
+
+	commands := help.HelpCategory{Title: "commands"}
+	for _, cmd := range vm.availableCommands() {
+		commands.Commands = append(commands.Commands, help.Command{
+			Key:         fmt.Sprintf(":%s", cmd.Usage()),
+			Description: cmd.Description,
+		})
+	}
+	return append(categories, commands)
+}
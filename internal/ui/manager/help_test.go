@@ -0,0 +1,72 @@
+package manager
+
+import (
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"github.com/stretchr/testify/assert"
+	"github.com/tpelletiersophos/cloudcutter/internal/ui/common"
+	"github.com/tpelletiersophos/cloudcutter/internal/ui/components/types"
+	"github.com/tpelletiersophos/cloudcutter/internal/ui/help"
+)
+
+// paneView is a view named dynamodb whose focus is always in its items pane.
+type paneView struct {
+	commandView
+}
+
+func (v *paneView) Name() string                      { return "dynamodb" }
+func (v *paneView) KeyContext(tview.Primitive) string { return "dynamodb.items" }
+
+func TestHelpCategories(t *testing.T) {
+	km, err := common.NewKeymap(map[string]map[string]string{
+		"dynamodb.items": {"next_page": "ctrl+n, ] ]", "toggle_row_numbers": "none"},
+	})
+	assert.NoError(t, err)
+	common.UseKeymap(km)
+	t.Cleanup(func() { common.UseKeymap(nil) })
+
+	vm := newTestManager(t)
+	vm.tab.activeView = &paneView{}
+
+	categories := vm.helpCategories(nil)
+	var titles []string
+	for _, category := range categories {
+		titles = append(titles, category.Title)
+	}
+	assert.Equal(t, []string{"dynamodb.items (focused)", "dynamodb", "dynamodb.tables", "global", "commands"}, titles)
+
+	items := categories[0].Commands
+	assert.Contains(t, items, help.Command{Key: "ctrl+n, ] ]", Description: "Next page", Custom: true})
+	assert.Contains(t, items, help.Command{Key: "none", Description: "Show or hide row numbers", Custom: true})
+	assert.Contains(t, items, help.Command{Key: "g g", Description: "Go to the first row"})
+
+	assert.Contains(t, categories[4].Commands, help.Command{Key: ":table <name>", Description: "Show a table's items"})
+	assert.Contains(t, categories[4].Commands, help.Command{Key: ":tab new|close|next|prev", Description: vm.findCommand("tab").Description})
+}
+
+func TestHelpModal(t *testing.T) {
+	vm := newTestManager(t)
+	vm.tab.activeView = &commandView{}
+	focus := tview.NewBox()
+	vm.app.SetFocus(focus)
+
+	vm.globalInputHandler(tcell.NewEventKey(tcell.KeyRune, '?', tcell.ModNone))
+	assert.True(t, vm.help.IsVisible())
+	assert.Same(t, vm.help.Search(), vm.app.GetFocus(), "the search has the focus")
+
+	assert.NotNil(t, vm.globalInputHandler(tcell.NewEventKey(tcell.KeyRune, ':', tcell.ModNone)),
+		"keys go to the search rather than opening the prompt")
+	assert.False(t, vm.tab.pages.HasPage(types.ModalCmdPrompt))
+
+	vm.help.Search().SetText("Go NEXT")
+	filtered := vm.help.Filter(vm.help.Search().GetText())
+	if assert.Len(t, filtered, 1) {
+		assert.Equal(t, []help.Command{{Key: "alt+right", Description: "Go to the next session tab"}}, filtered[0].Commands)
+	}
+
+	vm.globalInputHandler(tcell.NewEventKey(tcell.KeyEsc, 0, tcell.ModNone))
+	assert.False(t, vm.help.IsVisible())
+	assert.Same(t, focus, vm.app.GetFocus(), "closing the help gives the focus back")
+}
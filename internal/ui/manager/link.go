@@ -0,0 +1,85 @@
+package manager
+
+import (
+	"fmt"
+
+	"github.com/atotto/clipboard"
+	"github.com/tpelletiersophos/cloudcutter/internal/deeplink"
+	"github.com/tpelletiersophos/cloudcutter/internal/ui/views"
+)
+
+// Open starts the current tab at link: its region, then its profile, then its view with the view's
+// state. Without a profile the profile selector is shown and the rest waits for the choice.
+func (vm *Manager) Open(link deeplink.Link) {
+	if link.Region != "" {
+		vm.setTabRegion(link.Region)
+	}
+	if link.View != "" {
+		vm.tab.link = &link
+	}
+
+	if link.Profile == "" {
+		vm.ShowProfileSelector()
+		return
+	}
+	vm.NotifyInfo(SourceApp, fmt.Sprintf("Switching to %s profile...", link.Profile))
+	vm.switchProfile(link.Profile)
+}
+
+// setTabRegion sets the region of the current tab, for the views it opens from then on.
+func (vm *Manager) setTabRegion(region string) {
+	vm.tab.profileHandler.SetRegion(region)
+	cfg := vm.tab.awsConfig.Copy()
+	cfg.Region = region
+	vm.tab.awsConfig = cfg
+	vm.updateSessionHeader()
+}
+
+// openStartView shows the view a pending link asks for, opened at the link's state. Without a link
+// it offers to restore the profile's saved session, or else shows Elastic.
+func (vm *Manager) openStartView() error {
+	tab := vm.tab
+	link := tab.link
+	tab.link = nil
+	if link == nil {
+		if state, ok := vm.savedSession(); ok {
+			vm.app.QueueUpdateDraw(func() {
+				vm.offerRestore(state)
+			})
+			return nil
+		}
+		return vm.SwitchToView(ViewElastic)
+	}
+
+	return vm.switchToView(link.View, func(view views.View) {
+		// ready runs on the event loop for a view built on demand, so the link is applied directly
+		if linker, ok := view.(views.Linker); ok {
+			linker.ApplyLink(*link)
+		}
+	})
+}
+
+// CurrentLink returns a deep link to the current tab's session, view and query.
+func (vm *Manager) CurrentLink() deeplink.Link {
+	link := deeplink.Link{
+		Profile: vm.tab.profile(),
+		Region:  vm.tab.region(),
+	}
+	if vm.tab.activeView != nil {
+		link.View = vm.tab.activeView.Name()
+		if linker, ok := vm.tab.activeView.(views.Linker); ok {
+			linker.FillLink(&link)
+		}
+	}
+	return link
+}
+
+// copyLink puts a deep link to what the current tab shows on the clipboard.
+func (vm *Manager) copyLink() {
+	link := vm.CurrentLink().String()
+	if err := clipboard.WriteAll(link); err != nil {
+		vm.NotifyInfo(SourceApp, fmt.Sprintf("Link: %s", link))
+		return
+	}
+	vm.NotifySuccess(SourceApp, fmt.Sprintf("Copied link %s", link))
+}
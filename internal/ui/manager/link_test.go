@@ -0,0 +1,29 @@
+package manager
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tpelletiersophos/cloudcutter/internal/deeplink"
+)
+
+func TestOpenWithoutProfile(t *testing.T) {
+	vm := newTestManager(t)
+
+	vm.Open(deeplink.Link{Region: "ap-southeast-2", View: deeplink.ViewDynamoDB, Table: "users"})
+
+	assert.Equal(t, "ap-southeast-2", vm.GetCurrentConfig().Region)
+	assert.Equal(t, "ap-southeast-2", vm.tab.profileHandler.GetRegion())
+	assert.True(t, vm.tab.pages.HasPage("profileSelector"), "the profile is still chosen in the selector")
+	if assert.NotNil(t, vm.tab.link, "the view waits for the profile") {
+		assert.Equal(t, "users", vm.tab.link.Table)
+	}
+}
+
+func TestCurrentLink(t *testing.T) {
+	vm := newTestManager(t)
+
+	link := vm.CurrentLink()
+	assert.Equal(t, deeplink.Link{Region: "eu-west-1"}, link, "a tab with no view links to its session only")
+	assert.Equal(t, "cloudcutter://?region=eu-west-1", link.String())
+}
@@ -3,13 +3,18 @@ package manager
 import (
 	"context"
 	"fmt"
-	"strings"
+	"sync"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/gdamore/tcell/v2"
 	"github.com/rivo/tview"
+	"github.com/tpelletiersophos/cloudcutter/internal/auth"
 	"github.com/tpelletiersophos/cloudcutter/internal/logger"
+	"github.com/tpelletiersophos/cloudcutter/internal/session"
 	"github.com/tpelletiersophos/cloudcutter/internal/ui"
+	"github.com/tpelletiersophos/cloudcutter/internal/ui/common"
+	commonEvents "github.com/tpelletiersophos/cloudcutter/internal/ui/common/events"
 	"github.com/tpelletiersophos/cloudcutter/internal/ui/components"
 	"github.com/tpelletiersophos/cloudcutter/internal/ui/components/header"
 	"github.com/tpelletiersophos/cloudcutter/internal/ui/components/profile"
@@ -18,6 +23,7 @@ import (
 	"github.com/tpelletiersophos/cloudcutter/internal/ui/components/statusbar"
 	"github.com/tpelletiersophos/cloudcutter/internal/ui/components/types"
 	"github.com/tpelletiersophos/cloudcutter/internal/ui/help"
+	"github.com/tpelletiersophos/cloudcutter/internal/ui/notify"
 	"github.com/tpelletiersophos/cloudcutter/internal/ui/style"
 	"github.com/tpelletiersophos/cloudcutter/internal/ui/views"
 )
@@ -25,21 +31,23 @@ import (
 const (
 	ViewDynamoDB   = "dynamodb"
 	ViewElastic    = "elastic"
+	ViewLogs       = "logs"
 	ModalCmdPrompt = "modalPrompt"
 	ModalJSON      = "modalJSON"
+	ModalSSOLogin  = "ssoLogin"
+	ModalMFA       = "mfaPrompt"
+	ModalGuard     = "mutationGuard"
 )
 
+// SourceApp is the source of the notifications the app posts itself rather than a view.
+const SourceApp = "app"
+
 type Manager struct {
 	app               *ui.App
 	ctx               context.Context
 	cancelFunc        context.CancelFunc
-	views             map[string]views.View
-	lazyViews         map[string]func() (views.View, error)
-	activeView        views.View
-	pages             *tview.Pages
+	lazyViews         map[string]lazyView
 	layout            *tview.Flex
-	awsConfig         aws.Config
-	primitivesByID    map[string]tview.Primitive
 	logger            *logger.Logger
 	spinner           *spinner.Spinner
 	loadingCancelFunc context.CancelFunc
@@ -52,15 +60,29 @@ type Manager struct {
 
 	StatusChan         chan string
 	focusedComponentID string
-	profileHandler     *profile.Handler
+
+	tabs      []*sessionTab
+	tab       *sessionTab // the tab shown and receiving input
+	tabPages  *tview.Pages
+	tabBar    *tview.TextView
+	nextTabID int
+
+	commands []*views.Command // available in every view
+	keys     *common.KeyResolver
+	events   *commonEvents.EventManager // measures how the active view handles keys
+	systems  *CommonSystemsManager
+	sessions *session.Store // nil when sessions are not saved
+
+	regionsMu sync.RWMutex
+	regions   []string // offered by the region selector, from config.yaml
 }
 
 func (vm *Manager) GetCurrentConfig() aws.Config {
-	return vm.awsConfig
+	return vm.tab.awsConfig
 }
 
 func (vm *Manager) Pages() *tview.Pages {
-	return vm.pages
+	return vm.tab.pages
 }
 
 func (vm *Manager) App() *tview.Application {
@@ -68,85 +90,106 @@ func (vm *Manager) App() *tview.Application {
 }
 
 func (vm *Manager) ActiveView() tview.Primitive {
-	return vm.activeView.Content()
+	return vm.tab.activeView.Content()
 }
 
 func NewViewManager(ctx context.Context, app *ui.App, awsConfig aws.Config, log *logger.Logger) *Manager {
 	ctx, cancel := context.WithCancel(ctx)
 	vm := &Manager{
-		ctx:            ctx,
-		cancelFunc:     cancel,
-		app:            app,
-		views:          make(map[string]views.View),
-		pages:          tview.NewPages(),
-		header:         header.NewHeader(),
-		statusBar:      statusbar.NewStatusBar(),
-		prompt:         components.NewPrompt(),
-		filterPrompt:   components.NewPrompt(),
-		awsConfig:      awsConfig,
-		primitivesByID: make(map[string]tview.Primitive),
-		StatusChan:     make(chan string, 10),
-		help:           help.NewHelp(),
-		logger:         log,
-	}
-
-	var err error
-	vm.profileHandler, err = profile.NewProfileHandler(
+		ctx:          ctx,
+		cancelFunc:   cancel,
+		app:          app,
+		header:       header.NewHeader(),
+		statusBar:    statusbar.NewStatusBar(),
+		prompt:       components.NewPrompt(),
+		filterPrompt: components.NewPrompt(),
+		StatusChan:   make(chan string, 10),
+		help:         help.NewHelp(),
+		keys:         common.NewKeyResolver(),
+		logger:       log,
+		tabPages:     tview.NewPages(),
+		tabBar:       tview.NewTextView(),
+	}
+
+	vm.events = commonEvents.NewEventManager(log, &commonEvents.EventManagerConfig{
+		EnableMetrics:   true,
+		MaxEventHistory: 100,
+	}, nil, nil, nil, viewKeys{vm})
+	vm.systems = NewCommonSystemsManager(ctx, vm)
+
+	vm.openTab(awsConfig)
+	vm.initialize()
+	return vm
+}
+
+// viewKeys hands keys to the active view, for the event manager to measure.
+type viewKeys struct {
+	vm *Manager
+}
+
+func (h viewKeys) HandleEvent(event *tcell.EventKey, _ tview.Primitive) *tcell.EventKey {
+	if handler := h.vm.tab.activeView.InputHandler(); handler != nil {
+		return handler(event)
+	}
+	return event
+}
+
+// newProfileHandler creates the authentication handler for one session tab.
+func (vm *Manager) newProfileHandler(tab *sessionTab) *profile.Handler {
+	handler, err := profile.NewProfileHandler(
 		vm.StatusChan,
 		func(message string) {
-			vm.pages.RemovePage("profileSelector")
+			vm.tab.pages.RemovePage("profileSelector")
 			vm.showLoading(message)
 		},
-		func() { vm.hideLoading() },
+		func() {
+			vm.hideLoading()
+			vm.app.QueueUpdateDraw(vm.hideSSOLogin)
+		},
 	)
 	if err != nil {
 		vm.logger.Error("Failed to initialize profile handler", "error", err)
+		return nil
 	}
 
-	vm.initialize()
-	return vm
+	handler.SetDeviceAuthHandler(func(device auth.DeviceAuthorization) {
+		vm.app.QueueUpdateDraw(func() {
+			vm.showSSOLogin(device)
+		})
+	})
+	handler.SetMFATokenHandler(func(req auth.MFARequest) (string, error) {
+		return vm.promptMFAToken(tab, req)
+	})
+	return handler
 }
 
 func (vm *Manager) initialize() {
 	vm.setupLayout()
 	vm.setupPrompts()
+	vm.setupCommands()
 	vm.startStatusListener()
+	vm.startExpiryMonitor()
+	vm.watchTheme()
 }
 
 func (vm *Manager) setupLayout() {
+	vm.tabBar.SetDynamicColors(true).SetBackgroundColor(style.Color(style.Background))
+	vm.renderTabBar()
+
 	vm.layout = tview.NewFlex().
 		SetDirection(tview.FlexRow).
 		AddItem(vm.header, 8, 0, false).
-		AddItem(vm.pages, 0, 1, true).
+		AddItem(vm.tabBar, 1, 0, false).
+		AddItem(vm.tabPages, 0, 1, true).
 		AddItem(vm.statusBar, 1, 0, false)
 }
 
 func (vm *Manager) setupPrompts() {
-	vm.prompt.SetAutocompleteFunc(func(input string) []string {
-		if input == "" {
-			return nil
-		}
-		commands := []string{"profile", "region", "dynamodb", "elastic", "help", "exit"}
-		var matches []string
-		for _, cmd := range commands {
-			if strings.HasPrefix(cmd, input) {
-				matches = append(matches, cmd)
-			}
-		}
-		return matches
-	})
-
-	vm.prompt.InputField.SetFieldBackgroundColor(tcell.ColorBlack)
-	vm.prompt.InputField.SetFieldTextColor(tcell.ColorBeige)
-
-	vm.prompt.SetAutocompleteStyles(
-		tcell.ColorBlack,
-		tcell.StyleDefault.Foreground(tcell.ColorBeige),
-		tcell.StyleDefault.Background(tcell.ColorDarkCyan).Foreground(tcell.ColorBeige))
+	vm.prompt.SetAutocompleteFunc(vm.completeCommand)
 
 	vm.prompt.SetDoneFunc(func(command string) {
 		if newFocus := vm.handleCommand(command); newFocus != nil {
-			vm.pages.RemovePage(types.ModalCmdPrompt)
+			vm.tab.pages.RemovePage(types.ModalCmdPrompt)
 			vm.app.SetFocus(newFocus)
 		} else {
 			vm.HideModal(types.ModalCmdPrompt)
@@ -163,13 +206,13 @@ func (vm *Manager) setupPrompts() {
 
 func (vm *Manager) showModal(name string, content tview.Primitive, width int, height int) {
 	modal := vm.createModalFlex(content, width, height)
-	vm.pages.AddPage(name, modal, true, true)
+	vm.tab.pages.AddPage(name, modal, true, true)
 }
 
 func (vm *Manager) HideModal(name string) {
-	vm.pages.RemovePage(name)
-	if vm.activeView != nil {
-		vm.app.SetFocus(vm.activeView.Content())
+	vm.tab.pages.RemovePage(name)
+	if vm.tab.activeView != nil {
+		vm.app.SetFocus(vm.tab.activeView.Content())
 	}
 }
 
@@ -185,40 +228,14 @@ func (vm *Manager) createModalFlex(content tview.Primitive, width int, height in
 		AddItem(nil, 0, 1, false)
 }
 
-func (vm *Manager) handleCommand(command string) (newFocus tview.Primitive) {
-	handlers := map[string]func() (tview.Primitive, error){
-		"profile":  vm.ShowProfileSelector,
-		"region":   vm.showRegionSelector,
-		"dynamodb": func() (tview.Primitive, error) { return nil, vm.SwitchToView(ViewDynamoDB) },
-		"elastic":  func() (tview.Primitive, error) { return nil, vm.SwitchToView(ViewElastic) },
-		"help": func() (tview.Primitive, error) {
-			vm.statusBar.SetText("Help: List of available commands...")
-			return nil, nil
-		},
-		"exit": func() (tview.Primitive, error) {
-			vm.app.Stop()
-			return nil, nil
-		},
-	}
-
-	if handler, exists := handlers[command]; exists {
-		if primitive, err := handler(); err != nil {
-			vm.statusBar.SetText(fmt.Sprintf("Error executing command: %s", err))
-		} else {
-			return primitive
-		}
-	} else {
-		vm.statusBar.SetText(fmt.Sprintf("Unknown command: %s", command))
-	}
-
-	return nil
-}
-
 func (vm *Manager) Run() error {
 	vm.app.SetRoot(vm.layout, true)
 	vm.app.EnableMouse(true)
 	vm.app.SetInputCapture(vm.globalInputHandler)
-	return vm.app.Run()
+	vm.app.SetBeforeDrawFunc(fillBackground)
+	err := vm.app.Run()
+	vm.saveSessions()
+	return err
 }
 
 func (vm *Manager) CreateLayout(cfg types.LayoutConfig) tview.Primitive {
@@ -232,80 +249,82 @@ func (vm *Manager) CreateLayout(cfg types.LayoutConfig) tview.Primitive {
 
 func (vm *Manager) buildPrimitiveFromComponent(c types.Component) tview.Primitive {
 	var primitive tview.Primitive
+	var restyle func(*style.Theme)
 
 	switch c.Type {
 	case types.ComponentList:
 		list := tview.NewList().ShowSecondaryText(false)
 
-		if style, ok := c.Style.(types.ListStyle); ok {
-			applyStyleToBox(list, style.BaseStyle)
-			list.SetSelectedStyle(tcell.StyleDefault.
-				Foreground(style.SelectedTextColor).
-				Background(style.SelectedBackgroundColor))
-			list.SetMainTextColor(style.TextColor).
-				SetTitleColor(style.TitleColor)
-		}
-
-		if props, ok := c.Properties.(types.ListProperties); ok {
-			for _, item := range props.Items {
-				list.AddItem(item, "", 0, nil)
+		s, styled := c.Style.(types.ListStyle)
+		if styled {
+			restyle = func(t *style.Theme) {
+				applyStyleToBox(list, s.BaseStyle, t)
+				list.SetSelectedStyle(tcell.StyleDefault.
+					Foreground(t.Color(s.SelectedTextColor)).
+					Background(t.Color(s.SelectedBackgroundColor)))
+				list.SetMainTextColor(t.Color(s.TextColor))
 			}
+		}
 
-			list.SetFocusFunc(func() {
-				vm.focusedComponentID = c.ID
-				if len(c.Help) > 0 {
-					helpCategory := &help.HelpCategory{
-						Title:    c.ID,
-						Commands: c.Help,
-					}
-					vm.help.SetContextHelp(helpCategory)
-				} else {
-					vm.help.ClearContextHelp()
-				}
-				if props.OnFocus != nil {
-					props.OnFocus(list)
-				}
-			})
+		props, _ := c.Properties.(types.ListProperties)
+		for _, item := range props.Items {
+			list.AddItem(item, "", 0, nil)
+		}
 
-			list.SetBlurFunc(func() {
-				if vm.focusedComponentID == c.ID {
-					vm.focusedComponentID = ""
-					vm.help.ClearContextHelp()
-				}
-				if props.OnBlur != nil {
-					props.OnBlur(list)
-				}
-			})
+		list.SetFocusFunc(func() {
+			vm.focusedComponentID = c.ID
+			focusBorder(list, s.BaseStyle, true)
+			if props.OnFocus != nil {
+				props.OnFocus(list)
+			}
+		})
 
-			if props.OnChanged != nil {
-				list.SetChangedFunc(props.OnChanged)
+		list.SetBlurFunc(func() {
+			if vm.focusedComponentID == c.ID {
+				vm.focusedComponentID = ""
 			}
-			if props.OnSelected != nil {
-				list.SetSelectedFunc(props.OnSelected)
+			focusBorder(list, s.BaseStyle, false)
+			if props.OnBlur != nil {
+				props.OnBlur(list)
 			}
+		})
+
+		if props.OnChanged != nil {
+			list.SetChangedFunc(props.OnChanged)
+		}
+		if props.OnSelected != nil {
+			list.SetSelectedFunc(props.OnSelected)
 		}
 
 		primitive = list
 
 	case types.ComponentTable:
 		table := tview.NewTable()
-		if style, ok := c.Style.(types.TableStyle); ok {
-			applyStyleToBox(table, style.BaseStyle)
-			table.SetSelectedStyle(tcell.StyleDefault.
-				Foreground(style.SelectedTextColor).
-				Background(style.SelectedBackgroundColor))
+		s, styled := c.Style.(types.TableStyle)
+		if styled {
+			restyle = func(t *style.Theme) {
+				applyStyleToBox(table, s.BaseStyle, t)
+				table.SetSelectedStyle(tcell.StyleDefault.
+					Foreground(t.Color(s.SelectedTextColor)).
+					Background(t.Color(s.SelectedBackgroundColor)))
+			}
 		}
 
-		if props, ok := c.Properties.(types.TableProperties); ok {
+		props, _ := c.Properties.(types.TableProperties)
+		table.SetFocusFunc(func() {
+			focusBorder(table, s.BaseStyle, true)
 			if props.OnFocus != nil {
-				table.SetFocusFunc(func() { props.OnFocus(table) })
+				props.OnFocus(table)
 			}
+		})
+		table.SetBlurFunc(func() {
+			focusBorder(table, s.BaseStyle, false)
 			if props.OnBlur != nil {
-				table.SetBlurFunc(func() { props.OnBlur(table) })
-			}
-			if props.OnSelected != nil {
-				table.SetSelectedFunc(props.OnSelected)
+				props.OnBlur(table)
 			}
+		})
+		if props.OnSelected != nil {
+			table.SetSelectedFunc(props.OnSelected)
 		}
 
 		table.SetSelectable(true, false)
@@ -313,29 +332,42 @@ func (vm *Manager) buildPrimitiveFromComponent(c types.Component) tview.Primitiv
 
 	case types.ComponentTextView:
 		textView := tview.NewTextView()
-		if style, ok := c.Style.(types.TextViewStyle); ok {
-			applyStyleToBox(textView, style.BaseStyle)
+		s, styled := c.Style.(types.TextViewStyle)
+		if styled {
+			restyle = func(t *style.Theme) {
+				applyStyleToBox(textView, s.BaseStyle, t)
+				if s.TextColor != 0 {
+					textView.SetTextColor(t.Color(s.TextColor))
+				}
+			}
 		}
 
-		if props, ok := c.Properties.(types.TextViewProperties); ok {
-			textView.SetText(props.Text)
-			textView.SetWrap(props.Wrap)
-			textView.SetScrollable(props.Scrollable)
-			textView.SetDynamicColors(props.DynamicColors)
+		props, _ := c.Properties.(types.TextViewProperties)
+		textView.SetText(props.Text)
+		textView.SetWrap(props.Wrap)
+		textView.SetScrollable(props.Scrollable)
+		textView.SetDynamicColors(props.DynamicColors)
 
+		textView.SetFocusFunc(func() {
+			focusBorder(textView, s.BaseStyle, true)
 			if props.OnFocus != nil {
-				textView.SetFocusFunc(func() { props.OnFocus(textView) })
+				props.OnFocus(textView)
 			}
+		})
+		textView.SetBlurFunc(func() {
+			focusBorder(textView, s.BaseStyle, false)
 			if props.OnBlur != nil {
-				textView.SetBlurFunc(func() { props.OnBlur(textView) })
+				props.OnBlur(textView)
 			}
-		}
+		})
 		primitive = textView
 
 	case types.ComponentFlex:
 		flex := tview.NewFlex().SetDirection(c.Direction)
-		if style, ok := c.Style.(types.FlexStyle); ok {
-			applyStyleToBox(flex, style.BaseStyle)
+		if s, ok := c.Style.(types.FlexStyle); ok {
+			restyle = func(t *style.Theme) {
+				applyStyleToBox(flex, s.BaseStyle, t)
+			}
 		}
 
 		if props, ok := c.Properties.(types.FlexProperties); ok {
@@ -355,100 +387,117 @@ func (vm *Manager) buildPrimitiveFromComponent(c types.Component) tview.Primitiv
 
 	case types.ComponentInputField:
 		input := tview.NewInputField()
-		if style, ok := c.Style.(types.InputFieldStyle); ok {
-			applyStyleToBox(input, style.BaseStyle)
-			input.SetLabelColor(style.LabelColor)
-			input.SetFieldBackgroundColor(style.FieldBackgroundColor)
-			input.SetFieldTextColor(style.FieldTextColor)
-		}
-
-		if props, ok := c.Properties.(types.InputFieldProperties); ok {
-			input.SetLabel(props.Label)
-			input.SetFieldWidth(props.FieldWidth)
-			input.SetText(props.Text)
-
-			if props.DoneFunc != nil {
-				input.SetDoneFunc(func(key tcell.Key) {
-					if key == tcell.KeyEnter {
-						props.DoneFunc(input.GetText())
-					}
-				})
-			}
-			if props.ChangedFunc != nil {
-				input.SetChangedFunc(props.ChangedFunc)
+		s, styled := c.Style.(types.InputFieldStyle)
+		if styled {
+			restyle = func(t *style.Theme) {
+				applyStyleToBox(input, s.BaseStyle, t)
+				input.SetLabelColor(t.Color(s.LabelColor))
+				input.SetFieldBackgroundColor(t.Color(s.FieldBackgroundColor))
+				input.SetFieldTextColor(t.Color(s.FieldTextColor))
 			}
+		}
 
-			input.SetFocusFunc(func() {
-				vm.focusedComponentID = c.ID
-				if len(c.Help) > 0 {
-					helpCategory := &help.HelpCategory{
-						Title:    c.ID,
-						Commands: c.Help,
-					}
-					vm.help.SetContextHelp(helpCategory)
-					if c.HelpProps != nil {
-						c.HelpProps.Commands = c.Help
-						vm.help.SetProperties(*c.HelpProps)
-					} else {
-						vm.help.SetProperties(help.HelpProperties{
-							Commands: c.Help,
-						})
-					}
-				}
-				if props.OnFocus != nil {
-					props.OnFocus(input)
-				}
-			})
+		props, _ := c.Properties.(types.InputFieldProperties)
+		input.SetLabel(props.Label)
+		input.SetFieldWidth(props.FieldWidth)
+		input.SetText(props.Text)
 
-			input.SetBlurFunc(func() {
-				if props.OnBlur != nil {
-					props.OnBlur(input)
+		if props.DoneFunc != nil {
+			input.SetDoneFunc(func(key tcell.Key) {
+				if key == tcell.KeyEnter {
+					props.DoneFunc(input.GetText())
 				}
 			})
 		}
+		if props.ChangedFunc != nil {
+			input.SetChangedFunc(props.ChangedFunc)
+		}
+
+		input.SetFocusFunc(func() {
+			vm.focusedComponentID = c.ID
+			focusBorder(input, s.BaseStyle, true)
+			if props.OnFocus != nil {
+				props.OnFocus(input)
+			}
+		})
+
+		input.SetBlurFunc(func() {
+			focusBorder(input, s.BaseStyle, false)
+			if props.OnBlur != nil {
+				props.OnBlur(input)
+			}
+		})
 		primitive = input
 
 	}
+
+	if restyle != nil {
+		restyle(style.Current())
+	}
 	if c.ID != "" && primitive != nil {
-		vm.primitivesByID[c.ID] = primitive
+		vm.tab.primitivesByID[c.ID] = primitive
+		if restyle != nil {
+			vm.tab.restylers[c.ID] = restyle
+		}
 	}
 
 	return primitive
 }
 
 func (vm *Manager) RegisterView(view views.View) error {
-	if _, exists := vm.views[view.Name()]; exists {
+	if _, exists := vm.tab.views[view.Name()]; exists {
 		return fmt.Errorf("view %s already registered", view.Name())
 	}
-	vm.views[view.Name()] = view
+	vm.tab.views[view.Name()] = view
 	return nil
 }
 
 func (vm *Manager) SwitchToView(name string) error {
+	return vm.switchToView(name, nil)
+}
+
+// switchToView shows the named view, building it first if needed, then calls ready with it.
+func (vm *Manager) switchToView(name string, ready func(views.View)) error {
 	if vm.spinner == nil {
 		vm.logger.Info("Spinner not initialized in SwitchToView!")
 	}
 
 	vm.showLoading("Switching view...")
 	vm.UpdateHeader(nil)
-	if view, exists := vm.views[name]; exists {
+	if view, exists := vm.tab.views[name]; exists {
 		// View already exists; switch directly
 		vm.logger.Debug("Switching to existing view", "view", name)
 		vm.setActiveView(view)
-	} else if constructor, exists := vm.lazyViews[name]; exists {
-		// Lazy view; construct it
+		if ready != nil {
+			ready(view)
+		}
+	} else if lazy, exists := vm.lazyViews[name]; exists {
+		// Lazy view; load its services in the background, then build it on the event loop
 		vm.logger.Debug("Initializing lazy view", "view", name)
+		tab := vm.tab
 		go func() {
-			view, err := constructor()
+			var err error
+			if lazy.load != nil {
+				err = lazy.load()
+			}
 			vm.App().QueueUpdateDraw(func() {
+				// Hide the switch's spinner first; the view may show its own as it loads
+				vm.hideLoading()
+				var view views.View
+				if err == nil {
+					view, err = lazy.build()
+				}
 				if err != nil {
 					vm.logger.Error("Failed to initialize lazy view", "view", name, "error", err)
-					vm.hideLoading()
 					return
 				}
-				vm.views[name] = view
-				vm.setActiveView(view)
-				vm.hideLoading()
+				tab.views[name] = view
+				if tab == vm.tab {
+					vm.setActiveView(view)
+					if ready != nil {
+						ready(view)
+					}
+				}
 			})
 		}()
 	} else {
@@ -466,17 +515,40 @@ func (vm *Manager) UpdateHeader(summary []types.SummaryItem) {
 	vm.header.UpdateSummary(summary)
 }
 
+// UpdateStatusBar posts text as a notification from the active view, guessing its severity from
+// its wording. It is the fallback for messages whose severity and source are not known; prefer the
+// Notify helpers below, or Notify to offer an action.
 func (vm *Manager) UpdateStatusBar(text string) {
-	vm.statusBar.SetText(text)
+	vm.Notify(notify.Notification{Severity: notify.SeverityOf(text), Message: text})
 }
 
+// NotifyInfo posts message from source, the view posting it or SourceApp.
+func (vm *Manager) NotifyInfo(source, message string) {
+	vm.Notify(notify.Notification{Severity: notify.Info, Source: source, Message: message})
+}
+
+// NotifySuccess posts message as a success from source.
+func (vm *Manager) NotifySuccess(source, message string) {
+	vm.Notify(notify.Notification{Severity: notify.Success, Source: source, Message: message})
+}
+
+// NotifyWarning posts message as a warning from source.
+func (vm *Manager) NotifyWarning(source, message string) {
+	vm.Notify(notify.Notification{Severity: notify.Warning, Source: source, Message: message})
+}
+
+// NotifyError posts message as an error from source, offering the logs.
+func (vm *Manager) NotifyError(source, message string) {
+	vm.Notify(notify.Notification{Severity: notify.Error, Source: source, Message: message})
+}
+
+// ViewContext is the context of the current tab, cancelled when the tab closes.
 func (vm *Manager) ViewContext() context.Context {
-	return vm.ctx
+	return vm.tab.ctx
 }
 
 func (vm *Manager) hidePrompt() {
-	vm.pages.RemovePage(types.ModalCmdPrompt)
-	vm.app.SetFocus(vm.activeView.Content())
+	vm.HideModal(types.ModalCmdPrompt)
 }
 
 func (vm *Manager) SetFocus(p tview.Primitive) {
@@ -488,8 +560,8 @@ func (vm *Manager) IsModalVisible() bool {
 		return true
 	}
 
-	if page, _ := vm.pages.GetFrontPage(); page != "" {
-		for _, name := range []string{types.ModalCmdPrompt, types.ModalFilter, help.ModalHelp} {
+	if page, _ := vm.tab.pages.GetFrontPage(); page != "" {
+		for _, name := range []string{types.ModalCmdPrompt, types.ModalFilter, help.ModalHelp, ModalSSOLogin, ModalMFA, ModalGuard, ModalRestore, ModalDiagnostics, ModalNotifications} {
 			if page == name {
 				return true
 			}
@@ -511,63 +583,79 @@ func (vm *Manager) globalInputHandler(event *tcell.EventKey) *tcell.EventKey {
 	currentFocus := vm.app.GetFocus()
 
 	if event.Key() == tcell.KeyEsc {
-		if vm.pages.HasPage(types.ModalCmdPrompt) {
-			vm.pages.RemovePage(types.ModalCmdPrompt)
-			vm.app.SetFocus(vm.activeView.Content())
+		if vm.tab.pages.HasPage(types.ModalCmdPrompt) {
+			vm.hidePrompt()
 			return nil
 		}
-		if vm.pages.HasPage(types.ModalFilter) {
+		if vm.tab.pages.HasPage(types.ModalFilter) {
 			vm.HideFilterPrompt()
 			return nil
 		}
-		if vm.pages.HasPage("profileSelector") {
+		if vm.tab.pages.HasPage("profileSelector") {
 			vm.hideProfileSelector()
 			return nil
 		}
-		if vm.pages.HasPage("regionSelector") {
+		if vm.tab.pages.HasPage("regionSelector") {
 			vm.hideRegionSelector()
 			return nil
 		}
+		if vm.tab.pages.HasPage(ModalSSOLogin) {
+			vm.tab.profileHandler.CancelLogin()
+			vm.hideSSOLogin()
+			return nil
+		}
 
-		if vm.pages.HasPage(ModalJSON) {
+		if vm.tab.pages.HasPage(ModalGuard) {
+			vm.HideModal(ModalGuard)
+			return nil
+		}
+
+		if vm.tab.pages.HasPage(ModalJSON) {
 			vm.hideJSON()
 			return nil
 		}
 
-		if vm.help.IsVisible() {
-			vm.help.Hide(vm.pages)
-			vm.app.SetFocus(currentFocus)
+		if vm.tab.pages.HasPage(ModalDiagnostics) {
+			vm.HideModal(ModalDiagnostics)
 			return nil
 		}
-	}
 
-	if event.Key() == tcell.KeyRune {
-		switch event.Rune() {
-		case '?':
-			if !vm.help.IsVisible() {
-				vm.help.Show(vm.pages, func() {
-					if vm.activeView != nil {
-						vm.app.SetFocus(currentFocus)
-					}
-				})
-				return nil
-			}
-			return event
-		case ':':
-			vm.showCmdPrompt()
+		if vm.tab.pages.HasPage(ModalNotifications) {
+			vm.HideModal(ModalNotifications)
 			return nil
 		}
+
 		if vm.help.IsVisible() {
-			return event
+			vm.help.Hide(vm.tab.pages)
+			return nil
 		}
 	}
 
+	// Keys typed while the help is open search it
+	if vm.help.IsVisible() {
+		return event
+	}
+
+	action, pending := vm.keys.Resolve(common.KeymapGlobal, event)
+	switch {
+	case action == "help":
+		vm.showHelp(currentFocus)
+		return nil
+	case action == "command_prompt":
+		vm.showCmdPrompt()
+		return nil
+	case action == "notifications" && !vm.IsModalVisible():
+		vm.ShowNotifications()
+		return nil
+	case action != "" && !vm.IsModalVisible() && vm.handleTabAction(action):
+		return nil
+	case pending:
+		return nil
+	}
 	// Delegate to active view if applicable
-	if !vm.IsModalVisible() && vm.activeView != nil {
-		if handler := vm.activeView.InputHandler(); handler != nil {
-			if result := handler(event); result == nil {
-				return nil
-			}
+	if !vm.IsModalVisible() && vm.tab.activeView != nil {
+		if result := vm.events.ProcessEvent(event, currentFocus); result == nil {
+			return nil
 		}
 	}
 
@@ -575,28 +663,28 @@ func (vm *Manager) globalInputHandler(event *tcell.EventKey) *tcell.EventKey {
 }
 
 func (vm *Manager) switchToDevProfile() error {
-	if vm.profileHandler.IsAuthenticating() {
+	if vm.tab.profileHandler.IsAuthenticating() {
 		status := "Authentication already in progress"
 		vm.StatusChan <- status
 		return fmt.Errorf(status)
 	}
 
-	vm.profileHandler.SwitchProfile(vm.ctx, "opal_dev", func(cfg aws.Config, err error) {
+	vm.tab.profileHandler.SwitchProfile(vm.tab.ctx, "opal_dev", func(cfg aws.Config, err error) {
 		if err != nil {
 			vm.StatusChan <- fmt.Sprintf("Failed to switch to dev profile: %v", err)
 			return
 		}
 
-		vm.awsConfig = cfg
-		vm.header.UpdateEnvVar("Profile", "opal_dev")
+		vm.tab.awsConfig = cfg
+		vm.updateSessionHeader()
 
 		if err := vm.reinitializeActiveView(); err != nil {
 			vm.StatusChan <- fmt.Sprintf("Error reinitializing views: %v", err)
 			return
 		}
 
-		if err := vm.SwitchToView(ViewElastic); err != nil {
-			vm.Logger().Error("Failed to switch to Elastic after dev profile", "error", err)
+		if err := vm.openStartView(); err != nil {
+			vm.Logger().Error("Failed to open start view after dev profile", "error", err)
 		}
 
 		vm.StatusChan <- "Successfully switched to dev profile"
@@ -606,22 +694,22 @@ func (vm *Manager) switchToDevProfile() error {
 }
 
 func (vm *Manager) switchToLocalProfile() error {
-	if vm.profileHandler.IsAuthenticating() {
+	if vm.tab.profileHandler.IsAuthenticating() {
 		status := "Authentication already in progress"
 		vm.StatusChan <- status
 		return fmt.Errorf(status)
 	}
 
 	vm.logger.Info("Starting local profile switch")
-	vm.profileHandler.SwitchProfile(vm.ctx, "local", func(cfg aws.Config, err error) {
+	vm.tab.profileHandler.SwitchProfile(vm.tab.ctx, "local", func(cfg aws.Config, err error) {
 		if err != nil {
 			vm.logger.Error("Failed to switch to local profile", "error", err)
 			vm.StatusChan <- fmt.Sprintf("Failed to switch to local profile: %v", err)
 			return
 		}
 
-		vm.awsConfig = cfg
-		vm.header.UpdateEnvVar("Profile", "local")
+		vm.tab.awsConfig = cfg
+		vm.updateSessionHeader()
 
 		// Instead of calling vm.reinitializeViews() here:
 		if err := vm.reinitializeActiveView(); err != nil {
@@ -631,8 +719,8 @@ func (vm *Manager) switchToLocalProfile() error {
 		}
 
 		// Switch to a default or main view if you wish
-		if err := vm.SwitchToView(ViewElastic); err != nil {
-			vm.logger.Error("Failed to switch to Elastic after local profile", "error", err)
+		if err := vm.openStartView(); err != nil {
+			vm.logger.Error("Failed to open start view after local profile", "error", err)
 		}
 
 		vm.StatusChan <- "Successfully switched to local profile"
@@ -642,10 +730,10 @@ func (vm *Manager) switchToLocalProfile() error {
 }
 
 func (vm *Manager) reinitializeViews() error {
-	if vm.activeView != nil {
-		activeName := vm.activeView.Name()
-		if reinitView, ok := vm.activeView.(views.Reinitializer); ok {
-			if err := reinitView.Reinitialize(vm.awsConfig); err != nil {
+	if vm.tab.activeView != nil {
+		activeName := vm.tab.activeView.Name()
+		if reinitView, ok := vm.tab.activeView.(views.Reinitializer); ok {
+			if err := reinitView.Reinitialize(vm.tab.awsConfig); err != nil {
 				return fmt.Errorf("failed to reinitialize %s view: %w", activeName, err)
 			}
 		}
@@ -655,28 +743,16 @@ func (vm *Manager) reinitializeViews() error {
 
 func (vm *Manager) ShowProfileSelector() (tview.Primitive, error) {
 	profileSelector := profile.NewSelector(
-		vm.profileHandler,
+		vm.tab.profileHandler,
 		func(profile string) {
-			if vm.activeView != nil {
-				vm.app.SetFocus(vm.activeView.Content())
+			if vm.tab.activeView != nil {
+				vm.app.SetFocus(vm.tab.activeView.Content())
 			}
 
-			vm.statusBar.SetText(fmt.Sprintf("Switching to %s profile...", profile))
-			switch profile {
-			case "opal_dev":
-				vm.switchToDevProfile()
-			case "opal_prod":
-				vm.switchToProdProfile()
-			case "local":
-				vm.switchToLocalProfile()
-			default:
-				vm.switchToStandardProfile(profile)
-			}
-		},
-		func() {
-			vm.pages.RemovePage("profileSelector")
-			vm.app.SetFocus(vm.activeView.Content())
+			vm.NotifyInfo(SourceApp, fmt.Sprintf("Switching to %s profile...", profile))
+			vm.switchProfile(profile)
 		},
+		vm.hideProfileSelector,
 		vm.statusBar,
 		vm,
 	)
@@ -684,29 +760,166 @@ func (vm *Manager) ShowProfileSelector() (tview.Primitive, error) {
 	return profileSelector.ShowSelector()
 }
 
+// switchProfile authenticates the current tab with profile.
+func (vm *Manager) switchProfile(profile string) {
+	vm.SaveSession()
+	switch profile {
+	case "opal_dev":
+		vm.switchToDevProfile()
+	case "opal_prod":
+		vm.switchToProdProfile()
+	case "local":
+		vm.switchToLocalProfile()
+	default:
+		vm.switchToStandardProfile(profile)
+	}
+}
+
 func (vm *Manager) hideProfileSelector() {
-	vm.pages.RemovePage("profileSelector")
-	if vm.activeView != nil {
-		vm.app.SetFocus(vm.activeView.Content())
+	vm.tab.pages.RemovePage("profileSelector")
+	if vm.tab.activeView != nil {
+		vm.app.SetFocus(vm.tab.activeView.Content())
 	}
 }
 
+// showSSOLogin shows the verification URL and code for a pending SSO device login.
+func (vm *Manager) showSSOLogin(device auth.DeviceAuthorization) {
+	url := device.VerificationURIComplete
+	if url == "" {
+		url = device.VerificationURI
+	}
+
+	modal := tview.NewModal().
+		SetText(fmt.Sprintf("SSO login for %s\n\nOpen %s\nand confirm the code %s\n\nWaiting for approval (expires %s)...",
+			device.Profile, url, device.UserCode, device.ExpiresAt.Format("15:04:05"))).
+		AddButtons([]string{"Cancel"}).
+		SetDoneFunc(func(int, string) {
+			vm.tab.profileHandler.CancelLogin()
+			vm.hideSSOLogin()
+		})
+	modal.SetBorderColor(style.Color(style.BorderFocus))
+
+	vm.tab.pages.RemovePage(ModalSSOLogin)
+	vm.tab.pages.AddPage(ModalSSOLogin, modal, true, true)
+	vm.app.SetFocus(modal)
+}
+
+func (vm *Manager) hideSSOLogin() {
+	if !vm.tab.pages.HasPage(ModalSSOLogin) {
+		return
+	}
+	vm.tab.pages.RemovePage(ModalSSOLogin)
+	if vm.tab.activeView != nil {
+		vm.app.SetFocus(vm.tab.activeView.Content())
+	}
+}
+
+// promptMFAToken asks for an MFA code in tab, switching to it, and blocks until the code is entered,
+// the prompt is cancelled or the tab is closed. It is called from authentication goroutines, never
+// the UI goroutine.
+func (vm *Manager) promptMFAToken(tab *sessionTab, req auth.MFARequest) (string, error) {
+	type answer struct {
+		code string
+		err  error
+	}
+	answers := make(chan answer, 1)
+
+	vm.app.QueueUpdateDraw(func() {
+		if vm.tabIndex(tab) < 0 {
+			return
+		}
+		if tab != vm.tab {
+			vm.showTab(tab)
+		}
+
+		input := tview.NewInputField().
+			SetLabel(" MFA code: ").
+			SetFieldWidth(8).
+			SetAcceptanceFunc(func(text string, last rune) bool {
+				return len(text) <= 6 && last >= '0' && last <= '9'
+			}).
+			SetFieldBackgroundColor(style.Color(style.InputBackground)).
+			SetFieldTextColor(style.Color(style.InputText)).
+			SetLabelColor(style.Color(style.Label))
+
+		input.SetDoneFunc(func(key tcell.Key) {
+			switch key {
+			case tcell.KeyEnter:
+				if len(input.GetText()) != 6 {
+					return
+				}
+				answers <- answer{code: input.GetText()}
+			case tcell.KeyEscape:
+				answers <- answer{err: fmt.Errorf("MFA prompt cancelled")}
+			default:
+				return
+			}
+			tab.pages.RemovePage(ModalMFA)
+			if tab == vm.tab && tab.activeView != nil {
+				vm.app.SetFocus(tab.activeView.Content())
+			}
+		})
+
+		device := req.MFASerial
+		if req.RoleARN != "" {
+			device = fmt.Sprintf("%s\nfor %s", req.MFASerial, req.RoleARN)
+		}
+		text := tview.NewTextView().
+			SetDynamicColors(true).
+			SetTextAlign(tview.AlignCenter).
+			SetText(fmt.Sprintf("%s%s[-] requires MFA\n%s", style.Tag(style.Warning), req.Profile, tview.Escape(device)))
+
+		content := tview.NewFlex().SetDirection(tview.FlexRow).
+			AddItem(text, 0, 1, false).
+			AddItem(input, 1, 0, true)
+		content.SetBorder(true).
+			SetTitle(" MFA ").
+			SetTitleColor(style.Color(style.Title)).
+			SetBorderColor(style.Color(style.BorderFocus))
+
+		tab.pages.RemovePage(ModalMFA)
+		tab.pages.AddPage(ModalMFA, vm.createModalFlex(content, 80, 7), true, true)
+		vm.app.SetFocus(input)
+	})
+
+	select {
+	case a := <-answers:
+		return a.code, a.err
+	case <-tab.ctx.Done():
+		return "", tab.ctx.Err()
+	}
+}
+
+// updateIdentity shows the effective caller identity of the current session in the header.
+func (vm *Manager) updateIdentity() {
+	identity := ""
+	if vm.tab.profileHandler != nil {
+		identity = vm.tab.profileHandler.GetCurrentIdentity()
+	}
+	if identity == "" {
+		identity = "-"
+	}
+	vm.header.UpdateEnvVar("Identity", identity)
+}
+
 func (vm *Manager) hideHelp() {
-	vm.pages.RemovePage(help.ModalHelp)
-	if vm.activeView != nil {
-		vm.app.SetFocus(vm.activeView.Content())
+	if vm.help.IsVisible() {
+		vm.help.Hide(vm.tab.pages)
+	}
+	if vm.tab.activeView != nil {
+		vm.app.SetFocus(vm.tab.activeView.Content())
 	}
 }
 
 func (vm *Manager) hideJSON() {
-	vm.pages.RemovePage(ModalJSON)
+	vm.tab.pages.RemovePage(ModalJSON)
 	if resultsTable := vm.GetPrimitiveByID("resultsTable"); resultsTable != nil {
 		vm.app.SetFocus(resultsTable)
 	}
 }
 
 func (vm *Manager) hideRowDetails() {
-	vm.pages.RemovePage(types.ModalRowDetails)
+	vm.tab.pages.RemovePage(types.ModalRowDetails)
 
 }
 
@@ -721,17 +934,107 @@ func (vm *Manager) startStatusListener() {
 			case <-vm.ctx.Done():
 				return
 			case status := <-vm.StatusChan:
-				vm.statusBar.SetText(status)
+				vm.Notify(notify.Notification{Severity: notify.SeverityOf(status), Source: SourceApp, Message: status})
 				vm.app.Draw()
 			}
 		}
 	}()
 }
 
+// startExpiryMonitor counts the current credentials down in the header and refreshes them shortly
+// before they expire.
+func (vm *Manager) startExpiryMonitor() {
+	go func() {
+		ticker := time.NewTicker(time.Second)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-vm.ctx.Done():
+				return
+			case <-ticker.C:
+				var changed bool
+				vm.app.QueueUpdate(func() {
+					changed = vm.checkExpiry()
+				})
+				if changed {
+					vm.app.Draw()
+				}
+			}
+		}
+	}()
+}
+
+// checkExpiry refreshes any tab whose credentials are about to expire and counts the current
+// tab's credentials down in the header, reporting whether anything on screen changed.
+func (vm *Manager) checkExpiry() bool {
+	authenticating := false
+	for _, tab := range vm.tabs {
+		if tab.profileHandler == nil {
+			continue
+		}
+		tab.profileHandler.RefreshIfExpiring(tab.ctx)
+		authenticating = authenticating || tab.profileHandler.IsAuthenticating()
+	}
+
+	changed := false
+	if vm.tab.profileHandler != nil {
+		if expiry := formatExpiry(vm.tab.profileHandler.GetCurrentExpiry()); expiry != vm.header.EnvVar("Expires") {
+			vm.header.UpdateEnvVar("Expires", expiry)
+			changed = true
+		}
+	}
+	// A background refresh may have needed an SSO login; close it once done
+	if !authenticating && vm.tab.pages.HasPage(ModalSSOLogin) {
+		vm.hideSSOLogin()
+		changed = true
+	}
+	return changed
+}
+
+// formatExpiry renders the time left on the session's credentials, highlighting it once a refresh is due.
+// It counts in minutes until then, and in seconds after.
+func formatExpiry(expires time.Time, ok bool) string {
+	if !ok {
+		return "-"
+	}
+
+	remaining := time.Until(expires)
+	switch {
+	case remaining <= 0:
+		return style.Tag(style.StatusError) + "expired"
+	case remaining <= auth.CredentialsRefreshWindow:
+		return style.Tag(style.Warning) + remaining.Truncate(time.Second).String()
+	default:
+		return remaining.Truncate(time.Minute).String()
+	}
+}
+
+// switchRegion moves the current tab to region, reporting whether it could. A failure can be
+// retried from the notifications.
+func (vm *Manager) switchRegion(region string) bool {
+	if err := vm.UpdateRegion(region); err != nil {
+		vm.Notify(notify.Notification{
+			Severity: notify.Error,
+			Source:   SourceApp,
+			Message:  fmt.Sprintf("Error switching region: %v", err),
+			Action: &notify.Action{Label: "Retry", Run: func() {
+				// Reinitializing the active view waits on the event loop, which runs actions
+				go func() {
+					vm.switchRegion(region)
+					vm.app.Draw()
+				}()
+			}},
+		})
+		return false
+	}
+	return true
+}
+
 func (vm *Manager) UpdateRegion(region string) error {
-	cfg := vm.awsConfig.Copy()
+	cfg := vm.tab.awsConfig.Copy()
 	cfg.Region = region
-	vm.awsConfig = cfg
+	vm.tab.awsConfig = cfg
 
 	// Re-init only the active view, if it’s a Reinitializer
 	if err := vm.reinitializeActiveView(); err != nil {
@@ -739,19 +1042,18 @@ func (vm *Manager) UpdateRegion(region string) error {
 		return err
 	}
 
-	vm.header.UpdateEnvVar("Region", region)
+	vm.updateSessionHeader()
 	vm.StatusChan <- fmt.Sprintf("Switched region to %s (active view reinitialized)", region)
 	return nil
 }
 
 func (vm *Manager) showCmdPrompt() {
 	vm.prompt.InputField.SetLabel(" > ")
-	vm.prompt.InputField.SetLabelColor(tcell.ColorTeal)
+	vm.prompt.ApplyTheme(style.Current())
+	vm.prompt.InputField.SetLabelColor(style.Color(style.Label))
 	vm.prompt.SetTitle(" Command ")
-	vm.prompt.SetTitleColor(style.GruvboxMaterial.Yellow)
 	vm.prompt.SetBorder(true)
 	vm.prompt.SetTitleAlign(tview.AlignLeft)
-	vm.prompt.SetBorderColor(tcell.ColorMediumTurquoise)
 
 	vm.app.SetFocus(vm.prompt.InputField)
 
@@ -759,57 +1061,77 @@ func (vm *Manager) showCmdPrompt() {
 }
 
 func (vm *Manager) CurrentProfile() string {
-	return vm.profileHandler.GetCurrentProfile()
+	return vm.tab.profileHandler.GetCurrentProfile()
 }
 
 func (vm *Manager) HideFilterPrompt() {
-	vm.pages.RemovePage(types.ModalFilter)
-	if vm.activeView != nil {
-		vm.app.SetFocus(vm.activeView.Content())
+	vm.tab.pages.RemovePage(types.ModalFilter)
+	if vm.tab.activeView != nil {
+		vm.app.SetFocus(vm.tab.activeView.Content())
 	}
 }
 
 func (vm *Manager) GetPrimitiveByID(id string) tview.Primitive {
-	return vm.primitivesByID[id]
-}
-
-func applyStyleToBox(box tview.Primitive, style types.BaseStyle) {
-	if b, ok := box.(interface {
-		SetBorder(bool) *tview.Box
-		SetTitle(string) *tview.Box
-		SetTitleAlign(int) *tview.Box
-		SetTitleColor(tcell.Color) *tview.Box
-		SetBorderColor(tcell.Color) *tview.Box
-	}); ok {
-		if style.Border {
-			b.SetBorder(true)
-			if style.Title != "" {
-				b.SetTitle(style.Title).SetTitleAlign(style.TitleAlign).SetTitleColor(style.TitleColor)
-			}
-			b.SetBorderColor(style.BorderColor)
+	return vm.tab.primitivesByID[id]
+}
+
+// styledBox is the part of every styled primitive that applyStyleToBox needs.
+type styledBox interface {
+	SetBorder(bool) *tview.Box
+	SetTitle(string) *tview.Box
+	SetTitleAlign(int) *tview.Box
+	SetTitleColor(tcell.Color) *tview.Box
+	SetBorderColor(tcell.Color) *tview.Box
+	SetBackgroundColor(tcell.Color) *tview.Box
+	HasFocus() bool
+}
+
+func applyStyleToBox(box styledBox, s types.BaseStyle, t *style.Theme) {
+	background := s.BackgroundColor
+	if background == 0 {
+		background = style.Background
+	}
+	box.SetBackgroundColor(t.Color(background))
+
+	if s.Border {
+		box.SetBorder(true)
+		if s.Title != "" {
+			box.SetTitle(s.Title).SetTitleAlign(s.TitleAlign).SetTitleColor(t.Color(s.TitleColor))
+		}
+		border := s.BorderColor
+		if s.FocusBorderColor != 0 && box.HasFocus() {
+			border = s.FocusBorderColor
 		}
+		box.SetBorderColor(t.Color(border))
+	}
+}
+
+// focusBorder switches a box to its focus border color as it gains focus and back as it loses it.
+func focusBorder(box styledBox, s types.BaseStyle, focused bool) {
+	if !s.Border || s.FocusBorderColor == 0 {
+		return
+	}
+	if focused {
+		box.SetBorderColor(style.Color(s.FocusBorderColor))
+	} else {
+		box.SetBorderColor(style.Color(s.BorderColor))
 	}
 }
 
 func (vm *Manager) showRegionSelector() (tview.Primitive, error) {
 	regionSelector := region.NewRegionSelector(
+		vm.availableRegions(),
 		func(region string) {
 			// Hide first
-			vm.pages.RemovePage("regionSelector")
-			vm.app.SetFocus(vm.activeView.Content())
+			vm.hideRegionSelector()
 
 			// Then do the update
-			vm.statusBar.SetText(fmt.Sprintf("Switching to region %s...", region))
-			if err := vm.UpdateRegion(region); err != nil {
-				vm.StatusChan <- fmt.Sprintf("Error switching region: %v", err)
-			} else {
+			vm.NotifyInfo(SourceApp, fmt.Sprintf("Switching to region %s...", region))
+			if vm.switchRegion(region) {
 				vm.StatusChan <- fmt.Sprintf("Successfully switched to region: %s", region)
 			}
 		},
-		func() {
-			vm.pages.RemovePage("regionSelector")
-			vm.app.SetFocus(vm.activeView.Content())
-		},
+		vm.hideRegionSelector,
 		vm.statusBar,
 		vm,
 	)
@@ -817,10 +1139,26 @@ func (vm *Manager) showRegionSelector() (tview.Primitive, error) {
 	return regionSelector.ShowRegionSelector()
 }
 
+// SetRegions sets the regions the region selector offers. Nil restores region.DefaultRegions.
+func (vm *Manager) SetRegions(regions []string) {
+	vm.regionsMu.Lock()
+	defer vm.regionsMu.Unlock()
+	vm.regions = regions
+}
+
+func (vm *Manager) availableRegions() []string {
+	vm.regionsMu.RLock()
+	defer vm.regionsMu.RUnlock()
+	if len(vm.regions) == 0 {
+		return region.DefaultRegions
+	}
+	return vm.regions
+}
+
 func (vm *Manager) hideRegionSelector() {
-	vm.pages.RemovePage("regionSelector")
-	if vm.activeView != nil {
-		vm.app.SetFocus(vm.activeView.Content())
+	vm.tab.pages.RemovePage("regionSelector")
+	if vm.tab.activeView != nil {
+		vm.app.SetFocus(vm.tab.activeView.Content())
 	}
 }
 
@@ -832,27 +1170,38 @@ func (vm *Manager) Logger() *logger.Logger {
 	return vm.logger
 }
 
-func (vm *Manager) RegisterLazyView(name string, constructor func() (views.View, error)) {
+// lazyView is a view built the first time it is shown.
+type lazyView struct {
+	load  func() error
+	build func() (views.View, error)
+}
+
+// RegisterLazyView registers a view built the first time it is shown. load, which may be nil,
+// runs in the background for the service calls the view needs; build then makes the view on the
+// event loop, as it lays out primitives the running app draws.
+func (vm *Manager) RegisterLazyView(name string, load func() error, build func() (views.View, error)) {
 	if vm.lazyViews == nil {
-		vm.lazyViews = make(map[string]func() (views.View, error))
+		vm.lazyViews = make(map[string]lazyView)
 	}
-	vm.lazyViews[name] = constructor
+	vm.lazyViews[name] = lazyView{load: load, build: build}
 }
 
 func (vm *Manager) setActiveView(view views.View) {
-	if vm.activeView != nil {
-		vm.activeView.Hide()
+	if vm.tab.activeView != nil {
+		vm.tab.activeView.Hide()
 	}
-	vm.activeView = view
+	vm.tab.activeView = view
 	view.Show()
-	vm.pages.SwitchToPage(view.Name())
+	vm.tab.pages.SwitchToPage(view.Name())
+	vm.themeActiveView()
+	vm.systems.viewShown(view.Name(), len(vm.tab.views))
 }
 
 func (vm *Manager) showLoading(message string) {
 	if vm.spinner == nil {
 		vm.spinner = spinner.NewSpinner(message)
 		vm.spinner.SetOnComplete(func() {
-			vm.pages.RemovePage("loading")
+			vm.tab.pages.RemovePage("loading")
 			if vm.loadingCancelFunc != nil {
 				vm.loadingCancelFunc()
 				vm.loadingCancelFunc = nil
@@ -867,7 +1216,7 @@ func (vm *Manager) showLoading(message string) {
 		ctx, vm.loadingCancelFunc = context.WithCancel(vm.ctx)
 
 		modal := spinner.CreateSpinnerModal(vm.spinner)
-		vm.pages.AddPage("loading", modal, true, true)
+		vm.tab.pages.AddPage("loading", modal, true, true)
 		vm.app.SetFocus(modal)
 
 		// Start the spinner with the cancellable context
@@ -887,7 +1236,7 @@ func (vm *Manager) hideLoading() {
 }
 
 func (vm *Manager) switchToProdProfile() error {
-	if vm.profileHandler.IsAuthenticating() {
+	if vm.tab.profileHandler.IsAuthenticating() {
 		status := "Authentication already in progress"
 		vm.StatusChan <- status
 		return fmt.Errorf(status)
@@ -895,20 +1244,20 @@ func (vm *Manager) switchToProdProfile() error {
 
 	vm.hideProfileSelector()
 
-	vm.profileHandler.SwitchProfile(vm.ctx, "opal_prod", func(cfg aws.Config, err error) {
+	vm.tab.profileHandler.SwitchProfile(vm.tab.ctx, "opal_prod", func(cfg aws.Config, err error) {
 		if err != nil {
 			vm.StatusChan <- fmt.Sprintf("Failed to switch to prod profile: %v", err)
 			return
 		}
 
 		vm.showLoading("Authenticating with prod profile...")
-		vm.awsConfig = cfg
-		vm.header.UpdateEnvVar("Profile", "opal_prod")
+		vm.tab.awsConfig = cfg
+		vm.updateSessionHeader()
 
 		if vm.spinner == nil {
 			vm.spinner = spinner.NewSpinner("Loading Available Fields...")
 			vm.spinner.SetOnComplete(func() {
-				vm.pages.RemovePage("loading")
+				vm.tab.pages.RemovePage("loading")
 				if vm.loadingCancelFunc != nil {
 					vm.loadingCancelFunc()
 					vm.loadingCancelFunc = nil
@@ -923,7 +1272,7 @@ func (vm *Manager) switchToProdProfile() error {
 			ctx, vm.loadingCancelFunc = context.WithCancel(vm.ctx)
 
 			modal := spinner.CreateSpinnerModal(vm.spinner)
-			vm.pages.AddPage("loading", modal, true, true)
+			vm.tab.pages.AddPage("loading", modal, true, true)
 			vm.app.SetFocus(modal)
 
 			vm.spinner.StartWithContext(ctx, vm.App())
@@ -934,6 +1283,10 @@ func (vm *Manager) switchToProdProfile() error {
 			return
 		}
 
+		if err := vm.openStartView(); err != nil {
+			vm.Logger().Error("Failed to open start view after prod profile", "error", err)
+		}
+
 		vm.StatusChan <- "Successfully switched to prod profile"
 	})
 
@@ -949,28 +1302,34 @@ func (vm *Manager) GetStatusBar() *statusbar.StatusBar {
 }
 
 func (vm *Manager) switchToStandardProfile(profile string) {
-	if vm.profileHandler.IsAuthenticating() {
+	if vm.tab.profileHandler.IsAuthenticating() {
 		status := "Authentication already in progress"
 		vm.StatusChan <- status
 		return
 	}
 
-	vm.profileHandler.SwitchProfile(vm.ctx, profile, func(cfg aws.Config, err error) {
+	vm.tab.profileHandler.SwitchProfile(vm.tab.ctx, profile, func(cfg aws.Config, err error) {
 		if err != nil {
-			vm.StatusChan <- fmt.Sprintf("Failed to switch to profile %s: %v", profile, err)
+			vm.Notify(notify.Notification{
+				Severity: notify.Error,
+				Source:   "auth",
+				Message:  fmt.Sprintf("Failed to switch to profile %s: %v", profile, err),
+				Action:   &notify.Action{Label: "Retry", Run: func() { vm.switchToStandardProfile(profile) }},
+			})
+			vm.app.Draw()
 			return
 		}
 
-		vm.awsConfig = cfg
-		vm.header.UpdateEnvVar("Profile", profile)
+		vm.tab.awsConfig = cfg
+		vm.updateSessionHeader()
 
 		if err := vm.reinitializeViews(); err != nil {
 			vm.StatusChan <- fmt.Sprintf("Error reinitializing views: %v", err)
 			return
 		}
 
-		if err := vm.SwitchToView(ViewElastic); err != nil {
-			vm.Logger().Error("Failed to switch to Elastic after standard profile", "error", err)
+		if err := vm.openStartView(); err != nil {
+			vm.Logger().Error("Failed to open start view after standard profile", "error", err)
 		}
 
 		vm.StatusChan <- fmt.Sprintf("Successfully switched to profile %s", profile)
@@ -978,11 +1337,11 @@ func (vm *Manager) switchToStandardProfile(profile string) {
 }
 
 func (vm *Manager) reinitializeActiveView() error {
-	if vm.activeView == nil {
+	if vm.tab.activeView == nil {
 		return nil
 	}
-	if reinit, ok := vm.activeView.(views.Reinitializer); ok {
-		return reinit.Reinitialize(vm.awsConfig)
+	if reinit, ok := vm.tab.activeView.(views.Reinitializer); ok {
+		return reinit.Reinitialize(vm.tab.awsConfig)
 	}
 	return nil
 }
@@ -0,0 +1,125 @@
+package manager
+
+import (
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"github.com/tpelletiersophos/cloudcutter/internal/ui/notify"
+	"github.com/tpelletiersophos/cloudcutter/internal/ui/style"
+)
+
+// ModalNotifications lists the notifications posted so far, newest first.
+const ModalNotifications = "modalNotifications"
+
+// Notify posts n to the status bar and the notification history. It may be called from any
+// goroutine. Unless given, the source is the active view, and errors offer to open the logs.
+func (vm *Manager) Notify(n notify.Notification) {
+	if n.Source == "" {
+		n.Source = vm.systems.activeView()
+	}
+	if n.Severity == notify.Error && n.Action == nil && n.Source != ViewLogs {
+		if _, ok := vm.lazyViews[ViewLogs]; ok {
+			n.Action = &notify.Action{Label: "Open logs", Run: func() {
+				if err := vm.SwitchToView(ViewLogs); err != nil {
+					vm.NotifyError(SourceApp, err.Error())
+				}
+			}}
+		}
+	}
+	vm.statusBar.Notifications().Post(n)
+}
+
+// Notifications returns the notification history behind the status bar.
+func (vm *Manager) Notifications() *notify.Center {
+	return vm.statusBar.Notifications()
+}
+
+// ShowNotifications opens the notification history and returns it for focus. Opening it marks the
+// notifications read; Enter runs the selected one's action, or shows it again in the status bar.
+func (vm *Manager) ShowNotifications() tview.Primitive {
+	center := vm.statusBar.Notifications()
+	history := center.History()
+	center.MarkRead()
+	vm.statusBar.Refresh()
+
+	table := tview.NewTable().
+		SetSelectable(true, false).
+		SetFixed(1, 0).
+		SetSelectedStyle(tcell.StyleDefault.
+			Foreground(style.Color(style.SelectionText)).
+			Background(style.Color(style.Selection)))
+	table.SetBorder(true).
+		SetTitle(" Notifications (Enter: run action or show again, r: show again, Esc: close) ").
+		SetBorderColor(style.Color(style.BorderFocus)).
+		SetBackgroundColor(style.Color(style.Background))
+
+	for col, title := range []string{"Time", "Severity", "Source", "Message", "Action"} {
+		table.SetCell(0, col, tview.NewTableCell(title).
+			SetTextColor(style.Color(style.Title)).
+			SetSelectable(false))
+	}
+	for i, n := range history {
+		action := ""
+		if n.Action != nil {
+			action = n.Action.Label
+		}
+		row := i + 1
+		table.SetCell(row, 0, tview.NewTableCell(n.Time.Format("15:04:05")).SetTextColor(style.Color(style.Muted)))
+		table.SetCell(row, 1, tview.NewTableCell(n.Severity.String()).SetTextColor(style.Color(severityRole(n.Severity))))
+		table.SetCell(row, 2, tview.NewTableCell(valueOr(n.Source, SourceApp)).SetTextColor(style.Color(style.Text)))
+		table.SetCell(row, 3, tview.NewTableCell(n.Message).SetTextColor(style.Color(style.Text)).SetExpansion(1))
+		table.SetCell(row, 4, tview.NewTableCell(tview.Escape(action)).SetTextColor(style.Color(style.Label)))
+	}
+	if len(history) == 0 {
+		table.SetCell(1, 0, tview.NewTableCell("No notifications yet").
+			SetTextColor(style.Color(style.Muted)).
+			SetSelectable(false))
+	} else {
+		table.Select(1, 0)
+	}
+
+	selected := func() (notify.Notification, bool) {
+		row, _ := table.GetSelection()
+		if row < 1 || row > len(history) {
+			return notify.Notification{}, false
+		}
+		return history[row-1], true
+	}
+	table.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		n, ok := selected()
+		switch {
+		case !ok:
+			return event
+		case event.Key() == tcell.KeyEnter:
+			vm.HideModal(ModalNotifications)
+			if n.Action != nil {
+				n.Action.Run()
+			} else {
+				vm.statusBar.Show(n)
+			}
+			return nil
+		case event.Key() == tcell.KeyRune && event.Rune() == 'r':
+			vm.HideModal(ModalNotifications)
+			vm.statusBar.Show(n)
+			return nil
+		}
+		return event
+	})
+
+	vm.tab.pages.RemovePage(ModalNotifications)
+	vm.showModal(ModalNotifications, table, 120, 24)
+	vm.app.SetFocus(table)
+	return table
+}
+
+func severityRole(s notify.Severity) style.Role {
+	switch s {
+	case notify.Error:
+		return style.StatusError
+	case notify.Warning:
+		return style.Warning
+	case notify.Success:
+		return style.Success
+	default:
+		return style.Text
+	}
+}
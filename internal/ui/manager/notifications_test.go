@@ -0,0 +1,88 @@
+package manager
+
+import (
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/tpelletiersophos/cloudcutter/internal/ui/notify"
+	"github.com/tpelletiersophos/cloudcutter/internal/ui/views"
+)
+
+func TestNotify(t *testing.T) {
+	vm := newTestManager(t)
+	vm.RegisterLazyView(ViewLogs, nil, func() (views.View, error) { return &commandView{}, nil })
+	vm.systems.viewShown("elastic", 1)
+
+	vm.UpdateStatusBar("Showing all available fields")
+	vm.UpdateStatusBar("Error loading fields: timeout")
+
+	history := vm.Notifications().History()
+	assert.Len(t, history, 2)
+	assert.Equal(t, notify.Error, history[0].Severity)
+	assert.Equal(t, "elastic", history[0].Source, "posted from the active view")
+	if assert.NotNil(t, history[0].Action, "errors offer the logs") {
+		assert.Equal(t, "Open logs", history[0].Action.Label)
+	}
+	assert.Nil(t, history[1].Action)
+	assert.Equal(t, "Error loading fields: timeout [Open logs]  (1 unread)", vm.statusBar.GetText(true))
+
+	vm.UpdateStatusBar("Loaded 10 documents")
+	assert.Equal(t, "Loaded 10 documents  (1 unread)", vm.statusBar.GetText(true), "the error is still counted once hidden")
+}
+
+func TestNotifyHelpers(t *testing.T) {
+	vm := newTestManager(t)
+	vm.systems.viewShown(ViewElastic, 1)
+
+	vm.NotifyInfo(ViewDynamoDB, "Showing 0 errors")
+	vm.NotifySuccess(ViewDynamoDB, "Document saved")
+	vm.NotifyWarning(ViewDynamoDB, "Invalid filter: unclosed quote")
+	vm.NotifyError(SourceApp, "Cannot open the table")
+
+	history := vm.Notifications().History()
+	if assert.Len(t, history, 4) {
+		assert.Equal(t, []notify.Severity{notify.Error, notify.Warning, notify.Success, notify.Info},
+			[]notify.Severity{history[0].Severity, history[1].Severity, history[2].Severity, history[3].Severity},
+			"the severity is the helper's, not guessed from the wording")
+		assert.Equal(t, ViewDynamoDB, history[1].Source, "posted from the view that posted it, not the active one")
+		assert.Equal(t, SourceApp, history[0].Source)
+	}
+}
+
+func TestNotificationHistory(t *testing.T) {
+	vm := newTestManager(t)
+	vm.tab.activeView = &commandView{}
+
+	retries := 0
+	vm.Notify(notify.Notification{
+		Severity: notify.Error,
+		Source:   "auth",
+		Message:  "Failed to switch to profile dev: expired",
+		Action:   &notify.Action{Label: "Retry", Run: func() { retries++ }},
+	})
+	vm.UpdateStatusBar("Switching view...")
+
+	vm.globalInputHandler(tcell.NewEventKey(tcell.KeyRune, 'n', tcell.ModAlt))
+	assert.True(t, vm.tab.pages.HasPage(ModalNotifications))
+	assert.True(t, vm.IsModalVisible())
+	assert.Zero(t, vm.Notifications().Unread(), "opening the history reads the notifications")
+	assert.Equal(t, "Switching view...", vm.statusBar.GetText(true))
+
+	table := vm.app.GetFocus()
+	table.InputHandler()(tcell.NewEventKey(tcell.KeyDown, 0, tcell.ModNone), nil)
+	table.InputHandler()(tcell.NewEventKey(tcell.KeyRune, 'r', tcell.ModNone), nil)
+	assert.False(t, vm.tab.pages.HasPage(ModalNotifications))
+	assert.Equal(t, "Failed to switch to profile dev: expired [Retry]", vm.statusBar.GetText(true), "shown again")
+	assert.Len(t, vm.Notifications().History(), 2, "showing again posts nothing")
+
+	table = vm.ShowNotifications()
+	table.InputHandler()(tcell.NewEventKey(tcell.KeyDown, 0, tcell.ModNone), nil)
+	table.InputHandler()(tcell.NewEventKey(tcell.KeyEnter, 0, tcell.ModNone), nil)
+	assert.Equal(t, 1, retries, "Enter runs the action")
+	assert.False(t, vm.tab.pages.HasPage(ModalNotifications))
+
+	vm.ShowNotifications()
+	vm.globalInputHandler(tcell.NewEventKey(tcell.KeyEsc, 0, tcell.ModNone))
+	assert.False(t, vm.tab.pages.HasPage(ModalNotifications), "Esc closes the history")
+}
@@ -0,0 +1,136 @@
+package manager
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/rivo/tview"
+	"github.com/tpelletiersophos/cloudcutter/internal/session"
+	"github.com/tpelletiersophos/cloudcutter/internal/ui/style"
+	"github.com/tpelletiersophos/cloudcutter/internal/ui/views"
+)
+
+// ModalRestore asks whether to restore a profile's saved session.
+const ModalRestore = "modalRestore"
+
+// SetSessionStore saves each profile's session to store, and offers to restore it once the profile
+// is signed in again.
+func (vm *Manager) SetSessionStore(store *session.Store) {
+	vm.sessions = store
+}
+
+// SaveSession saves the current tab's session under its profile. Views call it after a significant
+// change, such as a new search or a toggled layout.
+func (vm *Manager) SaveSession() {
+	vm.saveTab(vm.tab)
+}
+
+// saveSessions saves the session of every tab; Run calls it when the app exits.
+func (vm *Manager) saveSessions() {
+	for _, tab := range vm.tabs {
+		vm.saveTab(tab)
+	}
+}
+
+func (vm *Manager) saveTab(tab *sessionTab) {
+	if vm.sessions == nil || tab.activeView == nil {
+		return
+	}
+	profile := tab.profile()
+	if profile == "" {
+		return
+	}
+
+	// Views with nothing to restore, such as the logs, leave the last session in place
+	persistent, ok := tab.activeView.(views.Persistent)
+	if !ok {
+		return
+	}
+	state := session.State{Region: tab.region(), View: tab.activeView.Name()}
+	persistent.SaveState(&state)
+	if err := vm.sessions.Put(profile, state); err != nil {
+		vm.logger.Warn("Failed to save session", "profile", profile, "error", err)
+	}
+}
+
+// savedSession returns the session saved for the current tab's profile, if it names a view.
+func (vm *Manager) savedSession() (session.State, bool) {
+	if vm.sessions == nil {
+		return session.State{}, false
+	}
+	state, ok := vm.sessions.Get(vm.tab.profile())
+	if !ok || !vm.hasView(state.View) {
+		return session.State{}, false
+	}
+	return state, true
+}
+
+func (vm *Manager) hasView(name string) bool {
+	_, built := vm.tab.views[name]
+	_, lazy := vm.lazyViews[name]
+	return built || lazy
+}
+
+// offerRestore asks whether to pick up the saved session or start in the Elastic view.
+func (vm *Manager) offerRestore(state session.State) {
+	profile := vm.tab.profile()
+	modal := tview.NewModal().
+		SetText(fmt.Sprintf("Restore your last %s session?\n\n%s", profile, describeSession(state))).
+		AddButtons([]string{"Restore", "Start fresh"}).
+		SetDoneFunc(func(index int, _ string) {
+			vm.HideModal(ModalRestore)
+			var err error
+			if index == 0 {
+				err = vm.restoreSession(state)
+			} else {
+				err = vm.SwitchToView(ViewElastic)
+			}
+			if err != nil {
+				vm.NotifyError(SourceApp, fmt.Sprintf("Error opening view: %v", err))
+			}
+		})
+	modal.SetBorderColor(style.Color(style.BorderFocus))
+
+	vm.tab.pages.RemovePage(ModalRestore)
+	vm.tab.pages.AddPage(ModalRestore, modal, true, true)
+	vm.app.SetFocus(modal)
+}
+
+// restoreSession opens the saved view and gives it its saved state. The saved region is only
+// restored before the tab has built any view, since built views keep the region they started in.
+func (vm *Manager) restoreSession(state session.State) error {
+	if state.Region != "" && state.Region != vm.tab.region() && len(vm.tab.views) == 0 {
+		vm.setTabRegion(state.Region)
+	}
+	return vm.switchToView(state.View, func(view views.View) {
+		if persistent, ok := view.(views.Persistent); ok {
+			persistent.RestoreState(state)
+		}
+	})
+}
+
+// describeSession summarizes a saved session for the restore prompt.
+func describeSession(state session.State) string {
+	parts := []string{state.View}
+	switch {
+	case state.Elastic != nil && state.View == ViewElastic:
+		parts = append(parts, state.Elastic.Index)
+		if n := len(state.Elastic.Filters); n > 0 {
+			parts = append(parts, fmt.Sprintf("%d filter(s)", n))
+		}
+		if state.Elastic.Timeframe != "" {
+			parts = append(parts, state.Elastic.Timeframe)
+		}
+	case state.DynamoDB != nil && state.View == ViewDynamoDB && state.DynamoDB.Table != "":
+		parts = append(parts, state.DynamoDB.Table)
+	}
+	if state.Region != "" {
+		parts = append(parts, state.Region)
+	}
+
+	summary := strings.Join(parts, " · ")
+	if !state.SavedAt.IsZero() {
+		summary += fmt.Sprintf("\nsaved %s", state.SavedAt.Local().Format("2006-01-02 15:04"))
+	}
+	return summary
+}
@@ -0,0 +1,89 @@
+package manager
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/stretchr/testify/assert"
+	"github.com/tpelletiersophos/cloudcutter/internal/logger"
+	"github.com/tpelletiersophos/cloudcutter/internal/session"
+	"github.com/tpelletiersophos/cloudcutter/internal/ui/components/profile"
+)
+
+// tableView is a dynamodb view that saves and restores the table it shows.
+type tableView struct {
+	commandView
+	table    string
+	restored []session.State
+}
+
+func (v *tableView) Name() string { return ViewDynamoDB }
+
+func (v *tableView) SaveState(state *session.State) {
+	state.DynamoDB = &session.DynamoDBState{Table: v.table, RowNumbers: true}
+}
+
+func (v *tableView) RestoreState(state session.State) {
+	v.restored = append(v.restored, state)
+}
+
+func TestSessionPersistence(t *testing.T) {
+	vm := newTestManager(t)
+	path := filepath.Join(t.TempDir(), "state.json")
+	store, err := session.Open(path)
+	assert.NoError(t, err)
+
+	view := &tableView{table: "users"}
+	vm.tab.views[ViewDynamoDB] = view
+	vm.tab.activeView = view
+	vm.SaveSession()
+	_, ok := store.Get("local")
+	assert.False(t, ok, "nothing is saved without a store")
+
+	vm.SetSessionStore(store)
+	vm.SaveSession()
+	_, ok = store.Get("")
+	assert.False(t, ok, "nothing is saved before a profile is signed in")
+
+	// Sign in quietly; the manager's own handler updates a UI that is not running
+	handler, err := profile.NewProfileHandler(make(chan string, 10), func(string) {}, func() {})
+	if !assert.NoError(t, err) {
+		return
+	}
+	vm.tab.profileHandler = handler
+	signedIn := make(chan error, 1)
+	vm.tab.profileHandler.SwitchProfile(vm.ctx, "local", func(_ aws.Config, err error) { signedIn <- err })
+	if !assert.NoError(t, <-signedIn) {
+		return
+	}
+
+	vm.SaveSession()
+	reopened, err := session.Open(path)
+	assert.NoError(t, err)
+	saved, ok := reopened.Get("local")
+	if assert.True(t, ok) {
+		assert.Equal(t, ViewDynamoDB, saved.View)
+		assert.Equal(t, &session.DynamoDBState{Table: "users", RowNumbers: true}, saved.DynamoDB)
+	}
+
+	state, ok := vm.savedSession()
+	assert.True(t, ok)
+	vm.offerRestore(state)
+	assert.True(t, vm.tab.pages.HasPage(ModalRestore))
+	assert.True(t, vm.IsModalVisible(), "the offer holds keys back from the view")
+	assert.Contains(t, describeSession(state), "dynamodb · users · ")
+
+	vm.logger, err = logger.New(logger.Config{LogDir: t.TempDir(), Prefix: "test", Level: logger.ERROR})
+	if assert.NoError(t, err) {
+		t.Cleanup(func() { vm.logger.Close() })
+		vm.tab.activeView = nil
+		assert.NoError(t, vm.restoreSession(state))
+		assert.Same(t, view, vm.tab.activeView)
+		assert.Equal(t, []session.State{state}, view.restored)
+	}
+
+	assert.NoError(t, store.Put("local", session.State{View: "s3"}))
+	_, ok = vm.savedSession()
+	assert.False(t, ok, "a session in a view that no longer exists is not offered")
+}
@@ -0,0 +1,242 @@
+package manager
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/rivo/tview"
+	"github.com/tpelletiersophos/cloudcutter/internal/auth"
+	"github.com/tpelletiersophos/cloudcutter/internal/deeplink"
+	"github.com/tpelletiersophos/cloudcutter/internal/services"
+	"github.com/tpelletiersophos/cloudcutter/internal/ui/components/profile"
+	"github.com/tpelletiersophos/cloudcutter/internal/ui/style"
+	"github.com/tpelletiersophos/cloudcutter/internal/ui/views"
+)
+
+const defaultTabRegion = "us-west-2"
+
+// sessionTab is one live session: a profile and region with its own authentication, service
+// instances and views. Views and manager modals live in the tab's own pages, so switching tabs
+// leaves every other session exactly as it was.
+type sessionTab struct {
+	id             int
+	profileHandler *profile.Handler
+	awsConfig      aws.Config
+	services       *services.Services
+	pages          *tview.Pages
+	views          map[string]views.View
+	activeView     views.View
+	primitivesByID map[string]tview.Primitive
+	restylers      map[string]func(*style.Theme) // by component ID, to follow theme changes
+	viewThemes     map[string]*style.Theme       // by view name, the theme each view last drew with
+	link           *deeplink.Link                // opened once the tab's profile is authenticated
+
+	// ctx is cancelled when the tab closes, stopping its authentication and its views' requests
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+func (t *sessionTab) pageName() string {
+	return fmt.Sprintf("tab-%d", t.id)
+}
+
+func (t *sessionTab) profile() string {
+	if t.profileHandler != nil {
+		if name := t.profileHandler.GetCurrentProfile(); name != "" {
+			return name
+		}
+	}
+	return ""
+}
+
+func (t *sessionTab) region() string {
+	if t.awsConfig.Region != "" {
+		return t.awsConfig.Region
+	}
+	return defaultTabRegion
+}
+
+// label is how the tab appears in the tab bar.
+func (t *sessionTab) label() string {
+	name := t.profile()
+	if name == "" {
+		name = "new"
+	}
+	return fmt.Sprintf("%s@%s", name, t.region())
+}
+
+// policy is the environment policy protecting the tab's session, nil when unprotected.
+func (t *sessionTab) policy() *auth.EnvironmentPolicy {
+	if t.profileHandler != nil {
+		return t.profileHandler.GetCurrentPolicy()
+	}
+	return nil
+}
+
+// openTab adds a session tab using cfg until a profile is chosen for it, and makes it current.
+func (vm *Manager) openTab(cfg aws.Config) *sessionTab {
+	vm.nextTabID++
+	svc, _ := services.New(cfg, cfg.Region)
+	tab := &sessionTab{
+		id:             vm.nextTabID,
+		awsConfig:      cfg,
+		services:       svc,
+		pages:          tview.NewPages(),
+		views:          make(map[string]views.View),
+		primitivesByID: make(map[string]tview.Primitive),
+		restylers:      make(map[string]func(*style.Theme)),
+		viewThemes:     make(map[string]*style.Theme),
+	}
+	tab.ctx, tab.cancel = context.WithCancel(vm.ctx)
+	tab.profileHandler = vm.newProfileHandler(tab)
+	if tab.profileHandler != nil && cfg.Region != "" {
+		tab.profileHandler.SetRegion(cfg.Region)
+	}
+
+	vm.tabs = append(vm.tabs, tab)
+	vm.tabPages.AddPage(tab.pageName(), tab.pages, true, false)
+	vm.showTab(tab)
+	return tab
+}
+
+// NewTab opens an empty session tab and asks which profile it should use.
+func (vm *Manager) NewTab() (tview.Primitive, error) {
+	if vm.tabSwitchBlocked() {
+		return nil, fmt.Errorf("wait for the current operation to finish before opening a tab")
+	}
+	vm.openTab(vm.tab.awsConfig)
+	return vm.ShowProfileSelector()
+}
+
+// CloseTab closes the current tab, keeping at least one open.
+func (vm *Manager) CloseTab() error {
+	if len(vm.tabs) == 1 {
+		return fmt.Errorf("cannot close the last tab")
+	}
+	if vm.tabSwitchBlocked() {
+		return fmt.Errorf("wait for the current operation to finish before closing the tab")
+	}
+
+	closing := vm.tab
+	vm.saveTab(closing)
+	closing.cancel()
+	index := vm.tabIndex(closing)
+	if closing.activeView != nil {
+		closing.activeView.Hide()
+	}
+	vm.tabs = append(vm.tabs[:index], vm.tabs[index+1:]...)
+	vm.tabPages.RemovePage(closing.pageName())
+
+	if index == len(vm.tabs) {
+		index--
+	}
+	vm.showTab(vm.tabs[index])
+	return nil
+}
+
+// CycleTab moves to the tab offset places from the current one, wrapping around.
+func (vm *Manager) CycleTab(offset int) {
+	if len(vm.tabs) < 2 || vm.tabSwitchBlocked() {
+		return
+	}
+	index := (vm.tabIndex(vm.tab) + offset + len(vm.tabs)) % len(vm.tabs)
+	vm.showTab(vm.tabs[index])
+}
+
+// SelectTab moves to the tab at a 1-based position.
+func (vm *Manager) SelectTab(position int) {
+	if position < 1 || position > len(vm.tabs) || vm.tabSwitchBlocked() {
+		return
+	}
+	vm.showTab(vm.tabs[position-1])
+}
+
+// handleTabAction runs the new_tab, next_tab, prev_tab and tab_1..tab_9 key actions.
+func (vm *Manager) handleTabAction(action string) bool {
+	switch action {
+	case "new_tab":
+		if _, err := vm.NewTab(); err != nil {
+			vm.NotifyWarning(SourceApp, err.Error())
+		}
+	case "next_tab":
+		vm.CycleTab(1)
+	case "prev_tab":
+		vm.CycleTab(-1)
+	default:
+		n, ok := strings.CutPrefix(action, "tab_")
+		if !ok || len(n) != 1 || n[0] < '1' || n[0] > '9' {
+			return false
+		}
+		vm.SelectTab(int(n[0] - '0'))
+	}
+	return true
+}
+
+// Services returns the service instances of the current tab.
+func (vm *Manager) Services() *services.Services {
+	return vm.tab.services
+}
+
+func (vm *Manager) tabIndex(tab *sessionTab) int {
+	for i, t := range vm.tabs {
+		if t == tab {
+			return i
+		}
+	}
+	return -1
+}
+
+// tabSwitchBlocked keeps the current tab while a view is loading, since views attach their pages
+// to whichever tab is current when they finish.
+func (vm *Manager) tabSwitchBlocked() bool {
+	return vm.spinner != nil && vm.spinner.IsLoading()
+}
+
+func (vm *Manager) showTab(tab *sessionTab) {
+	if vm.tab != nil && vm.tab != tab && vm.tab.activeView != nil {
+		vm.tab.activeView.Hide()
+	}
+	vm.tab = tab
+	vm.tabPages.SwitchToPage(tab.pageName())
+
+	vm.UpdateHeader(nil)
+	if tab.activeView != nil {
+		tab.activeView.Show()
+		vm.app.SetFocus(tab.activeView.Content())
+		vm.themeActiveView()
+		vm.systems.viewShown(tab.activeView.Name(), len(tab.views))
+	}
+	vm.updateSessionHeader()
+}
+
+// updateSessionHeader shows the current tab's session in the header and redraws the tab bar.
+func (vm *Manager) updateSessionHeader() {
+	profileName := vm.tab.profile()
+	if profileName == "" {
+		profileName = "default"
+	}
+	vm.header.UpdateEnvVar("Profile", profileName)
+	vm.header.UpdateEnvVar("Region", vm.tab.region())
+	vm.updateIdentity()
+	vm.updateEnvironmentBanner()
+	vm.renderTabBar()
+}
+
+// renderTabBar draws one label per tab. Protected tabs are red, and the current tab is reversed.
+func (vm *Manager) renderTabBar() {
+	var b strings.Builder
+	for i, tab := range vm.tabs {
+		color := style.Color(style.Label)
+		if tab.policy() != nil {
+			color = style.Color(style.ProdWarning)
+		}
+		attrs := "-"
+		if tab == vm.tab {
+			attrs = "rb"
+		}
+		fmt.Fprintf(&b, "[%s::%s] %d:%s [-:-:-] ", color, attrs, i+1, tview.Escape(tab.label()))
+	}
+	vm.tabBar.SetText(b.String())
+}
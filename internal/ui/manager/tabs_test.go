@@ -0,0 +1,144 @@
+package manager
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/gdamore/tcell/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tpelletiersophos/cloudcutter/internal/auth"
+	"github.com/tpelletiersophos/cloudcutter/internal/ui"
+)
+
+func newTestManager(t *testing.T) *Manager {
+	t.Helper()
+	t.Setenv("HOME", t.TempDir())
+	vm := NewViewManager(context.Background(), ui.NewApp(), aws.Config{Region: "eu-west-1"}, nil)
+	t.Cleanup(vm.cancelFunc)
+	return vm
+}
+
+func TestSessionTabs(t *testing.T) {
+	vm := newTestManager(t)
+	first := vm.tab
+
+	assert.Len(t, vm.tabs, 1)
+	assert.Equal(t, "new@eu-west-1", first.label())
+	assert.NotNil(t, vm.Services())
+
+	second := vm.openTab(aws.Config{Region: "us-east-1"})
+	third := vm.openTab(aws.Config{})
+	assert.Same(t, third, vm.tab)
+	assert.Equal(t, "new@us-west-2", third.label())
+
+	// Each tab keeps its own pages, services and authentication
+	assert.NotSame(t, first.pages, second.pages)
+	assert.NotSame(t, first.services, second.services)
+	assert.NotSame(t, first.profileHandler, second.profileHandler)
+	assert.Same(t, third.pages, vm.Pages())
+
+	vm.CycleTab(1)
+	assert.Same(t, first, vm.tab, "cycling past the last tab wraps around")
+	vm.CycleTab(-1)
+	assert.Same(t, third, vm.tab)
+	vm.SelectTab(2)
+	assert.Same(t, second, vm.tab)
+	assert.Equal(t, "us-east-1", vm.GetCurrentConfig().Region)
+
+	vm.SelectTab(9)
+	assert.Same(t, second, vm.tab, "selecting a missing tab is ignored")
+
+	assert.NoError(t, vm.CloseTab())
+	assert.Equal(t, []*sessionTab{first, third}, vm.tabs)
+	assert.Same(t, third, vm.tab, "closing a tab moves to the one that took its place")
+	assert.False(t, vm.tabPages.HasPage(second.pageName()))
+	assert.Error(t, second.ctx.Err(), "closing a tab cancels its work")
+	assert.NoError(t, third.ctx.Err())
+	assert.Same(t, third.ctx, vm.ViewContext())
+
+	assert.NoError(t, vm.CloseTab())
+	assert.Same(t, first, vm.tab)
+	assert.Error(t, vm.CloseTab(), "the last tab cannot be closed")
+}
+
+func TestTabKeys(t *testing.T) {
+	vm := newTestManager(t)
+	first := vm.tab
+	second := vm.openTab(aws.Config{})
+
+	assert.Nil(t, vm.globalInputHandler(tcell.NewEventKey(tcell.KeyRune, '1', tcell.ModAlt)))
+	assert.Same(t, first, vm.tab)
+	assert.Nil(t, vm.globalInputHandler(tcell.NewEventKey(tcell.KeyRight, 0, tcell.ModAlt)))
+	assert.Same(t, second, vm.tab)
+
+	assert.NotNil(t, vm.globalInputHandler(tcell.NewEventKey(tcell.KeyRune, '1', tcell.ModNone)), "plain digits belong to the view")
+	assert.NotNil(t, vm.globalInputHandler(tcell.NewEventKey(tcell.KeyRight, 0, tcell.ModNone)))
+	assert.Same(t, second, vm.tab)
+}
+
+func TestRenderTabBar(t *testing.T) {
+	vm := newTestManager(t)
+	vm.openTab(aws.Config{Region: "us-east-1"})
+
+	text := vm.tabBar.GetText(false)
+	assert.True(t, strings.Contains(text, "[mediumturquoise::-] 1:new@eu-west-1"), text)
+	assert.True(t, strings.Contains(text, "[mediumturquoise::rb] 2:new@us-east-1"), "current tab is highlighted: %s", text)
+}
+
+func TestMFAPromptInRequestingTab(t *testing.T) {
+	vm := newTestManager(t)
+	first := vm.tab
+	second := vm.openTab(aws.Config{})
+
+	screen := tcell.NewSimulationScreen("UTF-8")
+	require.NoError(t, screen.Init())
+	vm.app.SetScreen(screen)
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		assert.NoError(t, vm.app.Run())
+	}()
+	t.Cleanup(func() {
+		vm.app.Stop()
+		<-stopped
+	})
+
+	// onLoop runs f on the event loop and waits for it
+	onLoop := func(f func()) {
+		done := make(chan struct{})
+		vm.app.QueueUpdate(func() {
+			f()
+			close(done)
+		})
+		<-done
+	}
+
+	codes := make(chan string, 1)
+	go func() {
+		code, err := vm.promptMFAToken(first, auth.MFARequest{Profile: "dev", MFASerial: "arn:aws:iam::1:mfa/dev"})
+		assert.NoError(t, err)
+		codes <- code
+	}()
+
+	prompted := func() (ok bool) {
+		onLoop(func() { ok = first.pages.HasPage(ModalMFA) })
+		return ok
+	}
+	require.Eventually(t, prompted, 5*time.Second, 10*time.Millisecond)
+	onLoop(func() {
+		assert.Same(t, first, vm.tab, "the prompt switches to the tab that asked for it")
+		assert.False(t, second.pages.HasPage(ModalMFA))
+
+		input := vm.app.GetFocus()
+		for _, r := range "123456" {
+			input.InputHandler()(tcell.NewEventKey(tcell.KeyRune, r, tcell.ModNone), nil)
+		}
+		input.InputHandler()(tcell.NewEventKey(tcell.KeyEnter, 0, tcell.ModNone), nil)
+		assert.False(t, first.pages.HasPage(ModalMFA))
+	})
+	assert.Equal(t, "123456", <-codes)
+}
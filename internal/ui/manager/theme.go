@@ -0,0 +1,75 @@
+package manager
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/tpelletiersophos/cloudcutter/internal/ui/style"
+	"github.com/tpelletiersophos/cloudcutter/internal/ui/views"
+)
+
+// watchTheme restyles the running app whenever the theme changes, until the manager's context
+// ends. The theme can change on any goroutine, the UI's own included, so the restyle is queued
+// from a fresh one rather than waited for.
+func (vm *Manager) watchTheme() {
+	stop := style.OnChange(func(*style.Theme) {
+		go vm.app.QueueUpdateDraw(func() { vm.applyTheme(style.Current()) })
+	})
+	go func() {
+		<-vm.ctx.Done()
+		stop()
+	}()
+}
+
+// SetTheme switches to a built-in theme, one in the themes directory or a theme file.
+func (vm *Manager) SetTheme(nameOrPath string) error {
+	t, err := style.LoadTheme(nameOrPath)
+	if err != nil {
+		return err
+	}
+	style.Use(t)
+	vm.NotifyInfo(SourceApp, fmt.Sprintf("Theme %s", t.Name))
+	return nil
+}
+
+// applyTheme recolors the manager's own components and every tab's layout components, and redraws
+// the active view. Other views redraw when they are next shown.
+func (vm *Manager) applyTheme(t *style.Theme) {
+	vm.header.ApplyTheme(t)
+	vm.statusBar.ApplyTheme(t)
+	vm.tabBar.SetBackgroundColor(t.Color(style.Background))
+	vm.renderTabBar()
+
+	for _, tab := range vm.tabs {
+		for _, restyle := range tab.restylers {
+			restyle(t)
+		}
+	}
+	vm.themeActiveView()
+}
+
+// themeActiveView redraws the current tab's active view if it last drew with another theme.
+func (vm *Manager) themeActiveView() {
+	view, ok := vm.tab.activeView.(views.Themed)
+	if !ok {
+		return
+	}
+	name := vm.tab.activeView.Name()
+	if t := style.Current(); vm.tab.viewThemes[name] != t {
+		vm.tab.viewThemes[name] = t
+		view.ApplyTheme(t)
+	}
+}
+
+// fillBackground paints the theme's background behind everything before each draw, so gaps
+// between components do not show the terminal's own.
+func fillBackground(screen tcell.Screen) bool {
+	screen.Fill(' ', tcell.StyleDefault.Background(style.Color(style.Background)))
+	return false
+}
+
+// themeStatus names the current theme and those that can be chosen.
+func themeStatus() string {
+	return fmt.Sprintf("Theme %s; available: %s", style.Current().Name, strings.Join(style.ThemeNames(), ", "))
+}
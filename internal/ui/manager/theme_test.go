@@ -0,0 +1,52 @@
+package manager
+
+import (
+	"testing"
+
+	"github.com/rivo/tview"
+	"github.com/stretchr/testify/assert"
+	"github.com/tpelletiersophos/cloudcutter/internal/ui/components/types"
+	"github.com/tpelletiersophos/cloudcutter/internal/ui/style"
+)
+
+func TestThemeCommand(t *testing.T) {
+	vm := newTestManager(t)
+	t.Cleanup(func() { style.Use(nil) })
+
+	vm.CreateLayout(types.LayoutConfig{
+		Direction: tview.FlexRow,
+		Components: []types.Component{{
+			ID:   "pane",
+			Type: types.ComponentTextView,
+			Style: types.TextViewStyle{BaseStyle: types.BaseStyle{
+				Border:           true,
+				BorderColor:      style.Border,
+				FocusBorderColor: style.BorderFocus,
+			}},
+		}},
+	})
+	pane := vm.GetPrimitiveByID("pane").(*tview.TextView)
+	assert.Equal(t, style.Gruvbox.Color(style.Border), pane.GetBorderColor())
+
+	vm.handleCommand("theme solarized-light")
+	assert.Same(t, style.SolarizedLight, style.Current())
+	assert.Equal(t, "Theme solarized-light", vm.statusBar.GetText(true))
+
+	// The switch is queued for the UI goroutine, which these tests do not run
+	vm.applyTheme(style.Current())
+	assert.Equal(t, style.SolarizedLight.Color(style.Border), pane.GetBorderColor())
+	assert.Equal(t, style.SolarizedLight.Color(style.Background), pane.GetBackgroundColor())
+
+	vm.app.SetFocus(pane)
+	assert.Equal(t, style.SolarizedLight.Color(style.BorderFocus), pane.GetBorderColor(), "focus switches the border color")
+
+	vm.handleCommand("theme neon")
+	assert.Contains(t, vm.statusBar.GetText(true), `Error executing command: unknown theme "neon"`)
+	assert.Same(t, style.SolarizedLight, style.Current())
+
+	vm.handleCommand("theme")
+	assert.Equal(t, "Theme solarized-light; available: gruvbox, solarized-light, high-contrast  (1 unread)", vm.statusBar.GetText(true),
+		"the unknown theme is still unread")
+
+	assert.Equal(t, []string{"theme high-contrast"}, vm.completeCommand("theme hi"))
+}
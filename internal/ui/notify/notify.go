@@ -0,0 +1,167 @@
+// Package notify keeps the notifications shown in the status bar, so that messages from background
+// operations can be read again, and acted on, after newer ones replace them.
+package notify
+
+import (
+	"strings"
+	"sync"
+	"time"
+)
+
+// MaxHistory is how many notifications a Center keeps.
+const MaxHistory = 200
+
+type Severity int
+
+const (
+	Info Severity = iota
+	Success
+	Warning
+	Error
+)
+
+func (s Severity) String() string {
+	switch s {
+	case Success:
+		return "success"
+	case Warning:
+		return "warning"
+	case Error:
+		return "error"
+	default:
+		return "info"
+	}
+}
+
+// Action is something the user can do about a notification, such as retrying what failed. Run is
+// called on the event loop, so work that waits on it must move to a goroutine.
+type Action struct {
+	Label string
+	Run   func()
+}
+
+type Notification struct {
+	ID       int
+	Time     time.Time
+	Severity Severity
+	Source   string // the view that posted it, or empty for the app itself
+	Message  string
+	Action   *Action
+}
+
+// Center holds the latest notifications. It is safe for use from any goroutine.
+type Center struct {
+	mu        sync.Mutex
+	items     []Notification // oldest first
+	max       int
+	nextID    int
+	unread    int
+	listeners []func(Notification)
+}
+
+// NewCenter returns a center that keeps the last max notifications.
+func NewCenter(max int) *Center {
+	return &Center{max: max}
+}
+
+// Post adds n, stamping its ID and, if unset, its time, and tells the listeners.
+func (c *Center) Post(n Notification) Notification {
+	c.mu.Lock()
+	c.nextID++
+	n.ID = c.nextID
+	if n.Time.IsZero() {
+		n.Time = time.Now()
+	}
+	c.items = append(c.items, n)
+	if len(c.items) > c.max {
+		c.items = append([]Notification(nil), c.items[len(c.items)-c.max:]...)
+	}
+	if n.Severity >= Warning {
+		c.unread++
+	}
+	listeners := c.listeners
+	c.mu.Unlock()
+
+	for _, fn := range listeners {
+		fn(n)
+	}
+	return n
+}
+
+// OnPost calls fn with every notification posted from now on.
+func (c *Center) OnPost(fn func(Notification)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.listeners = append(c.listeners, fn)
+}
+
+// Latest returns the newest notification, or false if there is none.
+func (c *Center) Latest() (Notification, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.items) == 0 {
+		return Notification{}, false
+	}
+	return c.items[len(c.items)-1], true
+}
+
+// History returns the notifications kept, newest first.
+func (c *Center) History() []Notification {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	history := make([]Notification, len(c.items))
+	for i, n := range c.items {
+		history[len(c.items)-1-i] = n
+	}
+	return history
+}
+
+// Get returns the notification with id, or false once it has been dropped.
+func (c *Center) Get(id int) (Notification, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, n := range c.items {
+		if n.ID == id {
+			return n, true
+		}
+	}
+	return Notification{}, false
+}
+
+// Unread counts the warnings and errors posted since MarkRead, which are the ones worth going back
+// for when a newer message hides them.
+func (c *Center) Unread() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.unread
+}
+
+func (c *Center) MarkRead() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.unread = 0
+}
+
+// Clear drops every notification.
+func (c *Center) Clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.items, c.unread = nil, 0
+}
+
+// SeverityOf guesses the severity of a plain status message from its wording and color.
+func SeverityOf(message string) Severity {
+	m := strings.ToLower(message)
+	switch {
+	case strings.HasPrefix(m, "[red]"), strings.Contains(m, "error"), strings.Contains(m, "failed"),
+		strings.HasPrefix(m, "unknown command"):
+		return Error
+	case strings.HasPrefix(m, "[yellow]"), strings.Contains(m, "rate limited"), strings.Contains(m, "not allowed"),
+		strings.Contains(m, "warning"):
+		return Warning
+	case strings.HasPrefix(m, "[green]"), strings.Contains(m, "successfully"):
+		return Success
+	default:
+		return Info
+	}
+}
@@ -0,0 +1,64 @@
+package notify
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCenter(t *testing.T) {
+	c := NewCenter(3)
+	var posted []int
+	c.OnPost(func(n Notification) { posted = append(posted, n.ID) })
+
+	_, ok := c.Latest()
+	assert.False(t, ok)
+
+	c.Post(Notification{Message: "loading"})
+	c.Post(Notification{Severity: Warning, Message: "rate limited"})
+	c.Post(Notification{Severity: Error, Message: "search failed"})
+	last := c.Post(Notification{Severity: Success, Message: "loaded"})
+
+	assert.Equal(t, []int{1, 2, 3, 4}, posted)
+	assert.Equal(t, 4, last.ID)
+	assert.False(t, last.Time.IsZero(), "stamped when posted")
+
+	latest, ok := c.Latest()
+	assert.True(t, ok)
+	assert.Equal(t, "loaded", latest.Message)
+
+	var messages []string
+	for _, n := range c.History() {
+		messages = append(messages, n.Message)
+	}
+	assert.Equal(t, []string{"loaded", "search failed", "rate limited"}, messages, "newest first, oldest dropped")
+
+	_, ok = c.Get(1)
+	assert.False(t, ok)
+	n, ok := c.Get(3)
+	assert.True(t, ok)
+	assert.Equal(t, "search failed", n.Message)
+
+	assert.Equal(t, 2, c.Unread(), "warnings and errors")
+	c.MarkRead()
+	assert.Zero(t, c.Unread())
+
+	c.Clear()
+	assert.Empty(t, c.History())
+}
+
+func TestSeverityOf(t *testing.T) {
+	for message, want := range map[string]Severity{
+		"Error fetching DynamoDB tables: timeout":          Error,
+		"Failed to switch to profile dev: expired":         Error,
+		"[red]Config not reloaded:[-] bad yaml":            Error,
+		"Unknown command: nosuch":                          Error,
+		"Rate limited (attempt 1/3), retrying in 200ms...": Warning,
+		"[yellow]Profile name does not match":              Warning,
+		"Successfully switched to region: eu-west-1":       Success,
+		"[green]Copied link[-] cloudcutter://x":            Success,
+		"Showing all tables":                               Info,
+	} {
+		assert.Equal(t, want, SeverityOf(message), message)
+	}
+}
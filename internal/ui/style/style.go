@@ -65,16 +65,17 @@ func ColorizeJSON(input string) string {
 
 // ColorizeValue recursively walks the JSON data and appends a colorized representation to sb.
 func ColorizeValue(v any, indent int, sb *strings.Builder, isKey bool) {
+	theme := Current()
 	switch val := v.(type) {
 
 	case map[string]interface{}:
-		sb.WriteString(fmt.Sprintf("[%s]{[%s]\n", GruvboxMaterial.Yellow, tcell.ColorReset))
+		sb.WriteString(fmt.Sprintf("[%s]{[%s]\n", theme.Color(JSONPunctuation), tcell.ColorReset))
 		keys := 0
 		for k, child := range val {
 			keys++
 			writeIndent(sb, indent+2)
 			// Color the key as a string:
-			sb.WriteString(fmt.Sprintf(`[%s]"%s"[%s]: `, GruvboxMaterial.Blue, k, tcell.ColorReset))
+			sb.WriteString(fmt.Sprintf(`[%s]"%s"[%s]: `, theme.Color(JSONKey), k, tcell.ColorReset))
 			// Then colorize the child
 			ColorizeValue(child, indent+2, sb, false)
 			if keys < len(val) {
@@ -83,10 +84,10 @@ func ColorizeValue(v any, indent int, sb *strings.Builder, isKey bool) {
 			sb.WriteRune('\n')
 		}
 		writeIndent(sb, indent)
-		sb.WriteString(fmt.Sprintf("[%s]}[%s]", GruvboxMaterial.Yellow, tcell.ColorReset))
+		sb.WriteString(fmt.Sprintf("[%s]}[%s]", theme.Color(JSONPunctuation), tcell.ColorReset))
 
 	case []interface{}:
-		sb.WriteString(fmt.Sprintf("[%s][[%s]\n", GruvboxMaterial.Yellow, tcell.ColorReset))
+		sb.WriteString(fmt.Sprintf("[%s][[%s]\n", theme.Color(JSONPunctuation), tcell.ColorReset))
 		for i, elem := range val {
 			writeIndent(sb, indent+2)
 			ColorizeValue(elem, indent+2, sb, false)
@@ -96,22 +97,19 @@ func ColorizeValue(v any, indent int, sb *strings.Builder, isKey bool) {
 			sb.WriteRune('\n')
 		}
 		writeIndent(sb, indent)
-		sb.WriteString(fmt.Sprintf("[%s]][%s]", GruvboxMaterial.Yellow, tcell.ColorReset))
+		sb.WriteString(fmt.Sprintf("[%s]][%s]", theme.Color(JSONPunctuation), tcell.ColorReset))
 
 	case string:
-		sb.WriteString(fmt.Sprintf(`[%s]"%s"[%s]`, GruvboxMaterial.Green, val, tcell.ColorReset))
+		sb.WriteString(fmt.Sprintf(`[%s]"%s"[%s]`, theme.Color(JSONString), val, tcell.ColorReset))
 
 	case float64:
-		// JSON numbers get our orange color
-		sb.WriteString(fmt.Sprintf(`[%s]%v[%s]`, GruvboxMaterial.Orange, val, tcell.ColorReset))
+		sb.WriteString(fmt.Sprintf(`[%s]%v[%s]`, theme.Color(JSONNumber), val, tcell.ColorReset))
 
 	case bool:
-		// true / false in purple
-		sb.WriteString(fmt.Sprintf(`[%s]%t[%s]`, GruvboxMaterial.Purple, val, tcell.ColorReset))
+		sb.WriteString(fmt.Sprintf(`[%s]%t[%s]`, theme.Color(JSONBool), val, tcell.ColorReset))
 
 	case nil:
-		// null in red
-		sb.WriteString(fmt.Sprintf(`[%s]null[%s]`, GruvboxMaterial.Red, tcell.ColorReset))
+		sb.WriteString(fmt.Sprintf(`[%s]null[%s]`, theme.Color(JSONNull), tcell.ColorReset))
 
 	default:
 		// Fallback—just stringify
@@ -0,0 +1,429 @@
+package style
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/pelletier/go-toml/v2"
+	"github.com/rivo/tview"
+)
+
+// Role names what a color is for rather than what it looks like, so a theme can restyle the
+// whole UI. The zero Role is unset and stands for the terminal's default color.
+type Role int
+
+const (
+	Background      Role = iota + 1 // behind every pane
+	Text                            // ordinary text
+	Muted                           // placeholders, empty values and other secondary text
+	Border                          // pane borders
+	BorderFocus                     // the focused pane's border, modals and prompts
+	Title                           // pane titles and table headers
+	Label                           // input labels and key names
+	Selection                       // background of the selected row
+	SelectionText                   // text of the selected row
+	InputBackground                 // input fields
+	InputText                       // text typed into input fields
+	Highlight                       // background of search matches
+	HighlightText                   // text of search matches
+	Success                         // healthy indices and finished operations
+	Warning                         // things worth a second look
+	ProdWarning                     // production banners, borders and confirmations
+	StatusText                      // the status bar
+	StatusError                     // errors in the status bar and modals
+	JSONKey                         // object keys in documents
+	JSONString                      // string values
+	JSONNumber                      // number values
+	JSONBool                        // true and false
+	JSONNull                        // null
+	JSONPunctuation                 // braces and brackets
+
+	roleCount
+)
+
+var roleNames = [roleCount]string{
+	Background:      "background",
+	Text:            "text",
+	Muted:           "muted",
+	Border:          "border",
+	BorderFocus:     "border-focus",
+	Title:           "title",
+	Label:           "label",
+	Selection:       "selection",
+	SelectionText:   "selection-text",
+	InputBackground: "input-background",
+	InputText:       "input-text",
+	Highlight:       "highlight",
+	HighlightText:   "highlight-text",
+	Success:         "success",
+	Warning:         "warning",
+	ProdWarning:     "prod-warning",
+	StatusText:      "status-text",
+	StatusError:     "status-error",
+	JSONKey:         "json-key",
+	JSONString:      "json-string",
+	JSONNumber:      "json-number",
+	JSONBool:        "json-bool",
+	JSONNull:        "json-null",
+	JSONPunctuation: "json-punctuation",
+}
+
+// String is the role's name in theme files, such as "prod-warning".
+func (r Role) String() string {
+	if r <= 0 || r >= roleCount {
+		return fmt.Sprintf("Role(%d)", int(r))
+	}
+	return roleNames[r]
+}
+
+// Roles lists every role in a stable order.
+func Roles() []Role {
+	roles := make([]Role, 0, roleCount-1)
+	for r := Background; r < roleCount; r++ {
+		roles = append(roles, r)
+	}
+	return roles
+}
+
+// Theme gives every role a color.
+type Theme struct {
+	Name   string
+	colors [roleCount]tcell.Color
+}
+
+// Color is the color t gives role.
+func (t *Theme) Color(role Role) tcell.Color {
+	if role <= 0 || role >= roleCount {
+		return tcell.ColorDefault
+	}
+	return t.colors[role]
+}
+
+// Tag is role's color as a tview color tag, such as "[yellow]".
+func (t *Theme) Tag(role Role) string {
+	color := t.Color(role)
+	if color == tcell.ColorDefault {
+		return "[-]"
+	}
+	return "[" + color.String() + "]"
+}
+
+func newTheme(name string, colors map[Role]tcell.Color) *Theme {
+	t := &Theme{Name: name}
+	for role, color := range colors {
+		t.colors[role] = color
+	}
+	return t
+}
+
+// Themes that ship with cloudcutter. Gruvbox is the default.
+var (
+	Gruvbox = newTheme("gruvbox", map[Role]tcell.Color{
+		Background:      tcell.ColorBlack,
+		Text:            tcell.ColorBeige,
+		Muted:           tcell.ColorGray,
+		Border:          tcell.ColorBeige,
+		BorderFocus:     tcell.ColorMediumTurquoise,
+		Title:           GruvboxMaterial.Yellow,
+		Label:           tcell.ColorMediumTurquoise,
+		Selection:       tcell.ColorDarkCyan,
+		SelectionText:   tcell.ColorBeige,
+		InputBackground: tcell.ColorBlack,
+		InputText:       tcell.ColorBeige,
+		Highlight:       GruvboxMaterial.Yellow,
+		HighlightText:   GruvboxMaterial.Dark0,
+		Success:         GruvboxMaterial.Green,
+		Warning:         GruvboxMaterial.Yellow,
+		ProdWarning:     GruvboxMaterial.Red,
+		StatusText:      tcell.ColorMediumTurquoise,
+		StatusError:     GruvboxMaterial.Red,
+		JSONKey:         GruvboxMaterial.Blue,
+		JSONString:      GruvboxMaterial.Green,
+		JSONNumber:      GruvboxMaterial.Orange,
+		JSONBool:        GruvboxMaterial.Purple,
+		JSONNull:        GruvboxMaterial.Red,
+		JSONPunctuation: GruvboxMaterial.Yellow,
+	})
+
+	SolarizedLight = newTheme("solarized-light", map[Role]tcell.Color{
+		Background:      tcell.NewHexColor(0xfdf6e3), // base3
+		Text:            tcell.NewHexColor(0x586e75), // base01
+		Muted:           tcell.NewHexColor(0x93a1a1), // base1
+		Border:          tcell.NewHexColor(0x93a1a1),
+		BorderFocus:     tcell.NewHexColor(0x268bd2), // blue
+		Title:           tcell.NewHexColor(0xb58900), // yellow
+		Label:           tcell.NewHexColor(0x2aa198), // cyan
+		Selection:       tcell.NewHexColor(0x268bd2),
+		SelectionText:   tcell.NewHexColor(0xfdf6e3),
+		InputBackground: tcell.NewHexColor(0xeee8d5), // base2
+		InputText:       tcell.NewHexColor(0x073642), // base02
+		Highlight:       tcell.NewHexColor(0xb58900),
+		HighlightText:   tcell.NewHexColor(0xfdf6e3),
+		Success:         tcell.NewHexColor(0x859900), // green
+		Warning:         tcell.NewHexColor(0xcb4b16), // orange
+		ProdWarning:     tcell.NewHexColor(0xdc322f), // red
+		StatusText:      tcell.NewHexColor(0x2aa198),
+		StatusError:     tcell.NewHexColor(0xdc322f),
+		JSONKey:         tcell.NewHexColor(0x268bd2),
+		JSONString:      tcell.NewHexColor(0x859900),
+		JSONNumber:      tcell.NewHexColor(0xd33682), // magenta
+		JSONBool:        tcell.NewHexColor(0x6c71c4), // violet
+		JSONNull:        tcell.NewHexColor(0xdc322f),
+		JSONPunctuation: tcell.NewHexColor(0x657b83), // base00
+	})
+
+	HighContrast = newTheme("high-contrast", map[Role]tcell.Color{
+		Background:      tcell.ColorBlack,
+		Text:            tcell.ColorWhite,
+		Muted:           tcell.ColorSilver,
+		Border:          tcell.ColorWhite,
+		BorderFocus:     tcell.ColorYellow,
+		Title:           tcell.ColorYellow,
+		Label:           tcell.ColorAqua,
+		Selection:       tcell.ColorYellow,
+		SelectionText:   tcell.ColorBlack,
+		InputBackground: tcell.ColorNavy,
+		InputText:       tcell.ColorWhite,
+		Highlight:       tcell.ColorFuchsia,
+		HighlightText:   tcell.ColorBlack,
+		Success:         tcell.ColorLime,
+		Warning:         tcell.ColorYellow,
+		ProdWarning:     tcell.ColorRed,
+		StatusText:      tcell.ColorWhite,
+		StatusError:     tcell.ColorRed,
+		JSONKey:         tcell.ColorAqua,
+		JSONString:      tcell.ColorLime,
+		JSONNumber:      tcell.ColorYellow,
+		JSONBool:        tcell.ColorFuchsia,
+		JSONNull:        tcell.ColorRed,
+		JSONPunctuation: tcell.ColorWhite,
+	})
+
+	builtinThemes = []*Theme{Gruvbox, SolarizedLight, HighContrast}
+)
+
+// ThemeDir is where themes can be kept to be chosen by name, ~/.cloudcutter/themes.
+func ThemeDir() string {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(homeDir, ".cloudcutter", "themes")
+}
+
+// ThemeNames lists the built-in themes followed by those in ThemeDir.
+func ThemeNames() []string {
+	names := make([]string, 0, len(builtinThemes))
+	for _, t := range builtinThemes {
+		names = append(names, t.Name)
+	}
+
+	var custom []string
+	entries, _ := os.ReadDir(ThemeDir())
+	for _, entry := range entries {
+		ext := filepath.Ext(entry.Name())
+		name := strings.TrimSuffix(entry.Name(), ext)
+		if entry.IsDir() || !isThemeFile(ext) || slices.Contains(names, name) || slices.Contains(custom, name) {
+			continue
+		}
+		custom = append(custom, name)
+	}
+	slices.Sort(custom)
+	return append(names, custom...)
+}
+
+// LoadTheme finds a theme by name or path: a built-in theme, a .toml or .json file in ThemeDir
+// named after it, or the .toml or .json file at that path.
+func LoadTheme(nameOrPath string) (*Theme, error) {
+	if nameOrPath == "" {
+		return Gruvbox, nil
+	}
+	if t := builtinTheme(nameOrPath); t != nil {
+		return t, nil
+	}
+	if isThemeFile(filepath.Ext(nameOrPath)) {
+		return LoadThemeFile(nameOrPath)
+	}
+	if strings.ContainsRune(nameOrPath, filepath.Separator) {
+		return nil, fmt.Errorf("theme file %s must end in .toml or .json", nameOrPath)
+	}
+	for _, ext := range []string{".toml", ".json"} {
+		path := filepath.Join(ThemeDir(), nameOrPath+ext)
+		if _, err := os.Stat(path); err == nil {
+			return LoadThemeFile(path)
+		}
+	}
+	return nil, fmt.Errorf("unknown theme %q; choose one of %s", nameOrPath, strings.Join(ThemeNames(), ", "))
+}
+
+// themeFile is the contents of a custom theme. Roles it leaves out keep the colors of its base,
+// gruvbox unless it names another built-in theme.
+type themeFile struct {
+	Name   string            `json:"name" toml:"name"`
+	Base   string            `json:"base" toml:"base"`
+	Colors map[string]string `json:"colors" toml:"colors"`
+}
+
+// LoadThemeFile reads a custom theme from a .toml or .json file. Colors are W3C names such as
+// "teal", #rrggbb values or "default" for the terminal's own color.
+func LoadThemeFile(path string) (*Theme, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var file themeFile
+	switch ext := filepath.Ext(path); ext {
+	case ".toml":
+		dec := toml.NewDecoder(bytes.NewReader(data))
+		dec.DisallowUnknownFields()
+		err = dec.Decode(&file)
+	case ".json":
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.DisallowUnknownFields()
+		err = dec.Decode(&file)
+	default:
+		return nil, fmt.Errorf("theme file %s must end in .toml or .json", path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid theme %s: %w", path, err)
+	}
+
+	t, err := file.theme()
+	if err != nil {
+		return nil, fmt.Errorf("invalid theme %s: %w", path, err)
+	}
+	if t.Name == "" {
+		t.Name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+	return t, nil
+}
+
+func (f themeFile) theme() (*Theme, error) {
+	base := Gruvbox
+	if f.Base != "" {
+		if base = builtinTheme(f.Base); base == nil {
+			return nil, fmt.Errorf("base %q is not a built-in theme", f.Base)
+		}
+	}
+
+	t := *base
+	t.Name = f.Name
+	var errs []error
+	for name, value := range f.Colors {
+		role := slices.Index(roleNames[:], name)
+		if role <= 0 {
+			errs = append(errs, fmt.Errorf("colors.%s: unknown role", name))
+			continue
+		}
+		color, err := parseColor(value)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("colors.%s: %w", name, err))
+			continue
+		}
+		t.colors[role] = color
+	}
+	if len(errs) > 0 {
+		slices.SortFunc(errs, func(a, b error) int { return strings.Compare(a.Error(), b.Error()) })
+		return nil, errors.Join(errs...)
+	}
+	return &t, nil
+}
+
+func parseColor(value string) (tcell.Color, error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if value == "default" {
+		return tcell.ColorDefault, nil
+	}
+	if color := tcell.GetColor(value); color != tcell.ColorDefault {
+		return color, nil
+	}
+	return tcell.ColorDefault, fmt.Errorf("unknown color %q", value)
+}
+
+func builtinTheme(name string) *Theme {
+	for _, t := range builtinThemes {
+		if t.Name == name {
+			return t
+		}
+	}
+	return nil
+}
+
+func isThemeFile(ext string) bool {
+	return ext == ".toml" || ext == ".json"
+}
+
+var (
+	themeMu   sync.RWMutex
+	current   = Gruvbox
+	listeners []*func(*Theme)
+)
+
+// Use makes t the theme for every primitive created from now on and tells the OnChange
+// listeners, which restyle what already exists. Nil restores the default theme.
+func Use(t *Theme) {
+	if t == nil {
+		t = Gruvbox
+	}
+
+	themeMu.Lock()
+	current = t
+	tview.Styles.PrimitiveBackgroundColor = t.Color(Background)
+	tview.Styles.ContrastBackgroundColor = t.Color(InputBackground)
+	tview.Styles.MoreContrastBackgroundColor = t.Color(Selection)
+	tview.Styles.BorderColor = t.Color(Border)
+	tview.Styles.TitleColor = t.Color(Title)
+	tview.Styles.GraphicsColor = t.Color(Border)
+	tview.Styles.PrimaryTextColor = t.Color(Text)
+	tview.Styles.SecondaryTextColor = t.Color(Label)
+	tview.Styles.TertiaryTextColor = t.Color(Muted)
+	tview.Styles.InverseTextColor = t.Color(SelectionText)
+	tview.Styles.ContrastSecondaryTextColor = t.Color(Muted)
+	notify := slices.Clone(listeners)
+	themeMu.Unlock()
+
+	for _, fn := range notify {
+		(*fn)(t)
+	}
+}
+
+// Current is the theme in use.
+func Current() *Theme {
+	themeMu.RLock()
+	defer themeMu.RUnlock()
+	return current
+}
+
+// Color is the color the current theme gives role.
+func Color(role Role) tcell.Color {
+	return Current().Color(role)
+}
+
+// Tag is role's color in the current theme as a tview color tag.
+func Tag(role Role) string {
+	return Current().Tag(role)
+}
+
+// OnChange calls fn with every theme Use switches to, on the goroutine that called Use, until
+// the returned function is called.
+func OnChange(fn func(*Theme)) (cancel func()) {
+	listener := &fn
+	themeMu.Lock()
+	listeners = append(listeners, listener)
+	themeMu.Unlock()
+
+	return func() {
+		themeMu.Lock()
+		defer themeMu.Unlock()
+		listeners = slices.DeleteFunc(listeners, func(l *func(*Theme)) bool { return l == listener })
+	}
+}
@@ -0,0 +1,130 @@
+package style
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func writeTheme(t *testing.T, dir, name, contents string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(contents), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestBuiltinThemesColorEveryRole(t *testing.T) {
+	for _, theme := range builtinThemes {
+		for _, role := range Roles() {
+			assert.NotEqual(t, tcell.ColorDefault, theme.Color(role), "%s leaves %s unset", theme.Name, role)
+		}
+	}
+}
+
+func TestLoadThemeFile(t *testing.T) {
+	dir := t.TempDir()
+
+	theme, err := LoadThemeFile(writeTheme(t, dir, "dusk.toml", `
+base = "high-contrast"
+
+[colors]
+border = "#665c54"
+prod-warning = "Orange"
+json-null = "default"
+`))
+	if assert.NoError(t, err) {
+		assert.Equal(t, "dusk", theme.Name, "the file name stands in for a missing name")
+		assert.Equal(t, tcell.NewHexColor(0x665c54), theme.Color(Border))
+		assert.Equal(t, tcell.ColorOrange, theme.Color(ProdWarning))
+		assert.Equal(t, tcell.ColorDefault, theme.Color(JSONNull))
+		assert.Equal(t, "[-]", theme.Tag(JSONNull))
+		assert.Equal(t, HighContrast.Color(Title), theme.Color(Title), "other roles keep the base colors")
+	}
+
+	theme, err = LoadThemeFile(writeTheme(t, dir, "paper.json", `{"name": "Paper", "colors": {"title": "navy"}}`))
+	if assert.NoError(t, err) {
+		assert.Equal(t, "Paper", theme.Name)
+		assert.Equal(t, "[navy]", theme.Tag(Title))
+		assert.Equal(t, Gruvbox.Color(Text), theme.Color(Text), "themes build on gruvbox by default")
+	}
+
+	tests := []struct {
+		name     string
+		contents string
+		expected string
+	}{
+		{name: "bad.toml", contents: "[colors]\nborderz = \"red\"\n", expected: "colors.borderz: unknown role"},
+		{name: "bad.json", contents: `{"colors": {"text": "reddish"}}`, expected: `colors.text: unknown color "reddish"`},
+		{name: "base.toml", contents: "base = \"dracula\"\n", expected: `base "dracula" is not a built-in theme`},
+		{name: "field.json", contents: `{"colours": {}}`, expected: `unknown field "colours"`},
+		{name: "theme.yaml", contents: "name: x\n", expected: "must end in .toml or .json"},
+	}
+	for _, tt := range tests {
+		_, err := LoadThemeFile(writeTheme(t, dir, tt.name, tt.contents))
+		if assert.Error(t, err, tt.name) {
+			assert.Contains(t, err.Error(), tt.expected, tt.name)
+		}
+	}
+}
+
+func TestLoadTheme(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	dir := filepath.Join(home, ".cloudcutter", "themes")
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		t.Fatal(err)
+	}
+	writeTheme(t, dir, "dusk.toml", "[colors]\ntitle = \"teal\"\n")
+	writeTheme(t, dir, "notes.txt", "not a theme")
+
+	assert.Equal(t, []string{"gruvbox", "solarized-light", "high-contrast", "dusk"}, ThemeNames())
+
+	theme, err := LoadTheme("")
+	assert.NoError(t, err)
+	assert.Same(t, Gruvbox, theme)
+
+	theme, err = LoadTheme("solarized-light")
+	assert.NoError(t, err)
+	assert.Same(t, SolarizedLight, theme)
+
+	theme, err = LoadTheme("dusk")
+	if assert.NoError(t, err) {
+		assert.Equal(t, tcell.ColorTeal, theme.Color(Title))
+	}
+
+	theme, err = LoadTheme(filepath.Join(dir, "dusk.toml"))
+	if assert.NoError(t, err) {
+		assert.Equal(t, "dusk", theme.Name)
+	}
+
+	_, err = LoadTheme("neon")
+	assert.EqualError(t, err, `unknown theme "neon"; choose one of gruvbox, solarized-light, high-contrast, dusk`)
+}
+
+func TestUse(t *testing.T) {
+	t.Cleanup(func() { Use(nil) })
+
+	var seen []string
+	stop := OnChange(func(theme *Theme) { seen = append(seen, theme.Name) })
+
+	Use(SolarizedLight)
+	assert.Same(t, SolarizedLight, Current())
+	assert.Equal(t, SolarizedLight.Color(Background), Color(Background))
+
+	stop()
+	Use(nil)
+	assert.Same(t, Gruvbox, Current(), "nil restores the default")
+	assert.Equal(t, []string{"solarized-light"}, seen, "listeners stop hearing once cancelled")
+}
+
+func TestColorizeJSONFollowsTheme(t *testing.T) {
+	t.Cleanup(func() { Use(nil) })
+
+	Use(HighContrast)
+	assert.Equal(t, `[lime]"up"[reset]`, ColorizeJSON(`"up"`))
+}
@@ -0,0 +1,125 @@
+package uitest
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	dynamodbtypes "github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/rivo/tview"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tpelletiersophos/cloudcutter/internal/services/elastic"
+	"github.com/tpelletiersophos/cloudcutter/internal/ui/manager"
+)
+
+var tables = map[string]Table{
+	"orders": {Key: []string{"id"}, Items: []map[string]dynamodbtypes.AttributeValue{
+		{"id": S("o-1"), "total": N("12")},
+		{"id": S("o-2"), "total": N("30")},
+	}},
+	"users": {Key: []string{"email"}, Items: []map[string]dynamodbtypes.AttributeValue{
+		{"email": S("ann@example.com"), "name": S("Ann")},
+	}},
+}
+
+// indices hold documents of today, which the Elastic view searches by default.
+var indices = map[string][]map[string]any{
+	"main-summary-2024.01": {
+		{"level": "info", "message": "started", "service": "api", "unixTime": time.Now().Unix()},
+		{"level": "error", "message": "connection refused", "service": "worker", "unixTime": time.Now().Unix()},
+	},
+}
+
+func TestE2EDynamoDB(t *testing.T) {
+	h := New(t, Options{Width: 120, Height: 30, Tables: tables})
+
+	h.Open(manager.ViewDynamoDB)
+	h.WaitForText("users")
+	h.AssertGolden("dynamodb_tables")
+
+	h.Keys("down enter")
+	h.WaitForText("ann@example.com")
+	h.AssertGolden("dynamodb_items")
+}
+
+func TestE2EElastic(t *testing.T) {
+	h := New(t, Options{Width: 120, Height: 40, Indices: indices})
+
+	h.Open(manager.ViewElastic)
+	h.WaitForText("main-summary-2024.01-2")
+
+	// Select the message field from the field list; the results gain its column. Wait for the list
+	// to hold every field and take focus first, as moving through it before would go astray
+	fields := func() tview.Primitive { return h.Manager.GetPrimitiveByID("fieldList") }
+	h.WaitFor("the field list", func() bool {
+		return fields().(*tview.List).GetItemCount() == 7
+	})
+	h.Keys("ctrl+a")
+	h.WaitFor("the field list to take focus", func() bool {
+		return h.App.GetFocus() == fields()
+	})
+	h.Keys("j j j j space")
+	h.WaitForText("connection refused")
+	h.Keys("ctrl+r")
+	h.AssertGolden("elastic_message_column")
+}
+
+func TestE2EKeysReachEventManager(t *testing.T) {
+	h := New(t, Options{Width: 120, Height: 30, Tables: tables})
+	h.Open(manager.ViewDynamoDB)
+	h.WaitForText("users")
+
+	h.Keys("down up down")
+	// The first enter takes the completion, the second runs the command
+	h.Keys(":")
+	h.Type("diagnostics")
+	h.Keys("enter enter")
+	h.WaitForText("Diagnostics")
+
+	screen := h.Snapshot()
+	assert.Contains(t, screen, "Total Events: 3")
+	assert.Contains(t, screen, "Most pressed: Down ×2, Up ×1")
+}
+
+func TestE2ERetryRegionSwitch(t *testing.T) {
+	// Clients made for a region from now on reach a port that refuses connections, so switching
+	// region fails, even back to local
+	require.NoError(t, elastic.UseClusterConfig(elastic.ClusterConfig{
+		Endpoint:      "http://127.0.0.1:1/{region}",
+		LocalEndpoint: "http://127.0.0.1:1",
+	}))
+	t.Cleanup(func() { elastic.UseClusterConfig(elastic.DefaultClusterConfig()) })
+
+	h := New(t, Options{Width: 120, Height: 40, Indices: indices})
+	h.Open(manager.ViewElastic)
+	h.WaitForText("main-summary-2024.01-2")
+
+	failures := func() int {
+		n := 0
+		for _, notification := range h.Manager.Notifications().History() {
+			if strings.HasPrefix(notification.Message, "Error switching region") {
+				n++
+			}
+		}
+		return n
+	}
+	h.Keys(":")
+	h.Type("region local")
+	h.Keys("enter enter")
+	h.WaitFor("the region switch to fail", func() bool { return failures() == 1 })
+
+	// Retry from the history while the Elastic view stays active
+	h.Keys("alt+n")
+	h.Do(func() {
+		table := h.App.GetFocus().(*tview.Table)
+		for row := 1; row < table.GetRowCount(); row++ {
+			if strings.HasPrefix(table.GetCell(row, 3).Text, "Error switching region") {
+				table.Select(row, 0)
+			}
+		}
+	})
+	h.Keys("enter")
+	h.WaitFor("the retry to fail again", func() bool { return failures() == 2 })
+}
@@ -0,0 +1,61 @@
+package uitest
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsdynamodb "github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	dynamodbtypes "github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/tpelletiersophos/cloudcutter/internal/services/aws/dynamodb/ddbfake"
+)
+
+// Table is a DynamoDB table served by the fake: its key attributes, hash key first, and items.
+type Table struct {
+	Key   []string
+	Items []map[string]dynamodbtypes.AttributeValue
+}
+
+// loadTables creates tables in the fake DynamoDB and puts their items. A key attribute has the
+// type it has in the items, or is a string when no item has it.
+func loadTables(client *ddbfake.Client, tables map[string]Table) error {
+	ctx := context.Background()
+	for name, table := range tables {
+		input := &awsdynamodb.CreateTableInput{TableName: aws.String(name)}
+		for i, attr := range table.Key {
+			keyType := dynamodbtypes.KeyTypeHash
+			if i > 0 {
+				keyType = dynamodbtypes.KeyTypeRange
+			}
+			attrType := dynamodbtypes.ScalarAttributeTypeS
+			for _, item := range table.Items {
+				switch item[attr].(type) {
+				case *dynamodbtypes.AttributeValueMemberN:
+					attrType = dynamodbtypes.ScalarAttributeTypeN
+				case *dynamodbtypes.AttributeValueMemberB:
+					attrType = dynamodbtypes.ScalarAttributeTypeB
+				}
+			}
+			input.KeySchema = append(input.KeySchema, dynamodbtypes.KeySchemaElement{AttributeName: aws.String(attr), KeyType: keyType})
+			input.AttributeDefinitions = append(input.AttributeDefinitions, dynamodbtypes.AttributeDefinition{AttributeName: aws.String(attr), AttributeType: attrType})
+		}
+		if _, err := client.CreateTable(ctx, input); err != nil {
+			return fmt.Errorf("table %s: %w", name, err)
+		}
+		for _, item := range table.Items {
+			if _, err := client.PutItem(ctx, &awsdynamodb.PutItemInput{TableName: aws.String(name), Item: item}); err != nil {
+				return fmt.Errorf("table %s: %w", name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// S and N make string and number attribute values for Table items.
+func S(value string) dynamodbtypes.AttributeValue {
+	return &dynamodbtypes.AttributeValueMemberS{Value: value}
+}
+
+func N(value string) dynamodbtypes.AttributeValue {
+	return &dynamodbtypes.AttributeValueMemberN{Value: value}
+}
@@ -0,0 +1,298 @@
+// Package uitest runs the real UI headless for end-to-end tests: a Manager with every view, on a
+// tcell simulation screen, against fake DynamoDB and Elasticsearch services. Tests drive it with
+// the key specs of config.yaml and compare what the screen shows with golden files.
+package uitest
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/gdamore/tcell/v2"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tpelletiersophos/cloudcutter/internal/logger"
+	"github.com/tpelletiersophos/cloudcutter/internal/services/aws/dynamodb"
+	"github.com/tpelletiersophos/cloudcutter/internal/services/aws/dynamodb/ddbfake"
+	"github.com/tpelletiersophos/cloudcutter/internal/services/elastic"
+	"github.com/tpelletiersophos/cloudcutter/internal/services/elastic/esfake"
+	"github.com/tpelletiersophos/cloudcutter/internal/ui"
+	"github.com/tpelletiersophos/cloudcutter/internal/ui/common"
+	"github.com/tpelletiersophos/cloudcutter/internal/ui/manager"
+	"github.com/tpelletiersophos/cloudcutter/internal/ui/views"
+	ddbv "github.com/tpelletiersophos/cloudcutter/internal/ui/views/dynamodb"
+	elasticView "github.com/tpelletiersophos/cloudcutter/internal/ui/views/elastic"
+	logsView "github.com/tpelletiersophos/cloudcutter/internal/ui/views/logs"
+)
+
+// update rewrites golden files with what the screen shows instead of comparing them:
+// go test ./... -run TestE2E -update
+var update = flag.Bool("update", false, "rewrite the golden files of UI tests")
+
+// Timeout bounds how long WaitFor waits for background work, such as a view loading.
+const Timeout = 5 * time.Second
+
+// loadingPage is the page the manager and the views show a spinner on while they load.
+const loadingPage = "loading"
+
+// Options sets up a harness. The zero value runs a 160x48 screen with no tables and no indices.
+type Options struct {
+	Width, Height int
+	// Tables are served by the fake DynamoDB, by name.
+	Tables map[string]Table
+	// Indices are served by the fake Elasticsearch, by name: the _source of each document. The
+	// documents of index i are i-1, i-2 and so on.
+	Indices map[string][]map[string]any
+}
+
+// Harness is a running UI. Its methods must be called from the test's goroutine.
+type Harness struct {
+	t       testing.TB
+	App     *ui.App
+	Manager *manager.Manager
+	Screen  tcell.SimulationScreen
+
+	marker *tcell.EventKey // queued after keys; seeing it means they were handled
+	synced chan struct{}
+}
+
+// New starts the UI for t and stops it when t ends.
+func New(t testing.TB, opts Options) *Harness {
+	t.Helper()
+	if opts.Width == 0 {
+		opts.Width = 160
+	}
+	if opts.Height == 0 {
+		opts.Height = 48
+	}
+	t.Setenv("HOME", t.TempDir())
+	common.UseKeymap(nil)
+
+	log, err := logger.New(logger.Config{LogDir: t.TempDir(), Prefix: "cloudcutter", Level: logger.DEBUG})
+	require.NoError(t, err)
+
+	screen := tcell.NewSimulationScreen("UTF-8")
+	app := ui.NewApp()
+	app.SetScreen(screen)
+	screen.SetSize(opts.Width, opts.Height)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	vm := manager.NewViewManager(ctx, app, aws.Config{Region: "local"}, log)
+
+	cluster := esfake.NewServer()
+	t.Cleanup(cluster.Close)
+	for name, docs := range opts.Indices {
+		for i, doc := range docs {
+			require.NoError(t, cluster.Index(name, fmt.Sprintf("%s-%d", name, i+1), doc))
+		}
+	}
+	client, err := cluster.Client()
+	require.NoError(t, err)
+	tables := ddbfake.New()
+	require.NoError(t, loadTables(tables, opts.Tables))
+	services := vm.Services()
+	services.DynamoDB = dynamodb.NewServiceWithClient(tables)
+	services.Elastic = elastic.NewServiceWithClient(client, log)
+	registerViews(vm, log)
+
+	h := &Harness{
+		t:       t,
+		App:     app,
+		Manager: vm,
+		Screen:  screen,
+		marker:  tcell.NewEventKey(tcell.KeyF64, 0, tcell.ModNone),
+		synced:  make(chan struct{}, 1),
+	}
+
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		if err := vm.Run(); err != nil {
+			t.Errorf("UI stopped: %v", err)
+		}
+	}()
+	t.Cleanup(func() {
+		app.Stop()
+		<-stopped
+		cancel()
+		log.Close()
+	})
+
+	// Run installs the manager's input capture; watch for the marker in front of it
+	h.Do(func() {
+		capture := app.GetInputCapture()
+		app.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+			if event == h.marker {
+				h.synced <- struct{}{}
+				return nil
+			}
+			return capture(event)
+		})
+	})
+	return h
+}
+
+// registerViews registers the views as the app does, each building on the tab's services. The
+// fakes need no loading, so each view is only built, on the event loop.
+func registerViews(vm *manager.Manager, log *logger.Logger) {
+	vm.RegisterLazyView(manager.ViewDynamoDB, nil, func() (views.View, error) {
+		return ddbv.NewView(vm, vm.Services().DynamoDB), nil
+	})
+	vm.RegisterLazyView(manager.ViewElastic, nil, func() (views.View, error) {
+		return elasticView.NewView(vm, vm.Services().Elastic, "")
+	})
+	vm.RegisterLazyView(manager.ViewLogs, nil, func() (views.View, error) {
+		return logsView.NewView(vm, log.Dir()), nil
+	})
+}
+
+// Do runs f on the UI goroutine, redraws, and waits for both.
+func (h *Harness) Do(f func()) {
+	h.t.Helper()
+	done := make(chan struct{})
+	h.App.QueueUpdateDraw(func() {
+		f()
+		close(done)
+	})
+	h.wait(done, "the UI to run an update")
+	// The draw follows the update
+	h.flush()
+}
+
+func (h *Harness) flush() {
+	done := make(chan struct{})
+	h.App.QueueUpdate(func() { close(done) })
+	h.wait(done, "the UI to draw")
+}
+
+func (h *Harness) wait(done <-chan struct{}, what string) {
+	h.t.Helper()
+	select {
+	case <-done:
+	case <-time.After(Timeout):
+		h.t.Fatalf("timed out waiting for %s", what)
+	}
+}
+
+// Open switches to the named view, as its command does, and waits until it shows.
+func (h *Harness) Open(view string) {
+	h.t.Helper()
+	h.Do(func() {
+		require.NoError(h.t, h.Manager.SwitchToView(view))
+	})
+	h.WaitFor("the "+view+" view", func() bool {
+		front, _ := h.Manager.Pages().GetFrontPage()
+		return front == view
+	})
+}
+
+// Keys presses the keys of script in turn, written as in config.yaml: keys separated by spaces,
+// such as "ctrl+r j j enter" or "g g". It returns once the UI has handled them and redrawn.
+func (h *Harness) Keys(script string) {
+	h.t.Helper()
+	seq, err := common.ParseKeySequence(script)
+	require.NoError(h.t, err, "key script %q", script)
+	for _, key := range seq {
+		h.press(tcell.NewEventKey(key.Key, key.Rune, key.Mod))
+	}
+}
+
+// Type types text, one rune at a time.
+func (h *Harness) Type(text string) {
+	h.t.Helper()
+	for _, r := range text {
+		h.press(tcell.NewEventKey(tcell.KeyRune, r, tcell.ModNone))
+	}
+}
+
+// press goes through the app's event loop, like a key typed in a terminal: the manager's input
+// capture, the event manager and the view's handlers, then the focused primitive.
+func (h *Harness) press(event *tcell.EventKey) {
+	h.t.Helper()
+	h.App.QueueEvent(event)
+	h.App.QueueEvent(h.marker)
+	h.wait(h.synced, "a key to be handled")
+	h.flush()
+}
+
+// WaitFor waits until cond, checked on the UI goroutine, holds, for work the UI finishes in the
+// background. It fails the test with what after Timeout.
+func (h *Harness) WaitFor(what string, cond func() bool) {
+	h.t.Helper()
+	deadline := time.Now().Add(Timeout)
+	for {
+		var ok bool
+		h.Do(func() { ok = cond() })
+		if ok {
+			return
+		}
+		if time.Now().After(deadline) {
+			h.t.Fatalf("timed out waiting for %s; the screen shows:\n%s", what, h.Snapshot())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// WaitForText waits until the screen shows text and has settled: nothing is loading and the screen
+// did not change since the last check, as views restore focus in updates of their own once they
+// have loaded.
+func (h *Harness) WaitForText(text string) {
+	h.t.Helper()
+	var last string
+	h.WaitFor(strings.TrimSpace(text), func() bool {
+		screen := h.snapshot()
+		settled := screen == last && !h.Manager.Pages().HasPage(loadingPage)
+		last = screen
+		return settled && strings.Contains(screen, text)
+	})
+}
+
+// Snapshot is the text on the screen, a line per row without trailing spaces.
+func (h *Harness) Snapshot() string {
+	var screen string
+	h.Do(func() { screen = h.snapshot() })
+	return screen
+}
+
+// snapshot reads the screen on the UI goroutine, which draws it.
+func (h *Harness) snapshot() string {
+	cells, width, height := h.Screen.GetContents()
+	var b strings.Builder
+	for y := 0; y < height; y++ {
+		var line strings.Builder
+		for x := 0; x < width; x++ {
+			cell := cells[y*width+x]
+			if len(cell.Runes) == 0 {
+				line.WriteByte(' ')
+				continue
+			}
+			line.WriteString(string(cell.Runes))
+		}
+		b.WriteString(strings.TrimRight(line.String(), " "))
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+// AssertGolden compares the screen with testdata/<name>.golden, or rewrites the file with -update.
+func (h *Harness) AssertGolden(name string) {
+	h.t.Helper()
+	path := filepath.Join("testdata", name+".golden")
+	got := h.Snapshot()
+	if *update {
+		require.NoError(h.t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(h.t, os.WriteFile(path, []byte(got), 0o644))
+		return
+	}
+	want, err := os.ReadFile(path)
+	require.NoError(h.t, err, "no golden file; run the test with -update to write it")
+	if string(want) != got {
+		h.t.Errorf("the screen differs from %s (run with -update to accept it)\n--- want\n%s--- got\n%s", path, want, got)
+	}
+}
@@ -0,0 +1,58 @@
+package views
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/rivo/tview"
+)
+
+// ErrUsage is returned by a command run with the wrong arguments; the prompt then shows its usage.
+var ErrUsage = errors.New("wrong arguments")
+
+// Command is a command the `:` prompt runs, such as `:region eu-west-1` or `:tab new`.
+type Command struct {
+	Name        string
+	Args        string // argument synopsis shown in usage, e.g. "[name]" or "<pattern>"
+	Description string
+	Subcommands []*Command // chosen by the first argument; a command with subcommands has no Run
+
+	// Run executes the command with its arguments. It may return a primitive to focus, such as a
+	// selector it opened. Run is called on the event loop, so slow work belongs in a goroutine.
+	Run func(args []string) (tview.Primitive, error)
+
+	// Complete returns the candidates for the last of args, which is the argument being typed.
+	// The prompt keeps those that start with it. Nil means nothing to complete.
+	Complete func(args []string) []string
+}
+
+// Usage is the command's synopsis, e.g. "region [name]" or "tab new|close|next|prev".
+func (c *Command) Usage() string {
+	synopsis := []string{c.Name}
+	if len(c.Subcommands) > 0 {
+		names := make([]string, len(c.Subcommands))
+		for i, sub := range c.Subcommands {
+			names[i] = sub.Name
+		}
+		synopsis = append(synopsis, strings.Join(names, "|"))
+	}
+	if c.Args != "" {
+		synopsis = append(synopsis, c.Args)
+	}
+	return strings.Join(synopsis, " ")
+}
+
+// Subcommand returns the subcommand called name, or nil.
+func (c *Command) Subcommand(name string) *Command {
+	for _, sub := range c.Subcommands {
+		if sub.Name == name {
+			return sub
+		}
+	}
+	return nil
+}
+
+// Commander is implemented by views that add commands to the prompt while they are active.
+type Commander interface {
+	Commands() []*Command
+}
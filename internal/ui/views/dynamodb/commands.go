@@ -0,0 +1,48 @@
+package dynamodb
+
+import (
+	"sort"
+
+	"github.com/rivo/tview"
+	"github.com/tpelletiersophos/cloudcutter/internal/deeplink"
+	"github.com/tpelletiersophos/cloudcutter/internal/ui/views"
+)
+
+var _ views.Commander = (*View)(nil)
+
+// Commands adds :table to the prompt while the view is active.
+func (v *View) Commands() []*views.Command {
+	return []*views.Command{
+		{
+			Name:        "table",
+			Args:        "<name>",
+			Description: "Show a table's items",
+			Run: func(args []string) (tview.Primitive, error) {
+				if len(args) != 1 {
+					return nil, views.ErrUsage
+				}
+				v.ApplyLink(deeplink.Link{Table: args[0]})
+				return nil, nil
+			},
+			Complete: func(args []string) []string {
+				if len(args) != 1 {
+					return nil
+				}
+				return v.tableNames()
+			},
+		},
+	}
+}
+
+// tableNames lists the tables described so far, whatever the table list's filter hides.
+func (v *View) tableNames() []string {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	names := make([]string, 0, len(v.state.tableCache))
+	for name := range v.state.tableCache {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
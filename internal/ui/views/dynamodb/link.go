@@ -0,0 +1,34 @@
+package dynamodb
+
+import (
+	"fmt"
+
+	"github.com/tpelletiersophos/cloudcutter/internal/deeplink"
+	"github.com/tpelletiersophos/cloudcutter/internal/ui/views"
+)
+
+var _ views.Linker = (*View)(nil)
+
+// ApplyLink selects the table a link names and shows its items.
+func (v *View) ApplyLink(link deeplink.Link) {
+	if link.Table == "" {
+		return
+	}
+
+	matches := v.leftPanel.FindItems(link.Table, "", false, false)
+	for _, index := range matches {
+		if main, _ := v.leftPanel.GetItemText(index); main == link.Table {
+			v.leftPanel.SetCurrentItem(index)
+			v.showTableItems(link.Table)
+			return
+		}
+	}
+	v.manager.NotifyWarning(v.Name(), fmt.Sprintf("Table %s not found", link.Table))
+}
+
+// FillLink records the selected table in link.
+func (v *View) FillLink(link *deeplink.Link) {
+	if v.leftPanel.GetItemCount() > 0 {
+		link.Table, _ = v.leftPanel.GetItemText(v.leftPanel.GetCurrentItem())
+	}
+}
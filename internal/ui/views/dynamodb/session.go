@@ -0,0 +1,29 @@
+package dynamodb
+
+import (
+	"github.com/tpelletiersophos/cloudcutter/internal/deeplink"
+	"github.com/tpelletiersophos/cloudcutter/internal/session"
+	"github.com/tpelletiersophos/cloudcutter/internal/ui/views"
+)
+
+var _ views.Persistent = (*View)(nil)
+
+// SaveState records the selected table and the layout in state.
+func (v *View) SaveState(state *session.State) {
+	link := deeplink.Link{}
+	v.FillLink(&link)
+	state.DynamoDB = &session.DynamoDBState{
+		Table:      link.Table,
+		RowNumbers: v.state.showRowNumbers,
+	}
+}
+
+// RestoreState reopens the saved table; one that no longer exists is reported, not opened.
+func (v *View) RestoreState(state session.State) {
+	saved := state.DynamoDB
+	if saved == nil {
+		return
+	}
+	v.state.showRowNumbers = saved.RowNumbers
+	v.ApplyLink(deeplink.Link{Table: saved.Table})
+}
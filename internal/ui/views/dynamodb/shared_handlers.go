@@ -17,28 +17,16 @@ func NewDynamoDBDataTableHandler(view common.ViewInterface) *DynamoDBDataTableHa
 	}
 }
 
-func (h *DynamoDBDataTableHandler) CanHandle(event *tcell.EventKey, component tview.Primitive) bool {
-	switch event.Key() {
-	case tcell.KeyEnter, tcell.KeyEsc:
-		return true
-	case tcell.KeyRune:
-		switch event.Rune() {
-		case 'r', 'n', 'p', '/':
-			return true
-		}
-	}
-	return false
-}
-
 func (h *DynamoDBDataTableHandler) HandleEvent(event *tcell.EventKey, ctx *common.HandlerContext) *tcell.EventKey {
 	view := ctx.View.(*View)
 
-	switch event.Key() {
-	case tcell.KeyEsc:
+	if event.Key() == tcell.KeyEsc {
 		view.manager.SetFocus(view.leftPanel)
 		return nil
+	}
 
-	case tcell.KeyEnter:
+	switch ctx.Action {
+	case "open_item":
 		row, _ := view.dataTable.GetSelection()
 		if row > 0 {
 			start := (view.state.currentPage - 1) * view.state.pageSize
@@ -47,26 +35,26 @@ func (h *DynamoDBDataTableHandler) HandleEvent(event *tcell.EventKey, ctx *commo
 				view.showItemDetails(view.state.filteredItems[itemIndex])
 			}
 		}
-		return nil
-
-	case tcell.KeyRune:
-		switch event.Rune() {
-		case 'r':
-			view.toggleRowNumbers()
-			return nil
-		case 'n':
-			view.nextPage()
-			return nil
-		case 'p':
-			view.previousPage()
-			return nil
-		case '/':
-			view.showFilterPrompt(view.dataTable)
-			return nil
+	case "first_row":
+		if view.dataTable.GetRowCount() > 1 {
+			view.dataTable.Select(1, 0).ScrollToBeginning()
+		}
+	case "last_row":
+		if rows := view.dataTable.GetRowCount(); rows > 1 {
+			view.dataTable.Select(rows-1, 0).ScrollToEnd()
 		}
+	case "toggle_row_numbers":
+		view.toggleRowNumbers()
+	case "next_page":
+		view.nextPage()
+	case "previous_page":
+		view.previousPage()
+	case "filter":
+		view.showFilterPrompt(view.dataTable)
+	default:
+		return event
 	}
-
-	return event
+	return nil
 }
 
 type DynamoDBLeftPanelHandler struct {
@@ -79,45 +67,25 @@ func NewDynamoDBLeftPanelHandler(view common.ViewInterface) *DynamoDBLeftPanelHa
 	}
 }
 
-func (h *DynamoDBLeftPanelHandler) CanHandle(event *tcell.EventKey, component tview.Primitive) bool {
-	switch event.Key() {
-	case tcell.KeyEnter:
-		return true
-	case tcell.KeyRune:
-		if event.Rune() == '/' {
-			return true
-		}
-	case tcell.KeyBackspace, tcell.KeyBackspace2:
-		return true
-	}
-	return false
-}
-
 func (h *DynamoDBLeftPanelHandler) HandleEvent(event *tcell.EventKey, ctx *common.HandlerContext) *tcell.EventKey {
 	view := ctx.View.(*View)
 
-	switch event.Key() {
-	case tcell.KeyEnter:
+	switch ctx.Action {
+	case "open_table":
 		index := view.leftPanel.GetCurrentItem()
 		if index >= 0 && index < view.leftPanel.GetItemCount() {
 			tableName, _ := view.leftPanel.GetItemText(index)
 			view.showTableItems(tableName)
 		}
-		return nil
-
-	case tcell.KeyRune:
-		if event.Rune() == '/' {
-			view.showFilterPrompt(view.leftPanel)
-			return nil
-		}
-
-	case tcell.KeyBackspace, tcell.KeyBackspace2:
+	case "filter":
+		view.showFilterPrompt(view.leftPanel)
+	case "clear_filter":
 		view.state.leftPanelFilter = ""
 		view.filterLeftPanel("")
-		return nil
+	default:
+		return event
 	}
-
-	return event
+	return nil
 }
 
 type DynamoDBFilterPromptHandler struct {
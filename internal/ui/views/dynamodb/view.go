@@ -18,7 +18,9 @@ import (
 	"github.com/tpelletiersophos/cloudcutter/internal/ui/components"
 	"github.com/tpelletiersophos/cloudcutter/internal/ui/components/spinner"
 	"github.com/tpelletiersophos/cloudcutter/internal/ui/components/types"
+	"github.com/tpelletiersophos/cloudcutter/internal/ui/components/vtable"
 	"github.com/tpelletiersophos/cloudcutter/internal/ui/manager"
+	"github.com/tpelletiersophos/cloudcutter/internal/ui/notify"
 	"github.com/tpelletiersophos/cloudcutter/internal/ui/style"
 	"github.com/tpelletiersophos/cloudcutter/internal/ui/views"
 )
@@ -59,6 +61,7 @@ type viewState struct {
 	pageSize          int
 	totalPages        int
 	spinner           *spinner.Spinner
+	columns           itemColumns
 }
 
 func NewView(manager *manager.Manager, dynamoService dynamodb.Interface) *View {
@@ -90,7 +93,7 @@ func NewView(manager *manager.Manager, dynamoService dynamodb.Interface) *View {
 		view.manager.Logger().Info,
 		view.manager.Logger().Error,
 	)
-	errorHandler := common.NewSimpleErrorHandler(view.manager, logger)
+	errorHandler := common.NewSimpleErrorHandler(view.manager, view.name, logger)
 	globalHandler := common.NewDefaultGlobalShortcutHandler()
 
 	view.sharedEventBus = common.NewEventBus(&common.EventBusConfig{
@@ -98,6 +101,10 @@ func NewView(manager *manager.Manager, dynamoService dynamodb.Interface) *View {
 		ErrorHandler:  errorHandler,
 		Logger:        logger,
 		GlobalHandler: globalHandler,
+		KeyContexts: map[common.ComponentType]string{
+			LeftPanelComponent: "dynamodb.tables",
+			DataTableComponent: "dynamodb.items",
+		},
 	})
 
 	// Register dynamodb-specific handlers
@@ -126,6 +133,18 @@ func (v *View) Show() {
 
 func (v *View) Hide() {}
 
+// ApplyTheme redraws the items table, whose cells are colored as they are rendered.
+func (v *View) ApplyTheme(*style.Theme) {
+	if len(v.state.filteredItems) > 0 {
+		v.updateDataTableForItems(v.state.filteredItems)
+	}
+}
+
+// KeyContext is the keymap context of the focused pane.
+func (v *View) KeyContext(focus tview.Primitive) string {
+	return v.sharedEventBus.KeyContext(focus)
+}
+
 // ViewInterface implementation methods
 
 // GetManager returns the manager for this view
@@ -146,7 +165,7 @@ func (v *View) GetName() string {
 func (v *View) fetchTables() {
 	tableNames, err := v.service.ListTables(v.ctx)
 	if err != nil {
-		v.manager.UpdateStatusBar(fmt.Sprintf("Error fetching DynamoDB tables: %v", err))
+		v.manager.NotifyError(v.Name(), fmt.Sprintf("Error fetching DynamoDB tables: %v", err))
 		return
 	}
 
@@ -154,7 +173,7 @@ func (v *View) fetchTables() {
 		v.leftPanel.AddItem(tableName, "", 0, nil)
 	}
 
-	v.manager.UpdateStatusBar("Select a table to view details or press Enter to view items")
+	v.manager.NotifyInfo(v.Name(), "Select a table to view details or press Enter to view items")
 }
 
 func (v *View) fetchTableDetails(tableName string) {
@@ -168,7 +187,7 @@ func (v *View) fetchTableDetails(tableName string) {
 
 	table, err := v.service.DescribeTable(ctx, tableName)
 	if err != nil {
-		v.manager.UpdateStatusBar(fmt.Sprintf("Error fetching table details: %v", err))
+		v.manager.NotifyError(v.Name(), fmt.Sprintf("Error fetching table details: %v", err))
 		v.updateTableSummary(nil)
 		return
 	}
@@ -209,28 +228,62 @@ func extractSortedHeaders(items []map[string]dynamodbtypes.AttributeValue) []str
 	return headers
 }
 
-func calculateColumnWidths(items []map[string]dynamodbtypes.AttributeValue, headers []string) map[string]int {
-	widths := make(map[string]int)
-	for _, header := range headers {
-		widths[header] = len(header)
-	}
+// maxColumnWidth caps the width of an item column.
+const maxColumnWidth = 50
 
-	for _, item := range items {
-		for header, value := range item {
-			width := len(attributeValueToString(value))
-			if width > widths[header] {
-				widths[header] = width
-			}
-		}
-	}
+// itemRowsPerLoad is how many items are rendered at a time as the table scrolls.
+const itemRowsPerLoad = 64
 
-	for header := range widths {
-		if widths[header] > 50 {
-			widths[header] = 50
-		}
+// itemColumns is the header of a set of items and its estimated column widths.
+type itemColumns struct {
+	items   []map[string]dynamodbtypes.AttributeValue
+	headers []string
+	widths  []int
+}
+
+// itemColumns returns the columns of items, reusing the last ones while the items are the same so
+// turning pages does not read every item again. Widths are estimated from a sample of items.
+func (v *View) itemColumns(items []map[string]dynamodbtypes.AttributeValue) itemColumns {
+	cached := v.state.columns
+	if len(cached.items) == len(items) && len(items) > 0 && &cached.items[0] == &items[0] {
+		return cached
 	}
 
-	return widths
+	headers := extractSortedHeaders(items)
+	widths := vtable.EstimateWidths(headers, len(items), vtable.DefaultSamples, maxColumnWidth, func(row, col int) string {
+		return attributeValueToString(items[row][headers[col]])
+	})
+	v.state.columns = itemColumns{items: items, headers: headers, widths: widths}
+	return v.state.columns
+}
+
+// itemRows renders items into table rows on demand, numbering them from 1.
+func (v *View) itemRows(items []map[string]dynamodbtypes.AttributeValue, columns itemColumns) *vtable.Pages[[]*tview.TableCell] {
+	showRowNumbers := v.state.showRowNumbers
+	return vtable.NewPages(len(items), itemRowsPerLoad, func(offset, limit int) ([][]*tview.TableCell, error) {
+		rows := make([][]*tview.TableCell, 0, limit)
+		for i, item := range items[offset : offset+limit] {
+			cells := make([]*tview.TableCell, 0, len(columns.headers)+1)
+			if showRowNumbers {
+				cells = append(cells, tview.NewTableCell(fmt.Sprintf("%d", offset+i+1)).
+					SetTextColor(style.Color(style.Muted)).
+					SetAlign(tview.AlignRight).
+					SetMaxWidth(3).
+					SetExpansion(0).
+					SetSelectable(false))
+			}
+			for col, header := range columns.headers {
+				cells = append(cells, tview.NewTableCell(strings.TrimSpace(attributeValueToString(item[header]))).
+					SetTextColor(style.Color(style.Text)).
+					SetAlign(tview.AlignLeft).
+					SetMaxWidth(columns.widths[col]).
+					SetExpansion(1).
+					SetSelectable(true))
+			}
+			rows = append(rows, cells)
+		}
+		return rows, nil
+	})
 }
 
 func attributeValueToString(av dynamodbtypes.AttributeValue) string {
@@ -266,7 +319,7 @@ func attributeValueToString(av dynamodbtypes.AttributeValue) string {
 func (v *View) initializeTableCache() {
 	tableNames, err := v.service.ListTables(v.ctx)
 	if err != nil {
-		v.manager.UpdateStatusBar(fmt.Sprintf("Error fetching DynamoDB tables: %v", err))
+		v.manager.NotifyError(v.Name(), fmt.Sprintf("Error fetching DynamoDB tables: %v", err))
 		return
 	}
 
@@ -277,7 +330,7 @@ func (v *View) initializeTableCache() {
 
 			table, err := v.service.DescribeTable(v.ctx, name)
 			if err != nil {
-				v.manager.UpdateStatusBar(fmt.Sprintf("Error fetching table details for %s: %v", name, err))
+				v.manager.NotifyError(v.Name(), fmt.Sprintf("Error fetching table details for %s: %v", name, err))
 				return
 			}
 
@@ -301,24 +354,19 @@ func (v *View) setupLayout() {
 				Focus:     true,
 				Style: types.ListStyle{
 					BaseStyle: types.BaseStyle{
-						Border:      true,
-						Title:       " DynamoDB ",
-						TitleAlign:  tview.AlignCenter,
-						TitleColor:  tcell.ColorMediumTurquoise,
-						BorderColor: tcell.ColorMediumTurquoise,
-						TextColor:   tcell.ColorBeige,
+						Border:           true,
+						Title:            " DynamoDB ",
+						TitleAlign:       tview.AlignCenter,
+						TitleColor:       style.Title,
+						BorderColor:      style.Border,
+						FocusBorderColor: style.BorderFocus,
+						TextColor:        style.Text,
 					},
-					SelectedTextColor:       tcell.ColorLightYellow,
-					SelectedBackgroundColor: tcell.ColorDarkCyan,
+					SelectedTextColor:       style.SelectionText,
+					SelectedBackgroundColor: style.Selection,
 				},
 				Properties: types.ListProperties{
 					Items: []string{},
-					OnFocus: func(list *tview.List) {
-						list.SetBorderColor(tcell.ColorMediumTurquoise)
-					},
-					OnBlur: func(list *tview.List) {
-						list.SetBorderColor(tcell.ColorBeige)
-					},
 					OnChanged: func(index int, mainText string, secondaryText string, shortcut rune) {
 						v.fetchTableDetails(mainText)
 					},
@@ -333,19 +381,12 @@ func (v *View) setupLayout() {
 				Proportion: 1,
 				Style: types.TableStyle{
 					BaseStyle: types.BaseStyle{
-						Border:      true,
-						BorderColor: tcell.ColorBeige,
-					},
-					SelectedTextColor:       tcell.ColorLightYellow,
-					SelectedBackgroundColor: tcell.ColorDarkCyan,
-				},
-				Properties: types.TableProperties{
-					OnFocus: func(table *tview.Table) {
-						table.SetBorderColor(tcell.ColorMediumTurquoise)
-					},
-					OnBlur: func(table *tview.Table) {
-						table.SetBorderColor(tcell.ColorBeige)
+						Border:           true,
+						BorderColor:      style.Border,
+						FocusBorderColor: style.BorderFocus,
 					},
+					SelectedTextColor:       style.SelectionText,
+					SelectedBackgroundColor: style.Selection,
 				},
 			},
 		},
@@ -361,13 +402,13 @@ func (v *View) setupLayout() {
 }
 
 func (v *View) updateDataTableForItems(items []map[string]dynamodbtypes.AttributeValue) {
-	v.dataTable.Clear()
+	v.dataTable.SetContent(nil)
 	v.calculateVisibleRows()
 
 	if len(items) == 0 {
 		v.dataTable.SetCell(0, 0,
 			tview.NewTableCell("No items found").
-				SetTextColor(tcell.ColorBeige).
+				SetTextColor(style.Color(style.Text)).
 				SetAlign(tview.AlignCenter).
 				SetSelectable(false))
 		return
@@ -394,77 +435,45 @@ func (v *View) updateDataTableForItems(items []map[string]dynamodbtypes.Attribut
 		end = totalItems
 	}
 
-	pageItems := items[start:end]
-
-	headers := extractSortedHeaders(items)
-	columnWidths := calculateColumnWidths(items, headers)
+	columns := v.itemColumns(items)
 
-	displayHeaders := headers
+	header := make([]*tview.TableCell, 0, len(columns.headers)+1)
 	if v.state.showRowNumbers {
-		displayHeaders = append([]string{"#"}, headers...)
+		header = append(header, tview.NewTableCell("#").
+			SetMaxWidth(2).
+			SetExpansion(0))
 	}
-
-	for col, header := range displayHeaders {
-		cell := tview.NewTableCell(header).
-			SetTextColor(style.GruvboxMaterial.Yellow).
+	for col, name := range columns.headers {
+		header = append(header, tview.NewTableCell(name).
+			SetMaxWidth(columns.widths[col]).
+			SetExpansion(1))
+	}
+	for _, cell := range header {
+		cell.SetTextColor(style.Color(style.Title)).
 			SetAlign(tview.AlignCenter).
 			SetSelectable(false).
 			SetAttributes(tcell.AttrBold)
-
-		if v.state.showRowNumbers && col == 0 {
-			cell.SetMaxWidth(2).
-				SetExpansion(0).
-				SetText("#")
-		} else {
-			cell.SetMaxWidth(columnWidths[header]).
-				SetExpansion(1)
-		}
-		v.dataTable.SetCell(0, col, cell)
 	}
 
-	for rowIdx, item := range pageItems {
-		currentRow := rowIdx + 1
-		currentCol := 0
-
-		if v.state.showRowNumbers {
-			v.dataTable.SetCell(currentRow, currentCol,
-				tview.NewTableCell(fmt.Sprintf("%d", start+rowIdx+1)).
-					SetTextColor(tcell.ColorGray).
-					SetAlign(tview.AlignRight).
-					SetMaxWidth(3).
-					SetExpansion(0).
-					SetSelectable(false))
-			currentCol++
-		}
-
-		for _, header := range headers {
-			value := attributeValueToString(item[header])
-			v.dataTable.SetCell(currentRow, currentCol,
-				tview.NewTableCell(strings.TrimSpace(value)).
-					SetTextColor(tcell.ColorBeige).
-					SetAlign(tview.AlignLeft).
-					SetMaxWidth(columnWidths[header]).
-					SetExpansion(1).
-					SetSelectable(true))
-			currentCol++
-		}
-	}
+	// Only the rows the table draws are rendered, however large the page
+	pageItems := vtable.NewRows(header, v.itemRows(items, columns), start, end-start)
+	v.dataTable.SetContent(vtable.New(pageItems))
 
 	v.dataTable.SetFixed(1, 0)
-	if len(pageItems) > 0 {
+	if pageItems.RowCount() > 0 {
 		v.dataTable.Select(1, 0)
 	}
 
 	statusMsg := fmt.Sprintf("Page %d/%d | Showing %d of %d items",
 		v.state.currentPage, v.state.totalPages,
-		len(pageItems), totalItems)
+		pageItems.RowCount(), totalItems)
 
 	if v.state.showRowNumbers {
 		statusMsg += fmt.Sprintf(" | [%s]Row numbers: on (press 'r' to toggle)[-]",
-			style.GruvboxMaterial.Yellow)
+			style.Color(style.Warning))
 	}
 
-	v.manager.UpdateStatusBar(statusMsg)
+	v.manager.NotifyInfo(v.Name(), statusMsg)
 }
 
 func (v *View) filterLeftPanel(filter string) {
@@ -486,9 +495,9 @@ func (v *View) filterLeftPanel(filter string) {
 	}
 
 	if filter == "" {
-		v.manager.UpdateStatusBar("Showing all tables")
+		v.manager.NotifyInfo(v.Name(), "Showing all tables")
 	} else {
-		v.manager.UpdateStatusBar(fmt.Sprintf("Filtered: showing tables matching '%s'", filter))
+		v.manager.NotifyInfo(v.Name(), fmt.Sprintf("Filtered: showing tables matching '%s'", filter))
 	}
 }
 
@@ -507,7 +516,7 @@ func (v *View) HandleFilter(prompt *components.Prompt, previousFocus tview.Primi
 		opts = components.PromptOptions{
 			Title:      " Filter Tables ",
 			Label:      " >_ ",
-			LabelColor: tcell.ColorMediumTurquoise,
+			LabelColor: style.Label,
 			OnDone: func(text string) {
 				v.state.leftPanelFilter = text
 				v.filterLeftPanel(text)
@@ -528,7 +537,7 @@ func (v *View) HandleFilter(prompt *components.Prompt, previousFocus tview.Primi
 		opts = components.PromptOptions{
 			Title:      " Filter Items ",
 			Label:      " >_ ",
-			LabelColor: tcell.ColorMediumTurquoise,
+			LabelColor: style.Label,
 			OnDone: func(text string) {
 				v.state.dataPanelFilter = text
 				v.filterItems(text)
@@ -562,7 +571,12 @@ func (v *View) showTableItems(tableName string) {
 			defer v.hideLoading()
 
 			if err != nil {
-				v.manager.UpdateStatusBar(fmt.Sprintf("Error scanning table %s: %v", tableName, err))
+				v.manager.Notify(notify.Notification{
+					Severity: notify.Error,
+					Source:   v.Name(),
+					Message:  fmt.Sprintf("Error scanning table %s: %v", tableName, err),
+					Action:   &notify.Action{Label: "Retry", Run: func() { v.showTableItems(tableName) }},
+				})
 				return
 			}
 
@@ -575,6 +589,7 @@ func (v *View) showTableItems(tableName string) {
 				v.updateDataTableForItems(items)
 			}
 			v.manager.SetFocus(v.dataTable)
+			v.manager.SaveSession()
 		})
 	}()
 }
@@ -587,7 +602,7 @@ func (v *View) Reinitialize(cfg aws.Config) error {
 	v.leftPanel.Clear()
 	v.dataTable.Clear()
 
-	v.manager.UpdateStatusBar("Reinitializing DynamoDB...")
+	v.manager.NotifyInfo(v.Name(), "Reinitializing DynamoDB...")
 	v.initializeTableCache()
 
 	tableNames := make([]string, 0, len(v.state.tableCache))
@@ -635,7 +650,7 @@ func (v *View) showFilterPrompt(source tview.Primitive) {
 		v.filterPrompt.Configure(components.PromptOptions{
 			Title:      " Filter Tables ",
 			Label:      " >_ ",
-			LabelColor: tcell.ColorMediumTurquoise,
+			LabelColor: style.Label,
 			OnDone: func(text string) {
 				v.state.leftPanelFilter = text
 				v.filterLeftPanel(text)
@@ -657,7 +672,7 @@ func (v *View) showFilterPrompt(source tview.Primitive) {
 		v.filterPrompt.Configure(components.PromptOptions{
 			Title:      " Filter Items ",
 			Label:      " >_ ",
-			LabelColor: tcell.ColorMediumTurquoise,
+			LabelColor: style.Label,
 			OnDone: func(text string) {
 				v.state.dataPanelFilter = text
 				v.filterItems(text)
@@ -704,6 +719,7 @@ func (v *View) calculateVisibleRows() {
 func (v *View) toggleRowNumbers() {
 	v.state.showRowNumbers = !v.state.showRowNumbers
 	v.updateDataTableForItems(v.state.filteredItems)
+	v.manager.SaveSession()
 }
 
 func (v *View) nextPage() {
@@ -715,7 +731,7 @@ func (v *View) nextPage() {
 		v.state.currentPage++
 		v.updateDataTableForItems(v.state.filteredItems)
 	} else {
-		v.manager.UpdateStatusBar("Already on the last page.")
+		v.manager.NotifyInfo(v.Name(), "Already on the last page.")
 	}
 }
 
@@ -728,7 +744,7 @@ func (v *View) previousPage() {
 		v.state.currentPage--
 		v.updateDataTableForItems(v.state.filteredItems)
 	} else {
-		v.manager.UpdateStatusBar("Already on the first page.")
+		v.manager.NotifyInfo(v.Name(), "Already on the first page.")
 	}
 }
 
@@ -770,23 +786,23 @@ func (v *View) showItemDetails(item map[string]dynamodbtypes.AttributeValue) {
 		value := attributeValueToString(item[attr])
 
 		attrCell := tview.NewTableCell(attr).
-			SetTextColor(tcell.ColorMediumTurquoise).
+			SetTextColor(style.Color(style.Label)).
 			SetAlign(tview.AlignLeft).
 			SetSelectable(false)
 		table.SetCell(row+1, 0, attrCell)
 
 		valueCell := tview.NewTableCell(value).
-			SetTextColor(tcell.ColorBeige).
+			SetTextColor(style.Color(style.Text)).
 			SetAlign(tview.AlignLeft).
-			SetSelectedStyle(tcell.StyleDefault.Foreground(tcell.ColorBeige).Background(tcell.ColorDarkCyan)).
+			SetSelectedStyle(tcell.StyleDefault.Foreground(style.Color(style.SelectionText)).Background(style.Color(style.Selection))).
 			SetSelectable(true)
 		table.SetCell(row+1, 1, valueCell)
 	}
 
 	table.SetBorder(true).
 		SetTitle(" Item Details (ESC to close, 'y' to copy value) ").
-		SetTitleColor(style.GruvboxMaterial.Yellow).
-		SetBorderColor(tcell.ColorMediumTurquoise)
+		SetTitleColor(style.Color(style.Title)).
+		SetBorderColor(style.Color(style.BorderFocus))
 
 	// Calculate modal height based on the number of attributes
 	// Add extra rows for padding and header
@@ -814,9 +830,9 @@ func (v *View) showItemDetails(item map[string]dynamodbtypes.AttributeValue) {
 					value := cell.Text
 					// Copy to clipboard
 					if err := clipboard.WriteAll(value); err != nil {
-						v.manager.UpdateStatusBar("Failed to copy value to clipboard")
+						v.manager.NotifyError(v.Name(), "Failed to copy value to clipboard")
 					} else {
-						v.manager.UpdateStatusBar("Value copied to clipboard")
+						v.manager.NotifySuccess(v.Name(), "Value copied to clipboard")
 					}
 				}
 				return nil
@@ -860,7 +876,7 @@ func (v *View) filterItems(filter string) {
 	if filter == "" {
 		v.state.filteredItems = v.state.originalItems
 		v.updateDataTableForItems(v.state.originalItems)
-		v.manager.UpdateStatusBar("Showing all items")
+		v.manager.NotifyInfo(v.Name(), "Showing all items")
 		return
 	}
 
@@ -896,10 +912,10 @@ func (v *View) filterItems(filter string) {
 
 	if v.state.showRowNumbers {
 		statusMsg += fmt.Sprintf(" | [%s]Row numbers: on (press 'r' to toggle)[-]",
-			style.GruvboxMaterial.Yellow)
+			style.Color(style.Warning))
 	}
 
-	v.manager.UpdateStatusBar(statusMsg)
+	v.manager.NotifyInfo(v.Name(), statusMsg)
 
 	if len(filtered) > 0 {
 		v.dataTable.Select(1, 0)
@@ -6,6 +6,8 @@ import (
 	"fmt"
 	"sync"
 	"time"
+
+	"github.com/tpelletiersophos/cloudcutter/internal/ui/notify"
 )
 
 // AsyncOperations provides centralized asynchronous operations with proper error handling,
@@ -39,7 +41,7 @@ func (ao *AsyncOperations) NewAsyncOperation(loadingMsg string) *AsyncOperation
 		successAction: func() {},
 		errorAction: func(err error) {
 			ao.view.manager.App().QueueUpdateDraw(func() {
-				ao.view.manager.UpdateStatusBar(fmt.Sprintf("Error: %v", err))
+				ao.view.manager.NotifyError(ao.view.Name(), fmt.Sprintf("Error: %v", err))
 			})
 		},
 	}
@@ -110,7 +112,7 @@ func (ao *AsyncOperations) FetchFullDocument(entry *DocEntry) {
 		}).
 		WithError(func(err error) {
 			ao.UIUpdateOperation(func() {
-				ao.view.manager.UpdateStatusBar(fmt.Sprintf("Error fetching document: %v", err))
+				ao.view.manager.NotifyError(ao.view.Name(), fmt.Sprintf("Error fetching document: %v", err))
 			})
 		}).
 		Execute(func(ctx context.Context) error {
@@ -145,7 +147,7 @@ func (ao *AsyncOperations) ReloadFieldsForNewIndex() {
 		WithSuccess(func() {
 			ao.UIUpdateOperation(func() {
 				ao.view.rebuildFieldList()
-				ao.view.manager.UpdateStatusBar("Fields loaded successfully")
+				ao.view.manager.NotifySuccess(ao.view.Name(), "Fields loaded successfully")
 			})
 			// Refresh with current timeframe after UI update
 			go func() {
@@ -154,7 +156,12 @@ func (ao *AsyncOperations) ReloadFieldsForNewIndex() {
 		}).
 		WithError(func(err error) {
 			ao.UIUpdateOperation(func() {
-				ao.view.manager.UpdateStatusBar(fmt.Sprintf("Error loading fields: %v", err))
+				ao.view.manager.Notify(notify.Notification{
+					Severity: notify.Error,
+					Source:   ao.view.Name(),
+					Message:  fmt.Sprintf("Error loading fields: %v", err),
+					Action:   &notify.Action{Label: "Retry", Run: ao.ReloadFieldsForNewIndex},
+				})
 			})
 		}).
 		Execute(func(ctx context.Context) error {
@@ -266,7 +273,7 @@ func (ao *AsyncOperations) RefreshResults() {
 		WithError(func(err error) {
 			ao.view.manager.Logger().Error("Error fetching results", "error", err)
 			ao.UIUpdateOperation(func() {
-				ao.view.manager.UpdateStatusBar(fmt.Sprintf("Error: %v", err))
+				ao.view.manager.NotifyError(ao.view.Name(), fmt.Sprintf("Error: %v", err))
 			})
 		}).
 		Execute(func(ctx context.Context) error {
@@ -288,7 +295,7 @@ func (ao *AsyncOperations) RefreshResults() {
 			ao.UIUpdateOperation(func() {
 				ao.view.displayCurrentPage()
 				ao.view.updateHeader()
-				ao.view.manager.UpdateStatusBar(fmt.Sprintf("Found %d results total (displaying %d)",
+				ao.view.manager.NotifyInfo(ao.view.Name(), fmt.Sprintf("Found %d results total (displaying %d)",
 					searchResult.totalHits, len(searchResult.entries)))
 			})
 
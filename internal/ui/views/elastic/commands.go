@@ -0,0 +1,61 @@
+package elastic
+
+import (
+	"slices"
+
+	"github.com/rivo/tview"
+	"github.com/tpelletiersophos/cloudcutter/internal/deeplink"
+	"github.com/tpelletiersophos/cloudcutter/internal/ui/views"
+)
+
+var _ views.Commander = (*View)(nil)
+
+// timeframeSuggestions are offered when completing :timeframe.
+var timeframeSuggestions = []string{"15m", "1h", "12h", "24h", "7d", "30d", "today", "week", "month", "quarter", "year"}
+
+// Commands adds :index and :timeframe to the prompt while the view is active.
+func (v *View) Commands() []*views.Command {
+	return []*views.Command{
+		{
+			Name:        "index",
+			Args:        "<pattern>",
+			Description: "Search another index or index pattern",
+			Run: func(args []string) (tview.Primitive, error) {
+				if len(args) != 1 {
+					return nil, views.ErrUsage
+				}
+				v.ApplyLink(deeplink.Link{Index: args[0]})
+				return nil, nil
+			},
+			Complete: func(args []string) []string {
+				if len(args) != 1 {
+					return nil
+				}
+				v.state.mu.RLock()
+				defer v.state.mu.RUnlock()
+				return slices.Clone(v.state.search.matchingIndices)
+			},
+		},
+		{
+			Name:        "timeframe",
+			Args:        "<timeframe>",
+			Description: "Search from this long ago until now, e.g. 2h, 7d or today",
+			Run: func(args []string) (tview.Primitive, error) {
+				if len(args) != 1 {
+					return nil, views.ErrUsage
+				}
+				if err := ValidateTimeframe(args[0]); err != nil {
+					return nil, err
+				}
+				v.ApplyLink(deeplink.Link{Timeframe: args[0]})
+				return nil, nil
+			},
+			Complete: func(args []string) []string {
+				if len(args) != 1 {
+					return nil
+				}
+				return timeframeSuggestions
+			},
+		},
+	}
+}
@@ -0,0 +1,41 @@
+package elastic
+
+import (
+	"testing"
+	"time"
+
+	"github.com/rivo/tview"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIndexCommandDoesNotWaitForEventLoop(t *testing.T) {
+	view := createTestView(t)
+	view.components.indexInput = tview.NewInputField()
+
+	var index func([]string) (tview.Primitive, error)
+	for _, cmd := range view.Commands() {
+		if cmd.Name == "index" {
+			index = cmd.Run
+		}
+	}
+	require.NotNil(t, index)
+
+	// The app isn't running, so anything Run waits for on the event loop never happens
+	done := make(chan error, 1)
+	go func() {
+		_, err := index([]string{"logs-*"})
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		require.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Fatal(":index waited for the event loop")
+	}
+
+	assert.Equal(t, "logs-*", view.components.indexInput.GetText())
+	view.state.mu.RLock()
+	defer view.state.mu.RUnlock()
+	assert.Equal(t, "logs-*", view.state.search.currentIndex)
+}
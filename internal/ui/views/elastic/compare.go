@@ -0,0 +1,519 @@
+package elastic
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/elastic/go-elasticsearch/v6"
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"github.com/tpelletiersophos/cloudcutter/internal/services/elastic"
+	"github.com/tpelletiersophos/cloudcutter/internal/ui/common"
+	"github.com/tpelletiersophos/cloudcutter/internal/ui/components"
+	"github.com/tpelletiersophos/cloudcutter/internal/ui/components/types"
+	"github.com/tpelletiersophos/cloudcutter/internal/ui/style"
+)
+
+const (
+	ModalCompare = "modalCompare"
+
+	// compareMaxResults caps each side of a comparison; comparisons never scroll.
+	compareMaxResults = 10000
+)
+
+// CompareTarget identifies one side of a comparison. An empty Region means the current cluster.
+type CompareTarget struct {
+	Region string
+	Index  string
+}
+
+func (t CompareTarget) String() string {
+	if t.Region == "" {
+		return t.Index
+	}
+	return t.Region + ":" + t.Index
+}
+
+// ParseCompareTarget parses "index" or "region:index", the same form cross-cluster search uses.
+func ParseCompareTarget(spec string) (CompareTarget, error) {
+	spec = strings.TrimSpace(spec)
+	if spec == "" {
+		return CompareTarget{}, fmt.Errorf("no index to compare with")
+	}
+
+	region, index, found := strings.Cut(spec, ":")
+	if !found {
+		return CompareTarget{Index: spec}, nil
+	}
+
+	region, index = strings.TrimSpace(region), strings.TrimSpace(index)
+	if region == "" || index == "" {
+		return CompareTarget{}, fmt.Errorf("invalid compare target %q, expected index or region:index", spec)
+	}
+	return CompareTarget{Region: region, Index: index}, nil
+}
+
+// FieldDiff is a field whose value differs between two documents with the same _id.
+type FieldDiff struct {
+	Field string
+	Left  string
+	Right string
+}
+
+// FieldCoverage counts how many documents on each side contain a field.
+type FieldCoverage struct {
+	Field string
+	Left  int
+	Right int
+}
+
+// CompareRow pairs the documents sharing an _id. Either side is nil when the _id only exists on
+// the other side.
+type CompareRow struct {
+	ID    string
+	Left  *DocEntry
+	Right *DocEntry
+	Diffs []FieldDiff
+}
+
+// Comparison is the result of running the same query against two targets.
+type Comparison struct {
+	LeftTotal  int
+	RightTotal int
+	LeftCount  int
+	RightCount int
+	Matching   int
+	Differing  int
+	Rows       []CompareRow
+	Coverage   []FieldCoverage
+}
+
+// sourceFields returns the document's _source fields, without metadata.
+func sourceFields(entry *DocEntry) []string {
+	var fields []string
+	entry.getFieldsRecursive(entry.data, "", &fields)
+	sort.Strings(fields)
+	return fields
+}
+
+// DiffDocEntries returns the _source fields whose formatted values differ, sorted by field name.
+func DiffDocEntries(left, right *DocEntry) []FieldDiff {
+	fields := make(map[string]struct{})
+	for _, f := range sourceFields(left) {
+		fields[f] = struct{}{}
+	}
+	for _, f := range sourceFields(right) {
+		fields[f] = struct{}{}
+	}
+
+	var diffs []FieldDiff
+	for f := range fields {
+		l, r := left.GetFormattedValue(f), right.GetFormattedValue(f)
+		if l != r {
+			diffs = append(diffs, FieldDiff{Field: f, Left: l, Right: r})
+		}
+	}
+	sort.Slice(diffs, func(i, j int) bool {
+		return diffs[i].Field < diffs[j].Field
+	})
+	return diffs
+}
+
+// CompareEntries pairs documents by _id and computes per-field coverage. Rows keep the left
+// side's order, followed by documents found only on the right.
+func CompareEntries(left, right []*DocEntry) *Comparison {
+	c := &Comparison{LeftCount: len(left), RightCount: len(right)}
+
+	rightByID := make(map[string]*DocEntry, len(right))
+	for _, entry := range right {
+		rightByID[entry.ID] = entry
+	}
+
+	seen := make(map[string]struct{}, len(left))
+	for _, entry := range left {
+		row := CompareRow{ID: entry.ID, Left: entry}
+		if other, ok := rightByID[entry.ID]; ok {
+			row.Right = other
+			row.Diffs = DiffDocEntries(entry, other)
+			c.Matching++
+			if len(row.Diffs) > 0 {
+				c.Differing++
+			}
+		}
+		seen[entry.ID] = struct{}{}
+		c.Rows = append(c.Rows, row)
+	}
+	for _, entry := range right {
+		if _, ok := seen[entry.ID]; !ok {
+			c.Rows = append(c.Rows, CompareRow{ID: entry.ID, Right: entry})
+		}
+	}
+
+	coverage := make(map[string]*FieldCoverage)
+	count := func(entries []*DocEntry, side func(*FieldCoverage)) {
+		for _, entry := range entries {
+			for _, f := range sourceFields(entry) {
+				fc, ok := coverage[f]
+				if !ok {
+					fc = &FieldCoverage{Field: f}
+					coverage[f] = fc
+				}
+				side(fc)
+			}
+		}
+	}
+	count(left, func(fc *FieldCoverage) { fc.Left++ })
+	count(right, func(fc *FieldCoverage) { fc.Right++ })
+
+	for _, fc := range coverage {
+		c.Coverage = append(c.Coverage, *fc)
+	}
+	sort.Slice(c.Coverage, func(i, j int) bool {
+		return c.Coverage[i].Field < c.Coverage[j].Field
+	})
+	return c
+}
+
+// CoverageDiffers reports whether a field is present in a different share of documents on each side.
+func (c *Comparison) CoverageDiffers(fc FieldCoverage) bool {
+	return fc.Left*c.RightCount != fc.Right*c.LeftCount
+}
+
+// compareView shows two result sets for the same query side by side.
+type compareView struct {
+	view       *View
+	left       CompareTarget
+	right      CompareTarget
+	comparison *Comparison
+
+	summary    *tview.TextView
+	leftTable  *tview.Table
+	rightTable *tview.Table
+	details    *tview.TextView
+	container  *tview.Flex
+
+	showCoverage bool
+	keys         *common.KeyResolver
+	restore      func()
+}
+
+// showComparePrompt asks for the index, or region:index, to compare the current results with.
+func (v *View) showComparePrompt() {
+	previousFocus := v.manager.App().GetFocus()
+
+	v.components.filterPrompt.Configure(components.PromptOptions{
+		Title:      " Compare With (index or region:index) ",
+		Label:      " >_ ",
+		LabelColor: style.Label,
+		OnDone: func(text string) {
+			v.manager.HideFilterPrompt()
+			v.manager.SetFocus(previousFocus)
+
+			target, err := ParseCompareTarget(text)
+			if err != nil {
+				v.manager.NotifyError(v.Name(), err.Error())
+				return
+			}
+			v.startComparison(target)
+		},
+		OnCancel: func() {
+			v.manager.HideFilterPrompt()
+			v.manager.SetFocus(previousFocus)
+		},
+	})
+
+	v.components.filterPrompt.SetText("")
+	v.manager.Pages().AddPage(types.ModalFilter, v.components.filterPrompt.Layout(), true, true)
+	v.manager.App().SetFocus(v.components.filterPrompt.InputField)
+}
+
+// compareClient returns the client for target's cluster, reusing the view's client for the current one.
+func (v *View) compareClient(target CompareTarget) (*elasticsearch.Client, error) {
+	cfg := v.manager.GetCurrentConfig()
+	if target.Region == "" || target.Region == cfg.Region {
+		return v.service.Client, nil
+	}
+	cfg.Region = target.Region
+	return elastic.NewClient(cfg, v.manager.CurrentProfile())
+}
+
+// startComparison runs the current filters against the current index and target concurrently.
+func (v *View) startComparison(right CompareTarget) {
+	v.state.mu.RLock()
+	left := CompareTarget{Index: v.state.search.currentIndex}
+	numResults := v.state.search.numResults
+	v.state.mu.RUnlock()
+
+	if numResults > compareMaxResults {
+		numResults = compareMaxResults
+	}
+
+	leftQuery, rightQuery := v.buildQuery(), v.buildQuery()
+	if leftQuery == nil || rightQuery == nil {
+		return
+	}
+
+	rightClient, err := v.compareClient(right)
+	if err != nil {
+		v.manager.NotifyError(v.Name(), fmt.Sprintf("Cannot connect to %s: %v", right.Region, err))
+		return
+	}
+
+	v.showLoading(fmt.Sprintf("Comparing %s with %s", left, right))
+
+	go func() {
+		defer v.hideLoading()
+
+		var wg sync.WaitGroup
+		var leftResult, rightResult *searchResult
+		var leftErr, rightErr error
+
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			leftResult, leftErr = v.fetchRegularResults(v.service.Client, leftQuery, numResults, left.Index)
+		}()
+		go func() {
+			defer wg.Done()
+			rightResult, rightErr = v.fetchRegularResults(rightClient, rightQuery, numResults, right.Index)
+		}()
+		wg.Wait()
+
+		for _, side := range []struct {
+			target CompareTarget
+			err    error
+		}{{left, leftErr}, {right, rightErr}} {
+			if side.err != nil {
+				v.manager.Logger().Error("Comparison search failed", "target", side.target.String(), "error", side.err)
+				v.manager.App().QueueUpdateDraw(func() {
+					v.manager.NotifyError(v.Name(), fmt.Sprintf("Compare failed for %s: %v", side.target, side.err))
+				})
+				return
+			}
+		}
+
+		comparison := CompareEntries(leftResult.entries, rightResult.entries)
+		comparison.LeftTotal = leftResult.totalHits
+		comparison.RightTotal = rightResult.totalHits
+
+		v.manager.App().QueueUpdateDraw(func() {
+			cv := &compareView{view: v, left: left, right: right, comparison: comparison}
+			cv.show()
+		})
+	}()
+}
+
+func (cv *compareView) show() {
+	cv.summary = tview.NewTextView().SetDynamicColors(true)
+	cv.leftTable = cv.newTable(cv.left)
+	cv.rightTable = cv.newTable(cv.right)
+	cv.details = tview.NewTextView().SetDynamicColors(true).SetScrollable(true).SetWrap(false)
+	cv.details.SetBorder(true).SetBorderColor(style.Color(style.BorderFocus))
+
+	footer := newKeyHints("elastic.compare",
+		keyHint{"focus_next", "switch side"},
+		keyHint{"toggle_coverage", "field coverage / doc diff"},
+		keyHint{"close", "close"})
+
+	tables := tview.NewFlex().
+		AddItem(cv.leftTable, 0, 1, true).
+		AddItem(cv.rightTable, 0, 1, false)
+
+	cv.container = tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(cv.summary, 3, 0, false).
+		AddItem(tables, 0, 2, true).
+		AddItem(cv.details, 0, 1, false).
+		AddItem(footer, 1, 0, false)
+	cv.container.SetBorder(true).
+		SetTitle(" Compare ").
+		SetTitleColor(style.Color(style.Title)).
+		SetBorderColor(style.Color(style.BorderFocus))
+
+	cv.renderSummary()
+	cv.renderTables()
+	cv.renderDetails(1)
+
+	pages := cv.view.manager.Pages()
+	pages.RemovePage(ModalCompare)
+	pages.AddPage(ModalCompare, cv.container, true, true)
+
+	cv.keys = common.NewKeyResolver()
+	cv.restore = cv.view.captureModalInput(ModalCompare, cv.handleInput)
+	cv.view.manager.App().SetFocus(cv.leftTable)
+}
+
+func (cv *compareView) close() {
+	cv.restore()
+	cv.view.manager.Pages().RemovePage(ModalCompare)
+	cv.view.manager.SetFocus(cv.view.components.resultsTable)
+}
+
+func (cv *compareView) handleInput(event *tcell.EventKey) *tcell.EventKey {
+	action, pending := cv.keys.Resolve("elastic.compare", event)
+	switch {
+	case pending:
+	case action == "close":
+		cv.close()
+	case action == "focus_next", action == "focus_prev":
+		if cv.view.manager.App().GetFocus() == cv.leftTable {
+			cv.view.manager.App().SetFocus(cv.rightTable)
+		} else {
+			cv.view.manager.App().SetFocus(cv.leftTable)
+		}
+	case action == "toggle_coverage":
+		cv.showCoverage = !cv.showCoverage
+		row, _ := cv.leftTable.GetSelection()
+		cv.renderDetails(row)
+	default:
+		return event
+	}
+	return nil
+}
+
+func (cv *compareView) newTable(target CompareTarget) *tview.Table {
+	table := tview.NewTable().
+		SetBorders(false).
+		SetSelectable(true, false).
+		SetSelectedStyle(tcell.StyleDefault.Background(style.Color(style.Selection)).Foreground(style.Color(style.SelectionText)))
+	table.SetBorder(true).
+		SetTitle(fmt.Sprintf(" %s ", target)).
+		SetTitleColor(style.Color(style.Title)).
+		SetBorderColor(style.Color(style.BorderFocus))
+
+	// Keep both sides on the same _id as the selection moves
+	table.SetSelectionChangedFunc(func(row, _ int) {
+		for _, other := range []*tview.Table{cv.leftTable, cv.rightTable} {
+			if other != nil && other != table {
+				if selected, _ := other.GetSelection(); selected != row {
+					other.Select(row, 0)
+				}
+			}
+		}
+		cv.renderDetails(row)
+	})
+	return table
+}
+
+func (cv *compareView) renderSummary() {
+	c := cv.comparison
+	onlyLeft := c.LeftCount - c.Matching
+	onlyRight := c.RightCount - c.Matching
+
+	var text strings.Builder
+	label, value := style.Tag(style.Label), style.Tag(style.Text)
+	text.WriteString(fmt.Sprintf(" %s%s:%s %d hits (%d fetched)   %s%s:%s %d hits (%d fetched)\n",
+		label, tview.Escape(cv.left.String()), value, c.LeftTotal, c.LeftCount,
+		label, tview.Escape(cv.right.String()), value, c.RightTotal, c.RightCount))
+	text.WriteString(fmt.Sprintf(" %sMatching _ids:%s %d (%s%d differ%s)   %sOnly left:%s %d   %sOnly right:%s %d",
+		label, value, c.Matching, style.Tag(style.Warning), c.Differing, value,
+		label, value, onlyLeft, label, value, onlyRight))
+	cv.summary.SetText(text.String())
+}
+
+func (cv *compareView) renderTables() {
+	v := cv.view
+	headers := v.getActiveHeaders()
+
+	displayHeaders := make([]string, 0, len(headers)+1)
+	if v.state.ui.showRowNumbers {
+		displayHeaders = append(displayHeaders, "#")
+	}
+	displayHeaders = append(displayHeaders, headers...)
+
+	v.setupTableHeaders(cv.leftTable, displayHeaders)
+	v.setupTableHeaders(cv.rightTable, displayHeaders)
+
+	for i, row := range cv.comparison.Rows {
+		cv.renderRow(cv.leftTable, i+1, row.Left, row, headers, len(displayHeaders))
+		cv.renderRow(cv.rightTable, i+1, row.Right, row, headers, len(displayHeaders))
+	}
+}
+
+// renderRow reuses the results table cells, coloring rows by how the _id compares across sides.
+func (cv *compareView) renderRow(table *tview.Table, row int, entry *DocEntry, cr CompareRow, headers []string, numCols int) {
+	if entry == nil {
+		for col := 0; col < numCols; col++ {
+			table.SetCell(row, col, tview.NewTableCell("—").SetTextColor(style.Color(style.Muted)))
+		}
+		return
+	}
+
+	color := style.Color(style.Text)
+	switch {
+	case cr.Left == nil || cr.Right == nil:
+		color = style.Color(style.StatusError)
+	case len(cr.Diffs) > 0:
+		color = style.Color(style.Warning)
+	}
+
+	for col, cell := range cv.view.entryCells(entry, headers, row) {
+		if col > 0 || !cv.view.state.ui.showRowNumbers {
+			cell.SetTextColor(color)
+		}
+		table.SetCell(row, col, cell)
+	}
+}
+
+func (cv *compareView) renderDetails(row int) {
+	if cv.showCoverage {
+		cv.renderCoverage()
+		return
+	}
+
+	idx := row - 1
+	if idx < 0 || idx >= len(cv.comparison.Rows) {
+		cv.details.SetTitle(" Document diff ")
+		cv.details.SetText(" " + style.Tag(style.Muted) + "No documents")
+		return
+	}
+
+	cr := cv.comparison.Rows[idx]
+	cv.details.SetTitle(fmt.Sprintf(" Document diff: %s ", tview.Escape(cr.ID)))
+
+	var text strings.Builder
+	switch {
+	case cr.Left == nil:
+		text.WriteString(fmt.Sprintf(" %sOnly found in %s", style.Tag(style.StatusError), tview.Escape(cv.right.String())))
+	case cr.Right == nil:
+		text.WriteString(fmt.Sprintf(" %sOnly found in %s", style.Tag(style.StatusError), tview.Escape(cv.left.String())))
+	case len(cr.Diffs) == 0:
+		text.WriteString(" " + style.Tag(style.Success) + "_source is identical on both sides")
+	default:
+		for _, d := range cr.Diffs {
+			text.WriteString(fmt.Sprintf(" %s%s[-]\n   %s- %s[-]\n   %s+ %s[-]\n",
+				style.Tag(style.Warning), tview.Escape(d.Field),
+				style.Tag(style.StatusError), tview.Escape(d.Left),
+				style.Tag(style.Success), tview.Escape(d.Right)))
+		}
+	}
+	cv.details.SetText(text.String()).ScrollToBeginning()
+}
+
+func (cv *compareView) renderCoverage() {
+	c := cv.comparison
+	cv.details.SetTitle(" Field coverage differences (left / right) ")
+
+	percent := func(n, total int) float64 {
+		if total == 0 {
+			return 0
+		}
+		return float64(n) * 100 / float64(total)
+	}
+
+	var text strings.Builder
+	for _, fc := range c.Coverage {
+		if !c.CoverageDiffers(fc) {
+			continue
+		}
+		text.WriteString(fmt.Sprintf(" %s%-50s[-] %5.1f%% (%d)  /  %5.1f%% (%d)\n",
+			style.Tag(style.Warning), tview.Escape(fc.Field),
+			percent(fc.Left, c.LeftCount), fc.Left,
+			percent(fc.Right, c.RightCount), fc.Right))
+	}
+	if text.Len() == 0 {
+		text.WriteString(" " + style.Tag(style.Success) + "Every field appears in the same share of documents on both sides")
+	}
+	cv.details.SetText(text.String()).ScrollToBeginning()
+}
@@ -0,0 +1,97 @@
+package elastic
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newCompareEntry(t *testing.T, id, source string) *DocEntry {
+	t.Helper()
+	entry, err := NewDocEntry([]byte(source), id, "logs", "_doc", nil, nil)
+	if err != nil {
+		t.Fatalf("NewDocEntry: %v", err)
+	}
+	return entry
+}
+
+func TestParseCompareTarget(t *testing.T) {
+	tests := []struct {
+		spec    string
+		want    CompareTarget
+		wantErr bool
+	}{
+		{spec: "main-summary-*", want: CompareTarget{Index: "main-summary-*"}},
+		{spec: " us-west-2:staging-summary ", want: CompareTarget{Region: "us-west-2", Index: "staging-summary"}},
+		{spec: "local:logs", want: CompareTarget{Region: "local", Index: "logs"}},
+		{spec: "", wantErr: true},
+		{spec: "us-west-2:", wantErr: true},
+		{spec: ":logs", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			got, err := ParseCompareTarget(tt.spec)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	assert.Equal(t, "us-west-2:logs", CompareTarget{Region: "us-west-2", Index: "logs"}.String())
+	assert.Equal(t, "logs", CompareTarget{Index: "logs"}.String())
+}
+
+func TestDiffDocEntries(t *testing.T) {
+	left := newCompareEntry(t, "1", `{"status": "active", "user": {"name": "alice", "age": 30}, "tag": "a"}`)
+	right := newCompareEntry(t, "1", `{"status": "inactive", "user": {"name": "alice", "age": 30}, "extra": true}`)
+
+	diffs := DiffDocEntries(left, right)
+	fields := make([]string, 0, len(diffs))
+	for _, d := range diffs {
+		fields = append(fields, d.Field)
+	}
+	assert.Equal(t, []string{"extra", "status", "tag"}, fields)
+	assert.Equal(t, FieldDiff{Field: "status", Left: "active", Right: "inactive"}, diffs[1])
+
+	assert.Empty(t, DiffDocEntries(left, left))
+}
+
+func TestCompareEntries(t *testing.T) {
+	left := []*DocEntry{
+		newCompareEntry(t, "1", `{"status": "active", "region": "eu"}`),
+		newCompareEntry(t, "2", `{"status": "active"}`),
+		newCompareEntry(t, "3", `{"status": "active", "region": "us"}`),
+	}
+	right := []*DocEntry{
+		newCompareEntry(t, "4", `{"status": "active"}`),
+		newCompareEntry(t, "2", `{"status": "active"}`),
+		newCompareEntry(t, "1", `{"status": "closed", "region": "eu"}`),
+	}
+
+	c := CompareEntries(left, right)
+
+	assert.Equal(t, 3, c.LeftCount)
+	assert.Equal(t, 3, c.RightCount)
+	assert.Equal(t, 2, c.Matching)
+	assert.Equal(t, 1, c.Differing)
+
+	ids := make([]string, 0, len(c.Rows))
+	for _, row := range c.Rows {
+		ids = append(ids, row.ID)
+	}
+	assert.Equal(t, []string{"1", "2", "3", "4"}, ids)
+	assert.Len(t, c.Rows[0].Diffs, 1)
+	assert.Nil(t, c.Rows[2].Right)
+	assert.Nil(t, c.Rows[3].Left)
+
+	assert.Equal(t, []FieldCoverage{
+		{Field: "region", Left: 2, Right: 1},
+		{Field: "status", Left: 3, Right: 3},
+	}, c.Coverage)
+	assert.True(t, c.CoverageDiffers(c.Coverage[0]))
+	assert.False(t, c.CoverageDiffers(c.Coverage[1]))
+}
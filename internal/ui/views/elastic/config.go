@@ -5,80 +5,82 @@ import (
 	"os"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 )
 
 // ElasticViewConfig contains all configuration settings for the elastic view
 type ElasticViewConfig struct {
 	// Pagination settings
-	Pagination PaginationConfig `json:"pagination"`
+	Pagination PaginationConfig `json:"pagination" yaml:"pagination"`
 
 	// Search settings
-	Search SearchConfig `json:"search"`
+	Search SearchConfig `json:"search" yaml:"search"`
 
 	// UI settings
-	UI UIConfig `json:"ui"`
+	UI UIConfig `json:"ui" yaml:"ui"`
 
 	// Async operation settings
-	AsyncOps AsyncOperationsConfig `json:"async_ops"`
+	AsyncOps AsyncOperationsConfig `json:"async_ops" yaml:"async_ops"`
 
 	// Rate limiting settings
-	RateLimit RateLimitConfig `json:"rate_limit"`
+	RateLimit RateLimitConfig `json:"rate_limit" yaml:"rate_limit"`
 
 	// Field management settings
-	Fields FieldConfig `json:"fields"`
+	Fields FieldConfig `json:"fields" yaml:"fields"`
 }
 
 // PaginationConfig contains pagination-related settings
 type PaginationConfig struct {
-	DefaultPageSize int `json:"default_page_size" env:"ELASTIC_VIEW_PAGE_SIZE"`
-	MaxPageSize     int `json:"max_page_size" env:"ELASTIC_VIEW_MAX_PAGE_SIZE"`
-	MinPageSize     int `json:"min_page_size" env:"ELASTIC_VIEW_MIN_PAGE_SIZE"`
+	DefaultPageSize int `json:"default_page_size" yaml:"default_page_size" env:"ELASTIC_VIEW_PAGE_SIZE"`
+	MaxPageSize     int `json:"max_page_size" yaml:"max_page_size" env:"ELASTIC_VIEW_MAX_PAGE_SIZE"`
+	MinPageSize     int `json:"min_page_size" yaml:"min_page_size" env:"ELASTIC_VIEW_MIN_PAGE_SIZE"`
 }
 
 // SearchConfig contains search-related settings
 type SearchConfig struct {
-	DefaultTimeframe  string        `json:"default_timeframe" env:"ELASTIC_VIEW_DEFAULT_TIMEFRAME"`
-	ValidTimeframes   []string      `json:"valid_timeframes"`
-	DefaultNumResults int           `json:"default_num_results" env:"ELASTIC_VIEW_NUM_RESULTS"`
-	MaxResults        int           `json:"max_results" env:"ELASTIC_VIEW_MAX_RESULTS"`
-	LargeResultLimit  int           `json:"large_result_limit" env:"ELASTIC_VIEW_LARGE_RESULT_LIMIT"`
-	ScrollBatchSize   int           `json:"scroll_batch_size" env:"ELASTIC_VIEW_SCROLL_BATCH_SIZE"`
-	ScrollTimeout     time.Duration `json:"scroll_timeout" env:"ELASTIC_VIEW_SCROLL_TIMEOUT"`
-	MaxRetries        int           `json:"max_retries" env:"ELASTIC_VIEW_MAX_RETRIES"`
-	BaseRetryDelay    time.Duration `json:"base_retry_delay" env:"ELASTIC_VIEW_BASE_RETRY_DELAY"`
+	DefaultIndex      string        `json:"default_index" yaml:"default_index" env:"ELASTIC_VIEW_DEFAULT_INDEX"`
+	DefaultTimeframe  string        `json:"default_timeframe" yaml:"default_timeframe" env:"ELASTIC_VIEW_DEFAULT_TIMEFRAME"`
+	ValidTimeframes   []string      `json:"valid_timeframes" yaml:"valid_timeframes"`
+	DefaultNumResults int           `json:"default_num_results" yaml:"default_num_results" env:"ELASTIC_VIEW_NUM_RESULTS"`
+	MaxResults        int           `json:"max_results" yaml:"max_results" env:"ELASTIC_VIEW_MAX_RESULTS"`
+	LargeResultLimit  int           `json:"large_result_limit" yaml:"large_result_limit" env:"ELASTIC_VIEW_LARGE_RESULT_LIMIT"`
+	ScrollBatchSize   int           `json:"scroll_batch_size" yaml:"scroll_batch_size" env:"ELASTIC_VIEW_SCROLL_BATCH_SIZE"`
+	ScrollTimeout     time.Duration `json:"scroll_timeout" yaml:"scroll_timeout" env:"ELASTIC_VIEW_SCROLL_TIMEOUT"`
+	MaxRetries        int           `json:"max_retries" yaml:"max_retries" env:"ELASTIC_VIEW_MAX_RETRIES"`
+	BaseRetryDelay    time.Duration `json:"base_retry_delay" yaml:"base_retry_delay" env:"ELASTIC_VIEW_BASE_RETRY_DELAY"`
 }
 
 // UIConfig contains user interface settings
 type UIConfig struct {
-	ShowRowNumbers         bool   `json:"show_row_numbers" env:"ELASTIC_VIEW_SHOW_ROW_NUMBERS"`
-	DefaultFieldListFilter string `json:"default_field_list_filter"`
-	FieldListVisible       bool   `json:"field_list_visible" env:"ELASTIC_VIEW_FIELD_LIST_VISIBLE"`
+	ShowRowNumbers         bool   `json:"show_row_numbers" yaml:"show_row_numbers" env:"ELASTIC_VIEW_SHOW_ROW_NUMBERS"`
+	DefaultFieldListFilter string `json:"default_field_list_filter" yaml:"default_field_list_filter"`
+	FieldListVisible       bool   `json:"field_list_visible" yaml:"field_list_visible" env:"ELASTIC_VIEW_FIELD_LIST_VISIBLE"`
 }
 
 // AsyncOperationsConfig contains async operation timeout and behavior settings
 type AsyncOperationsConfig struct {
-	DefaultTimeout       time.Duration `json:"default_timeout" env:"ELASTIC_VIEW_DEFAULT_TIMEOUT"`
-	DocumentFetchTimeout time.Duration `json:"document_fetch_timeout" env:"ELASTIC_VIEW_DOC_FETCH_TIMEOUT"`
-	FieldLoadTimeout     time.Duration `json:"field_load_timeout" env:"ELASTIC_VIEW_FIELD_LOAD_TIMEOUT"`
-	SearchRefreshTimeout time.Duration `json:"search_refresh_timeout" env:"ELASTIC_VIEW_SEARCH_REFRESH_TIMEOUT"`
-	FieldInitTimeout     time.Duration `json:"field_init_timeout" env:"ELASTIC_VIEW_FIELD_INIT_TIMEOUT"`
+	DefaultTimeout       time.Duration `json:"default_timeout" yaml:"default_timeout" env:"ELASTIC_VIEW_DEFAULT_TIMEOUT"`
+	DocumentFetchTimeout time.Duration `json:"document_fetch_timeout" yaml:"document_fetch_timeout" env:"ELASTIC_VIEW_DOC_FETCH_TIMEOUT"`
+	FieldLoadTimeout     time.Duration `json:"field_load_timeout" yaml:"field_load_timeout" env:"ELASTIC_VIEW_FIELD_LOAD_TIMEOUT"`
+	SearchRefreshTimeout time.Duration `json:"search_refresh_timeout" yaml:"search_refresh_timeout" env:"ELASTIC_VIEW_SEARCH_REFRESH_TIMEOUT"`
+	FieldInitTimeout     time.Duration `json:"field_init_timeout" yaml:"field_init_timeout" env:"ELASTIC_VIEW_FIELD_INIT_TIMEOUT"`
 }
 
 // RateLimitConfig contains rate limiting settings
 type RateLimitConfig struct {
-	MaxConcurrentOps  int           `json:"max_concurrent_ops" env:"ELASTIC_VIEW_MAX_CONCURRENT_OPS"`
-	InitialRetryDelay time.Duration `json:"initial_retry_delay" env:"ELASTIC_VIEW_INITIAL_RETRY_DELAY"`
-	MaxRetryDelay     time.Duration `json:"max_retry_delay" env:"ELASTIC_VIEW_MAX_RETRY_DELAY"`
-	RetryMultiplier   float64       `json:"retry_multiplier" env:"ELASTIC_VIEW_RETRY_MULTIPLIER"`
+	MaxConcurrentOps  int           `json:"max_concurrent_ops" yaml:"max_concurrent_ops" env:"ELASTIC_VIEW_MAX_CONCURRENT_OPS"`
+	InitialRetryDelay time.Duration `json:"initial_retry_delay" yaml:"initial_retry_delay" env:"ELASTIC_VIEW_INITIAL_RETRY_DELAY"`
+	MaxRetryDelay     time.Duration `json:"max_retry_delay" yaml:"max_retry_delay" env:"ELASTIC_VIEW_MAX_RETRY_DELAY"`
+	RetryMultiplier   float64       `json:"retry_multiplier" yaml:"retry_multiplier" env:"ELASTIC_VIEW_RETRY_MULTIPLIER"`
 }
 
 // FieldConfig contains field management settings
 type FieldConfig struct {
-	CacheTimeout      time.Duration `json:"cache_timeout" env:"ELASTIC_VIEW_FIELD_CACHE_TIMEOUT"`
-	MaxCachedFields   int           `json:"max_cached_fields" env:"ELASTIC_VIEW_MAX_CACHED_FIELDS"`
-	DefaultFieldOrder []string      `json:"default_field_order"`
-	AutoSelectFields  []string      `json:"auto_select_fields"`
+	CacheTimeout      time.Duration `json:"cache_timeout" yaml:"cache_timeout" env:"ELASTIC_VIEW_FIELD_CACHE_TIMEOUT"`
+	MaxCachedFields   int           `json:"max_cached_fields" yaml:"max_cached_fields" env:"ELASTIC_VIEW_MAX_CACHED_FIELDS"`
+	DefaultFieldOrder []string      `json:"default_field_order" yaml:"default_field_order"`
+	AutoSelectFields  []string      `json:"auto_select_fields" yaml:"auto_select_fields"`
 }
 
 // ConfigManager handles loading and validation of configuration
@@ -93,6 +95,12 @@ func NewConfigManager() *ConfigManager {
 	}
 }
 
+// NewConfigManagerFrom creates a configuration manager over settings read elsewhere, such as
+// config.yaml. They are not validated until LoadFromEnvironment or Validate.
+func NewConfigManagerFrom(config *ElasticViewConfig) *ConfigManager {
+	return &ConfigManager{config: config}
+}
+
 // getDefaultConfig returns the default configuration values
 func getDefaultConfig() *ElasticViewConfig {
 	return &ElasticViewConfig{
@@ -102,6 +110,7 @@ func getDefaultConfig() *ElasticViewConfig {
 			MinPageSize:     10,
 		},
 		Search: SearchConfig{
+			DefaultIndex:      "main-summary-*",
 			DefaultTimeframe:  "today",
 			ValidTimeframes:   []string{"today", "yesterday", "week", "month", "quarter", "year", "hour", "day"},
 			DefaultNumResults: 1000,
@@ -172,6 +181,10 @@ func (cm *ConfigManager) LoadFromEnvironment() error {
 	}
 
 	// Load search settings
+	if val, exists := os.LookupEnv("ELASTIC_VIEW_DEFAULT_INDEX"); exists {
+		config.Search.DefaultIndex = val
+	}
+
 	if val, exists := os.LookupEnv("ELASTIC_VIEW_DEFAULT_TIMEFRAME"); exists {
 		config.Search.DefaultTimeframe = val
 	}
@@ -313,6 +326,9 @@ func (cm *ConfigManager) Validate() error {
 	}
 
 	// Validate search settings
+	if config.Search.DefaultIndex == "" {
+		return fmt.Errorf("search.default_index must not be empty")
+	}
 	if config.Search.DefaultNumResults < 1 {
 		return fmt.Errorf("search.default_num_results must be >= 1, got %d", config.Search.DefaultNumResults)
 	}
@@ -410,29 +426,40 @@ func (cm *ConfigManager) ShouldUseScrollAPI(numResults int) bool {
 }
 
 // Global configuration instance
-var globalConfigManager *ConfigManager
+var (
+	globalConfigMu      sync.RWMutex
+	globalConfigManager *ConfigManager
+)
 
 // InitializeConfig initializes the global configuration
 func InitializeConfig() error {
+	globalConfigMu.Lock()
+	defer globalConfigMu.Unlock()
 	globalConfigManager = NewConfigManager()
 	return globalConfigManager.LoadFromEnvironment()
 }
 
+// SetGlobalConfigManager replaces the global configuration, as when config.yaml is reloaded.
+// Views created afterwards start from the new settings.
+func SetGlobalConfigManager(cm *ConfigManager) {
+	globalConfigMu.Lock()
+	defer globalConfigMu.Unlock()
+	globalConfigManager = cm
+}
+
 // GetGlobalConfig returns the global configuration instance
 func GetGlobalConfig() *ElasticViewConfig {
-	if globalConfigManager == nil {
-		// Initialize with defaults if not already initialized
-		globalConfigManager = NewConfigManager()
-		_ = globalConfigManager.LoadFromEnvironment() // Ignore errors for default initialization
-	}
-	return globalConfigManager.GetConfig()
+	return GetGlobalConfigManager().GetConfig()
 }
 
 // GetGlobalConfigManager returns the global configuration manager
 func GetGlobalConfigManager() *ConfigManager {
+	globalConfigMu.Lock()
+	defer globalConfigMu.Unlock()
 	if globalConfigManager == nil {
+		// Initialize with defaults if not already initialized
 		globalConfigManager = NewConfigManager()
-		_ = globalConfigManager.LoadFromEnvironment()
+		_ = globalConfigManager.LoadFromEnvironment() // Ignore errors for default initialization
 	}
 	return globalConfigManager
 }
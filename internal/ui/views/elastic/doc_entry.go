@@ -11,12 +11,15 @@ import (
 
 // DocEntry represents a single document entry with metadata and dynamic fields.
 type DocEntry struct {
-	data    map[string]any // The main document data
-	ID      string         `json:"_id"`      // Document ID
-	Index   string         `json:"_index"`   // Index name
-	Type    string         `json:"_type"`    // Document type
-	Score   *float64       `json:"_score"`   // Relevance score (optional)
-	Version *int64         `json:"_version"` // Document version (optional)
+	data        map[string]any      // The main document data
+	highlights  map[string][]string // Highlight fragments keyed by field (optional)
+	ID          string              `json:"_id"`           // Document ID
+	Index       string              `json:"_index"`        // Index name
+	Type        string              `json:"_type"`         // Document type
+	Score       *float64            `json:"_score"`        // Relevance score (optional)
+	Version     *int64              `json:"_version"`      // Document version (optional)
+	SeqNo       *int64              `json:"_seq_no"`       // Sequence number of the last write (optional)
+	PrimaryTerm *int64              `json:"_primary_term"` // Primary term of the last write (optional)
 }
 
 // NewDocEntry creates a new DocEntry instance by unmarshalling the source data and setting metadata fields.
@@ -36,6 +39,58 @@ func NewDocEntry(source []byte, id, index, docType string, score *float64, versi
 	}, nil
 }
 
+// SetHighlights stores the highlight fragments returned by Elasticsearch for the document.
+func (de *DocEntry) SetHighlights(highlights map[string][]string) {
+	de.highlights = highlights
+}
+
+// GetHighlights returns the highlight fragments for a field, or nil if the field had no matches.
+func (de *DocEntry) GetHighlights(field string) []string {
+	return de.highlights[field]
+}
+
+// HasHighlights reports whether the document carries any highlight fragments.
+func (de *DocEntry) HasHighlights() bool {
+	return len(de.highlights) > 0
+}
+
+// HighlightTerms returns the distinct matched terms found between highlight tags, sorted longest first.
+func (de *DocEntry) HighlightTerms() []string {
+	seen := make(map[string]struct{})
+	var terms []string
+
+	for _, fragments := range de.highlights {
+		for _, fragment := range fragments {
+			rest := fragment
+			for {
+				start := strings.Index(rest, HighlightPreTag)
+				if start < 0 {
+					break
+				}
+				rest = rest[start+len(HighlightPreTag):]
+				end := strings.Index(rest, HighlightPostTag)
+				if end < 0 {
+					break
+				}
+				term := rest[:end]
+				rest = rest[end+len(HighlightPostTag):]
+
+				key := strings.ToLower(term)
+				if _, ok := seen[key]; ok || term == "" {
+					continue
+				}
+				seen[key] = struct{}{}
+				terms = append(terms, term)
+			}
+		}
+	}
+
+	sort.SliceStable(terms, func(i, j int) bool {
+		return len(terms[i]) > len(terms[j])
+	})
+	return terms
+}
+
 // GetMetadataFields returns a list of metadata fields available for the document.
 func (de *DocEntry) GetMetadataFields() []string {
 	fields := []string{"_id", "_index", "_type"}
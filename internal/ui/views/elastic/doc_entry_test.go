@@ -199,3 +199,35 @@ func TestDocEntry_ConcurrentAccess(t *testing.T) {
 
 	wg.Wait()
 }
+
+func TestDocEntry_HighlightTerms(t *testing.T) {
+	doc, err := elastic.NewDocEntry([]byte(`{"message":"Disk Full on host"}`), "1", "index", "type", nil, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if doc.HasHighlights() {
+		t.Error("expected no highlights on a new entry")
+	}
+
+	doc.SetHighlights(map[string][]string{
+		"message": {elastic.HighlightPreTag + "Disk" + elastic.HighlightPostTag + " " +
+			elastic.HighlightPreTag + "Full" + elastic.HighlightPostTag + " on host"},
+		"host": {elastic.HighlightPreTag + "disk" + elastic.HighlightPostTag + "-01"},
+	})
+
+	if !doc.HasHighlights() {
+		t.Error("expected highlights after SetHighlights")
+	}
+	if got := doc.GetHighlights("message"); len(got) != 1 {
+		t.Errorf("GetHighlights(message) = %v, want 1 fragment", got)
+	}
+	if got := doc.GetHighlights("missing"); got != nil {
+		t.Errorf("GetHighlights(missing) = %v, want nil", got)
+	}
+
+	terms := doc.HighlightTerms()
+	if len(terms) != 2 {
+		t.Fatalf("HighlightTerms() = %v, want 2 distinct terms", terms)
+	}
+}
@@ -23,9 +23,11 @@ type EnhancedJSONModal struct {
 	container *tview.Flex
 
 	// Modal state
-	jsonContent string
-	lines       []string
-	mode        ModalMode
+	jsonContent    string
+	lines          []string
+	mode           ModalMode
+	highlightTerms []string
+	entry          *DocEntry
 
 	// Navigation state
 	cursorX int
@@ -95,9 +97,9 @@ func (m *EnhancedJSONModal) setupUI() {
 	m.textView = tview.NewTextView()
 	m.textView.SetBorder(true)
 	m.textView.SetTitle(" JSON Document - Vim Mode ")
-	m.textView.SetTitleColor(style.GruvboxMaterial.Foreground)
-	m.textView.SetBorderColor(tcell.ColorMediumTurquoise)
-	m.textView.SetTextColor(style.GruvboxMaterial.Foreground)
+	m.textView.SetTitleColor(style.Color(style.Title))
+	m.textView.SetBorderColor(style.Color(style.BorderFocus))
+	m.textView.SetTextColor(style.Color(style.Text))
 	m.textView.SetDynamicColors(true)
 	m.textView.SetRegions(true)
 	m.textView.SetScrollable(true)
@@ -106,8 +108,8 @@ func (m *EnhancedJSONModal) setupUI() {
 	// Status bar showing current mode and shortcuts
 	m.statusBar = tview.NewTextView()
 	m.statusBar.SetBorder(false)
-	m.statusBar.SetBackgroundColor(tcell.ColorDarkSlateGray)
-	m.statusBar.SetTextColor(tcell.ColorWhite)
+	m.statusBar.SetBackgroundColor(style.Color(style.InputBackground))
+	m.statusBar.SetTextColor(style.Color(style.StatusText))
 	m.statusBar.SetDynamicColors(true)
 	m.updateStatusBar()
 
@@ -139,12 +141,14 @@ func (m *EnhancedJSONModal) ShowJSON(entry *DocEntry) {
 
 	prettyJSON, err := json.MarshalIndent(data, "", "  ")
 	if err != nil {
-		m.view.manager.UpdateStatusBar(fmt.Sprintf("Error formatting JSON: %v", err))
+		m.view.manager.NotifyError(m.view.Name(), fmt.Sprintf("Error formatting JSON: %v", err))
 		return
 	}
 
 	m.jsonContent = string(prettyJSON)
 	m.lines = strings.Split(m.jsonContent, "\n")
+	m.highlightTerms = entry.HighlightTerms()
+	m.entry = entry
 	m.updateDisplay()
 
 	// Create modal grid with dynamic sizing
@@ -278,6 +282,13 @@ func (m *EnhancedJSONModal) handleNormalMode(event *tcell.EventKey) *tcell.Event
 		// JSON-specific actions
 		case 'c':
 			m.copyJSONValue()
+		case 'H':
+			m.view.toggleHighlighting()
+		case 'e':
+			m.closeModal()
+			entry := m.entry
+			m.view.manager.GuardMutation("Edit document", func() { m.view.showDocumentEditor(entry) })
+			return nil
 		case 'f':
 			m.formatAndCopy()
 		}
@@ -536,9 +547,9 @@ func (m *EnhancedJSONModal) executeSearch(searchTerm string) {
 		firstMatch := m.searchMatches[0]
 		m.cursorY = firstMatch.line
 		m.cursorX = firstMatch.start
-		m.view.manager.UpdateStatusBar(fmt.Sprintf("Found %d matches", len(m.searchMatches)))
+		m.view.manager.NotifyInfo(m.view.Name(), fmt.Sprintf("Found %d matches", len(m.searchMatches)))
 	} else {
-		m.view.manager.UpdateStatusBar(fmt.Sprintf("Pattern not found: %s", searchTerm))
+		m.view.manager.NotifyInfo(m.view.Name(), fmt.Sprintf("Pattern not found: %s", searchTerm))
 	}
 
 	// Exit search mode and return to normal mode
@@ -551,7 +562,7 @@ func (m *EnhancedJSONModal) executeSearch(searchTerm string) {
 // nextSearchMatch navigates to the next search match
 func (m *EnhancedJSONModal) nextSearchMatch() {
 	if len(m.searchMatches) == 0 {
-		m.view.manager.UpdateStatusBar("No search matches")
+		m.view.manager.NotifyInfo(m.view.Name(), "No search matches")
 		return
 	}
 
@@ -559,13 +570,13 @@ func (m *EnhancedJSONModal) nextSearchMatch() {
 	match := m.searchMatches[m.currentMatch]
 	m.cursorY = match.line
 	m.cursorX = match.start
-	m.view.manager.UpdateStatusBar(fmt.Sprintf("Match %d of %d: %s", m.currentMatch+1, len(m.searchMatches), m.searchTerm))
+	m.view.manager.NotifyInfo(m.view.Name(), fmt.Sprintf("Match %d of %d: %s", m.currentMatch+1, len(m.searchMatches), m.searchTerm))
 }
 
 // previousSearchMatch navigates to the previous search match
 func (m *EnhancedJSONModal) previousSearchMatch() {
 	if len(m.searchMatches) == 0 {
-		m.view.manager.UpdateStatusBar("No search matches")
+		m.view.manager.NotifyInfo(m.view.Name(), "No search matches")
 		return
 	}
 
@@ -573,25 +584,25 @@ func (m *EnhancedJSONModal) previousSearchMatch() {
 	match := m.searchMatches[m.currentMatch]
 	m.cursorY = match.line
 	m.cursorX = match.start
-	m.view.manager.UpdateStatusBar(fmt.Sprintf("Match %d of %d: %s", m.currentMatch+1, len(m.searchMatches), m.searchTerm))
+	m.view.manager.NotifyInfo(m.view.Name(), fmt.Sprintf("Match %d of %d: %s", m.currentMatch+1, len(m.searchMatches), m.searchTerm))
 }
 
 // Copy operations
 func (m *EnhancedJSONModal) copyCurrentLine() {
 	if m.cursorY < len(m.lines) {
 		if err := clipboard.WriteAll(m.lines[m.cursorY]); err != nil {
-			m.view.manager.UpdateStatusBar("Failed to copy line to clipboard")
+			m.view.manager.NotifyError(m.view.Name(), "Failed to copy line to clipboard")
 		} else {
-			m.view.manager.UpdateStatusBar("Line copied to clipboard")
+			m.view.manager.NotifySuccess(m.view.Name(), "Line copied to clipboard")
 		}
 	}
 }
 
 func (m *EnhancedJSONModal) copyEntireDocument() {
 	if err := clipboard.WriteAll(m.jsonContent); err != nil {
-		m.view.manager.UpdateStatusBar("Failed to copy JSON to clipboard")
+		m.view.manager.NotifyError(m.view.Name(), "Failed to copy JSON to clipboard")
 	} else {
-		m.view.manager.UpdateStatusBar("JSON copied to clipboard")
+		m.view.manager.NotifySuccess(m.view.Name(), "JSON copied to clipboard")
 	}
 }
 
@@ -604,9 +615,9 @@ func (m *EnhancedJSONModal) copySelection() {
 	selectedText := m.getSelectedText()
 	if selectedText != "" {
 		if err := clipboard.WriteAll(selectedText); err != nil {
-			m.view.manager.UpdateStatusBar("Failed to copy selection to clipboard")
+			m.view.manager.NotifyError(m.view.Name(), "Failed to copy selection to clipboard")
 		} else {
-			m.view.manager.UpdateStatusBar("Selection copied to clipboard")
+			m.view.manager.NotifySuccess(m.view.Name(), "Selection copied to clipboard")
 		}
 	}
 }
@@ -617,9 +628,9 @@ func (m *EnhancedJSONModal) copyJSONValue() {
 	value := m.extractJSONValueAt(line, m.cursorX)
 	if value != "" {
 		if err := clipboard.WriteAll(value); err != nil {
-			m.view.manager.UpdateStatusBar("Failed to copy value to clipboard")
+			m.view.manager.NotifyError(m.view.Name(), "Failed to copy value to clipboard")
 		} else {
-			m.view.manager.UpdateStatusBar(fmt.Sprintf("Value copied: %s", value))
+			m.view.manager.NotifySuccess(m.view.Name(), fmt.Sprintf("Value copied: %s", value))
 		}
 	}
 }
@@ -756,6 +767,29 @@ func (m *EnhancedJSONModal) updateDisplay() {
 	m.updateStatusBar()
 }
 
+// colorizeLine applies JSON coloring and, when enabled, search-term highlighting to a line segment
+func (m *EnhancedJSONModal) colorizeLine(line string) string {
+	if m.view.state.ui.highlightEnabled && len(m.highlightTerms) > 0 {
+		line = highlightTerms(line, m.highlightTerms)
+	}
+	return style.ColorizeJSON(line)
+}
+
+// cursorTag colors the cursor in the current theme
+func cursorTag() string {
+	return fmt.Sprintf("[%s:%s]", style.Color(style.SelectionText), style.Color(style.Selection))
+}
+
+// selectionTag colors the visual-mode selection in the current theme
+func selectionTag() string {
+	return fmt.Sprintf("[%s:%s]", style.Color(style.HighlightText), style.Color(style.Highlight))
+}
+
+// selectionCursorTag colors the cursor inside the visual-mode selection in the current theme
+func selectionCursorTag() string {
+	return fmt.Sprintf("[%s:%s]", style.Color(style.Selection), style.Color(style.Highlight))
+}
+
 // buildTextWithCursor creates text with cursor highlighting (normal mode)
 func (m *EnhancedJSONModal) buildTextWithCursor() string {
 	var result strings.Builder
@@ -765,19 +799,19 @@ func (m *EnhancedJSONModal) buildTextWithCursor() string {
 			// Current line - highlight cursor position
 			if m.cursorX < len(line) {
 				// Cursor is on a character
-				result.WriteString(style.ColorizeJSON(line[:m.cursorX]))
-				result.WriteString("[white:blue]") // Cursor highlight - white text on blue background
+				result.WriteString(m.colorizeLine(line[:m.cursorX]))
+				result.WriteString(cursorTag()) // Cursor highlight
 				result.WriteString(string(line[m.cursorX]))
 				result.WriteString("[-:-]") // Reset colors
-				result.WriteString(style.ColorizeJSON(line[m.cursorX+1:]))
+				result.WriteString(m.colorizeLine(line[m.cursorX+1:]))
 			} else {
 				// Cursor is at end of line - show as space
-				result.WriteString(style.ColorizeJSON(line))
-				result.WriteString("[white:blue] [-:-]") // Highlighted space at end
+				result.WriteString(m.colorizeLine(line))
+				result.WriteString(cursorTag() + " [-:-]") // Highlighted space at end
 			}
 		} else {
 			// Other lines - normal coloring
-			result.WriteString(style.ColorizeJSON(line))
+			result.WriteString(m.colorizeLine(line))
 		}
 
 		if i < len(m.lines)-1 {
@@ -811,23 +845,23 @@ func (m *EnhancedJSONModal) buildTextWithSelectionAndCursor() string {
 			// Lines outside selection - show with cursor if applicable
 			if i == m.cursorY {
 				if m.cursorX < len(line) {
-					result.WriteString(style.ColorizeJSON(line[:m.cursorX]))
-					result.WriteString("[white:blue]")
+					result.WriteString(m.colorizeLine(line[:m.cursorX]))
+					result.WriteString(cursorTag())
 					result.WriteString(string(line[m.cursorX]))
 					result.WriteString("[-:-]")
-					result.WriteString(style.ColorizeJSON(line[m.cursorX+1:]))
+					result.WriteString(m.colorizeLine(line[m.cursorX+1:]))
 				} else {
-					result.WriteString(style.ColorizeJSON(line))
-					result.WriteString("[white:blue] [-:-]")
+					result.WriteString(m.colorizeLine(line))
+					result.WriteString(cursorTag() + " [-:-]")
 				}
 			} else {
-				result.WriteString(style.ColorizeJSON(line))
+				result.WriteString(m.colorizeLine(line))
 			}
 		} else if i == startY && i == endY {
 			// Single line selection
 			if startX < len(line) && endX <= len(line) {
-				result.WriteString(style.ColorizeJSON(line[:startX]))
-				result.WriteString("[black:yellow]") // Highlight selection
+				result.WriteString(m.colorizeLine(line[:startX]))
+				result.WriteString(selectionTag()) // Highlight selection
 
 				// Handle cursor within selection
 				if i == m.cursorY && m.cursorX >= startX && m.cursorX < endX {
@@ -835,9 +869,9 @@ func (m *EnhancedJSONModal) buildTextWithSelectionAndCursor() string {
 					if m.cursorX > startX {
 						result.WriteString(line[startX:m.cursorX])
 					}
-					result.WriteString("[blue:yellow]") // Cursor in selection - blue text on yellow
+					result.WriteString(selectionCursorTag()) // Cursor in selection
 					result.WriteString(string(line[m.cursorX]))
-					result.WriteString("[black:yellow]") // Back to selection color
+					result.WriteString(selectionTag()) // Back to selection color
 					if m.cursorX+1 < endX {
 						result.WriteString(line[m.cursorX+1 : endX])
 					}
@@ -850,34 +884,34 @@ func (m *EnhancedJSONModal) buildTextWithSelectionAndCursor() string {
 				// Handle cursor after selection
 				if i == m.cursorY && m.cursorX >= endX {
 					if m.cursorX < len(line) {
-						result.WriteString(style.ColorizeJSON(line[endX:m.cursorX]))
-						result.WriteString("[white:blue]")
+						result.WriteString(m.colorizeLine(line[endX:m.cursorX]))
+						result.WriteString(cursorTag())
 						result.WriteString(string(line[m.cursorX]))
 						result.WriteString("[-:-]")
-						result.WriteString(style.ColorizeJSON(line[m.cursorX+1:]))
+						result.WriteString(m.colorizeLine(line[m.cursorX+1:]))
 					} else {
-						result.WriteString(style.ColorizeJSON(line[endX:]))
-						result.WriteString("[white:blue] [-:-]")
+						result.WriteString(m.colorizeLine(line[endX:]))
+						result.WriteString(cursorTag() + " [-:-]")
 					}
 				} else {
-					result.WriteString(style.ColorizeJSON(line[endX:]))
+					result.WriteString(m.colorizeLine(line[endX:]))
 				}
 			} else {
-				result.WriteString(style.ColorizeJSON(line))
+				result.WriteString(m.colorizeLine(line))
 			}
 		} else if i == startY {
 			// First line of multi-line selection
-			result.WriteString(style.ColorizeJSON(line[:startX]))
-			result.WriteString("[black:yellow]")
+			result.WriteString(m.colorizeLine(line[:startX]))
+			result.WriteString(selectionTag())
 
 			// Handle cursor in first line
 			if i == m.cursorY && m.cursorX >= startX {
 				if m.cursorX > startX {
 					result.WriteString(line[startX:m.cursorX])
 				}
-				result.WriteString("[blue:yellow]")
+				result.WriteString(selectionCursorTag())
 				result.WriteString(string(line[m.cursorX]))
-				result.WriteString("[black:yellow]")
+				result.WriteString(selectionTag())
 				if m.cursorX+1 < len(line) {
 					result.WriteString(line[m.cursorX+1:])
 				}
@@ -888,16 +922,16 @@ func (m *EnhancedJSONModal) buildTextWithSelectionAndCursor() string {
 			result.WriteString("[-:-]")
 		} else if i == endY {
 			// Last line of multi-line selection
-			result.WriteString("[black:yellow]")
+			result.WriteString(selectionTag())
 
 			// Handle cursor in last line
 			if i == m.cursorY && m.cursorX < endX {
 				if m.cursorX > 0 {
 					result.WriteString(line[:m.cursorX])
 				}
-				result.WriteString("[blue:yellow]")
+				result.WriteString(selectionCursorTag())
 				result.WriteString(string(line[m.cursorX]))
-				result.WriteString("[black:yellow]")
+				result.WriteString(selectionTag())
 				if m.cursorX+1 < endX {
 					result.WriteString(line[m.cursorX+1 : endX])
 				}
@@ -912,21 +946,21 @@ func (m *EnhancedJSONModal) buildTextWithSelectionAndCursor() string {
 			// Handle cursor after selection on last line
 			if i == m.cursorY && m.cursorX >= endX {
 				if m.cursorX < len(line) {
-					result.WriteString(style.ColorizeJSON(line[endX:m.cursorX]))
-					result.WriteString("[white:blue]")
+					result.WriteString(m.colorizeLine(line[endX:m.cursorX]))
+					result.WriteString(cursorTag())
 					result.WriteString(string(line[m.cursorX]))
 					result.WriteString("[-:-]")
-					result.WriteString(style.ColorizeJSON(line[m.cursorX+1:]))
+					result.WriteString(m.colorizeLine(line[m.cursorX+1:]))
 				} else {
-					result.WriteString(style.ColorizeJSON(line[endX:]))
-					result.WriteString("[white:blue] [-:-]")
+					result.WriteString(m.colorizeLine(line[endX:]))
+					result.WriteString(cursorTag() + " [-:-]")
 				}
 			} else {
-				result.WriteString(style.ColorizeJSON(line[endX:]))
+				result.WriteString(m.colorizeLine(line[endX:]))
 			}
 		} else {
 			// Middle lines of selection - fully highlighted
-			result.WriteString("[black:yellow]")
+			result.WriteString(selectionTag())
 
 			// Handle cursor in middle lines
 			if i == m.cursorY {
@@ -934,9 +968,9 @@ func (m *EnhancedJSONModal) buildTextWithSelectionAndCursor() string {
 					if m.cursorX > 0 {
 						result.WriteString(line[:m.cursorX])
 					}
-					result.WriteString("[blue:yellow]")
+					result.WriteString(selectionCursorTag())
 					result.WriteString(string(line[m.cursorX]))
-					result.WriteString("[black:yellow]")
+					result.WriteString(selectionTag())
 					if m.cursorX+1 < len(line) {
 						result.WriteString(line[m.cursorX+1:])
 					}
@@ -1009,17 +1043,18 @@ func (m *EnhancedJSONModal) scrollToShowCursor() {
 
 func (m *EnhancedJSONModal) updateStatusBar() {
 	var status string
+	text := style.Tag(style.Text)
 	switch m.mode {
 	case ModeNormal:
-		status = "[white]NORMAL[white] | hjkl:move | v:visual | /:search | n/N:next/prev | y:copy line | Y:copy all | c:copy value | r:resize | q:quit"
+		status = text + "NORMAL | hjkl:move | v:visual | /:search | n/N:next/prev | y:copy line | Y:copy all | c:copy value | H:highlight | e:edit | r:resize | q:quit"
 	case ModeVisual:
-		status = "[yellow]VISUAL[white] | hjkl:extend | y:copy selection | Esc:exit"
+		status = style.Tag(style.Warning) + "VISUAL" + text + " | hjkl:extend | y:copy selection | Esc:exit"
 	case ModeResize:
-		status = "[green]RESIZE[white] | hjkl:resize | r:reset | q:exit resize"
+		status = style.Tag(style.Success) + "RESIZE" + text + " | hjkl:resize | r:reset | q:exit resize"
 	case ModeSearch:
-		status = fmt.Sprintf("[blue]SEARCH[white] | /%s | Esc:exit", m.searchBuffer)
+		status = fmt.Sprintf("%sSEARCH%s | /%s | Esc:exit", style.Tag(style.Label), text, m.searchBuffer)
 	case ModeCommand:
-		status = "[red]COMMAND[white] | Esc:exit"
+		status = style.Tag(style.StatusError) + "COMMAND" + text + " | Esc:exit"
 	}
 
 	status += fmt.Sprintf(" | Pos: %d,%d | Size: %dx%d", m.cursorX, m.cursorY, m.width, m.height)
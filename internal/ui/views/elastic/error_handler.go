@@ -6,6 +6,8 @@ import (
 	"runtime"
 	"strings"
 	"time"
+
+	"github.com/tpelletiersophos/cloudcutter/internal/diagnostics"
 )
 
 // ErrorCode represents different types of errors in the elastic view
@@ -364,8 +366,7 @@ func (eh *ErrorHandler) logError(err *ElasticViewError) {
 
 // recordMetrics records error metrics for monitoring
 func (eh *ErrorHandler) recordMetrics(err *ElasticViewError) {
-	// TODO: Implement metrics recording
-	// This could integrate with Prometheus, StatsD, or other metrics systems
+	diagnostics.RecordError(string(err.Code))
 }
 
 // attemptRecovery attempts to recover from recoverable errors
@@ -0,0 +1,390 @@
+package elastic
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"github.com/tpelletiersophos/cloudcutter/internal/ui/common"
+	"github.com/tpelletiersophos/cloudcutter/internal/ui/style"
+)
+
+const (
+	ModalFieldStats = "modalFieldStats"
+
+	// fieldStatsTopValues is the number of buckets requested by the terms aggregation
+	fieldStatsTopValues = 20
+)
+
+// FieldValueCount is a single bucket of a terms aggregation.
+type FieldValueCount struct {
+	Value string
+	Count int64
+}
+
+// FieldStats holds the value distribution of a field over the current query and timeframe.
+type FieldStats struct {
+	Field     string // Field the aggregations ran against (may be a .keyword sub-field)
+	Type      string
+	Numeric   bool
+	TotalHits int
+
+	// Keyword/text fields
+	TopValues   []FieldValueCount
+	OtherCount  int64
+	Cardinality int64
+
+	// Numeric and date fields
+	Count       int64
+	Min         *float64
+	Max         *float64
+	Avg         *float64
+	MinAsString string
+	MaxAsString string
+	AvgAsString string
+
+	Missing int64
+}
+
+// ResolveAggregatableField returns the field to aggregate on, falling back to the .keyword
+// sub-field for analyzed text fields.
+func ResolveAggregatableField(field string, fieldCache *FieldCache) (string, *FieldMetadata, error) {
+	metadata, exists := fieldCache.Get(field)
+	if !exists {
+		return "", nil, &ParseError{Field: field, Message: "no metadata available for field"}
+	}
+	if metadata.Aggregatable {
+		return field, metadata, nil
+	}
+
+	keywordField := field + ".keyword"
+	if keywordMeta, ok := fieldCache.Get(keywordField); ok && keywordMeta.Aggregatable {
+		return keywordField, keywordMeta, nil
+	}
+
+	return "", nil, &ParseError{Field: field, Message: "field is not aggregatable"}
+}
+
+// BuildFieldStatsQuery builds a size-0 search over the current filters and timeframe that
+// aggregates the value distribution of field.
+func BuildFieldStatsQuery(filters []string, timeframe, field string, fieldCache *FieldCache) (map[string]any, *FieldStats, error) {
+	aggField, metadata, err := ResolveAggregatableField(field, fieldCache)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	query, err := BuildQuery(filters, 0, timeframe, fieldCache)
+	if err != nil {
+		return nil, nil, err
+	}
+	delete(query, "highlight")
+
+	stats := &FieldStats{
+		Field:   aggField,
+		Type:    metadata.Type,
+		Numeric: isNumericFieldType(metadata.Type) || metadata.Type == "date",
+	}
+
+	aggs := map[string]any{
+		"missing": map[string]any{
+			"missing": map[string]any{"field": aggField},
+		},
+	}
+	if stats.Numeric {
+		aggs["stats"] = map[string]any{
+			"stats": map[string]any{"field": aggField},
+		}
+	} else {
+		aggs["top_values"] = map[string]any{
+			"terms": map[string]any{
+				"field": aggField,
+				"size":  fieldStatsTopValues,
+			},
+		}
+		aggs["cardinality"] = map[string]any{
+			"cardinality": map[string]any{"field": aggField},
+		}
+	}
+	query["aggs"] = aggs
+
+	return query, stats, nil
+}
+
+// ParseFieldStatsAggregations fills stats from the aggregations section of a search response.
+func ParseFieldStatsAggregations(data json.RawMessage, stats *FieldStats) error {
+	if len(data) == 0 {
+		return fmt.Errorf("response contained no aggregations")
+	}
+
+	var aggs struct {
+		TopValues *struct {
+			SumOtherDocCount int64 `json:"sum_other_doc_count"`
+			Buckets          []struct {
+				Key         any    `json:"key"`
+				KeyAsString string `json:"key_as_string"`
+				DocCount    int64  `json:"doc_count"`
+			} `json:"buckets"`
+		} `json:"top_values"`
+		Cardinality *struct {
+			Value int64 `json:"value"`
+		} `json:"cardinality"`
+		Missing *struct {
+			DocCount int64 `json:"doc_count"`
+		} `json:"missing"`
+		Stats *struct {
+			Count       int64    `json:"count"`
+			Min         *float64 `json:"min"`
+			Max         *float64 `json:"max"`
+			Avg         *float64 `json:"avg"`
+			MinAsString string   `json:"min_as_string"`
+			MaxAsString string   `json:"max_as_string"`
+			AvgAsString string   `json:"avg_as_string"`
+		} `json:"stats"`
+	}
+	if err := json.Unmarshal(data, &aggs); err != nil {
+		return fmt.Errorf("error decoding aggregations: %v", err)
+	}
+
+	if aggs.Missing != nil {
+		stats.Missing = aggs.Missing.DocCount
+	}
+	if aggs.Cardinality != nil {
+		stats.Cardinality = aggs.Cardinality.Value
+	}
+	if aggs.TopValues != nil {
+		stats.OtherCount = aggs.TopValues.SumOtherDocCount
+		for _, bucket := range aggs.TopValues.Buckets {
+			value := bucket.KeyAsString
+			if value == "" {
+				value = formatBucketKey(bucket.Key)
+			}
+			stats.TopValues = append(stats.TopValues, FieldValueCount{Value: value, Count: bucket.DocCount})
+		}
+	}
+	if aggs.Stats != nil {
+		stats.Count = aggs.Stats.Count
+		stats.Min = aggs.Stats.Min
+		stats.Max = aggs.Stats.Max
+		stats.Avg = aggs.Stats.Avg
+		stats.MinAsString = aggs.Stats.MinAsString
+		stats.MaxAsString = aggs.Stats.MaxAsString
+		stats.AvgAsString = aggs.Stats.AvgAsString
+	}
+
+	return nil
+}
+
+// FilterForValue builds a filter expression matching value exactly, escaping wildcard characters.
+func FilterForValue(field, value string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, `*`, `\*`, `?`, `\?`)
+	return fmt.Sprintf("%s=%s", field, replacer.Replace(value))
+}
+
+func formatBucketKey(key any) string {
+	switch k := key.(type) {
+	case float64:
+		return strconv.FormatFloat(k, 'f', -1, 64)
+	case string:
+		return k
+	case nil:
+		return ""
+	default:
+		return fmt.Sprintf("%v", k)
+	}
+}
+
+func formatStat(value *float64, asString string) string {
+	if asString != "" {
+		return asString
+	}
+	if value == nil {
+		return "-"
+	}
+	return strconv.FormatFloat(*value, 'f', -1, 64)
+}
+
+// showFieldStats fetches the value distribution of field and displays it in a popup.
+func (v *View) showFieldStats(field string) {
+	v.state.mu.RLock()
+	filters := make([]string, len(v.state.data.filters))
+	copy(filters, v.state.data.filters)
+	timeframe := v.state.search.timeframe
+	v.state.mu.RUnlock()
+
+	query, stats, err := BuildFieldStatsQuery(filters, timeframe, field, v.state.data.fieldCache)
+	if err != nil {
+		v.manager.NotifyError(v.Name(), fmt.Sprintf("Cannot show stats for %s: %v", field, err))
+		return
+	}
+
+	v.showLoading(fmt.Sprintf("Loading stats for %s", field))
+
+	go func() {
+		defer v.hideLoading()
+
+		result, err := v.executeSearch(query)
+		if err == nil {
+			err = ParseFieldStatsAggregations(result.Aggregations, stats)
+		}
+		if err != nil {
+			v.manager.Logger().Error("Failed to load field stats", "field", field, "error", err)
+			v.manager.App().QueueUpdateDraw(func() {
+				v.manager.NotifyError(v.Name(), fmt.Sprintf("Error loading stats for %s: %v", field, err))
+			})
+			return
+		}
+		stats.TotalHits = result.Hits.Total.Value
+
+		v.manager.App().QueueUpdateDraw(func() {
+			v.displayFieldStats(stats)
+		})
+	}()
+}
+
+func (v *View) displayFieldStats(stats *FieldStats) {
+	summary := tview.NewTextView().SetDynamicColors(true)
+	table := tview.NewTable().
+		SetSelectable(!stats.Numeric, false).
+		SetSelectedStyle(tcell.StyleDefault.
+			Foreground(style.Color(style.SelectionText)).
+			Background(style.Color(style.Selection)))
+
+	headerCell := func(text string) *tview.TableCell {
+		return tview.NewTableCell(text).
+			SetTextColor(style.Color(style.Title)).
+			SetAttributes(tcell.AttrBold).
+			SetSelectable(false)
+	}
+
+	label, value := style.Tag(style.Label), style.Tag(style.Text)
+	if stats.Numeric {
+		summary.SetText(fmt.Sprintf(" %sType:%s %s  %sHits:%s %d  %sMissing:%s %d",
+			label, value, stats.Type, label, value, stats.TotalHits, label, value, stats.Missing))
+
+		table.SetCell(0, 0, headerCell("Statistic"))
+		table.SetCell(0, 1, headerCell("Value"))
+		rows := [][2]string{
+			{"Count", strconv.FormatInt(stats.Count, 10)},
+			{"Min", formatStat(stats.Min, stats.MinAsString)},
+			{"Max", formatStat(stats.Max, stats.MaxAsString)},
+			{"Avg", formatStat(stats.Avg, stats.AvgAsString)},
+		}
+		for i, row := range rows {
+			table.SetCell(i+1, 0, tview.NewTableCell(row[0]).SetTextColor(style.Color(style.Label)))
+			table.SetCell(i+1, 1, tview.NewTableCell(row[1]).SetTextColor(style.Color(style.Text)).SetExpansion(1))
+		}
+	} else {
+		summary.SetText(fmt.Sprintf(" %sType:%s %s  %sHits:%s %d  %sDistinct:%s ~%d  %sMissing:%s %d  %sOther:%s %d",
+			label, value, stats.Type, label, value, stats.TotalHits, label, value, stats.Cardinality,
+			label, value, stats.Missing, label, value, stats.OtherCount))
+
+		table.SetCell(0, 0, headerCell("Value"))
+		table.SetCell(0, 1, headerCell("Count"))
+		table.SetCell(0, 2, headerCell("%"))
+		for i, bucket := range stats.TopValues {
+			percent := 0.0
+			if stats.TotalHits > 0 {
+				percent = float64(bucket.Count) / float64(stats.TotalHits) * 100
+			}
+			table.SetCell(i+1, 0, tview.NewTableCell(tview.Escape(bucket.Value)).
+				SetTextColor(style.Color(style.Text)).
+				SetExpansion(1).
+				SetMaxWidth(60))
+			table.SetCell(i+1, 1, tview.NewTableCell(strconv.FormatInt(bucket.Count, 10)).
+				SetTextColor(style.Color(style.Label)).
+				SetAlign(tview.AlignRight))
+			table.SetCell(i+1, 2, tview.NewTableCell(fmt.Sprintf("%.1f", percent)).
+				SetTextColor(style.Color(style.Muted)).
+				SetAlign(tview.AlignRight))
+		}
+		if len(stats.TopValues) == 0 {
+			table.SetCell(1, 0, tview.NewTableCell("No values found").SetTextColor(style.Color(style.Muted)).SetSelectable(false))
+		} else {
+			table.Select(1, 0)
+		}
+
+	}
+
+	hints := []keyHint{{"close", "close"}}
+	if !stats.Numeric {
+		hints = append([]keyHint{{"add_filter", "add filter for value"}}, hints...)
+	}
+	footer := newKeyHints("elastic.field_stats", hints...)
+
+	container := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(summary, 1, 0, false).
+		AddItem(table, 0, 1, true).
+		AddItem(footer, 1, 0, false)
+	container.SetBorder(true).
+		SetTitle(fmt.Sprintf(" Field Stats: %s ", stats.Field)).
+		SetTitleColor(style.Color(style.Title)).
+		SetBorderColor(style.Color(style.BorderFocus))
+
+	height := table.GetRowCount() + 5
+	if height > 30 {
+		height = 30
+	}
+
+	modal := tview.NewFlex().
+		SetDirection(tview.FlexRow).
+		AddItem(nil, 0, 1, false).
+		AddItem(tview.NewFlex().
+			AddItem(nil, 0, 1, false).
+			AddItem(container, 90, 0, true).
+			AddItem(nil, 0, 1, false),
+			height, 0, true).
+		AddItem(nil, 0, 1, false)
+
+	pages := v.manager.Pages()
+	pages.RemovePage(ModalFieldStats)
+	pages.AddPage(ModalFieldStats, modal, true, true)
+
+	keys := common.NewKeyResolver()
+	var restore func()
+	restore = v.captureModalInput(ModalFieldStats, func(event *tcell.EventKey) *tcell.EventKey {
+		action, pending := keys.Resolve("elastic.field_stats", event)
+		switch {
+		case pending:
+		case action == "close":
+			restore()
+			v.hideFieldStats()
+		case action == "add_filter" && !stats.Numeric:
+			row, _ := table.GetSelection()
+			if row <= 0 || row > len(stats.TopValues) {
+				break
+			}
+			if v.applyFieldStatsFilter(stats.Field, stats.TopValues[row-1].Value) {
+				restore()
+				v.hideFieldStats()
+				v.refreshWithCurrentTimeframe()
+			}
+		default:
+			return event
+		}
+		return nil
+	})
+	v.manager.App().SetFocus(table)
+}
+
+func (v *View) hideFieldStats() {
+	v.manager.Pages().RemovePage(ModalFieldStats)
+	v.manager.SetFocus(v.components.fieldList)
+}
+
+// applyFieldStatsFilter adds a filter for field being value, reporting whether it could.
+func (v *View) applyFieldStatsFilter(field, value string) bool {
+	filter := FilterForValue(field, value)
+
+	v.state.mu.Lock()
+	if _, err := ParseFilter(filter, v.state.data.fieldCache); err != nil {
+		v.state.mu.Unlock()
+		v.manager.NotifyError(v.Name(), fmt.Sprintf("Cannot filter on value: %v", err))
+		return false
+	}
+	v.addFilter(filter)
+	v.state.mu.Unlock()
+	return true
+}
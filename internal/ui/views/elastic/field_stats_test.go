@@ -0,0 +1,142 @@
+package elastic
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildFieldStatsQuery(t *testing.T) {
+	fieldCache := newTestFieldCache()
+	fieldCache.Set("message", &FieldMetadata{Type: "text", Searchable: true, Aggregatable: false})
+	fieldCache.Set("message.keyword", &FieldMetadata{Type: "keyword", Searchable: true, Aggregatable: true})
+
+	t.Run("keyword field uses terms and cardinality", func(t *testing.T) {
+		query, stats, err := BuildFieldStatsQuery([]string{"status=active"}, "", "name", fieldCache)
+		if !assert.NoError(t, err) {
+			return
+		}
+
+		assert.Equal(t, "name", stats.Field)
+		assert.False(t, stats.Numeric)
+		assert.Equal(t, 0, query["size"])
+		assert.NotContains(t, query, "highlight")
+
+		aggs := query["aggs"].(map[string]any)
+		assert.Contains(t, aggs, "top_values")
+		assert.Contains(t, aggs, "cardinality")
+		assert.Contains(t, aggs, "missing")
+		assert.NotContains(t, aggs, "stats")
+
+		terms := aggs["top_values"].(map[string]any)["terms"].(map[string]any)
+		assert.Equal(t, fieldStatsTopValues, terms["size"])
+	})
+
+	t.Run("numeric field uses stats", func(t *testing.T) {
+		query, stats, err := BuildFieldStatsQuery(nil, "12h", "age", fieldCache)
+		if !assert.NoError(t, err) {
+			return
+		}
+
+		assert.True(t, stats.Numeric)
+		aggs := query["aggs"].(map[string]any)
+		assert.Contains(t, aggs, "stats")
+		assert.Contains(t, aggs, "missing")
+		assert.NotContains(t, aggs, "top_values")
+	})
+
+	t.Run("text field falls back to keyword sub-field", func(t *testing.T) {
+		_, stats, err := BuildFieldStatsQuery(nil, "", "message", fieldCache)
+		if !assert.NoError(t, err) {
+			return
+		}
+		assert.Equal(t, "message.keyword", stats.Field)
+	})
+
+	t.Run("non-aggregatable field without keyword returns error", func(t *testing.T) {
+		_, _, err := BuildFieldStatsQuery(nil, "", "description", fieldCache)
+		assert.ErrorContains(t, err, "not aggregatable")
+	})
+
+	t.Run("unknown field returns error", func(t *testing.T) {
+		_, _, err := BuildFieldStatsQuery(nil, "", "does_not_exist", fieldCache)
+		assert.Error(t, err)
+	})
+}
+
+func TestParseFieldStatsAggregations(t *testing.T) {
+	t.Run("terms response", func(t *testing.T) {
+		data := json.RawMessage(`{
+			"top_values": {"sum_other_doc_count": 7, "buckets": [
+				{"key": "alpha", "doc_count": 10},
+				{"key": 42, "doc_count": 3},
+				{"key": 1, "key_as_string": "true", "doc_count": 2}
+			]},
+			"cardinality": {"value": 12},
+			"missing": {"doc_count": 4}
+		}`)
+		stats := &FieldStats{}
+		assert.NoError(t, ParseFieldStatsAggregations(data, stats))
+
+		assert.Equal(t, int64(7), stats.OtherCount)
+		assert.Equal(t, int64(12), stats.Cardinality)
+		assert.Equal(t, int64(4), stats.Missing)
+		assert.Equal(t, []FieldValueCount{
+			{Value: "alpha", Count: 10},
+			{Value: "42", Count: 3},
+			{Value: "true", Count: 2},
+		}, stats.TopValues)
+	})
+
+	t.Run("stats response", func(t *testing.T) {
+		data := json.RawMessage(`{
+			"stats": {"count": 5, "min": 1, "max": 9.5, "avg": 4.2},
+			"missing": {"doc_count": 0}
+		}`)
+		stats := &FieldStats{Numeric: true}
+		assert.NoError(t, ParseFieldStatsAggregations(data, stats))
+
+		assert.Equal(t, int64(5), stats.Count)
+		assert.Equal(t, "1", formatStat(stats.Min, stats.MinAsString))
+		assert.Equal(t, "9.5", formatStat(stats.Max, stats.MaxAsString))
+		assert.Equal(t, "4.2", formatStat(stats.Avg, stats.AvgAsString))
+	})
+
+	t.Run("empty stats on no matching documents", func(t *testing.T) {
+		data := json.RawMessage(`{"stats": {"count": 0, "min": null, "max": null, "avg": null}}`)
+		stats := &FieldStats{Numeric: true}
+		assert.NoError(t, ParseFieldStatsAggregations(data, stats))
+		assert.Equal(t, "-", formatStat(stats.Min, stats.MinAsString))
+	})
+
+	t.Run("missing aggregations", func(t *testing.T) {
+		assert.Error(t, ParseFieldStatsAggregations(nil, &FieldStats{}))
+	})
+}
+
+func TestFilterForValue(t *testing.T) {
+	fieldCache := newTestFieldCache()
+
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{value: "active", want: "status=active"},
+		{value: "a*b?", want: `status=a\*b\?`},
+		{value: `C:\temp`, want: `status=C:\\temp`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			filter := FilterForValue("status", tt.value)
+			assert.Equal(t, tt.want, filter)
+
+			clause, err := ParseFilter(filter, fieldCache)
+			if !assert.NoError(t, err) {
+				return
+			}
+			assert.Equal(t, map[string]any{"match": map[string]any{"status": tt.value}}, clause)
+		})
+	}
+}
@@ -4,10 +4,11 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"github.com/tpelletiersophos/cloudcutter/internal/ui/help"
 	"sort"
 	"strings"
 	"sync"
+
+	"github.com/tpelletiersophos/cloudcutter/internal/ui/common"
 )
 
 type FieldCaps struct {
@@ -73,7 +74,7 @@ func (v *View) resetFieldState() error {
 	if err := v.loadFields(); err != nil {
 		v.manager.Logger().Error("Failed to load fields for new index", "error", err)
 		v.manager.App().QueueUpdateDraw(func() {
-			v.manager.UpdateStatusBar(fmt.Sprintf("Error loading fields: %v", err))
+			v.manager.NotifyError(v.Name(), fmt.Sprintf("Error loading fields: %v", err))
 		})
 		return err
 	}
@@ -107,6 +108,9 @@ func (v *View) loadFields() error {
 
 	// Update field state with discovered fields
 	v.state.data.fieldState.UpdateFromDocuments(entries)
+	if len(entries) > 0 {
+		v.restoreSelectedFields()
+	}
 
 	needMetadata := false
 	discoveredFields := v.state.data.fieldState.GetDiscoveredFields()
@@ -181,6 +185,7 @@ func (v *View) updateFieldsFromResults(results []*DocEntry) {
 
 	// Update the field state with new document fields
 	v.state.data.fieldState.UpdateFromDocuments(results)
+	v.restoreSelectedFields()
 
 	v.manager.App().QueueUpdateDraw(func() {
 		v.rebuildFieldList()
@@ -190,6 +195,7 @@ func (v *View) updateFieldsFromResults(results []*DocEntry) {
 func (v *View) toggleFieldList() {
 	v.state.ui.fieldListVisible = !v.state.ui.fieldListVisible
 	v.updateResultsLayout()
+	v.manager.SaveSession()
 
 	if !v.state.ui.fieldListVisible {
 		v.manager.App().SetFocus(v.components.resultsTable)
@@ -265,10 +271,10 @@ func (v *View) filterFieldList(filter string) {
 	v.updateFieldListTitle(filter, len(filteredFields))
 
 	if filter != "" {
-		v.manager.UpdateStatusBar(fmt.Sprintf("Filtered: showing available fields matching '%s' (%d matches)",
+		v.manager.NotifyInfo(v.Name(), fmt.Sprintf("Filtered: showing available fields matching '%s' (%d matches)",
 			filter, len(filteredFields)))
 	} else {
-		v.manager.UpdateStatusBar("Showing all available fields")
+		v.manager.NotifyInfo(v.Name(), "Showing all available fields")
 	}
 }
 
@@ -278,11 +284,18 @@ func (v *View) updateFieldListTitle(filter string, matchCount int) {
 	if filter != "" {
 		title = fmt.Sprintf("Available Fields - Filtered: \"%s\" (%d)", filter, matchCount)
 	} else {
-		title = "Available Fields (Enter to select)"
+		title = fieldListTitle()
 	}
 	v.components.fieldList.SetTitle(title)
 }
 
+// fieldListTitle names the field list with the keys that act on a field, as bound now.
+func fieldListTitle() string {
+	keymap := common.CurrentKeymap()
+	return fmt.Sprintf("Available Fields (%s: values, %s: add)",
+		keymap.Keys("elastic.fields", "field_stats"), keymap.Keys("elastic.fields", "toggle_field"))
+}
+
 func (v *View) moveFieldPosition(field string, moveUp bool) {
 	if moved := v.state.data.fieldState.MoveField(field, moveUp); moved {
 		v.rebuildFieldList()
@@ -299,20 +312,23 @@ func (v *View) moveFieldPosition(field string, moveUp bool) {
 		v.components.selectedList.SetCurrentItem(newPos)
 
 		v.displayCurrentPage()
+		v.manager.SaveSession()
 	}
 }
 
-func (v *View) initFieldsSync() error {
-	var wg sync.WaitGroup
-	errChan := make(chan error, 2)
+// initFields lists the indices and loads the fields of the current index in the background, then
+// shows the fields and searches. The view counts as loading until then, so searches asked for
+// meanwhile wait for the fields.
+func (v *View) initFields() {
+	v.state.mu.Lock()
+	v.state.ui.isLoading = true
+	v.state.mu.Unlock()
+	v.showLoading("Loading fields...")
 
-	wg.Add(1)
 	go func() {
-		defer wg.Done()
 		indices, err := v.service.ListIndices(context.Background(), "*")
 		if err != nil {
 			v.manager.Logger().Error("Failed to list indices", "error", err)
-			errChan <- err
 			return
 		}
 		v.state.mu.Lock()
@@ -320,46 +336,16 @@ func (v *View) initFieldsSync() error {
 		v.state.mu.Unlock()
 	}()
 
-	wg.Add(1)
 	go func() {
-		defer wg.Done()
 		if err := v.loadFields(); err != nil {
-			errChan <- err
-			return
+			// The view still searches without them
+			v.manager.Logger().Error("Failed to initialize fields", "error", err)
 		}
-
 		v.manager.App().QueueUpdateDraw(func() {
 			v.rebuildFieldList()
+			v.finishLoading(true)
 		})
 	}()
-
-	go func() {
-		wg.Wait()
-		close(errChan)
-	}()
-
-	for err := range errChan {
-		if err != nil {
-			return err
-		}
-	}
-
-	return nil
-}
-
-func getIndices(v *View) []help.Command {
-	v.state.mu.RLock()
-	indices := v.state.search.matchingIndices
-	v.state.mu.RUnlock()
-
-	indexCommands := make([]help.Command, 0, len(indices))
-	for _, idx := range indices {
-		indexCommands = append(indexCommands, help.Command{
-			Key: idx,
-		})
-	}
-	indexCommands = append(indexCommands, help.Command{})
-	return indexCommands
 }
 
 func (v *View) getActiveHeaders() []string {
@@ -2,6 +2,7 @@ package elastic
 
 import (
 	"fmt"
+	"github.com/tpelletiersophos/cloudcutter/internal/ui/style"
 	"math"
 	"strings"
 )
@@ -47,7 +48,7 @@ func (v *View) updateFiltersDisplay() {
 
 	var filters []string
 	for i, filter := range v.state.data.filters {
-		filters = append(filters, fmt.Sprintf("[#fabd2f]%d:[#70cae2]%s[-]", i+1, filter))
+		filters = append(filters, fmt.Sprintf("%s%d:%s%s[-]", style.Tag(style.Title), i+1, style.Tag(style.Label), filter))
 	}
 
 	v.components.activeFilters.SetText(strings.Join(filters, " | "))
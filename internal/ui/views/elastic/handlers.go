@@ -8,7 +8,107 @@ import (
 	"strconv"
 )
 
-func (v *View) handleTabKey(currentFocus tview.Primitive) *tcell.EventKey {
+// KeyContext is the keymap context of the focused pane.
+func (v *View) KeyContext(focus tview.Primitive) string {
+	switch focus {
+	case v.components.activeFilters:
+		return "elastic.filters"
+	case v.components.fieldList:
+		return "elastic.fields"
+	case v.components.selectedList:
+		return "elastic.selected"
+	case v.components.resultsTable:
+		return "elastic.results"
+	case v.components.localFilterInput:
+		return "elastic.local_filter"
+	}
+	return v.Name()
+}
+
+// handleAction runs a keymap action in the focused pane, reporting whether there was one.
+func (v *View) handleAction(action string, currentFocus tview.Primitive) bool {
+	switch action {
+	case "focus_next":
+		v.handleTabKey(currentFocus)
+	case "focus_prev":
+		v.handleShiftTabKey(currentFocus)
+	case "focus_fields":
+		v.manager.SetFocus(v.components.fieldList)
+	case "focus_selected":
+		v.manager.SetFocus(v.components.selectedList)
+	case "focus_results":
+		v.manager.SetFocus(v.components.resultsTable)
+	case "filter":
+		v.showFilterPrompt(currentFocus)
+	case "delete_filter":
+		if len(v.state.data.filters) > 0 {
+			v.deleteSelectedFilter()
+		}
+	case "down", "up":
+		list := currentFocus.(*tview.List)
+		index := list.GetCurrentItem()
+		if action == "down" && index < list.GetItemCount()-1 {
+			list.SetCurrentItem(index + 1)
+		} else if action == "up" && index > 0 {
+			list.SetCurrentItem(index - 1)
+		}
+	case "toggle_field", "field_stats", "move_down", "move_up":
+		list := currentFocus.(*tview.List)
+		index := list.GetCurrentItem()
+		if index < 0 || index >= list.GetItemCount() {
+			break
+		}
+		field, _ := list.GetItemText(index)
+		switch action {
+		case "toggle_field":
+			v.toggleField(field)
+		case "field_stats":
+			v.showFieldStats(field)
+		default:
+			v.moveFieldPosition(field, action == "move_up")
+		}
+	case "clear_filter":
+		v.state.ui.fieldListFilter = ""
+		v.filterFieldList("")
+	case "open_document":
+		v.openDocument()
+	case "first_row":
+		if v.components.resultsTable.GetRowCount() > 1 {
+			v.components.resultsTable.Select(1, 0).ScrollToBeginning()
+		}
+	case "last_row":
+		if rows := v.components.resultsTable.GetRowCount(); rows > 1 {
+			v.components.resultsTable.Select(rows-1, 0).ScrollToEnd()
+		}
+	case "next_page":
+		v.nextPage()
+	case "previous_page":
+		v.previousPage()
+	case "toggle_row_numbers":
+		v.toggleRowNumbers()
+	case "toggle_fields":
+		v.toggleFieldList()
+	case "toggle_highlighting":
+		v.toggleHighlighting()
+	case "inspect_query":
+		v.showQueryInspector(v.selectedResultEntry())
+	case "compare":
+		v.showComparePrompt()
+	case "delete_document":
+		if entry := v.selectedResultEntry(); entry != nil {
+			v.manager.GuardMutation("Delete document", func() { v.confirmDeleteDocument(entry) })
+		}
+	case "update_by_query":
+		v.manager.GuardMutation(string(OperationUpdateByQuery), func() { v.showByQueryConfirm(OperationUpdateByQuery) })
+	case "delete_by_query":
+		v.manager.GuardMutation(string(OperationDeleteByQuery), func() { v.showByQueryConfirm(OperationDeleteByQuery) })
+	default:
+		return false
+	}
+	return true
+}
+
+func (v *View) handleTabKey(currentFocus tview.Primitive) {
 	switch currentFocus {
 	case v.components.filterInput:
 		v.manager.App().SetFocus(v.components.activeFilters)
@@ -32,14 +132,9 @@ func (v *View) handleTabKey(currentFocus tview.Primitive) *tcell.EventKey {
 	default:
 		v.manager.App().SetFocus(v.components.filterInput)
 	}
-	return tcell.NewEventKey(tcell.KeyTab, 0, tcell.ModNone)
 }
 
 func (v *View) handleFilterInput(event *tcell.EventKey) *tcell.EventKey {
-	if shortcut := v.handleCommonShortcuts(event); shortcut == nil {
-		return nil
-	}
-
 	switch event.Key() {
 	case tcell.KeyEsc:
 		v.components.filterInput.SetText("")
@@ -53,7 +148,7 @@ func (v *View) handleFilterInput(event *tcell.EventKey) *tcell.EventKey {
 		v.state.mu.Lock()
 		if _, err := ParseFilter(text, v.state.data.fieldCache); err != nil {
 			v.state.mu.Unlock()
-			v.manager.UpdateStatusBar(fmt.Sprintf("Invalid filter: %v", err))
+			v.manager.NotifyWarning(v.Name(), fmt.Sprintf("Invalid filter: %v", err))
 			return nil
 		}
 
@@ -68,19 +163,10 @@ func (v *View) handleFilterInput(event *tcell.EventKey) *tcell.EventKey {
 }
 
 func (v *View) handleActiveFilters(event *tcell.EventKey) *tcell.EventKey {
-	if shortcut := v.handleCommonShortcuts(event); shortcut == nil {
-		return nil
-	}
-
 	switch event.Key() {
 	case tcell.KeyEsc:
 		v.manager.SetFocus(v.components.filterInput)
 		return nil
-	case tcell.KeyDelete, tcell.KeyBackspace2, tcell.KeyBackspace:
-		if len(v.state.data.filters) > 0 {
-			v.deleteSelectedFilter()
-		}
-		return nil
 	case tcell.KeyRune:
 		if num, err := strconv.Atoi(string(event.Rune())); err == nil && num > 0 && num <= len(v.state.data.filters) {
 			v.deleteFilterByIndex(num - 1)
@@ -91,10 +177,6 @@ func (v *View) handleActiveFilters(event *tcell.EventKey) *tcell.EventKey {
 }
 
 func (v *View) handleIndexInput(event *tcell.EventKey) *tcell.EventKey {
-	if shortcut := v.handleCommonShortcuts(event); shortcut == nil {
-		return nil
-	}
-
 	switch event.Key() {
 	case tcell.KeyEsc:
 		v.components.indexInput.SetText(v.state.search.currentIndex)
@@ -119,14 +201,14 @@ func (v *View) handleIndexInput(event *tcell.EventKey) *tcell.EventKey {
 				if err := v.loadFields(); err != nil {
 					v.manager.Logger().Error("Failed to load fields for new index", "error", err)
 					v.manager.App().QueueUpdateDraw(func() {
-						v.manager.UpdateStatusBar(fmt.Sprintf("Error loading fields: %v", err))
+						v.manager.NotifyError(v.Name(), fmt.Sprintf("Error loading fields: %v", err))
 					})
 					return
 				}
 
 				v.manager.App().QueueUpdateDraw(func() {
 					v.rebuildFieldList()
-					v.manager.UpdateStatusBar("Fields loaded successfully")
+					v.manager.NotifySuccess(v.Name(), "Fields loaded successfully")
 				})
 
 				v.refreshWithCurrentTimeframe()
@@ -139,189 +221,79 @@ func (v *View) handleIndexInput(event *tcell.EventKey) *tcell.EventKey {
 	return event
 }
 
-func (v *View) handleResultsTable(event *tcell.EventKey) *tcell.EventKey {
-	if shortcut := v.handleCommonShortcuts(event); shortcut == nil {
+// selectedResultEntry returns the document under the results table cursor, or nil.
+func (v *View) selectedResultEntry() *DocEntry {
+	row, _ := v.components.resultsTable.GetSelection()
+	if row <= 0 {
 		return nil
 	}
 
-	switch event.Key() {
-	case tcell.KeyRune:
-		switch event.Rune() {
-		case 'f':
-			v.toggleFieldList()
-			return nil
-		case 'a':
-			v.manager.SetFocus(v.components.fieldList)
-		case 's':
-			v.manager.SetFocus(v.components.selectedList)
-		}
-	case tcell.KeyEnter:
-		row, _ := v.components.resultsTable.GetSelection()
-		if row <= 0 {
-			return nil
-		}
+	v.state.mu.RLock()
+	currentPage := v.state.pagination.currentPage
+	pageSize := v.state.pagination.pageSize
+	displayedResults := v.state.data.displayedResults
+	v.state.mu.RUnlock()
 
-		v.state.mu.RLock()
-		currentPage := v.state.pagination.currentPage
-		pageSize := v.state.pagination.pageSize
-		displayedResults := v.state.data.displayedResults
-		v.state.mu.RUnlock()
-
-		// Calculate the actual index in displayedResults
-		actualIndex := (currentPage-1)*pageSize + (row - 1)
-
-		if actualIndex >= len(displayedResults) {
-			return nil
-		}
-
-		entry := displayedResults[actualIndex]
-
-		v.showLoading("Fetching document...")
-
-		go func() {
-			defer v.hideLoading()
-
-			res, err := v.service.Client.Get(
-				entry.Index,
-				entry.ID,
-			)
-			if err != nil {
-				v.manager.App().QueueUpdateDraw(func() {
-					v.manager.UpdateStatusBar(fmt.Sprintf("Error fetching document: %v", err))
-				})
-				return
-			}
-			defer res.Body.Close()
-
-			var fullDoc struct {
-				Source map[string]any `json:"_source"`
-			}
-			if err := json.NewDecoder(res.Body).Decode(&fullDoc); err != nil {
-				v.manager.App().QueueUpdateDraw(func() {
-					v.manager.UpdateStatusBar(fmt.Sprintf("Error decoding document: %v", err))
-				})
-				return
-			}
-
-			// Display full doc
-			entry.data = fullDoc.Source
-			v.manager.App().QueueUpdateDraw(func() {
-				v.showJSONModal(entry)
-			})
-		}()
+	// Calculate the actual index in displayedResults
+	actualIndex := (currentPage-1)*pageSize + (row - 1)
+	if actualIndex >= len(displayedResults) {
 		return nil
 	}
-	return event
+
+	return displayedResults[actualIndex]
 }
 
-func (v *View) handleFieldList(event *tcell.EventKey) *tcell.EventKey {
-	if shortcut := v.handleCommonShortcuts(event); shortcut == nil {
-		return nil
+// openDocument fetches the document under the results table cursor and shows it in full.
+func (v *View) openDocument() {
+	entry := v.selectedResultEntry()
+	if entry == nil {
+		return
 	}
 
-	switch event.Key() {
-	case tcell.KeyRune:
-		switch event.Rune() {
-		case 'j': // Navigate down
-			currentIndex := v.components.fieldList.GetCurrentItem()
-			maxIndex := v.components.fieldList.GetItemCount() - 1
-			if currentIndex < maxIndex {
-				v.components.fieldList.SetCurrentItem(currentIndex + 1)
-			}
-			return nil
-		case 'k': // Navigate up
-			currentIndex := v.components.fieldList.GetCurrentItem()
-			if currentIndex > 0 {
-				v.components.fieldList.SetCurrentItem(currentIndex - 1)
-			}
-			return nil
-		case 's':
-			v.manager.SetFocus(v.components.selectedList)
-		}
+	v.showLoading("Fetching document...")
 
-	case tcell.KeyEnter:
-		// Field list only handles activation
-		index := v.components.fieldList.GetCurrentItem()
-		if index >= 0 && index < v.components.fieldList.GetItemCount() {
-			mainText, _ := v.components.fieldList.GetItemText(index)
-			v.toggleField(mainText)
-		}
-		return nil
-	case tcell.KeyBackspace, tcell.KeyBackspace2:
-		v.state.ui.fieldListFilter = ""
-		v.filterFieldList("")
-		return nil
-	}
-	return event
-}
+	go func() {
+		defer v.hideLoading()
 
-func (v *View) handleSelectedList(event *tcell.EventKey) *tcell.EventKey {
-	if shortcut := v.handleCommonShortcuts(event); shortcut == nil {
-		return nil
-	}
+		res, err := v.service.Client.Get(
+			entry.Index,
+			entry.ID,
+		)
+		if err != nil {
+			v.manager.App().QueueUpdateDraw(func() {
+				v.manager.NotifyError(v.Name(), fmt.Sprintf("Error fetching document: %v", err))
+			})
+			return
+		}
+		defer res.Body.Close()
 
-	switch event.Key() {
-	case tcell.KeyRune:
-		switch event.Rune() {
-		case 'j': // Navigate down
-			currentIndex := v.components.selectedList.GetCurrentItem()
-			maxIndex := v.components.selectedList.GetItemCount() - 1
-			if currentIndex < maxIndex {
-				v.components.selectedList.SetCurrentItem(currentIndex + 1)
-			}
-			return nil
-		case 'k': // Navigate up
-			currentIndex := v.components.selectedList.GetCurrentItem()
-			if currentIndex > 0 {
-				v.components.selectedList.SetCurrentItem(currentIndex - 1)
-			}
-			return nil
-		case 'J': // Reorder down (move field down in order)
-			index := v.components.selectedList.GetCurrentItem()
-			if index >= 0 && index < v.components.selectedList.GetItemCount() {
-				mainText, _ := v.components.selectedList.GetItemText(index)
-				v.moveFieldPosition(mainText, false)
-			}
-			return nil
-		case 'K': // Reorder up (move field up in order)
-			index := v.components.selectedList.GetCurrentItem()
-			if index >= 0 && index < v.components.selectedList.GetItemCount() {
-				mainText, _ := v.components.selectedList.GetItemText(index)
-				v.moveFieldPosition(mainText, true)
-			}
-			return nil
-		case 'a':
-			v.manager.SetFocus(v.components.fieldList)
+		var fullDoc struct {
+			Source      map[string]any `json:"_source"`
+			Version     *int64         `json:"_version"`
+			SeqNo       *int64         `json:"_seq_no"`
+			PrimaryTerm *int64         `json:"_primary_term"`
 		}
-	case tcell.KeyEnter:
-		index := v.components.selectedList.GetCurrentItem()
-		if index >= 0 && index < v.components.selectedList.GetItemCount() {
-			mainText, _ := v.components.selectedList.GetItemText(index)
-			v.toggleField(mainText)
+		if err := json.NewDecoder(res.Body).Decode(&fullDoc); err != nil {
+			v.manager.App().QueueUpdateDraw(func() {
+				v.manager.NotifyError(v.Name(), fmt.Sprintf("Error decoding document: %v", err))
+			})
+			return
 		}
-		return nil
-	}
-	return event
-}
-
-func (v *View) handleLocalFilterInput(event *tcell.EventKey) *tcell.EventKey {
-	if shortcut := v.handleCommonShortcuts(event); shortcut == nil {
-		return nil
-	}
 
-	switch event.Key() {
-	case tcell.KeyEsc:
-		v.manager.SetFocus(v.components.filterInput)
-		return nil
-	}
-	return event
+		// Display full doc, keeping the write metadata needed for concurrency-safe edits
+		entry.data = fullDoc.Source
+		if fullDoc.Version != nil {
+			entry.Version = fullDoc.Version
+		}
+		entry.SeqNo = fullDoc.SeqNo
+		entry.PrimaryTerm = fullDoc.PrimaryTerm
+		v.manager.App().QueueUpdateDraw(func() {
+			v.showJSONModal(entry)
+		})
+	}()
 }
 
 func (v *View) handleTimeframeInput(event *tcell.EventKey) *tcell.EventKey {
-	if shortcut := v.handleCommonShortcuts(event); shortcut == nil {
-		return nil
-	}
-
 	switch event.Key() {
 	case tcell.KeyEsc:
 		v.manager.SetFocus(v.components.filterInput)
@@ -329,7 +301,7 @@ func (v *View) handleTimeframeInput(event *tcell.EventKey) *tcell.EventKey {
 	case tcell.KeyEnter:
 		timeframe := v.components.timeframeInput.GetText()
 		if err := ValidateTimeframe(timeframe); err != nil {
-			v.manager.UpdateStatusBar(fmt.Sprintf("Error: %v", err))
+			v.manager.NotifyError(v.Name(), fmt.Sprintf("Error: %v", err))
 			return nil
 		}
 		v.state.search.timeframe = v.components.timeframeInput.GetText()
@@ -339,35 +311,7 @@ func (v *View) handleTimeframeInput(event *tcell.EventKey) *tcell.EventKey {
 	return event
 }
 
-func (v *View) handleNumResultsInput(event *tcell.EventKey) *tcell.EventKey {
-	if shortcut := v.handleCommonShortcuts(event); shortcut == nil {
-		return nil
-	}
-
-	switch event.Key() {
-	case tcell.KeyEsc:
-		v.manager.SetFocus(v.components.filterInput)
-		return nil
-	}
-	return event
-}
-
-func (v *View) handleCommonShortcuts(event *tcell.EventKey) *tcell.EventKey {
-	switch event.Key() {
-	case tcell.KeyCtrlA:
-		v.manager.SetFocus(v.components.fieldList)
-		return nil
-	case tcell.KeyCtrlS:
-		v.manager.SetFocus(v.components.selectedList)
-		return nil
-	case tcell.KeyCtrlR:
-		v.manager.SetFocus(v.components.resultsTable)
-		return nil
-	}
-	return event
-}
-
-func (v *View) handleShiftTabKey(currentFocus tview.Primitive) *tcell.EventKey {
+func (v *View) handleShiftTabKey(currentFocus tview.Primitive) {
 	switch currentFocus {
 	case v.components.filterInput:
 		v.manager.App().SetFocus(v.components.resultsTable)
@@ -390,5 +334,4 @@ func (v *View) handleShiftTabKey(currentFocus tview.Primitive) *tcell.EventKey {
 	default:
 		v.manager.App().SetFocus(v.components.filterInput)
 	}
-	return tcell.NewEventKey(tcell.KeyBacktab, 0, tcell.ModNone)
 }
@@ -14,6 +14,7 @@ import (
 	"github.com/tpelletiersophos/cloudcutter/internal/ui/components"
 	"github.com/tpelletiersophos/cloudcutter/internal/ui/components/types"
 	"github.com/tpelletiersophos/cloudcutter/internal/ui/manager"
+	"github.com/tpelletiersophos/cloudcutter/internal/ui/style"
 )
 
 // createTestView creates and initializes the View for testing.
@@ -84,12 +85,12 @@ func createTestView(t *testing.T) *View {
 						Style: types.InputFieldStyle{
 							BaseStyle: types.BaseStyle{
 								Border:      true,
-								BorderColor: tcell.ColorBeige,
+								BorderColor: style.Border,
 								TitleAlign:  tview.AlignLeft,
 							},
-							LabelColor:           tcell.ColorMediumTurquoise,
-							FieldBackgroundColor: tcell.ColorBlack,
-							FieldTextColor:       tcell.ColorBeige,
+							LabelColor:           style.Label,
+							FieldBackgroundColor: style.InputBackground,
+							FieldTextColor:       style.InputText,
 						},
 					},
 					{
@@ -99,7 +100,7 @@ func createTestView(t *testing.T) *View {
 						Style: types.TextViewStyle{
 							BaseStyle: types.BaseStyle{
 								Border:      true,
-								BorderColor: tcell.ColorBeige,
+								BorderColor: style.Border,
 							},
 						},
 					},
@@ -110,7 +111,7 @@ func createTestView(t *testing.T) *View {
 						Style: types.InputFieldStyle{
 							BaseStyle: types.BaseStyle{
 								Border:      true,
-								BorderColor: tcell.ColorBeige,
+								BorderColor: style.Border,
 							},
 						},
 					},
@@ -121,7 +122,7 @@ func createTestView(t *testing.T) *View {
 						Style: types.TextViewStyle{
 							BaseStyle: types.BaseStyle{
 								Border:      true,
-								BorderColor: tcell.ColorBeige,
+								BorderColor: style.Border,
 							},
 						},
 					},
@@ -132,7 +133,7 @@ func createTestView(t *testing.T) *View {
 						Style: types.TextViewStyle{
 							BaseStyle: types.BaseStyle{
 								Border:      true,
-								BorderColor: tcell.ColorBeige,
+								BorderColor: style.Border,
 							},
 						},
 					},
@@ -143,7 +144,7 @@ func createTestView(t *testing.T) *View {
 						Style: types.TableStyle{
 							BaseStyle: types.BaseStyle{
 								Border:      true,
-								BorderColor: tcell.ColorBeige,
+								BorderColor: style.Border,
 							},
 						},
 					},
@@ -0,0 +1,85 @@
+package elastic
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/rivo/tview"
+	"github.com/tpelletiersophos/cloudcutter/internal/ui/style"
+)
+
+const highlightCloseTag = "[-:-]"
+
+// highlightOpenTag colors matches with the current theme's highlight colors.
+func highlightOpenTag() string {
+	return fmt.Sprintf("[%s:%s]", style.Color(style.HighlightText), style.Color(style.Highlight))
+}
+
+// highlightMarkup converts Elasticsearch highlight tags in a fragment into tview color tags.
+// The remaining text is escaped so document content cannot inject color tags.
+func highlightMarkup(fragment string) string {
+	escaped := tview.Escape(fragment)
+	escaped = strings.ReplaceAll(escaped, HighlightPreTag, highlightOpenTag())
+	return strings.ReplaceAll(escaped, HighlightPostTag, highlightCloseTag)
+}
+
+// highlightTerms wraps case-insensitive occurrences of terms in text with highlight color tags.
+// Terms are expected longest first so overlapping matches prefer the longer term.
+func highlightTerms(text string, terms []string) string {
+	if len(terms) == 0 || text == "" {
+		return text
+	}
+
+	lower := strings.ToLower(text)
+	caseFold := len(lower) == len(text)
+	if !caseFold {
+		lower = text
+	}
+
+	var result strings.Builder
+	pos := 0
+	for pos < len(text) {
+		best, bestLen := -1, 0
+		for _, term := range terms {
+			if term == "" {
+				continue
+			}
+			needle := term
+			if caseFold {
+				needle = strings.ToLower(term)
+			}
+			idx := strings.Index(lower[pos:], needle)
+			if idx >= 0 && (best < 0 || idx < best || (idx == best && len(needle) > bestLen)) {
+				best, bestLen = idx, len(needle)
+			}
+		}
+
+		if best < 0 {
+			result.WriteString(text[pos:])
+			break
+		}
+
+		result.WriteString(text[pos : pos+best])
+		result.WriteString(highlightOpenTag())
+		result.WriteString(text[pos+best : pos+best+bestLen])
+		result.WriteString(highlightCloseTag)
+		pos += best + bestLen
+	}
+
+	return result.String()
+}
+
+// cellText returns the results table text for a field, rendering highlight fragments when enabled.
+func (v *View) cellText(entry *DocEntry, field string) string {
+	if v.state.ui.highlightEnabled {
+		if fragments := entry.GetHighlights(field); len(fragments) > 0 {
+			return highlightMarkup(strings.Join(fragments, ", "))
+		}
+	}
+	return entry.GetFormattedValue(field)
+}
+
+func (v *View) toggleHighlighting() {
+	v.state.ui.highlightEnabled = !v.state.ui.highlightEnabled
+	v.displayCurrentPage()
+}
@@ -0,0 +1,609 @@
+package elastic
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/elastic/go-elasticsearch/v6"
+	"github.com/elastic/go-elasticsearch/v6/esapi"
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"github.com/tpelletiersophos/cloudcutter/internal/services/elastic"
+	"github.com/tpelletiersophos/cloudcutter/internal/ui/common"
+	"github.com/tpelletiersophos/cloudcutter/internal/ui/style"
+)
+
+const (
+	ModalInspector = "modalInspector"
+
+	// profileMaxSize caps the hits requested by a profile run; the query phase timings
+	// are what matter, not the fetched documents.
+	profileMaxSize = 10
+)
+
+// queryInspector shows the last results query, the cluster response summary and on-demand
+// validate, explain and profile output.
+type queryInspector struct {
+	view      *View
+	textView  *tview.TextView
+	docInput  *tview.InputField
+	inner     *tview.Flex
+	container *tview.Grid
+
+	info    *searchInfo
+	entry   *DocEntry
+	keys    *common.KeyResolver
+	restore func()
+
+	validation  string
+	explanation string
+	profile     string
+}
+
+// showQueryInspector opens the inspector for the last search, explaining entry if non-nil.
+func (v *View) showQueryInspector(entry *DocEntry) {
+	v.state.mu.RLock()
+	info := v.state.search.lastSearch
+	v.state.mu.RUnlock()
+
+	if info == nil {
+		v.manager.NotifyInfo(v.Name(), "No search has been run yet")
+		return
+	}
+
+	qi := &queryInspector{
+		view:        v,
+		info:        info,
+		entry:       entry,
+		validation:  "running...",
+		explanation: pressTo("explain_document", "no document selected", "explain any index/_id"),
+		profile:     pressTo("profile", "not run", "run the query with profile enabled"),
+		keys:        common.NewKeyResolver(),
+	}
+	if entry != nil {
+		qi.explanation = "running..."
+	}
+
+	qi.setupUI()
+	qi.render()
+
+	pages := v.manager.Pages()
+	pages.RemovePage(ModalInspector)
+	pages.AddPage(ModalInspector, qi.container, true, true)
+	qi.restore = v.captureModalInput(ModalInspector, qi.handleInput)
+	v.manager.App().SetFocus(qi.textView)
+
+	qi.runValidate()
+	if entry != nil {
+		qi.runExplain()
+	}
+}
+
+// pressTo describes output not there yet: what it is, then the keys of action in the inspector
+// that produce it, as bound now.
+func pressTo(action, missing, purpose string) string {
+	keys := common.CurrentKeymap().Keys("elastic.inspector", action)
+	if keys == "" {
+		return missing
+	}
+	return fmt.Sprintf("%s; press %s to %s", missing, keys, purpose)
+}
+
+func (qi *queryInspector) close() {
+	qi.restore()
+	qi.view.manager.Pages().RemovePage(ModalInspector)
+	qi.view.manager.SetFocus(qi.view.components.resultsTable)
+}
+
+func (qi *queryInspector) handleInput(event *tcell.EventKey) *tcell.EventKey {
+	action, pending := qi.keys.Resolve("elastic.inspector", event)
+	switch {
+	case pending:
+	case action == "validate":
+		qi.runValidate()
+	case action == "explain":
+		qi.runExplain()
+	case action == "explain_document":
+		qi.askExplainDocument()
+	case action == "profile":
+		qi.runProfile()
+	case action == "close":
+		qi.close()
+	default:
+		return event
+	}
+	return nil
+}
+
+func (qi *queryInspector) setupUI() {
+	qi.textView = tview.NewTextView().
+		SetDynamicColors(true).
+		SetScrollable(true).
+		SetWrap(false)
+	qi.textView.SetBorder(true).
+		SetTitle(" Query Inspector ").
+		SetTitleColor(style.Color(style.Title)).
+		SetBorderColor(style.Color(style.BorderFocus))
+
+	footer := newKeyHints("elastic.inspector",
+		keyHint{"validate", "validate"},
+		keyHint{"explain", "explain"},
+		keyHint{"explain_document", "explain index/_id"},
+		keyHint{"profile", "profile"},
+		keyHint{"close", "close"})
+
+	qi.docInput = tview.NewInputField().
+		SetLabel(" Explain index/_id: ").
+		SetLabelColor(style.Color(style.Label)).
+		SetFieldBackgroundColor(style.Color(style.InputBackground)).
+		SetFieldTextColor(style.Color(style.Text))
+
+	qi.inner = tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(qi.textView, 0, 1, true).
+		AddItem(footer, 1, 0, false)
+
+	qi.container = tview.NewGrid().
+		SetColumns(0, 160, 0).
+		SetRows(0, 45, 0).
+		AddItem(qi.inner, 1, 1, 1, 1, 0, 0, true)
+}
+
+func (qi *queryInspector) render() {
+	var sb strings.Builder
+	section := func(title string) {
+		sb.WriteString(fmt.Sprintf("[%s::b]%s[-::-]\n", style.Color(style.Title), title))
+	}
+
+	section("Request")
+	mode := "search"
+	if qi.info.scroll {
+		mode = "scroll"
+	}
+	label, text := style.Tag(style.Label), style.Tag(style.Text)
+	sb.WriteString(fmt.Sprintf("  %sIndex:%s %s  %sMode:%s %s\n",
+		label, text, tview.Escape(qi.info.index), label, text, mode))
+	if queryJSON, err := json.MarshalIndent(qi.info.query, "", "  "); err == nil {
+		sb.WriteString(tview.Escape(string(queryJSON)))
+		sb.WriteString("\n")
+	}
+
+	sb.WriteString("\n")
+	section("Response")
+	shards := qi.info.shards
+	sb.WriteString(fmt.Sprintf("  %stook:%s %dms  %stimed_out:%s %t  %shits:%s %d\n",
+		label, text, qi.info.took, label, text, qi.info.timedOut, label, text, qi.info.totalHits))
+	sb.WriteString(fmt.Sprintf("  %sshards:%s %d total, %d successful, %d skipped, %d failed\n",
+		label, text, shards.Total, shards.Successful, shards.Skipped, shards.Failed))
+	for _, failure := range shards.Failures {
+		sb.WriteString(fmt.Sprintf("  %s%s[-]\n", style.Tag(style.StatusError), tview.Escape(string(failure))))
+	}
+
+	sb.WriteString("\n")
+	section("Validate (_validate/query?explain)")
+	sb.WriteString(indent(tview.Escape(qi.validation), "  "))
+
+	sb.WriteString("\n")
+	if qi.entry != nil {
+		section(fmt.Sprintf("Explain (%s/%s)", tview.Escape(qi.entry.Index), tview.Escape(qi.entry.ID)))
+	} else {
+		section("Explain")
+	}
+	sb.WriteString(indent(tview.Escape(qi.explanation), "  "))
+
+	sb.WriteString("\n")
+	section("Profile")
+	sb.WriteString(indent(tview.Escape(qi.profile), "  "))
+
+	qi.textView.SetText(sb.String())
+}
+
+// update sets a section's text from a background goroutine and redraws.
+func (qi *queryInspector) update(target *string, text string) {
+	qi.view.manager.App().QueueUpdateDraw(func() {
+		*target = text
+		qi.render()
+	})
+}
+
+func (qi *queryInspector) queryBody() ([]byte, error) {
+	return json.Marshal(map[string]any{"query": qi.info.query["query"]})
+}
+
+func (qi *queryInspector) runValidate() {
+	qi.validation = "running..."
+	qi.render()
+
+	client := qi.view.service.Client
+	go func() {
+		body, err := qi.queryBody()
+		if err == nil {
+			body, err = readResponse(client.Indices.ValidateQuery(
+				client.Indices.ValidateQuery.WithIndex(qi.info.index),
+				client.Indices.ValidateQuery.WithBody(bytes.NewReader(body)),
+				client.Indices.ValidateQuery.WithExplain(true),
+			))
+		}
+
+		text := fmt.Sprintf("error: %v", err)
+		if err == nil {
+			if text, err = FormatValidateResponse(body); err != nil {
+				text = fmt.Sprintf("error: %v", err)
+			}
+		}
+		qi.update(&qi.validation, text)
+	}()
+}
+
+func (qi *queryInspector) runExplain() {
+	if qi.entry == nil {
+		return
+	}
+	qi.explanation = "running..."
+	qi.render()
+
+	client := qi.view.service.Client
+	entry := qi.entry
+	go func() {
+		body, err := qi.queryBody()
+		if err == nil {
+			body, err = explainDocument(client, entry, body)
+		}
+
+		text := fmt.Sprintf("error: %v", err)
+		if err == nil {
+			if text, err = FormatExplainResponse(body); err != nil {
+				text = fmt.Sprintf("error: %v", err)
+			}
+		}
+		qi.update(&qi.explanation, text)
+	}()
+}
+
+// explainDocument runs the explain API for entry with the query in body. An entry typed in by
+// hand has no mapping type, and the client would explain it as _doc, which indices created
+// before 6.x do not use; its type is looked up first.
+func explainDocument(client *elasticsearch.Client, entry *DocEntry, body []byte) ([]byte, error) {
+	docType := entry.Type
+	if docType == "" {
+		var err error
+		if docType, err = documentType(client, entry.Index, entry.ID); err != nil {
+			return nil, err
+		}
+	}
+	// The client puts the _id in the path as it is
+	return readResponse(client.Explain(entry.Index, url.PathEscape(entry.ID),
+		client.Explain.WithDocumentType(docType),
+		client.Explain.WithBody(bytes.NewReader(body))))
+}
+
+// documentType returns the mapping type of the document id of index, by a one-hit ids search.
+func documentType(client *elasticsearch.Client, index, id string) (string, error) {
+	query, err := json.Marshal(map[string]any{
+		"size":    1,
+		"_source": false,
+		"query":   map[string]any{"ids": map[string]any{"values": []string{id}}},
+	})
+	if err != nil {
+		return "", err
+	}
+	body, err := readResponse(client.Search(
+		client.Search.WithIndex(index),
+		client.Search.WithBody(bytes.NewReader(query)),
+	))
+	if err != nil {
+		return "", err
+	}
+
+	var resp struct {
+		Hits struct {
+			Hits []struct {
+				Type string `json:"_type"`
+			} `json:"hits"`
+		} `json:"hits"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return "", fmt.Errorf("error decoding search response: %v", err)
+	}
+	if len(resp.Hits.Hits) == 0 {
+		return "", fmt.Errorf("document %s/%s not found", index, id)
+	}
+	return resp.Hits.Hits[0].Type, nil
+}
+
+// askExplainDocument prompts for the index and _id of any document to explain, such as one the
+// query was expected to match but did not.
+func (qi *queryInspector) askExplainDocument() {
+	if qi.entry != nil {
+		qi.docInput.SetText(qi.entry.Index + "/" + qi.entry.ID)
+	}
+	qi.inner.AddItem(qi.docInput, 1, 0, true)
+
+	// Let '?' and ':' in an _id reach the input rather than the global shortcuts
+	restore := qi.view.captureModalInput(ModalInspector, func(event *tcell.EventKey) *tcell.EventKey {
+		return event
+	})
+	qi.docInput.SetDoneFunc(func(key tcell.Key) {
+		if key == tcell.KeyEnter {
+			index, id, err := ParseDocumentRef(qi.docInput.GetText(), qi.info.index)
+			if err != nil {
+				qi.view.manager.NotifyError(qi.view.Name(), err.Error())
+				return
+			}
+			entry := &DocEntry{Index: index, ID: id}
+			if qi.entry != nil && qi.entry.Index == index {
+				entry.Type = qi.entry.Type
+			}
+			qi.entry = entry
+		}
+		restore()
+		qi.inner.RemoveItem(qi.docInput)
+		qi.view.manager.App().SetFocus(qi.textView)
+		if key == tcell.KeyEnter {
+			qi.runExplain()
+		}
+	})
+	qi.view.manager.App().SetFocus(qi.docInput)
+}
+
+// ParseDocumentRef reads "index/_id" as typed in the inspector. A bare _id is looked up in
+// defaultIndex, which must then name a single index rather than a pattern.
+func ParseDocumentRef(text, defaultIndex string) (index, id string, err error) {
+	text = strings.TrimSpace(text)
+	index, id, found := strings.Cut(text, "/")
+	if !found {
+		index, id = defaultIndex, text
+		if strings.ContainsAny(index, "*,") {
+			return "", "", fmt.Errorf("%s is an index pattern; enter the document as index/_id", index)
+		}
+	}
+	if index == "" || id == "" {
+		return "", "", fmt.Errorf("enter the document to explain as index/_id")
+	}
+	return index, id, nil
+}
+
+func (qi *queryInspector) runProfile() {
+	qi.profile = "running..."
+	qi.render()
+
+	query := make(map[string]any, len(qi.info.query)+1)
+	for k, val := range qi.info.query {
+		query[k] = val
+	}
+	delete(query, "highlight")
+	query["profile"] = true
+	if size, ok := query["size"].(int); !ok || size > profileMaxSize {
+		query["size"] = profileMaxSize
+	}
+
+	client := qi.view.service.Client
+	go func() {
+		body, err := json.Marshal(query)
+		if err == nil {
+			body, err = readResponse(client.Search(
+				client.Search.WithIndex(qi.info.index),
+				client.Search.WithBody(bytes.NewReader(body)),
+			))
+		}
+
+		text := fmt.Sprintf("error: %v", err)
+		if err == nil {
+			var result elastic.ESSearchResult
+			if err = json.Unmarshal(body, &result); err == nil {
+				text, err = FormatProfileResponse(result.Profile)
+			}
+			if err != nil {
+				text = fmt.Sprintf("error: %v", err)
+			}
+		}
+		qi.update(&qi.profile, text)
+	}()
+}
+
+// readResponse drains an API response, turning transport and HTTP errors into a Go error.
+func readResponse(res *esapi.Response, err error) ([]byte, error) {
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error reading response: %v", err)
+	}
+	if res.IsError() {
+		return nil, fmt.Errorf("%s: %s", res.Status(), strings.TrimSpace(string(body)))
+	}
+	return body, nil
+}
+
+// FormatValidateResponse renders a _validate/query?explain response as plain text.
+func FormatValidateResponse(body []byte) (string, error) {
+	var resp struct {
+		Valid        bool   `json:"valid"`
+		Error        string `json:"error"`
+		Explanations []struct {
+			Index       string `json:"index"`
+			Shard       *int   `json:"shard"`
+			Valid       bool   `json:"valid"`
+			Explanation string `json:"explanation"`
+			Error       string `json:"error"`
+		} `json:"explanations"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return "", fmt.Errorf("error decoding validate response: %v", err)
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("valid: %t\n", resp.Valid))
+	if resp.Error != "" {
+		sb.WriteString(fmt.Sprintf("error: %s\n", resp.Error))
+	}
+	for _, e := range resp.Explanations {
+		sb.WriteString(fmt.Sprintf("%s (valid: %t)\n", e.Index, e.Valid))
+		if e.Explanation != "" {
+			sb.WriteString(fmt.Sprintf("  %s\n", e.Explanation))
+		}
+		if e.Error != "" {
+			sb.WriteString(fmt.Sprintf("  error: %s\n", e.Error))
+		}
+	}
+	return sb.String(), nil
+}
+
+type explanationNode struct {
+	Value       float64           `json:"value"`
+	Description string            `json:"description"`
+	Details     []explanationNode `json:"details"`
+}
+
+// FormatExplainResponse renders an _explain response as an indented scoring tree.
+func FormatExplainResponse(body []byte) (string, error) {
+	var resp struct {
+		Matched     bool             `json:"matched"`
+		Explanation *explanationNode `json:"explanation"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return "", fmt.Errorf("error decoding explain response: %v", err)
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("matched: %t\n", resp.Matched))
+	if resp.Explanation != nil {
+		writeExplanation(&sb, *resp.Explanation, "", true, true)
+	}
+	return sb.String(), nil
+}
+
+func writeExplanation(sb *strings.Builder, node explanationNode, prefix string, last, root bool) {
+	connector, childPrefix := treeConnector(prefix, last, root)
+	sb.WriteString(fmt.Sprintf("%s%g %s\n", connector, node.Value, node.Description))
+	for i, child := range node.Details {
+		writeExplanation(sb, child, childPrefix, i == len(node.Details)-1, false)
+	}
+}
+
+type profileNode struct {
+	Type        string           `json:"type"`
+	Name        string           `json:"name"`
+	Reason      string           `json:"reason"`
+	Description string           `json:"description"`
+	TimeInNanos int64            `json:"time_in_nanos"`
+	Breakdown   map[string]int64 `json:"breakdown"`
+	Children    []profileNode    `json:"children"`
+}
+
+// FormatProfileResponse renders the profile section of a search response as a per-shard timing tree.
+func FormatProfileResponse(data json.RawMessage) (string, error) {
+	if len(data) == 0 {
+		return "", fmt.Errorf("response contained no profile")
+	}
+
+	var profile struct {
+		Shards []struct {
+			ID       string `json:"id"`
+			Searches []struct {
+				Query       []profileNode `json:"query"`
+				RewriteTime int64         `json:"rewrite_time"`
+				Collector   []profileNode `json:"collector"`
+			} `json:"searches"`
+			Aggregations []profileNode `json:"aggregations"`
+		} `json:"shards"`
+	}
+	if err := json.Unmarshal(data, &profile); err != nil {
+		return "", fmt.Errorf("error decoding profile: %v", err)
+	}
+
+	var sb strings.Builder
+	for _, shard := range profile.Shards {
+		sb.WriteString(fmt.Sprintf("shard %s\n", shard.ID))
+		for _, search := range shard.Searches {
+			sb.WriteString(fmt.Sprintf("  rewrite: %s\n", formatNanos(search.RewriteTime)))
+			sb.WriteString("  query\n")
+			for i, node := range search.Query {
+				writeProfileNode(&sb, node, "    ", i == len(search.Query)-1)
+			}
+			sb.WriteString("  collector\n")
+			for i, node := range search.Collector {
+				writeProfileNode(&sb, node, "    ", i == len(search.Collector)-1)
+			}
+		}
+		if len(shard.Aggregations) > 0 {
+			sb.WriteString("  aggregations\n")
+			for i, node := range shard.Aggregations {
+				writeProfileNode(&sb, node, "    ", i == len(shard.Aggregations)-1)
+			}
+		}
+	}
+	return sb.String(), nil
+}
+
+func writeProfileNode(sb *strings.Builder, node profileNode, prefix string, last bool) {
+	connector, childPrefix := treeConnector(prefix, last, false)
+
+	label := node.Type
+	if label == "" {
+		label = node.Name
+	}
+	detail := node.Description
+	if detail == "" {
+		detail = node.Reason
+	}
+	sb.WriteString(fmt.Sprintf("%s%s %s [%s]\n", connector, formatNanos(node.TimeInNanos), label, detail))
+
+	// Show the most expensive breakdown phases first
+	if len(node.Breakdown) > 0 {
+		phases := make([]string, 0, len(node.Breakdown))
+		for phase, nanos := range node.Breakdown {
+			if nanos > 0 && !strings.HasSuffix(phase, "_count") {
+				phases = append(phases, phase)
+			}
+		}
+		sort.Slice(phases, func(i, j int) bool {
+			return node.Breakdown[phases[i]] > node.Breakdown[phases[j]]
+		})
+		if len(phases) > 0 {
+			parts := make([]string, 0, len(phases))
+			for _, phase := range phases {
+				parts = append(parts, fmt.Sprintf("%s=%s", phase, formatNanos(node.Breakdown[phase])))
+			}
+			bar := "│ "
+			if len(node.Children) == 0 {
+				bar = "  "
+			}
+			sb.WriteString(fmt.Sprintf("%s%s%s\n", childPrefix, bar, strings.Join(parts, " ")))
+		}
+	}
+
+	for i, child := range node.Children {
+		writeProfileNode(sb, child, childPrefix, i == len(node.Children)-1)
+	}
+}
+
+// treeConnector returns the branch drawn before a node and the prefix for its children.
+func treeConnector(prefix string, last, root bool) (string, string) {
+	if root {
+		return prefix, prefix
+	}
+	if last {
+		return prefix + "└─ ", prefix + "   "
+	}
+	return prefix + "├─ ", prefix + "│  "
+}
+
+func formatNanos(nanos int64) string {
+	return fmt.Sprintf("%.3fms", float64(nanos)/1e6)
+}
+
+func indent(text, prefix string) string {
+	text = strings.TrimRight(text, "\n")
+	if text == "" {
+		return "\n"
+	}
+	return prefix + strings.ReplaceAll(text, "\n", "\n"+prefix) + "\n"
+}
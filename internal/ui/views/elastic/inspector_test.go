@@ -0,0 +1,162 @@
+package elastic
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tpelletiersophos/cloudcutter/internal/services/elastic/esfake"
+)
+
+func TestFormatValidateResponse(t *testing.T) {
+	tests := []struct {
+		name         string
+		body         string
+		wantContains []string
+		wantErr      bool
+	}{
+		{
+			name: "valid query with explanation",
+			body: `{"valid":true,"explanations":[{"index":"logs-1","valid":true,"explanation":"+status:active"}]}`,
+			wantContains: []string{
+				"valid: true",
+				"logs-1 (valid: true)",
+				"+status:active",
+			},
+		},
+		{
+			name: "invalid query with error",
+			body: `{"valid":false,"explanations":[{"index":"logs-1","valid":false,"error":"failed to parse date"}]}`,
+			wantContains: []string{
+				"valid: false",
+				"error: failed to parse date",
+			},
+		},
+		{
+			name:    "malformed body",
+			body:    `{`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := FormatValidateResponse([]byte(tt.body))
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			for _, want := range tt.wantContains {
+				assert.Contains(t, got, want)
+			}
+		})
+	}
+}
+
+func TestFormatExplainResponse(t *testing.T) {
+	body := `{
+		"matched": true,
+		"explanation": {
+			"value": 1.5,
+			"description": "sum of:",
+			"details": [
+				{"value": 1, "description": "status:active", "details": []},
+				{"value": 0.5, "description": "weight(message:error)", "details": [
+					{"value": 0.5, "description": "tf", "details": []}
+				]}
+			]
+		}
+	}`
+
+	got, err := FormatExplainResponse([]byte(body))
+	assert.NoError(t, err)
+
+	lines := strings.Split(strings.TrimSpace(got), "\n")
+	assert.Equal(t, []string{
+		"matched: true",
+		"1.5 sum of:",
+		"├─ 1 status:active",
+		"└─ 0.5 weight(message:error)",
+		"   └─ 0.5 tf",
+	}, lines)
+
+	got, err = FormatExplainResponse([]byte(`{"matched": false}`))
+	assert.NoError(t, err)
+	assert.Equal(t, "matched: false\n", got)
+}
+
+func TestExplainDocument(t *testing.T) {
+	server := esfake.NewServer()
+	defer server.Close()
+	client, err := server.Client()
+	require.NoError(t, err)
+	require.NoError(t, server.Index("logs-2024.01", "abc/def", map[string]any{"level": "error"}))
+
+	query := []byte(`{"query":{"term":{"level":"error"}}}`)
+	body, err := explainDocument(client, &DocEntry{Index: "logs-2024.01", ID: "abc/def"}, query)
+	require.NoError(t, err, "an _id with slashes is escaped and its type looked up")
+	got, err := FormatExplainResponse(body)
+	require.NoError(t, err)
+	assert.Contains(t, got, "matched: true")
+
+	_, err = explainDocument(client, &DocEntry{Index: "logs-2024.01", ID: "missing"}, query)
+	assert.ErrorContains(t, err, "document logs-2024.01/missing not found")
+}
+
+func TestParseDocumentRef(t *testing.T) {
+	index, id, err := ParseDocumentRef(" logs-2024.01/abc/def ", "logs-*")
+	assert.NoError(t, err)
+	assert.Equal(t, "logs-2024.01", index)
+	assert.Equal(t, "abc/def", id, "an _id may contain slashes")
+
+	index, id, err = ParseDocumentRef("abc", "logs-2024.01")
+	assert.NoError(t, err)
+	assert.Equal(t, "logs-2024.01", index)
+	assert.Equal(t, "abc", id)
+
+	_, _, err = ParseDocumentRef("abc", "logs-*")
+	assert.Error(t, err, "a bare _id needs a single index")
+	_, _, err = ParseDocumentRef("logs/", "logs")
+	assert.Error(t, err)
+}
+
+func TestFormatProfileResponse(t *testing.T) {
+	profile := json.RawMessage(`{
+		"shards": [{
+			"id": "[node1][logs-1][0]",
+			"searches": [{
+				"query": [{
+					"type": "BooleanQuery",
+					"description": "+status:active",
+					"time_in_nanos": 2500000,
+					"breakdown": {"score": 1000000, "build_scorer": 1500000, "score_count": 3},
+					"children": [{
+						"type": "TermQuery",
+						"description": "status:active",
+						"time_in_nanos": 1000000,
+						"breakdown": {}
+					}]
+				}],
+				"rewrite_time": 5000,
+				"collector": [{"name": "SimpleTopScoreDocCollector", "reason": "search_top_hits", "time_in_nanos": 30000}]
+			}]
+		}]
+	}`)
+
+	got, err := FormatProfileResponse(profile)
+	assert.NoError(t, err)
+
+	assert.Contains(t, got, "shard [node1][logs-1][0]")
+	assert.Contains(t, got, "rewrite: 0.005ms")
+	assert.Contains(t, got, "└─ 2.500ms BooleanQuery [+status:active]")
+	assert.Contains(t, got, "build_scorer=1.500ms score=1.000ms")
+	assert.NotContains(t, got, "score_count")
+	assert.Contains(t, got, "   └─ 1.000ms TermQuery [status:active]")
+	assert.Contains(t, got, "└─ 0.030ms SimpleTopScoreDocCollector [search_top_hits]")
+
+	_, err = FormatProfileResponse(nil)
+	assert.Error(t, err)
+}
@@ -6,9 +6,9 @@ import (
 
 	"github.com/gdamore/tcell/v2"
 	"github.com/rivo/tview"
+	"github.com/tpelletiersophos/cloudcutter/internal/ui/common"
 	"github.com/tpelletiersophos/cloudcutter/internal/ui/components/header"
 	"github.com/tpelletiersophos/cloudcutter/internal/ui/components/types"
-	"github.com/tpelletiersophos/cloudcutter/internal/ui/help"
 	"github.com/tpelletiersophos/cloudcutter/internal/ui/style"
 )
 
@@ -29,23 +29,18 @@ func (v *View) setupLayout() {
 						FixedSize: 3,
 						Style: types.InputFieldStyle{
 							BaseStyle: types.BaseStyle{
-								Border:      true,
-								BorderColor: tcell.ColorBeige,
-								TitleAlign:  tview.AlignLeft,
+								Border:           true,
+								BorderColor:      style.Border,
+								FocusBorderColor: style.BorderFocus,
+								TitleAlign:       tview.AlignLeft,
 							},
-							LabelColor:           tcell.ColorMediumTurquoise,
-							FieldBackgroundColor: tcell.ColorBlack,
-							FieldTextColor:       tcell.ColorBeige,
+							LabelColor:           style.Label,
+							FieldBackgroundColor: style.InputBackground,
+							FieldTextColor:       style.InputText,
 						},
 						Properties: types.InputFieldProperties{
 							Label:      " ES Filter >_ ",
 							FieldWidth: 0,
-							OnFocus: func(inputField *tview.InputField) {
-								inputField.SetBorderColor(tcell.ColorMediumTurquoise)
-							},
-							OnBlur: func(inputField *tview.InputField) {
-								inputField.SetBorderColor(tcell.ColorBeige)
-							},
 						},
 					},
 					// Active Filters
@@ -55,22 +50,17 @@ func (v *View) setupLayout() {
 						FixedSize: 3,
 						Style: types.TextViewStyle{
 							BaseStyle: types.BaseStyle{
-								Border:      true,
-								BorderColor: tcell.ColorBeige,
-								Title:       " Active Filters (Delete/Backspace to remove all, or press filter number) ",
-								TitleColor:  style.GruvboxMaterial.Yellow,
-								TextColor:   tcell.ColorBeige,
+								Border:           true,
+								BorderColor:      style.Border,
+								FocusBorderColor: style.BorderFocus,
+								Title:            " Active Filters (Delete/Backspace to remove all, or press filter number) ",
+								TitleColor:       style.Title,
+								TextColor:        style.Text,
 							},
 						},
 						Properties: types.TextViewProperties{
 							Text:          "No active filters",
 							DynamicColors: true,
-							OnFocus: func(textView *tview.TextView) {
-								textView.SetBorderColor(tcell.ColorMediumTurquoise)
-							},
-							OnBlur: func(textView *tview.TextView) {
-								textView.SetBorderColor(tcell.ColorBeige)
-							},
 						},
 					},
 					// Index and Timeframe Row
@@ -86,32 +76,22 @@ func (v *View) setupLayout() {
 								Proportion: 1,
 								Style: types.InputFieldStyle{
 									BaseStyle: types.BaseStyle{
-										Border:      true,
-										BorderColor: tcell.ColorBeige,
-										Title:       " Index ",
-										TitleAlign:  tview.AlignCenter,
-										TitleColor:  style.GruvboxMaterial.Yellow,
+										Border:           true,
+										BorderColor:      style.Border,
+										FocusBorderColor: style.BorderFocus,
+										Title:            " Index ",
+										TitleAlign:       tview.AlignCenter,
+										TitleColor:       style.Title,
 									},
-									LabelColor:           tcell.ColorMediumTurquoise,
-									FieldBackgroundColor: tcell.ColorBlack,
-									FieldTextColor:       tcell.ColorBeige,
+									LabelColor:           style.Label,
+									FieldBackgroundColor: style.InputBackground,
+									FieldTextColor:       style.InputText,
 								},
 								Properties: types.InputFieldProperties{
 									Label:      ">_ ",
 									FieldWidth: 0,
 									Text:       v.state.search.currentIndex,
-									OnFocus: func(inputField *tview.InputField) {
-										inputField.SetBorderColor(tcell.ColorMediumTurquoise)
-										if helpCategory := v.manager.Help().GetContextHelp(); helpCategory != nil {
-											helpCategory.Commands = getIndices(v)
-											v.manager.Help().SetContextHelp(helpCategory)
-										}
-									},
-									OnBlur: func(inputField *tview.InputField) {
-										inputField.SetBorderColor(tcell.ColorBeige)
-									},
 								},
-								Help: getIndices(v),
 							},
 							// Timeframe Input
 							{
@@ -120,26 +100,21 @@ func (v *View) setupLayout() {
 								Proportion: 1,
 								Style: types.InputFieldStyle{
 									BaseStyle: types.BaseStyle{
-										Border:      true,
-										BorderColor: tcell.ColorBeige,
-										Title:       " Timeframe ",
-										TitleAlign:  tview.AlignCenter,
-										TitleColor:  style.GruvboxMaterial.Yellow,
+										Border:           true,
+										BorderColor:      style.Border,
+										FocusBorderColor: style.BorderFocus,
+										Title:            " Timeframe ",
+										TitleAlign:       tview.AlignCenter,
+										TitleColor:       style.Title,
 									},
-									LabelColor:           tcell.ColorMediumTurquoise,
-									FieldBackgroundColor: tcell.ColorBlack,
-									FieldTextColor:       tcell.ColorBeige,
+									LabelColor:           style.Label,
+									FieldBackgroundColor: style.InputBackground,
+									FieldTextColor:       style.InputText,
 								},
 								Properties: types.InputFieldProperties{
 									Label:      ">_ ",
 									FieldWidth: 0,
-									Text:       "today",
-									OnFocus: func(inputField *tview.InputField) {
-										inputField.SetBorderColor(tcell.ColorMediumTurquoise)
-									},
-									OnBlur: func(inputField *tview.InputField) {
-										inputField.SetBorderColor(tcell.ColorBeige)
-									},
+									Text:       v.state.search.timeframe,
 									DoneFunc: func(s string) {
 										if s == "" {
 											return
@@ -152,18 +127,6 @@ func (v *View) setupLayout() {
 										}
 									},
 								},
-								Help: []help.Command{
-									{Key: "12h", Description: "Last 12 hours"},
-									{Key: "24h", Description: "Last 24 hours"},
-									{Key: "7d", Description: "Last 7 days"},
-									{Key: "30d", Description: "Last 30 days"},
-									{Key: "today", Description: "Since start of day"},
-									{Key: "week", Description: "Last week"},
-									{Key: "month", Description: "Last month"},
-									{Key: "quarter", Description: "Last quarter"},
-									{Key: "year", Description: "Last year"},
-									{Key: "Enter", Description: "Apply timeframe"},
-								},
 							},
 							{
 								ID:         "numResultsInput",
@@ -171,26 +134,21 @@ func (v *View) setupLayout() {
 								Proportion: 1,
 								Style: types.InputFieldStyle{
 									BaseStyle: types.BaseStyle{
-										Border:      true,
-										BorderColor: tcell.ColorBeige,
-										Title:       " # Results ",
-										TitleAlign:  tview.AlignCenter,
-										TitleColor:  style.GruvboxMaterial.Yellow,
+										Border:           true,
+										BorderColor:      style.Border,
+										FocusBorderColor: style.BorderFocus,
+										Title:            " # Results ",
+										TitleAlign:       tview.AlignCenter,
+										TitleColor:       style.Title,
 									},
-									LabelColor:           tcell.ColorMediumTurquoise,
-									FieldBackgroundColor: tcell.ColorBlack,
-									FieldTextColor:       tcell.ColorBeige,
+									LabelColor:           style.Label,
+									FieldBackgroundColor: style.InputBackground,
+									FieldTextColor:       style.InputText,
 								},
 								Properties: types.InputFieldProperties{
 									Label:      ">_ ",
 									FieldWidth: 0,
 									Text:       strconv.Itoa(v.state.search.numResults),
-									OnFocus: func(inputField *tview.InputField) {
-										inputField.SetBorderColor(tcell.ColorMediumTurquoise)
-									},
-									OnBlur: func(inputField *tview.InputField) {
-										inputField.SetBorderColor(tcell.ColorBeige)
-									},
 									DoneFunc: func(s string) {
 										if num, err := strconv.Atoi(s); err == nil && num > 0 {
 											v.state.search.numResults = num
@@ -198,9 +156,6 @@ func (v *View) setupLayout() {
 										}
 									},
 								},
-								Help: []help.Command{
-									{Key: "Enter", Description: "Apply number of results"},
-								},
 							},
 						},
 					},
@@ -211,25 +166,20 @@ func (v *View) setupLayout() {
 						FixedSize: 3,
 						Style: types.InputFieldStyle{
 							BaseStyle: types.BaseStyle{
-								Border:      true,
-								BorderColor: tcell.ColorBeige,
-								TitleAlign:  tview.AlignLeft,
-								Title:       " Filter Results ",
-								TitleColor:  style.GruvboxMaterial.Yellow,
+								Border:           true,
+								BorderColor:      style.Border,
+								FocusBorderColor: style.BorderFocus,
+								TitleAlign:       tview.AlignLeft,
+								Title:            " Filter Results ",
+								TitleColor:       style.Title,
 							},
-							LabelColor:           tcell.ColorMediumTurquoise,
-							FieldBackgroundColor: tcell.ColorBlack,
-							FieldTextColor:       tcell.ColorBeige,
+							LabelColor:           style.Label,
+							FieldBackgroundColor: style.InputBackground,
+							FieldTextColor:       style.InputText,
 						},
 						Properties: types.InputFieldProperties{
 							Label:      ">_ ",
 							FieldWidth: 0,
-							OnFocus: func(inputField *tview.InputField) {
-								inputField.SetBorderColor(tcell.ColorMediumTurquoise)
-							},
-							OnBlur: func(inputField *tview.InputField) {
-								inputField.SetBorderColor(tcell.ColorBeige)
-							},
 						},
 					},
 				},
@@ -254,22 +204,15 @@ func (v *View) setupLayout() {
 								Proportion: 1,
 								Style: types.ListStyle{
 									BaseStyle: types.BaseStyle{
-										Border:      true,
-										BorderColor: tcell.ColorBeige,
-										Title:       "Available Fields (Enter to select)",
-										TitleColor:  style.GruvboxMaterial.Yellow,
-										TextColor:   tcell.ColorBeige,
-									},
-									SelectedTextColor:       tcell.ColorBeige,
-									SelectedBackgroundColor: tcell.ColorDarkCyan,
-								},
-								Properties: types.ListProperties{
-									OnFocus: func(list *tview.List) {
-										list.SetBorderColor(tcell.ColorMediumTurquoise)
-									},
-									OnBlur: func(list *tview.List) {
-										list.SetBorderColor(tcell.ColorBeige)
+										Border:           true,
+										BorderColor:      style.Border,
+										FocusBorderColor: style.BorderFocus,
+										Title:            fieldListTitle(),
+										TitleColor:       style.Title,
+										TextColor:        style.Text,
 									},
+									SelectedTextColor:       style.Text,
+									SelectedBackgroundColor: style.Selection,
 								},
 							},
 							{
@@ -278,22 +221,15 @@ func (v *View) setupLayout() {
 								Proportion: 1,
 								Style: types.ListStyle{
 									BaseStyle: types.BaseStyle{
-										Border:      true,
-										BorderColor: tcell.ColorBeige,
-										Title:       "Selected Fields (shift+j/k to reorder)",
-										TitleColor:  style.GruvboxMaterial.Yellow,
-										TextColor:   tcell.ColorBeige,
-									},
-									SelectedTextColor:       tcell.ColorBeige,
-									SelectedBackgroundColor: tcell.ColorDarkCyan,
-								},
-								Properties: types.ListProperties{
-									OnFocus: func(list *tview.List) {
-										list.SetBorderColor(tcell.ColorMediumTurquoise)
-									},
-									OnBlur: func(list *tview.List) {
-										list.SetBorderColor(tcell.ColorBeige)
+										Border:           true,
+										BorderColor:      style.Border,
+										FocusBorderColor: style.BorderFocus,
+										Title:            "Selected Fields (shift+j/k to reorder)",
+										TitleColor:       style.Title,
+										TextColor:        style.Text,
 									},
+									SelectedTextColor:       style.Text,
+									SelectedBackgroundColor: style.Selection,
 								},
 							},
 						},
@@ -305,19 +241,12 @@ func (v *View) setupLayout() {
 						Proportion: 1,
 						Style: types.TableStyle{
 							BaseStyle: types.BaseStyle{
-								Border:      true,
-								BorderColor: tcell.ColorBeige,
-							},
-							SelectedTextColor:       tcell.ColorBeige,
-							SelectedBackgroundColor: tcell.ColorDarkCyan,
-						},
-						Properties: types.TableProperties{
-							OnFocus: func(table *tview.Table) {
-								table.SetBorderColor(tcell.ColorMediumTurquoise)
-							},
-							OnBlur: func(table *tview.Table) {
-								table.SetBorderColor(tcell.ColorBeige)
+								Border:           true,
+								BorderColor:      style.Border,
+								FocusBorderColor: style.BorderFocus,
 							},
+							SelectedTextColor:       style.Text,
+							SelectedBackgroundColor: style.Selection,
 						},
 					},
 				},
@@ -341,18 +270,11 @@ func (v *View) setupLayout() {
 	v.components.resultsTable = v.manager.GetPrimitiveByID("resultsTable").(*tview.Table)
 	v.components.listsContainer = v.manager.GetPrimitiveByID("listsContainer").(*tview.Flex)
 
-	// Set up input capture for field lists to prevent default tview behavior
-	v.components.fieldList.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
-		return v.handleFieldList(event)
-	})
-	v.components.selectedList.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
-		return v.handleSelectedList(event)
-	})
-
+	keymap := common.CurrentKeymap()
 	v.manager.UpdateViewCommands([]header.ViewCommands{
-		{View: "ctrl+a", Description: "Available Fields"},
-		{View: "ctrl+s", Description: "Selected Fields"},
-		{View: "ctrl+r", Description: "Results"},
+		{View: keymap.Keys(v.Name(), "focus_fields"), Description: "Available Fields"},
+		{View: keymap.Keys(v.Name(), "focus_selected"), Description: "Selected Fields"},
+		{View: keymap.Keys(v.Name(), "focus_results"), Description: "Results"},
 	})
 	v.components.localFilterInput.SetChangedFunc(func(text string) {
 		v.displayFilteredResults(text)
@@ -365,12 +287,12 @@ func (v *View) updateHeader() {
 
 	var indexInfo string
 	if stats := v.state.search.indexStats; stats != nil {
-		var healthColor = style.GruvboxMaterial.Red
+		var healthColor = style.Color(style.StatusError)
 		switch stats.Health {
 		case "green":
-			healthColor = style.GruvboxMaterial.Green
+			healthColor = style.Color(style.Success)
 		case "yellow":
-			healthColor = style.GruvboxMaterial.Yellow
+			healthColor = style.Color(style.Warning)
 		}
 
 		indexInfo = fmt.Sprintf("%s ([%s]%s[-]) | %s docs | %s",
@@ -388,7 +310,7 @@ func (v *View) updateHeader() {
 		types.SummaryItem{Key: "Index", Value: indexInfo},
 		types.SummaryItem{Key: "Filters", Value: fmt.Sprintf("%d", len(v.state.data.filters))},
 		types.SummaryItem{Key: "Results", Value: fmt.Sprintf("%d", len(v.state.data.displayedResults))},
-		types.SummaryItem{Key: "Page", Value: fmt.Sprintf("[%s::b]%d/%d[-]", style.GruvboxMaterial.Yellow, v.state.pagination.currentPage, v.state.pagination.totalPages)},
+		types.SummaryItem{Key: "Page", Value: fmt.Sprintf("[%s::b]%d/%d[-]", style.Color(style.Title), v.state.pagination.currentPage, v.state.pagination.totalPages)},
 		types.SummaryItem{Key: "Timeframe", Value: v.components.timeframeInput.GetText()},
 	)
 
@@ -424,17 +346,32 @@ func (v *View) updateStatusBar(currentPageSize int) {
 		statusMsg += fmt.Sprintf(" (filtered: %q)", filterText)
 	}
 
+	keymap := common.CurrentKeymap()
 	if v.state.ui.showRowNumbers {
-		statusMsg += fmt.Sprintf(" | [%s]Row numbers: on (press 'r' to toggle)[-]",
-			style.GruvboxMaterial.Yellow)
+		statusMsg += fmt.Sprintf(" | [%s]Row numbers: on (press %s to toggle)[-]",
+			style.Color(style.Warning), keymap.Keys("elastic.results", "toggle_row_numbers"))
+	}
+
+	if !v.state.ui.highlightEnabled {
+		statusMsg += fmt.Sprintf(" | [%s]Highlighting: off (press %s to toggle)[-]",
+			style.Color(style.Warning), keymap.Keys("elastic.results", "toggle_highlighting"))
 	}
 
-	v.manager.UpdateStatusBar(statusMsg)
+	v.manager.NotifyInfo(v.Name(), statusMsg)
 }
 
-func (v *View) setupResultsTableHeaders(headers []string) {
-	table := v.components.resultsTable
+// setupTableHeaders resets table and writes the bold header row used by results tables.
+func (v *View) setupTableHeaders(table *tview.Table, headers []string) {
 	table.Clear()
+	v.fixTableHeaders(table)
+
+	for col, cell := range headerCells(headers) {
+		table.SetCell(0, col, cell)
+	}
+}
+
+// fixTableHeaders keeps the header row, and the row numbers when shown, in place while scrolling.
+func (v *View) fixTableHeaders(table *tview.Table) {
 	table.SetSelectable(true, false)
 
 	if v.state.ui.showRowNumbers {
@@ -442,18 +379,23 @@ func (v *View) setupResultsTableHeaders(headers []string) {
 	} else {
 		table.SetFixed(1, 0)
 	}
+}
 
+// headerCells renders the bold header row used by results tables.
+func headerCells(headers []string) []*tview.TableCell {
+	cells := make([]*tview.TableCell, len(headers))
 	for col, header := range headers {
-		table.SetCell(0, col,
-			tview.NewTableCell(header).
-				SetTextColor(style.GruvboxMaterial.Yellow).
-				SetAlign(tview.AlignCenter).
-				SetSelectable(false).
-				SetAttributes(tcell.AttrBold))
+		cells[col] = tview.NewTableCell(header).
+			SetTextColor(style.Color(style.Title)).
+			SetAlign(tview.AlignCenter).
+			SetSelectable(false).
+			SetAttributes(tcell.AttrBold)
 	}
+	return cells
 }
 
 func (v *View) toggleRowNumbers() {
 	v.state.ui.showRowNumbers = !v.state.ui.showRowNumbers
 	v.displayCurrentPage() // No need for full refresh
+	v.manager.SaveSession()
 }
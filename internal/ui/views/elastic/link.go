@@ -0,0 +1,45 @@
+package elastic
+
+import (
+	"github.com/tpelletiersophos/cloudcutter/internal/deeplink"
+	"github.com/tpelletiersophos/cloudcutter/internal/ui/views"
+)
+
+var _ views.Linker = (*View)(nil)
+
+// ApplyLink replaces the current search with the index, filters and timeframe a link sets.
+func (v *View) ApplyLink(link deeplink.Link) {
+	v.state.mu.Lock()
+	indexChanged := link.Index != "" && link.Index != v.state.search.currentIndex
+	if link.Index != "" {
+		v.state.search.currentIndex = link.Index
+	}
+	if link.Filters != nil {
+		v.state.data.filters = append([]string(nil), link.Filters...)
+	}
+	index := v.state.search.currentIndex
+	v.state.mu.Unlock()
+
+	v.components.indexInput.SetText(index)
+	if link.Timeframe != "" {
+		v.components.timeframeInput.SetText(link.Timeframe)
+	}
+	v.updateFiltersDisplay()
+
+	// ApplyLink may run on the event loop, which the field reload queues updates to and waits on
+	if indexChanged {
+		go NewAsyncOperations(v).ReloadFieldsForNewIndex()
+	} else {
+		v.refreshWithCurrentTimeframe()
+	}
+}
+
+// FillLink records the current index, filters and timeframe in link.
+func (v *View) FillLink(link *deeplink.Link) {
+	v.state.mu.RLock()
+	defer v.state.mu.RUnlock()
+
+	link.Index = v.state.search.currentIndex
+	link.Filters = append([]string(nil), v.state.data.filters...)
+	link.Timeframe = v.state.search.timeframe
+}
@@ -1,8 +1,76 @@
 package elastic
 
+import (
+	"slices"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"github.com/tpelletiersophos/cloudcutter/internal/ui/common"
+	"github.com/tpelletiersophos/cloudcutter/internal/ui/style"
+)
 
 func (v *View) showJSONModal(entry *DocEntry) {
 	// Use the enhanced modal for better functionality
 	v.showEnhancedJSONModal(entry)
 }
 
+// modalCapture is an application input capture installed by captureModalInput. previous is the
+// capture it falls back to, which is replaced when the capture beneath it is restored first.
+type modalCapture struct {
+	previous func(event *tcell.EventKey) *tcell.EventKey
+}
+
+// captureModalInput routes key events straight to handler while page is open, bypassing the
+// manager's global shortcuts so free text entry (':' and '?') reaches the modal.
+// The returned function restores the input capture beneath the modal as it is when the modal
+// closes, so modals may close in any order.
+func (v *View) captureModalInput(page string, handler func(event *tcell.EventKey) *tcell.EventKey) func() {
+	app := v.manager.App()
+	pages := v.manager.Pages()
+	capture := &modalCapture{previous: app.GetInputCapture()}
+
+	app.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if pages.HasPage(page) {
+			return handler(event)
+		}
+		if capture.previous != nil {
+			return capture.previous(event)
+		}
+		return event
+	})
+	v.modalCaptures = append(v.modalCaptures, capture)
+
+	return func() {
+		i := slices.Index(v.modalCaptures, capture)
+		switch {
+		case i < 0:
+			return
+		case i == len(v.modalCaptures)-1:
+			app.SetInputCapture(capture.previous)
+		default:
+			v.modalCaptures[i+1].previous = capture.previous
+		}
+		v.modalCaptures = slices.Delete(v.modalCaptures, i, i+1)
+	}
+}
+
+// keyHint labels a keymap action in a modal's footer.
+type keyHint struct {
+	action, label string
+}
+
+// newKeyHints returns a footer listing the keys of hints in context as the current keymap binds
+// them, e.g. "q, esc: close"; actions config.yaml unbinds are left out.
+func newKeyHints(context string, hints ...keyHint) *tview.TextView {
+	km := common.CurrentKeymap()
+	parts := make([]string, 0, len(hints))
+	for _, hint := range hints {
+		if keys := km.Keys(context, hint.action); keys != "" {
+			parts = append(parts, keys+": "+hint.label)
+		}
+	}
+	return tview.NewTextView().
+		SetTextColor(style.Color(style.Muted)).
+		SetText(" " + strings.Join(parts, " | "))
+}
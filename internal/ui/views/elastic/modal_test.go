@@ -0,0 +1,43 @@
+package elastic
+
+import (
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCaptureModalInputClosesInAnyOrder(t *testing.T) {
+	view := createTestView(t)
+	app := view.manager.App()
+	pages := view.manager.Pages()
+
+	var reached []string
+	app.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		reached = append(reached, "global")
+		return event
+	})
+	open := func(page string) func() {
+		pages.AddPage(page, tview.NewBox(), true, true)
+		return view.captureModalInput(page, func(event *tcell.EventKey) *tcell.EventKey {
+			reached = append(reached, page)
+			return nil
+		})
+	}
+
+	key := tcell.NewEventKey(tcell.KeyRune, 'x', tcell.ModNone)
+	restoreOuter := open("outer")
+	restoreInner := open("inner")
+
+	// The inner modal keeps its keys when the one beneath it closes first
+	pages.RemovePage("outer")
+	restoreOuter()
+	app.GetInputCapture()(key)
+	pages.RemovePage("inner")
+	restoreInner()
+	app.GetInputCapture()(key)
+
+	assert.Equal(t, []string{"inner", "global"}, reached)
+	assert.Empty(t, view.modalCaptures)
+}
@@ -0,0 +1,602 @@
+package elastic
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"strings"
+
+	"github.com/elastic/go-elasticsearch/v6/esapi"
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"github.com/tpelletiersophos/cloudcutter/internal/ui/style"
+)
+
+const (
+	ModalDocEditor     = "modalDocEditor"
+	ModalConfirmDelete = "modalConfirmDelete"
+	ModalByQuery       = "modalByQuery"
+)
+
+// ByQueryOperation identifies a bulk operation run over the current filters.
+type ByQueryOperation string
+
+const (
+	OperationUpdateByQuery ByQueryOperation = "update_by_query"
+	OperationDeleteByQuery ByQueryOperation = "delete_by_query"
+)
+
+// ByQueryResult summarizes an update_by_query or delete_by_query response.
+type ByQueryResult struct {
+	Took             int               `json:"took"`
+	TimedOut         bool              `json:"timed_out"`
+	Total            int64             `json:"total"`
+	Updated          int64             `json:"updated"`
+	Deleted          int64             `json:"deleted"`
+	VersionConflicts int64             `json:"version_conflicts"`
+	Failures         []json.RawMessage `json:"failures"`
+}
+
+// docWriteResponse is the metadata returned by index and delete calls.
+type docWriteResponse struct {
+	Result      string `json:"result"`
+	Version     *int64 `json:"_version"`
+	SeqNo       *int64 `json:"_seq_no"`
+	PrimaryTerm *int64 `json:"_primary_term"`
+}
+
+// ParseSourceEdit validates edited text and returns it as a document _source object.
+func ParseSourceEdit(text string) (map[string]any, error) {
+	var source map[string]any
+	decoder := json.NewDecoder(strings.NewReader(text))
+	decoder.UseNumber()
+	if err := decoder.Decode(&source); err != nil {
+		return nil, fmt.Errorf("invalid JSON: %v", err)
+	}
+	if source == nil {
+		return nil, fmt.Errorf("_source must be a JSON object")
+	}
+	if decoder.More() {
+		return nil, fmt.Errorf("invalid JSON: unexpected content after object")
+	}
+	return source, nil
+}
+
+// errNoConcurrencyGuard is returned when a document has neither seq_no/primary_term nor a version,
+// so saving it could silently overwrite a newer write.
+var errNoConcurrencyGuard = errors.New("document has no seq_no/primary_term or version to guard the save; reopen it and retry")
+
+// hasConcurrencyGuard reports whether a write to entry can be made conditional on what was loaded.
+func hasConcurrencyGuard(entry *DocEntry) bool {
+	return (entry.SeqNo != nil && entry.PrimaryTerm != nil) || entry.Version != nil
+}
+
+// indexConcurrencyOptions guards an index call with the document's seq_no/primary_term, or its
+// version when those are not available. It refuses to build an unguarded call.
+func indexConcurrencyOptions(index esapi.Index, entry *DocEntry) ([]func(*esapi.IndexRequest), error) {
+	opts := []func(*esapi.IndexRequest){index.WithDocumentID(entry.ID)}
+	if entry.Type != "" {
+		opts = append(opts, index.WithDocumentType(entry.Type))
+	}
+
+	switch {
+	case entry.SeqNo != nil && entry.PrimaryTerm != nil:
+		opts = append(opts,
+			index.WithIfSeqNo(int(*entry.SeqNo)),
+			index.WithIfPrimaryTerm(int(*entry.PrimaryTerm)))
+	case entry.Version != nil:
+		opts = append(opts, index.WithVersion(int(*entry.Version)))
+	default:
+		return nil, errNoConcurrencyGuard
+	}
+	return opts, nil
+}
+
+// deleteConcurrencyOptions mirrors indexConcurrencyOptions for delete calls.
+func deleteConcurrencyOptions(del esapi.Delete, entry *DocEntry) []func(*esapi.DeleteRequest) {
+	var opts []func(*esapi.DeleteRequest)
+	if entry.Type != "" {
+		opts = append(opts, del.WithDocumentType(entry.Type))
+	}
+
+	switch {
+	case entry.SeqNo != nil && entry.PrimaryTerm != nil:
+		opts = append(opts,
+			del.WithIfSeqNo(int(*entry.SeqNo)),
+			del.WithIfPrimaryTerm(int(*entry.PrimaryTerm)))
+	case entry.Version != nil:
+		opts = append(opts, del.WithVersion(int(*entry.Version)))
+	}
+	return opts
+}
+
+// BuildByQueryBody builds the request body for a by-query operation from the current search query.
+// It refuses to run without any constraint so a mistake cannot touch every document in an index.
+func BuildByQueryBody(query map[string]any, op ByQueryOperation, script string) (map[string]any, error) {
+	clause, ok := query["query"].(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("no query to run")
+	}
+	if _, matchAll := clause["match_all"]; matchAll {
+		return nil, fmt.Errorf("refusing to run %s without filters or a timeframe", op)
+	}
+
+	body := map[string]any{"query": clause}
+	if op == OperationUpdateByQuery {
+		script = strings.TrimSpace(script)
+		if script == "" {
+			return nil, fmt.Errorf("update_by_query requires a script")
+		}
+		body["script"] = map[string]any{
+			"source": script,
+			"lang":   "painless",
+		}
+	}
+	return body, nil
+}
+
+// ParseByQueryResponse decodes the summary of a by-query response.
+func ParseByQueryResponse(body []byte) (*ByQueryResult, error) {
+	var result ByQueryResult
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, fmt.Errorf("error decoding response: %v", err)
+	}
+	return &result, nil
+}
+
+// readWriteResponse reads a document write response, reporting version conflicts explicitly.
+func readWriteResponse(res *esapi.Response, err error) (*docWriteResponse, error) {
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error reading response: %v", err)
+	}
+	if res.StatusCode == http.StatusConflict {
+		return nil, fmt.Errorf("document was modified since it was loaded (version conflict); reopen it and retry")
+	}
+	if res.IsError() {
+		return nil, fmt.Errorf("%s: %s", res.Status(), strings.TrimSpace(string(body)))
+	}
+
+	var write docWriteResponse
+	if err := json.Unmarshal(body, &write); err != nil {
+		return nil, fmt.Errorf("error decoding response: %v", err)
+	}
+	return &write, nil
+}
+
+// showDocumentEditor opens a text editor over the document's _source. A document loaded without
+// seq_no/primary_term or version is fetched again first, so the save is guarded against writes
+// made since.
+func (v *View) showDocumentEditor(entry *DocEntry) {
+	if !hasConcurrencyGuard(entry) {
+		v.refetchForEdit(entry)
+		return
+	}
+
+	source, err := json.MarshalIndent(entry.data, "", "  ")
+	if err != nil {
+		v.manager.NotifyError(v.Name(), fmt.Sprintf("Error formatting document: %v", err))
+		return
+	}
+
+	editor := tview.NewTextArea().SetText(string(source), false)
+	editor.SetBorder(true).
+		SetTitle(fmt.Sprintf(" Edit _source: %s/%s ", entry.Index, entry.ID)).
+		SetTitleColor(style.Color(style.Title)).
+		SetBorderColor(style.Color(style.BorderFocus))
+
+	var guard string
+	if entry.SeqNo != nil && entry.PrimaryTerm != nil {
+		guard = fmt.Sprintf("if_seq_no=%d if_primary_term=%d", *entry.SeqNo, *entry.PrimaryTerm)
+	} else {
+		guard = fmt.Sprintf("version=%d", *entry.Version)
+	}
+	footer := tview.NewTextView().
+		SetDynamicColors(true).
+		SetText(fmt.Sprintf(" %sCtrl-S: save (%s) | Esc: cancel", style.Tag(style.Muted), guard))
+
+	container := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(editor, 0, 1, true).
+		AddItem(footer, 1, 0, false)
+
+	grid := tview.NewGrid().
+		SetColumns(0, 160, 0).
+		SetRows(0, 45, 0).
+		AddItem(container, 1, 1, 1, 1, 0, 0, true)
+
+	var restore func()
+	closeEditor := func() {
+		restore()
+		v.manager.Pages().RemovePage(ModalDocEditor)
+		v.manager.SetFocus(v.components.resultsTable)
+	}
+
+	pages := v.manager.Pages()
+	pages.RemovePage(ModalDocEditor)
+	pages.AddPage(ModalDocEditor, grid, true, true)
+
+	restore = v.captureModalInput(ModalDocEditor, func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Key() {
+		case tcell.KeyEsc:
+			closeEditor()
+			return nil
+		case tcell.KeyCtrlS:
+			v.saveDocumentSource(entry, editor.GetText(), closeEditor)
+			return nil
+		}
+		return event
+	})
+
+	v.manager.App().SetFocus(editor)
+}
+
+// refetchForEdit loads the document's _source and write metadata again, then opens the editor.
+func (v *View) refetchForEdit(entry *DocEntry) {
+	v.showLoading("Fetching document...")
+	client := v.service.Client
+
+	go func() {
+		defer v.hideLoading()
+
+		opts := []func(*esapi.GetRequest){}
+		if entry.Type != "" {
+			opts = append(opts, client.Get.WithDocumentType(entry.Type))
+		}
+		res, err := client.Get(entry.Index, entry.ID, opts...)
+		if err != nil {
+			v.manager.App().QueueUpdateDraw(func() {
+				v.manager.NotifyError(v.Name(), fmt.Sprintf("Cannot edit: %v", err))
+			})
+			return
+		}
+		defer res.Body.Close()
+
+		var doc struct {
+			Source      map[string]any `json:"_source"`
+			Version     *int64         `json:"_version"`
+			SeqNo       *int64         `json:"_seq_no"`
+			PrimaryTerm *int64         `json:"_primary_term"`
+		}
+		if res.IsError() {
+			err = fmt.Errorf("%s", res.Status())
+		} else {
+			err = json.NewDecoder(res.Body).Decode(&doc)
+		}
+		if err == nil && doc.Version == nil && (doc.SeqNo == nil || doc.PrimaryTerm == nil) {
+			err = errNoConcurrencyGuard
+		}
+		v.manager.App().QueueUpdateDraw(func() {
+			if err != nil {
+				v.manager.NotifyError(v.Name(), fmt.Sprintf("Cannot edit: %v", err))
+				return
+			}
+			entry.data = doc.Source
+			entry.Version = doc.Version
+			entry.SeqNo = doc.SeqNo
+			entry.PrimaryTerm = doc.PrimaryTerm
+			v.showDocumentEditor(entry)
+		})
+	}()
+}
+
+// saveDocumentSource indexes edited source over the document using optimistic concurrency control.
+func (v *View) saveDocumentSource(entry *DocEntry, text string, onSaved func()) {
+	source, err := ParseSourceEdit(text)
+	if err != nil {
+		v.manager.NotifyError(v.Name(), fmt.Sprintf("Cannot save: %v", err))
+		return
+	}
+
+	body, err := json.Marshal(source)
+	if err != nil {
+		v.manager.NotifyError(v.Name(), fmt.Sprintf("Cannot save: %v", err))
+		return
+	}
+
+	client := v.service.Client
+	opts, err := indexConcurrencyOptions(client.Index, entry)
+	if err != nil {
+		v.manager.NotifyError(v.Name(), fmt.Sprintf("Cannot save: %v", err))
+		return
+	}
+	opts = append(opts, client.Index.WithRefresh("wait_for"))
+
+	v.showLoading("Saving document...")
+
+	go func() {
+		defer v.hideLoading()
+
+		write, err := readWriteResponse(client.Index(entry.Index, bytes.NewReader(body), opts...))
+		if err != nil {
+			v.manager.Logger().Error("Failed to save document", "index", entry.Index, "id", entry.ID, "error", err)
+			v.manager.App().QueueUpdateDraw(func() {
+				v.manager.NotifyError(v.Name(), fmt.Sprintf("Save failed: %v", err))
+			})
+			return
+		}
+
+		// Re-decode without UseNumber so values render like freshly fetched documents
+		var data map[string]any
+		_ = json.Unmarshal(body, &data)
+
+		v.manager.App().QueueUpdateDraw(func() {
+			entry.data = data
+			entry.Version = write.Version
+			entry.SeqNo = write.SeqNo
+			entry.PrimaryTerm = write.PrimaryTerm
+
+			onSaved()
+			v.displayCurrentPage()
+			v.manager.NotifySuccess(v.Name(), fmt.Sprintf("Document %s %s", entry.ID, write.Result))
+		})
+	}()
+}
+
+// confirmDeleteDocument asks for confirmation before deleting a single document by id.
+func (v *View) confirmDeleteDocument(entry *DocEntry) {
+	if entry == nil {
+		return
+	}
+
+	modal := tview.NewModal().
+		SetText(fmt.Sprintf("Delete document %s from %s?\nThis cannot be undone.", entry.ID, entry.Index)).
+		AddButtons([]string{"Cancel", "Delete"}).
+		SetDoneFunc(func(_ int, label string) {
+			v.manager.Pages().RemovePage(ModalConfirmDelete)
+			v.manager.SetFocus(v.components.resultsTable)
+			if label == "Delete" {
+				v.deleteDocument(entry)
+			}
+		})
+	modal.SetBorderColor(style.Color(style.ProdWarning))
+
+	v.manager.Pages().AddPage(ModalConfirmDelete, modal, true, true)
+	v.manager.App().SetFocus(modal)
+}
+
+func (v *View) deleteDocument(entry *DocEntry) {
+	v.showLoading("Deleting document...")
+	client := v.service.Client
+
+	go func() {
+		defer v.hideLoading()
+
+		opts := append(deleteConcurrencyOptions(client.Delete, entry), client.Delete.WithRefresh("wait_for"))
+		_, err := readWriteResponse(client.Delete(entry.Index, entry.ID, opts...))
+		if err != nil {
+			v.manager.Logger().Error("Failed to delete document", "index", entry.Index, "id", entry.ID, "error", err)
+			v.manager.App().QueueUpdateDraw(func() {
+				v.manager.NotifyError(v.Name(), fmt.Sprintf("Delete failed: %v", err))
+			})
+			return
+		}
+
+		v.removeEntry(entry)
+		v.manager.App().QueueUpdateDraw(func() {
+			v.displayCurrentPage()
+			v.manager.NotifySuccess(v.Name(), fmt.Sprintf("Deleted document %s", entry.ID))
+		})
+	}()
+}
+
+// removeEntry drops a deleted document from every result list.
+func (v *View) removeEntry(entry *DocEntry) {
+	without := func(entries []*DocEntry) []*DocEntry {
+		kept := make([]*DocEntry, 0, len(entries))
+		for _, e := range entries {
+			if e != entry {
+				kept = append(kept, e)
+			}
+		}
+		return kept
+	}
+
+	v.state.mu.Lock()
+	defer v.state.mu.Unlock()
+
+	v.state.data.currentResults = without(v.state.data.currentResults)
+	v.state.data.filteredResults = without(v.state.data.filteredResults)
+	v.state.data.displayedResults = without(v.state.data.displayedResults)
+	v.state.pagination.totalPages = int(math.Ceil(float64(len(v.state.data.displayedResults)) /
+		float64(v.state.pagination.pageSize)))
+	if v.state.pagination.totalPages < 1 {
+		v.state.pagination.totalPages = 1
+	}
+}
+
+// showByQueryConfirm previews how many documents the current filters match before running op.
+func (v *View) showByQueryConfirm(op ByQueryOperation) {
+	query := v.buildQuery()
+	if query == nil {
+		return
+	}
+	if _, err := BuildByQueryBody(query, op, "preview"); err != nil {
+		v.manager.NotifyError(v.Name(), err.Error())
+		return
+	}
+
+	v.state.mu.RLock()
+	index := v.state.search.currentIndex
+	filters := append([]string(nil), v.state.data.filters...)
+	timeframe := v.state.search.timeframe
+	v.state.mu.RUnlock()
+
+	v.showLoading("Counting matching documents...")
+	client := v.service.Client
+
+	go func() {
+		defer v.hideLoading()
+
+		countBody, err := json.Marshal(map[string]any{"query": query["query"]})
+		if err != nil {
+			return
+		}
+		body, err := readResponse(client.Count(
+			client.Count.WithIndex(index),
+			client.Count.WithBody(bytes.NewReader(countBody)),
+		))
+
+		var count struct {
+			Count int64 `json:"count"`
+		}
+		if err == nil {
+			err = json.Unmarshal(body, &count)
+		}
+		if err != nil {
+			v.manager.App().QueueUpdateDraw(func() {
+				v.manager.NotifyError(v.Name(), fmt.Sprintf("Count failed: %v", err))
+			})
+			return
+		}
+
+		v.manager.App().QueueUpdateDraw(func() {
+			if count.Count == 0 {
+				v.manager.NotifyInfo(v.Name(), fmt.Sprintf("No documents match; nothing to %s", op))
+				return
+			}
+			v.displayByQueryForm(op, index, query, filters, timeframe, count.Count)
+		})
+	}()
+}
+
+func (v *View) displayByQueryForm(op ByQueryOperation, index string, query map[string]any, filters []string, timeframe string, count int64) {
+	summary := tview.NewTextView().SetDynamicColors(true).SetWrap(true)
+	label, value, warning := style.Tag(style.Label), style.Tag(style.Text), style.Tag(style.Warning)
+	summary.SetText(fmt.Sprintf(
+		" %s[::b]%s[-::-] will affect %s[::b]%d[-::-] documents in %s%s[-]\n"+
+			" %sFilters:%s %s\n %sTimeframe:%s %s\n"+
+			" %sType the index name exactly to confirm.",
+		style.Tag(style.ProdWarning), op, warning, count, warning, tview.Escape(index),
+		label, value, tview.Escape(strings.Join(filters, " AND ")), label, value, tview.Escape(timeframe),
+		style.Tag(style.Muted)))
+
+	form := tview.NewForm()
+	scriptField := tview.NewInputField().SetLabel("Painless script ").SetFieldWidth(0)
+	confirmField := tview.NewInputField().SetLabel("Index name       ").SetFieldWidth(0)
+	if op == OperationUpdateByQuery {
+		form.AddFormItem(scriptField)
+	}
+	form.AddFormItem(confirmField)
+	form.SetFieldBackgroundColor(style.Color(style.InputBackground)).
+		SetFieldTextColor(style.Color(style.InputText)).
+		SetLabelColor(style.Color(style.Label)).
+		SetButtonBackgroundColor(style.Color(style.Selection))
+
+	container := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(summary, 4, 0, false).
+		AddItem(form, 0, 1, true)
+	container.SetBorder(true).
+		SetTitle(fmt.Sprintf(" Confirm %s ", op)).
+		SetTitleColor(style.Color(style.ProdWarning)).
+		SetBorderColor(style.Color(style.ProdWarning))
+
+	height := 11
+	if op == OperationUpdateByQuery {
+		height += 2
+	}
+	modal := tview.NewFlex().
+		SetDirection(tview.FlexRow).
+		AddItem(nil, 0, 1, false).
+		AddItem(tview.NewFlex().
+			AddItem(nil, 0, 1, false).
+			AddItem(container, 100, 0, true).
+			AddItem(nil, 0, 1, false),
+			height, 0, true).
+		AddItem(nil, 0, 1, false)
+
+	var restore func()
+	closeForm := func() {
+		restore()
+		v.manager.Pages().RemovePage(ModalByQuery)
+		v.manager.SetFocus(v.components.resultsTable)
+	}
+
+	form.AddButton("Run", func() {
+		if confirmField.GetText() != index {
+			v.manager.NotifyWarning(v.Name(), "Index name does not match; nothing was changed")
+			return
+		}
+		body, err := BuildByQueryBody(query, op, scriptField.GetText())
+		if err != nil {
+			v.manager.NotifyError(v.Name(), err.Error())
+			return
+		}
+		closeForm()
+		v.runByQuery(op, index, body)
+	})
+	form.AddButton("Cancel", closeForm)
+
+	pages := v.manager.Pages()
+	pages.RemovePage(ModalByQuery)
+	pages.AddPage(ModalByQuery, modal, true, true)
+
+	restore = v.captureModalInput(ModalByQuery, func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyEsc {
+			closeForm()
+			return nil
+		}
+		return event
+	})
+
+	v.manager.App().SetFocus(form)
+}
+
+func (v *View) runByQuery(op ByQueryOperation, index string, body map[string]any) {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		v.manager.NotifyError(v.Name(), err.Error())
+		return
+	}
+
+	v.showLoading(fmt.Sprintf("Running %s...", op))
+	client := v.service.Client
+	indices := strings.Split(index, ",")
+
+	go func() {
+		defer v.hideLoading()
+
+		var res *esapi.Response
+		if op == OperationDeleteByQuery {
+			res, err = client.DeleteByQuery(indices, bytes.NewReader(payload),
+				client.DeleteByQuery.WithRefresh(true),
+				client.DeleteByQuery.WithWaitForCompletion(true))
+		} else {
+			res, err = client.UpdateByQuery(indices,
+				client.UpdateByQuery.WithBody(bytes.NewReader(payload)),
+				client.UpdateByQuery.WithRefresh(true),
+				client.UpdateByQuery.WithWaitForCompletion(true))
+		}
+
+		data, err := readResponse(res, err)
+		var result *ByQueryResult
+		if err == nil {
+			result, err = ParseByQueryResponse(data)
+		}
+		if err != nil {
+			v.manager.Logger().Error("By-query operation failed", "operation", op, "index", index, "error", err)
+			v.manager.App().QueueUpdateDraw(func() {
+				v.manager.NotifyError(v.Name(), fmt.Sprintf("%s failed: %v", op, err))
+			})
+			return
+		}
+
+		v.manager.Logger().Info("By-query operation completed", "operation", op, "index", index,
+			"total", result.Total, "updated", result.Updated, "deleted", result.Deleted,
+			"conflicts", result.VersionConflicts, "failures", len(result.Failures))
+
+		v.manager.App().QueueUpdateDraw(func() {
+			v.manager.NotifySuccess(v.Name(), fmt.Sprintf("%s: %d total, %d updated, %d deleted, %d conflicts, %d failures (%dms)",
+				op, result.Total, result.Updated, result.Deleted, result.VersionConflicts, len(result.Failures), result.Took))
+		})
+		v.refreshResults()
+	}()
+}
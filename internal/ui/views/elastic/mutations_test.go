@@ -0,0 +1,127 @@
+package elastic
+
+import (
+	"testing"
+
+	"github.com/elastic/go-elasticsearch/v6/esapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSourceEdit(t *testing.T) {
+	source, err := ParseSourceEdit(`{"status": "active", "count": 3}`)
+	assert.NoError(t, err)
+	assert.Contains(t, source, "status")
+
+	_, err = ParseSourceEdit(`{"status": `)
+	assert.ErrorContains(t, err, "invalid JSON")
+
+	_, err = ParseSourceEdit(`null`)
+	assert.ErrorContains(t, err, "JSON object")
+
+	_, err = ParseSourceEdit(`{"a": 1} {"b": 2}`)
+	assert.Error(t, err)
+}
+
+func TestIndexConcurrencyOptions(t *testing.T) {
+	seqNo, primaryTerm, version := int64(12), int64(3), int64(7)
+
+	t.Run("seq_no and primary_term take precedence", func(t *testing.T) {
+		entry := &DocEntry{ID: "1", Type: "_doc", SeqNo: &seqNo, PrimaryTerm: &primaryTerm, Version: &version}
+		req := &esapi.IndexRequest{}
+		opts, err := indexConcurrencyOptions(esapi.Index(nil), entry)
+		require.NoError(t, err)
+		for _, opt := range opts {
+			opt(req)
+		}
+
+		assert.Equal(t, "1", req.DocumentID)
+		assert.Equal(t, "_doc", req.DocumentType)
+		assert.Equal(t, 12, *req.IfSeqNo)
+		assert.Equal(t, 3, *req.IfPrimaryTerm)
+		assert.Nil(t, req.Version)
+	})
+
+	t.Run("falls back to version", func(t *testing.T) {
+		entry := &DocEntry{ID: "1", Version: &version}
+		req := &esapi.IndexRequest{}
+		opts, err := indexConcurrencyOptions(esapi.Index(nil), entry)
+		require.NoError(t, err)
+		for _, opt := range opts {
+			opt(req)
+		}
+
+		assert.Empty(t, req.DocumentType)
+		assert.Nil(t, req.IfSeqNo)
+		assert.Equal(t, 7, *req.Version)
+	})
+
+	t.Run("refuses an unguarded save", func(t *testing.T) {
+		_, err := indexConcurrencyOptions(esapi.Index(nil), &DocEntry{ID: "1"})
+		assert.ErrorIs(t, err, errNoConcurrencyGuard)
+	})
+
+	t.Run("delete uses the same guard", func(t *testing.T) {
+		entry := &DocEntry{ID: "1", SeqNo: &seqNo, PrimaryTerm: &primaryTerm}
+		req := &esapi.DeleteRequest{}
+		for _, opt := range deleteConcurrencyOptions(esapi.Delete(nil), entry) {
+			opt(req)
+		}
+
+		assert.Equal(t, 12, *req.IfSeqNo)
+		assert.Equal(t, 3, *req.IfPrimaryTerm)
+	})
+}
+
+func TestBuildByQueryBody(t *testing.T) {
+	filtered := map[string]any{
+		"query": map[string]any{"bool": map[string]any{"must": []any{}}},
+		"size":  100,
+	}
+
+	t.Run("delete keeps only the query", func(t *testing.T) {
+		body, err := BuildByQueryBody(filtered, OperationDeleteByQuery, "")
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]any{"query": filtered["query"]}, body)
+	})
+
+	t.Run("update adds a painless script", func(t *testing.T) {
+		body, err := BuildByQueryBody(filtered, OperationUpdateByQuery, " ctx._source.flag = true ")
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]any{"source": "ctx._source.flag = true", "lang": "painless"}, body["script"])
+	})
+
+	t.Run("update without script is rejected", func(t *testing.T) {
+		_, err := BuildByQueryBody(filtered, OperationUpdateByQuery, "  ")
+		assert.ErrorContains(t, err, "requires a script")
+	})
+
+	t.Run("match_all is rejected", func(t *testing.T) {
+		_, err := BuildByQueryBody(map[string]any{"query": map[string]any{"match_all": map[string]any{}}}, OperationDeleteByQuery, "")
+		assert.ErrorContains(t, err, "refusing")
+	})
+
+	t.Run("missing query is rejected", func(t *testing.T) {
+		_, err := BuildByQueryBody(map[string]any{}, OperationDeleteByQuery, "")
+		assert.Error(t, err)
+	})
+}
+
+func TestParseByQueryResponse(t *testing.T) {
+	result, err := ParseByQueryResponse([]byte(`{
+		"took": 147, "timed_out": false, "total": 120, "updated": 118, "deleted": 0,
+		"version_conflicts": 2, "failures": [{"cause": "boom"}]
+	}`))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.Equal(t, 147, result.Took)
+	assert.Equal(t, int64(120), result.Total)
+	assert.Equal(t, int64(118), result.Updated)
+	assert.Equal(t, int64(2), result.VersionConflicts)
+	assert.Len(t, result.Failures, 1)
+
+	_, err = ParseByQueryResponse([]byte(`not json`))
+	assert.Error(t, err)
+}
@@ -2,8 +2,10 @@ package elastic
 
 import (
 	"fmt"
-	"github.com/gdamore/tcell/v2"
+
 	"github.com/rivo/tview"
+	"github.com/tpelletiersophos/cloudcutter/internal/ui/components/vtable"
+	"github.com/tpelletiersophos/cloudcutter/internal/ui/style"
 )
 
 func (v *View) nextPage() {
@@ -15,7 +17,7 @@ func (v *View) nextPage() {
 		v.state.pagination.currentPage++
 		v.displayCurrentPage()
 	} else {
-		v.manager.UpdateStatusBar("Already on the last page.")
+		v.manager.NotifyInfo(v.Name(), "Already on the last page.")
 	}
 }
 
@@ -28,7 +30,7 @@ func (v *View) previousPage() {
 		v.state.pagination.currentPage--
 		v.displayCurrentPage()
 	} else {
-		v.manager.UpdateStatusBar("Already on the first page.")
+		v.manager.NotifyInfo(v.Name(), "Already on the first page.")
 	}
 }
 
@@ -40,7 +42,7 @@ func (v *View) displayCurrentPage() {
 
 	headers := v.getActiveHeaders()
 	if len(headers) == 0 {
-		v.manager.UpdateStatusBar("No fields selected. Select a field to see data.")
+		v.manager.NotifyInfo(v.Name(), "No fields selected. Select a field to see data.")
 		return
 	}
 
@@ -50,17 +52,17 @@ func (v *View) displayCurrentPage() {
 	}
 	displayHeaders = append(displayHeaders, headers...)
 
-	v.setupResultsTableHeaders(displayHeaders)
-
 	v.state.mu.RLock()
 	displayedResults := v.state.data.displayedResults
 	currentPage := v.state.pagination.currentPage
 	pageSize := v.state.pagination.pageSize
 	v.state.mu.RUnlock()
 
+	v.fixTableHeaders(table)
 	totalResults := len(displayedResults)
 	if totalResults == 0 {
-		v.manager.UpdateStatusBar("No results to display.")
+		table.SetContent(vtable.New(vtable.NewRows(headerCells(displayHeaders), v.resultRows(nil, headers), 0, 0)))
+		v.manager.NotifyInfo(v.Name(), "No results to display.")
 		return
 	}
 
@@ -75,38 +77,44 @@ func (v *View) displayCurrentPage() {
 		end = totalResults
 	}
 
-	pageResults := displayedResults[start:end]
-	numCols := len(displayHeaders)
-	cells := make([][]*tview.TableCell, len(pageResults))
+	// Only the rows the table draws are rendered, however large the page
+	rows := vtable.NewRows(headerCells(displayHeaders), v.resultRows(displayedResults, headers), start, end-start)
+	table.SetContent(vtable.New(rows))
 
-	for rowIdx := range pageResults {
-		cells[rowIdx] = make([]*tview.TableCell, numCols)
-		entry := pageResults[rowIdx]
-		currentCol := 0
+	table.SetOffset(oldRowOffset, oldColOffset)
 
-		if v.state.ui.showRowNumbers {
-			cells[rowIdx][currentCol] = tview.NewTableCell(fmt.Sprintf("%d", start+rowIdx+1)).
-				SetTextColor(tcell.ColorGray).
-				SetAlign(tview.AlignRight)
-			currentCol++
-		}
+	v.updateStatusBar(rows.RowCount())
+	v.updateHeader()
+}
 
-		for _, header := range headers {
-			cells[rowIdx][currentCol] = tview.NewTableCell(entry.GetFormattedValue(header)).
-				SetTextColor(tcell.ColorBeige).
-				SetAlign(tview.AlignLeft)
-			currentCol++
-		}
-	}
+// resultRowsPerLoad is how many result rows are rendered at a time as the table scrolls.
+const resultRowsPerLoad = 64
 
-	for rowIdx, row := range cells {
-		for colIdx, cell := range row {
-			table.SetCell(rowIdx+1, colIdx, cell)
+// resultRows renders entries into table rows on demand, numbering them from 1.
+func (v *View) resultRows(entries []*DocEntry, headers []string) *vtable.Pages[[]*tview.TableCell] {
+	return vtable.NewPages(len(entries), resultRowsPerLoad, func(offset, limit int) ([][]*tview.TableCell, error) {
+		rows := make([][]*tview.TableCell, 0, limit)
+		for i, entry := range entries[offset : offset+limit] {
+			rows = append(rows, v.entryCells(entry, headers, offset+i+1))
 		}
-	}
+		return rows, nil
+	})
+}
 
-	table.SetOffset(oldRowOffset, oldColOffset)
+// entryCells renders one results row for entry, prefixed with rowNumber when row numbers are shown.
+func (v *View) entryCells(entry *DocEntry, headers []string, rowNumber int) []*tview.TableCell {
+	cells := make([]*tview.TableCell, 0, len(headers)+1)
 
-	v.updateStatusBar(len(pageResults))
-	v.updateHeader()
+	if v.state.ui.showRowNumbers {
+		cells = append(cells, tview.NewTableCell(fmt.Sprintf("%d", rowNumber)).
+			SetTextColor(style.Color(style.Muted)).
+			SetAlign(tview.AlignRight))
+	}
+
+	for _, header := range headers {
+		cells = append(cells, tview.NewTableCell(v.cellText(entry, header)).
+			SetTextColor(style.Color(style.Text)).
+			SetAlign(tview.AlignLeft))
+	}
+	return cells
 }
@@ -14,6 +14,13 @@ var (
 	validOperatorRegex = regexp.MustCompile(`^(>=|<=|>|<|=)$`)
 )
 
+// Highlight tags requested from Elasticsearch. They are chosen so they cannot be
+// confused with tview color tags or typical document content.
+const (
+	HighlightPreTag  = "@@cc-hl@@"
+	HighlightPostTag = "@@/cc-hl@@"
+)
+
 type ParseError struct {
 	Field   string
 	Message string
@@ -79,14 +86,81 @@ func BuildQueryWithTime(filters []string, size int, timeframe string, now time.T
 	}
 
 	// Otherwise, build a bool query with everything in "must"
-	return map[string]any{
+	query := map[string]any{
 		"query": map[string]any{
 			"bool": map[string]any{
 				"must": mustClauses,
 			},
 		},
 		"size": size,
-	}, nil
+	}
+
+	if highlight := BuildHighlight(filters, fieldCache); highlight != nil {
+		query["highlight"] = highlight
+	}
+
+	return query, nil
+}
+
+// BuildHighlight requests highlight fragments for the text and keyword fields referenced by filters.
+// Returns nil when none of the filters target a highlightable field.
+func BuildHighlight(filters []string, fieldCache *FieldCache) map[string]any {
+	fields := make(map[string]any)
+	for _, f := range filters {
+		if field, ok := highlightField(f, fieldCache); ok {
+			// number_of_fragments 0 returns the whole field value with matches tagged
+			fields[field] = map[string]any{
+				"number_of_fragments": 0,
+			}
+		}
+	}
+
+	if len(fields) == 0 {
+		return nil
+	}
+
+	return map[string]any{
+		"pre_tags":            []string{HighlightPreTag},
+		"post_tags":           []string{HighlightPostTag},
+		"require_field_match": true,
+		"fields":              fields,
+	}
+}
+
+// highlightField returns the field name a filter targets if that field supports highlighting.
+func highlightField(filter string, fieldCache *FieldCache) (string, bool) {
+	filter = strings.TrimSpace(filter)
+	if strings.HasPrefix(filter, "_id=") || strings.ContainsAny(filter, "<>") {
+		return "", false
+	}
+
+	parts := strings.SplitN(filter, "=", 2)
+	if len(parts) != 2 {
+		return "", false
+	}
+
+	fieldName := strings.TrimSpace(parts[0])
+	value := strings.TrimSpace(parts[1])
+	if !isValidFieldName(fieldName) || value == "" || isNullValue(value) {
+		return "", false
+	}
+
+	if metadata, exists := fieldCache.Get(fieldName); exists {
+		if isNumericFieldType(metadata.Type) || metadata.Type == "date" || metadata.Type == "boolean" {
+			return "", false
+		}
+	}
+
+	return fieldName, true
+}
+
+// isNumericFieldType reports whether an Elasticsearch mapping type holds numbers.
+func isNumericFieldType(fieldType string) bool {
+	switch fieldType {
+	case "long", "integer", "short", "byte", "float", "double", "half_float", "scaled_float":
+		return true
+	}
+	return false
 }
 
 func ParseFilter(filter string, fieldCache *FieldCache) (map[string]any, error) {
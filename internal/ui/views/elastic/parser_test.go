@@ -119,6 +119,16 @@ func TestBuildQuery(t *testing.T) {
 					},
 				},
 				"size": 20,
+				"highlight": map[string]any{
+					"pre_tags":            []string{HighlightPreTag},
+					"post_tags":           []string{HighlightPostTag},
+					"require_field_match": true,
+					"fields": map[string]any{
+						"status": map[string]any{
+							"number_of_fragments": 0,
+						},
+					},
+				},
 			},
 		},
 		{
@@ -153,6 +163,82 @@ func TestBuildQuery(t *testing.T) {
 	}
 }
 
+func TestBuildHighlight(t *testing.T) {
+	fieldCache := newTestFieldCache()
+	tests := []struct {
+		name       string
+		filters    []string
+		wantFields []string
+	}{
+		{
+			name:       "Text and keyword fields are highlighted",
+			filters:    []string{"status=active", "description=error*"},
+			wantFields: []string{"status", "description"},
+		},
+		{
+			name:       "Unknown fields default to highlightable",
+			filters:    []string{"message=timeout"},
+			wantFields: []string{"message"},
+		},
+		{
+			name:    "Numeric, boolean and range filters are skipped",
+			filters: []string{"age=30", "active=true", "price>10"},
+		},
+		{
+			name:    "Id and null filters are skipped",
+			filters: []string{"_id=abc", "name=null"},
+		},
+		{
+			name: "No filters",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := BuildHighlight(tt.filters, fieldCache)
+			if len(tt.wantFields) == 0 {
+				if got != nil {
+					t.Errorf("BuildHighlight() = %v, want nil", got)
+				}
+				return
+			}
+
+			fields, ok := got["fields"].(map[string]any)
+			if !ok {
+				t.Fatalf("BuildHighlight() missing fields: %v", got)
+			}
+			if len(fields) != len(tt.wantFields) {
+				t.Errorf("BuildHighlight() fields = %v, want %v", fields, tt.wantFields)
+			}
+			for _, field := range tt.wantFields {
+				if _, ok := fields[field]; !ok {
+					t.Errorf("BuildHighlight() missing field %q in %v", field, fields)
+				}
+			}
+		})
+	}
+}
+
+func TestHighlightRendering(t *testing.T) {
+	fragment := "user " + HighlightPreTag + "admin" + HighlightPostTag + " logged [in]"
+	got := highlightMarkup(fragment)
+	want := "user " + highlightOpenTag() + "admin" + highlightCloseTag + " logged [in[]"
+	if got != want {
+		t.Errorf("highlightMarkup() = %q, want %q", got, want)
+	}
+
+	got = highlightTerms(`"message": "Connection Timeout after timeout"`, []string{"timeout"})
+	want = `"message": "Connection ` + highlightOpenTag() + "Timeout" + highlightCloseTag +
+		" after " + highlightOpenTag() + "timeout" + highlightCloseTag + `"`
+	if got != want {
+		t.Errorf("highlightTerms() = %q, want %q", got, want)
+	}
+
+	if got := highlightTerms("no match here", []string{"absent"}); got != "no match here" {
+		t.Errorf("highlightTerms() = %q, want input unchanged", got)
+	}
+}
+
 func TestParseFilter(t *testing.T) {
 	fieldCache := newTestFieldCache()
 	tests := []struct {
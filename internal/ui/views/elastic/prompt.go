@@ -1,10 +1,10 @@
 package elastic
 
 import (
-	"github.com/gdamore/tcell/v2"
 	"github.com/rivo/tview"
 	"github.com/tpelletiersophos/cloudcutter/internal/ui/components"
 	"github.com/tpelletiersophos/cloudcutter/internal/ui/components/types"
+	"github.com/tpelletiersophos/cloudcutter/internal/ui/style"
 )
 
 func (v *View) showFilterPrompt(source tview.Primitive) {
@@ -15,7 +15,7 @@ func (v *View) showFilterPrompt(source tview.Primitive) {
 		v.components.filterPrompt.Configure(components.PromptOptions{
 			Title:      " Filter Fields ",
 			Label:      " >_ ",
-			LabelColor: tcell.ColorMediumTurquoise,
+			LabelColor: style.Label,
 			OnChanged: func(text string) {
 				v.filterFieldList(text)
 			},
@@ -37,7 +37,7 @@ func (v *View) showFilterPrompt(source tview.Primitive) {
 		v.components.filterPrompt.Configure(components.PromptOptions{
 			Title:      " Filter Results ",
 			Label:      " >_ ",
-			LabelColor: tcell.ColorMediumTurquoise,
+			LabelColor: style.Label,
 			OnChanged: func(text string) {
 				v.displayFilteredResults(text)
 			},
@@ -56,7 +56,7 @@ func (v *View) showFilterPrompt(source tview.Primitive) {
 		v.components.filterPrompt.Configure(components.PromptOptions{
 			Title:      " Filter Results ",
 			Label:      " >_ ",
-			LabelColor: tcell.ColorMediumTurquoise,
+			LabelColor: style.Label,
 			OnChanged: func(text string) {
 				v.components.localFilterInput.SetText(v.components.filterPrompt.GetText())
 			},
@@ -87,7 +87,7 @@ func (v *View) HandleFilter(prompt *components.Prompt, previousFocus tview.Primi
 		opts = components.PromptOptions{
 			Title:      " Filter Query ",
 			Label:      " >_ ",
-			LabelColor: tcell.ColorMediumTurquoise,
+			LabelColor: style.Label,
 			OnDone: func(text string) {
 				v.addFilter(text)
 				v.components.filterInput.SetText("")
@@ -106,7 +106,7 @@ func (v *View) HandleFilter(prompt *components.Prompt, previousFocus tview.Primi
 		opts = components.PromptOptions{
 			Title:      " Filter Fields ",
 			Label:      " >_ ",
-			LabelColor: tcell.ColorMediumTurquoise,
+			LabelColor: style.Label,
 			OnChanged: func(text string) {
 				v.filterFieldList(text)
 			},
@@ -128,7 +128,7 @@ func (v *View) HandleFilter(prompt *components.Prompt, previousFocus tview.Primi
 		opts = components.PromptOptions{
 			Title:      " Filter Results ",
 			Label:      " >_ ",
-			LabelColor: tcell.ColorMediumTurquoise,
+			LabelColor: style.Label,
 			OnChanged: func(text string) {
 				v.displayFilteredResults(text)
 			},
@@ -9,15 +9,28 @@ import (
 	"strings"
 	"time"
 
+	"github.com/elastic/go-elasticsearch/v6"
 	"github.com/tpelletiersophos/cloudcutter/internal/services/elastic"
 )
 
 type searchResult struct {
 	entries   []*DocEntry
 	totalHits int
+	info      searchInfo
 }
 
-func (v *View) fetchRegularResults(query map[string]any, numResults int, index string) (*searchResult, error) {
+// searchInfo records what was sent for the last results search and how the cluster responded.
+type searchInfo struct {
+	index     string
+	query     map[string]any
+	took      int
+	timedOut  bool
+	shards    elastic.ESShards
+	totalHits int
+	scroll    bool
+}
+
+func (v *View) fetchRegularResults(client *elasticsearch.Client, query map[string]any, numResults int, index string) (*searchResult, error) {
 	query["size"] = numResults
 
 	maxRetries := 3
@@ -31,16 +44,16 @@ func (v *View) fetchRegularResults(query map[string]any, numResults int, index s
 			return nil, fmt.Errorf("error encoding query: %v", err)
 		}
 
-		res, err := v.service.Client.Search(
-			v.service.Client.Search.WithIndex(index),
-			v.service.Client.Search.WithBody(bytes.NewReader(queryJSON)),
+		res, err := client.Search(
+			client.Search.WithIndex(index),
+			client.Search.WithBody(bytes.NewReader(queryJSON)),
 		)
 
 		if err != nil {
 			lastErr = err
 			if strings.Contains(err.Error(), "429") {
 				v.state.misc.rateLimit.HandleTooManyRequests()
-				v.manager.UpdateStatusBar(fmt.Sprintf("Rate limited (attempt %d/%d), retrying in %v...",
+				v.manager.NotifyWarning(v.Name(), fmt.Sprintf("Rate limited (attempt %d/%d), retrying in %v...",
 					attempt+1, maxRetries, v.state.misc.rateLimit.GetRetryAfter()))
 				continue
 			}
@@ -56,7 +69,7 @@ func (v *View) fetchRegularResults(query map[string]any, numResults int, index s
 		// rate limit?
 		if res.StatusCode == 429 {
 			v.state.misc.rateLimit.HandleTooManyRequests()
-			v.manager.UpdateStatusBar(fmt.Sprintf("Rate limited (attempt %d/%d), retrying in %v...",
+			v.manager.NotifyWarning(v.Name(), fmt.Sprintf("Rate limited (attempt %d/%d), retrying in %v...",
 				attempt+1, maxRetries, v.state.misc.rateLimit.GetRetryAfter()))
 			continue
 		}
@@ -76,6 +89,14 @@ func (v *View) fetchRegularResults(query map[string]any, numResults int, index s
 		return &searchResult{
 			entries:   entries,
 			totalHits: result.Hits.GetTotalHits(),
+			info: searchInfo{
+				index:     index,
+				query:     query,
+				took:      result.Took,
+				timedOut:  result.TimedOut,
+				shards:    result.Shards,
+				totalHits: result.Hits.GetTotalHits(),
+			},
 		}, nil
 	}
 
@@ -90,6 +111,7 @@ func (v *View) fetchLargeResults(query map[string]any, index string) (*searchRes
 	// Initial scroll request with retries
 	var scrollID string
 	var allResults []*DocEntry
+	info := searchInfo{index: index, query: query, scroll: true}
 
 	for attempt := 0; attempt < maxRetries; attempt++ {
 		v.state.misc.rateLimit.Wait()
@@ -108,7 +130,7 @@ func (v *View) fetchLargeResults(query map[string]any, index string) (*searchRes
 			lastErr = err
 			if strings.Contains(err.Error(), "429") {
 				v.state.misc.rateLimit.HandleTooManyRequests()
-				v.manager.UpdateStatusBar(fmt.Sprintf("Rate limited (attempt %d/%d), retrying in %v...",
+				v.manager.NotifyWarning(v.Name(), fmt.Sprintf("Rate limited (attempt %d/%d), retrying in %v...",
 					attempt+1, maxRetries, v.state.misc.rateLimit.GetRetryAfter()))
 				continue
 			}
@@ -124,7 +146,7 @@ func (v *View) fetchLargeResults(query map[string]any, index string) (*searchRes
 
 		if res.StatusCode == 429 {
 			v.state.misc.rateLimit.HandleTooManyRequests()
-			v.manager.UpdateStatusBar(fmt.Sprintf("Rate limited (attempt %d/%d), retrying in %v...",
+			v.manager.NotifyWarning(v.Name(), fmt.Sprintf("Rate limited (attempt %d/%d), retrying in %v...",
 				attempt+1, maxRetries, v.state.misc.rateLimit.GetRetryAfter()))
 			continue
 		}
@@ -140,6 +162,10 @@ func (v *View) fetchLargeResults(query map[string]any, index string) (*searchRes
 		}
 		allResults = append(allResults, entries...)
 		scrollID = result.ScrollID
+		info.took = result.Took
+		info.timedOut = result.TimedOut
+		info.shards = result.Shards
+		info.totalHits = result.Hits.GetTotalHits()
 
 		// Successfully got first batch
 		v.state.misc.rateLimit.Reset()
@@ -165,7 +191,7 @@ func (v *View) fetchLargeResults(query map[string]any, index string) (*searchRes
 			if err != nil {
 				if strings.Contains(err.Error(), "429") {
 					v.state.misc.rateLimit.HandleTooManyRequests()
-					v.manager.UpdateStatusBar(fmt.Sprintf("Rate limited (attempt %d/%d), retrying in %v...",
+					v.manager.NotifyWarning(v.Name(), fmt.Sprintf("Rate limited (attempt %d/%d), retrying in %v...",
 						attempt+1, maxRetries, v.state.misc.rateLimit.GetRetryAfter()))
 					continue
 				}
@@ -175,7 +201,7 @@ func (v *View) fetchLargeResults(query map[string]any, index string) (*searchRes
 			if scrollRes.StatusCode == 429 {
 				scrollRes.Body.Close()
 				v.state.misc.rateLimit.HandleTooManyRequests()
-				v.manager.UpdateStatusBar(fmt.Sprintf("Rate limited (attempt %d/%d), retrying in %v...",
+				v.manager.NotifyWarning(v.Name(), fmt.Sprintf("Rate limited (attempt %d/%d), retrying in %v...",
 					attempt+1, maxRetries, v.state.misc.rateLimit.GetRetryAfter()))
 				continue
 			}
@@ -215,6 +241,7 @@ func (v *View) fetchLargeResults(query map[string]any, index string) (*searchRes
 	return &searchResult{
 		entries:   allResults,
 		totalHits: len(allResults),
+		info:      info,
 	}, nil
 }
 
@@ -243,7 +270,7 @@ func (v *View) executeSearch(query map[string]any) (*elastic.ESSearchResult, err
 			// rate limit?
 			if strings.Contains(err.Error(), "429") {
 				v.state.misc.rateLimit.HandleTooManyRequests()
-				v.manager.UpdateStatusBar(fmt.Sprintf("Rate limited, retrying in %v...", v.state.misc.rateLimit.GetRetryAfter()))
+				v.manager.NotifyWarning(v.Name(), fmt.Sprintf("Rate limited, retrying in %v...", v.state.misc.rateLimit.GetRetryAfter()))
 				continue
 			}
 			v.manager.Logger().Error("Search query failed", "error", err, "index", v.state.search.currentIndex)
@@ -260,7 +287,7 @@ func (v *View) executeSearch(query map[string]any) (*elastic.ESSearchResult, err
 		// rate limit?
 		if res.StatusCode == 429 {
 			v.state.misc.rateLimit.HandleTooManyRequests()
-			v.manager.UpdateStatusBar(fmt.Sprintf("Rate limited, retrying in %v...", v.state.misc.rateLimit.GetRetryAfter()))
+			v.manager.NotifyWarning(v.Name(), fmt.Sprintf("Rate limited, retrying in %v...", v.state.misc.rateLimit.GetRetryAfter()))
 			continue
 		}
 
@@ -291,12 +318,14 @@ func (v *View) fetchResults() (*searchResult, error) {
 	if numResults > 10000 {
 		return v.fetchLargeResults(query, currentIndex)
 	}
-	return v.fetchRegularResults(query, numResults, currentIndex)
+	return v.fetchRegularResults(v.service.Client, query, numResults, currentIndex)
 }
 
 func (v *View) refreshResults() {
 	v.state.mu.Lock()
 	if v.state.ui.isLoading {
+		// Run again once the current search finishes, so the latest filters always win
+		v.state.ui.refreshQueued = true
 		v.state.mu.Unlock()
 		return
 	}
@@ -307,21 +336,16 @@ func (v *View) refreshResults() {
 	v.showLoading("Refreshing results")
 
 	go func() {
-		defer func() {
-			v.state.mu.Lock()
-			v.state.ui.isLoading = false
-			v.state.mu.Unlock()
-			v.hideLoading()
-			v.manager.App().QueueUpdateDraw(func() {
-				v.manager.SetFocus(currentFocus)
-			})
-		}()
+		defer v.manager.App().QueueUpdateDraw(func() {
+			v.manager.SetFocus(currentFocus)
+			v.finishLoading(false)
+		})
 
 		searchResult, err := v.fetchResults()
 		if err != nil {
 			v.manager.Logger().Error("Error fetching results", "error", err)
 			v.manager.App().QueueUpdateDraw(func() {
-				v.manager.UpdateStatusBar(fmt.Sprintf("Error: %v", err))
+				v.manager.NotifyError(v.Name(), fmt.Sprintf("Error: %v", err))
 			})
 			return
 		}
@@ -330,6 +354,7 @@ func (v *View) refreshResults() {
 
 		// Update results state
 		v.state.mu.Lock()
+		v.state.search.lastSearch = &searchResult.info
 		v.state.data.filteredResults = searchResult.entries
 		v.state.data.displayedResults = append([]*DocEntry(nil), searchResult.entries...)
 		v.state.pagination.totalPages = int(math.Ceil(float64(len(searchResult.entries)) /
@@ -343,12 +368,29 @@ func (v *View) refreshResults() {
 			//v.updateIndexStats()
 			v.displayCurrentPage()
 			v.updateHeader()
-			v.manager.UpdateStatusBar(fmt.Sprintf("Found %d results total (displaying %d)",
+			v.manager.NotifyInfo(v.Name(), fmt.Sprintf("Found %d results total (displaying %d)",
 				searchResult.totalHits, len(searchResult.entries)))
+			v.manager.SaveSession()
 		})
 	}()
 }
 
+// finishLoading ends a load on the event loop: it hides the spinner and starts the search asked for
+// meanwhile, or one anyway with refresh. A search shows the spinner again in the same update, so
+// the loading page only goes once the view has settled.
+func (v *View) finishLoading(refresh bool) {
+	v.state.mu.Lock()
+	v.state.ui.isLoading = false
+	refresh = refresh || v.state.ui.refreshQueued
+	v.state.ui.refreshQueued = false
+	v.state.mu.Unlock()
+
+	v.hideLoading()
+	if refresh {
+		v.refreshWithCurrentTimeframe()
+	}
+}
+
 func (v *View) processSearchResults(hits []elastic.ESSearchHit) ([]*DocEntry, error) {
 	results := make([]*DocEntry, 0, len(hits))
 	errorCount := 0
@@ -370,6 +412,7 @@ func (v *View) processSearchResults(hits []elastic.ESSearchHit) ([]*DocEntry, er
 				"hitID", hit.ID)
 			continue
 		}
+		entry.SetHighlights(hit.Highlight)
 		results = append(results, entry)
 	}
 
@@ -394,7 +437,7 @@ func (v *View) buildQuery() map[string]any {
 	query, err := BuildQuery(filters, numResults, timeframe, v.state.data.fieldCache)
 	if err != nil {
 		v.manager.Logger().Error("Error building query", "error", err)
-		v.manager.UpdateStatusBar(fmt.Sprintf("Error building query: %v", err))
+		v.manager.NotifyError(v.Name(), fmt.Sprintf("Error building query: %v", err))
 		return nil
 	}
 
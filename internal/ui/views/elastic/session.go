@@ -0,0 +1,85 @@
+package elastic
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/tpelletiersophos/cloudcutter/internal/deeplink"
+	"github.com/tpelletiersophos/cloudcutter/internal/session"
+	"github.com/tpelletiersophos/cloudcutter/internal/ui/views"
+)
+
+var _ views.Persistent = (*View)(nil)
+
+// SaveState records the search, the selected fields in column order and the layout in state.
+func (v *View) SaveState(state *session.State) {
+	v.state.mu.RLock()
+	defer v.state.mu.RUnlock()
+
+	state.Elastic = &session.ElasticState{
+		Index:          v.state.search.currentIndex,
+		Filters:        append([]string(nil), v.state.data.filters...),
+		Timeframe:      v.state.search.timeframe,
+		SelectedFields: v.state.data.fieldState.GetOrderedSelectedFields(),
+		NumResults:     v.state.search.numResults,
+		RowNumbers:     v.state.ui.showRowNumbers,
+		FieldList:      v.state.ui.fieldListVisible,
+	}
+}
+
+// RestoreState reopens a saved search. An index pattern that no longer matches an index leaves the
+// current one in place, and selected fields the new documents lack are skipped.
+func (v *View) RestoreState(state session.State) {
+	saved := state.Elastic
+	if saved == nil {
+		return
+	}
+
+	v.state.mu.Lock()
+	if saved.NumResults > 0 {
+		v.state.search.numResults = saved.NumResults
+	}
+	v.state.ui.showRowNumbers = saved.RowNumbers
+	v.state.ui.fieldListVisible = saved.FieldList
+	v.state.data.pendingFields = append([]string(nil), saved.SelectedFields...)
+	numResults := v.state.search.numResults
+	currentIndex := v.state.search.currentIndex
+	v.state.mu.Unlock()
+
+	v.components.numResultsInput.SetText(strconv.Itoa(numResults))
+	v.updateResultsLayout()
+
+	link := deeplink.Link{
+		Filters:   append([]string{}, saved.Filters...),
+		Timeframe: saved.Timeframe,
+	}
+	if saved.Index == "" || saved.Index == currentIndex {
+		v.ApplyLink(link)
+		return
+	}
+
+	go func() {
+		indices, err := v.service.ListIndices(v.manager.ViewContext(), saved.Index)
+		v.manager.App().QueueUpdateDraw(func() {
+			if err == nil && len(indices) == 0 {
+				v.manager.NotifyWarning(v.Name(), fmt.Sprintf("Index %s no longer exists; staying on %s", saved.Index, currentIndex))
+			} else {
+				link.Index = saved.Index
+			}
+			v.ApplyLink(link)
+		})
+	}()
+}
+
+// restoreSelectedFields selects the fields of a restored session, in their saved order, once
+// documents have shown which of them the index still has.
+func (v *View) restoreSelectedFields() {
+	v.state.mu.Lock()
+	fields := v.state.data.pendingFields
+	v.state.data.pendingFields = nil
+	v.state.mu.Unlock()
+
+	for _, field := range fields {
+		v.state.data.fieldState.SelectField(field)
+	}
+}
@@ -53,7 +53,7 @@ func (h *ElasticResultsTableHandler) HandleEvent(event *tcell.EventKey, view *Vi
 			entry, err := h.getDocumentEntry(row)
 			if err != nil {
 				h.view.manager.App().QueueUpdateDraw(func() {
-					h.view.manager.UpdateStatusBar(fmt.Sprintf("Error: %v", err))
+					h.view.manager.NotifyError(h.view.Name(), fmt.Sprintf("Error: %v", err))
 				})
 				return
 			}
@@ -163,7 +163,7 @@ func (h *SimpleResultsTableHandler) HandleEvent(event *tcell.EventKey, ctx *Simp
 			entry, err := h.getDocumentEntry(ctx.View, row)
 			if err != nil {
 				ctx.View.manager.App().QueueUpdateDraw(func() {
-					ctx.View.manager.UpdateStatusBar(fmt.Sprintf("Error: %v", err))
+					ctx.View.manager.NotifyError(ctx.View.Name(), fmt.Sprintf("Error: %v", err))
 				})
 				return
 			}
@@ -281,7 +281,7 @@ func (h *SimpleFilterInputHandler) HandleEvent(event *tcell.EventKey, ctx *Simpl
 			ctx.View.refreshResults()
 
 		case ctx.View.components.timeframeInput:
-			ctx.View.manager.UpdateStatusBar("Timeframe processing temporarily disabled for debugging")
+			ctx.View.manager.NotifyWarning(ctx.View.Name(), "Timeframe processing temporarily disabled for debugging")
 			return nil
 		}
 
@@ -24,7 +24,9 @@ type PaginationState struct {
 
 type UIState struct {
 	showRowNumbers   bool
+	highlightEnabled bool
 	isLoading        bool
+	refreshQueued    bool // a refresh was asked for while one was loading
 	fieldListFilter  string
 	fieldListVisible bool
 }
@@ -35,6 +37,7 @@ type DataState struct {
 
 	filters       []string
 	currentFilter string
+	pendingFields []string // selected in a restored session, chosen once documents arrive
 
 	currentResults   []*DocEntry
 	filteredResults  []*DocEntry
@@ -49,6 +52,7 @@ type SearchState struct {
 	timeframe       string
 	indexStats      *elastic.IndexStats
 	cancelCurrentOp context.CancelFunc
+	lastSearch      *searchInfo
 }
 
 type MiscState struct {
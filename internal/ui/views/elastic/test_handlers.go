@@ -33,7 +33,7 @@ func (h *MockFilterInputHandler) HandleEvent(event *tcell.EventKey, view *View)
 		h.view.state.mu.Lock()
 		if _, err := ParseFilter(text, h.view.state.data.fieldCache); err != nil {
 			h.view.state.mu.Unlock()
-			h.view.manager.UpdateStatusBar(fmt.Sprintf("Invalid filter: %v", err))
+			h.view.manager.NotifyWarning(h.view.Name(), fmt.Sprintf("Invalid filter: %v", err))
 			return nil
 		}
 
@@ -190,7 +190,7 @@ func (h *MockTimeframeInputHandler) HandleEvent(event *tcell.EventKey, view *Vie
 	case tcell.KeyEnter:
 		timeframe := h.view.components.timeframeInput.GetText()
 		if err := ValidateTimeframe(timeframe); err != nil {
-			h.view.manager.UpdateStatusBar(fmt.Sprintf("Error: %v", err))
+			h.view.manager.NotifyError(h.view.Name(), fmt.Sprintf("Error: %v", err))
 			return nil
 		}
 		h.view.state.search.timeframe = h.view.components.timeframeInput.GetText()
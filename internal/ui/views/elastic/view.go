@@ -6,8 +6,10 @@ import (
 	"github.com/gdamore/tcell/v2"
 	"github.com/rivo/tview"
 	"github.com/tpelletiersophos/cloudcutter/internal/services/elastic"
+	"github.com/tpelletiersophos/cloudcutter/internal/ui/common"
 	"github.com/tpelletiersophos/cloudcutter/internal/ui/components"
 	"github.com/tpelletiersophos/cloudcutter/internal/ui/manager"
+	"github.com/tpelletiersophos/cloudcutter/internal/ui/style"
 	"strings"
 )
 
@@ -17,6 +19,9 @@ type View struct {
 	service    *elastic.Service
 	state      State
 	layout     tview.Primitive
+	keys       *common.KeyResolver
+
+	modalCaptures []*modalCapture // input captures of the open modals, innermost last
 }
 
 type viewComponents struct {
@@ -35,26 +40,33 @@ type viewComponents struct {
 	listsContainer   *tview.Flex
 }
 
+// NewView creates the Elastic view opened at defaultIndex, or the configured default index when empty.
 func NewView(manager *manager.Manager, esClient *elastic.Service, defaultIndex string) (*View, error) {
+	config := GetGlobalConfig()
+	if defaultIndex == "" {
+		defaultIndex = config.Search.DefaultIndex
+	}
 	fieldCache := NewFieldCache()
 	fieldState := NewFieldState(fieldCache)
 
 	v := &View{
 		manager: manager,
 		service: esClient,
+		keys:    common.NewKeyResolver(),
 		components: viewComponents{
 			filterPrompt: components.NewPrompt(),
 		},
 		state: State{
 			pagination: PaginationState{
 				currentPage: 1,
-				pageSize:    50,
+				pageSize:    config.Pagination.DefaultPageSize,
 				totalPages:  1,
 			},
 			ui: UIState{
-				showRowNumbers:  true,
-				isLoading:       false,
-				fieldListFilter: "",
+				showRowNumbers:   config.UI.ShowRowNumbers,
+				highlightEnabled: true,
+				isLoading:        false,
+				fieldListFilter:  "",
 			},
 			data: DataState{
 				fieldCache: fieldCache,
@@ -71,8 +83,8 @@ func NewView(manager *manager.Manager, esClient *elastic.Service, defaultIndex s
 			search: SearchState{
 				currentIndex:    defaultIndex,
 				matchingIndices: []string{},
-				numResults:      1000,
-				timeframe:       "today",
+				numResults:      config.Search.DefaultNumResults,
+				timeframe:       config.Search.DefaultTimeframe,
 			},
 			misc: MiscState{
 				visibleRows:       0,
@@ -86,15 +98,8 @@ func NewView(manager *manager.Manager, esClient *elastic.Service, defaultIndex s
 	v.manager.Logger().Info("Initializing Elastic View", "defaultIndex", defaultIndex)
 
 	v.setupLayout()
-	v.initTimeframeState()
-	err := v.initFieldsSync()
-	if err != nil {
-		v.manager.Logger().Error("Failed to initialize fields", "error", err)
-		// don't return here so the view continues to load
-	}
-
-	v.components.timeframeInput.SetText("today")
-	v.refreshWithCurrentTimeframe()
+	v.components.timeframeInput.SetText(v.state.search.timeframe)
+	v.initFields()
 
 	manager.SetFocus(v.components.filterInput)
 	v.manager.Logger().Info("Elastic View successfully initialized")
@@ -111,53 +116,34 @@ func (v *View) Content() tview.Primitive {
 
 func (v *View) Hide() {}
 
+// ApplyTheme redraws what the view colors as it renders: the active filters and the results table.
+func (v *View) ApplyTheme(*style.Theme) {
+	v.updateFiltersDisplay()
+
+	v.state.mu.RLock()
+	hasResults := len(v.state.data.displayedResults) > 0
+	v.state.mu.RUnlock()
+	if hasResults {
+		v.displayCurrentPage()
+	}
+}
+
 func (v *View) InputHandler() func(event *tcell.EventKey) *tcell.EventKey {
 	return func(event *tcell.EventKey) *tcell.EventKey {
 		currentFocus := v.manager.App().GetFocus()
 
-		switch event.Key() {
-		case tcell.KeyTab:
-			return v.handleTabKey(currentFocus)
-		case tcell.KeyBacktab:
-			return v.handleShiftTabKey(currentFocus)
-
-		case tcell.KeyRune:
-			switch event.Rune() {
-			case 'r':
-				if currentFocus == v.components.resultsTable {
-					v.toggleRowNumbers()
-					return nil
-				}
-			}
-		}
-
-		if event.Rune() == 'n' || event.Rune() == 'p' {
-			if currentFocus == v.components.resultsTable {
-				if event.Rune() == 'n' {
-					v.nextPage()
-				} else if event.Rune() == 'p' {
-					v.previousPage()
-				}
-				return nil
-			}
-		}
-		if event.Key() == tcell.KeyRune && event.Rune() == '/' {
-			switch currentFocus {
-			case v.components.fieldList:
-				v.showFilterPrompt(v.components.fieldList)
-				return nil
-			case v.components.localFilterInput:
-				v.showFilterPrompt(v.components.localFilterInput)
-				return nil
-			case v.components.resultsTable:
-				v.showFilterPrompt(v.components.resultsTable)
-				return nil
-
-			}
+		action, pending := v.keys.Resolve(v.KeyContext(currentFocus), event)
+		if pending || v.handleAction(action, currentFocus) {
+			return nil
 		}
 
 		switch event.Key() {
 		case tcell.KeyEsc:
+			if v.manager.Pages().HasPage(ModalConfirmDelete) {
+				v.manager.Pages().RemovePage(ModalConfirmDelete)
+				v.manager.SetFocus(v.components.resultsTable)
+				return nil
+			}
 			switch currentFocus {
 			case v.components.resultsTable:
 				v.manager.SetFocus(v.components.fieldList)
@@ -177,19 +163,8 @@ func (v *View) InputHandler() func(event *tcell.EventKey) *tcell.EventKey {
 			return v.handleActiveFilters(event)
 		case v.components.indexInput:
 			return v.handleIndexInput(event)
-		case v.components.fieldList:
-			return v.handleFieldList(event)
-		case v.components.selectedList:
-			return v.handleSelectedList(event)
 		case v.components.timeframeInput:
 			return v.handleTimeframeInput(event)
-		case v.components.numResultsInput:
-			return v.handleNumResultsInput(event)
-
-		case v.components.resultsTable:
-			return v.handleResultsTable(event)
-		case v.components.localFilterInput:
-			return v.handleLocalFilterInput(event)
 		}
 
 		return event
@@ -198,22 +173,25 @@ func (v *View) InputHandler() func(event *tcell.EventKey) *tcell.EventKey {
 
 func (v *View) Reinitialize(cfg aws.Config) error {
 	if err := v.service.Reinitialize(cfg, v.manager.CurrentProfile()); err != nil {
-		v.manager.UpdateStatusBar(fmt.Sprintf("Error reinitializing ES service: %v", err))
+		v.manager.NotifyError(v.Name(), fmt.Sprintf("Error reinitializing ES service: %v", err))
 		return err
 	}
 
-	if cfg.Region == "local" {
-		v.components.timeframeInput.SetText("")
-		v.state.search.timeframe = ""
-	}
+	local := cfg.Region == "local"
 
 	v.state.mu.Lock()
+	if local {
+		v.state.search.timeframe = ""
+	}
 	// Reset field management
 	v.state.data.fieldCache = NewFieldCache()
 	v.state.data.fieldState = NewFieldState(v.state.data.fieldCache)
 	v.state.mu.Unlock()
 
 	v.manager.App().QueueUpdateDraw(func() {
+		if local {
+			v.components.timeframeInput.SetText("")
+		}
 		v.components.fieldList.Clear()
 		v.components.selectedList.Clear()
 		v.manager.SetFocus(v.components.filterInput)
@@ -221,7 +199,7 @@ func (v *View) Reinitialize(cfg aws.Config) error {
 
 	// Load fields and rebuild UI
 	if err := v.loadFields(); err != nil {
-		v.manager.UpdateStatusBar(fmt.Sprintf("Error loading fields: %v", err))
+		v.manager.NotifyError(v.Name(), fmt.Sprintf("Error loading fields: %v", err))
 		return err
 	}
 
@@ -245,15 +223,6 @@ func (v *View) Close() error {
 	return nil
 }
 
-func (v *View) initTimeframeState() {
-	defaultTimeframe := "today"
-
-	v.state.search.timeframe = defaultTimeframe
-	v.components.timeframeInput.SetText(defaultTimeframe)
-
-	v.refreshWithCurrentTimeframe()
-}
-
 func (v *View) refreshWithCurrentTimeframe() {
 	timeframe := strings.TrimSpace(v.components.timeframeInput.GetText())
 	v.state.search.timeframe = timeframe
@@ -0,0 +1,115 @@
+package logs
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/tpelletiersophos/cloudcutter/internal/logger"
+)
+
+// maxBacklog is how much of a file is read when it is first seen; older entries are skipped.
+const maxBacklog = 4 << 20
+
+// tail reads the entries appended to today's log files since it last looked.
+type tail struct {
+	dir     string
+	offsets map[string]int64
+}
+
+func newTail(dir string) *tail {
+	return &tail{dir: dir, offsets: make(map[string]int64)}
+}
+
+// read returns the complete entries written since the last read, oldest first.
+func (t *tail) read() ([]logger.Entry, error) {
+	files, err := logger.CurrentFiles(t.dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []logger.Entry
+	for _, path := range files {
+		read, err := t.readFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("reading %s: %w", path, err)
+		}
+		entries = append(entries, read...)
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Time.Before(entries[j].Time)
+	})
+	return entries, nil
+}
+
+func (t *tail) readFile(path string) ([]logger.Entry, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	offset, seen := t.offsets[path]
+	switch {
+	case !seen && info.Size() > maxBacklog:
+		offset = info.Size() - maxBacklog
+	case info.Size() < offset:
+		offset = 0 // truncated
+	}
+	if info.Size() == offset {
+		t.offsets[path] = offset
+		return nil, nil
+	}
+
+	data := make([]byte, info.Size()-offset)
+	if _, err := f.ReadAt(data, offset); err != nil && err != io.EOF {
+		return nil, err
+	}
+	entries, consumed := logger.ParseEntries(data, logger.FileSource(path))
+	t.offsets[path] = offset + int64(consumed)
+	return entries, nil
+}
+
+// filter picks the entries shown: those at level or above whose text has every word of the query.
+type filter struct {
+	level logger.Level
+	words []string
+}
+
+func newFilter(level logger.Level, query string) filter {
+	return filter{level: level, words: strings.Fields(strings.ToLower(query))}
+}
+
+func (f filter) matches(e logger.Entry) bool {
+	if e.Level < f.level {
+		return false
+	}
+	if len(f.words) == 0 {
+		return true
+	}
+	text := strings.ToLower(e.Source + " " + e.Message)
+	for _, word := range f.words {
+		if !strings.Contains(text, word) {
+			return false
+		}
+	}
+	return true
+}
+
+// levels are the minimum levels the level action cycles through.
+var levels = []logger.Level{logger.DEBUG, logger.INFO, logger.WARN, logger.ERROR}
+
+func nextLevel(level logger.Level) logger.Level {
+	for i, l := range levels {
+		if l == level {
+			return levels[(i+1)%len(levels)]
+		}
+	}
+	return levels[0]
+}
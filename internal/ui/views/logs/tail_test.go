@@ -0,0 +1,75 @@
+package logs
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tpelletiersophos/cloudcutter/internal/logger"
+)
+
+func entryText(level, at, message string) string {
+	return "=== [" + level + "] " + at + " ===\n" + message + "\n====================\n"
+}
+
+func TestTail(t *testing.T) {
+	dir := t.TempDir()
+	today := time.Now().Format("2006-01-02")
+	app := filepath.Join(dir, "cloudcutter_"+today+".log")
+	es := filepath.Join(dir, "es_svc_"+today+".log")
+	old := filepath.Join(dir, "cloudcutter_2001-01-01.log")
+
+	require.NoError(t, os.WriteFile(old, []byte(entryText("ERROR", "2001-01-01 10:00:00.000", "yesterday's news")), 0o644))
+	require.NoError(t, os.WriteFile(app, []byte(entryText("INFO", today+" 10:00:02.000", "started")+"=== [ERROR] "+today+" 10:00:03.000 ===\nhalf"), 0o644))
+	require.NoError(t, os.WriteFile(es, []byte(entryText("DEBUG", today+" 10:00:01.000", "connecting")), 0o644))
+
+	tl := newTail(dir)
+	entries, err := tl.read()
+	require.NoError(t, err)
+	require.Len(t, entries, 2, "only today's complete entries")
+	assert.Equal(t, "connecting", entries[0].Message, "entries from every file, oldest first")
+	assert.Equal(t, "es_svc", entries[0].Source)
+	assert.Equal(t, "started", entries[1].Message)
+
+	entries, err = tl.read()
+	require.NoError(t, err)
+	assert.Empty(t, entries, "nothing new")
+
+	f, err := os.OpenFile(app, os.O_APPEND|os.O_WRONLY, 0)
+	require.NoError(t, err)
+	_, err = f.WriteString(" written\n====================\n")
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	entries, err = tl.read()
+	require.NoError(t, err)
+	require.Len(t, entries, 1, "the entry finished since the last read")
+	assert.Equal(t, logger.ERROR, entries[0].Level)
+	assert.Equal(t, "half written", entries[0].Message)
+
+	require.NoError(t, os.WriteFile(app, []byte(entryText("WARN", today+" 10:00:04.000", "truncated")), 0o644))
+	entries, err = tl.read()
+	require.NoError(t, err)
+	require.Len(t, entries, 1, "a truncated file is read from the start")
+	assert.Equal(t, "truncated", entries[0].Message)
+}
+
+func TestFilter(t *testing.T) {
+	entry := logger.Entry{Level: logger.WARN, Source: "es_svc", Message: "Search failed\nindex: logs-2024"}
+
+	assert.True(t, newFilter(logger.DEBUG, "").matches(entry))
+	assert.True(t, newFilter(logger.WARN, "").matches(entry))
+	assert.False(t, newFilter(logger.ERROR, "").matches(entry), "below the level")
+	assert.True(t, newFilter(logger.DEBUG, "FAILED logs-2024").matches(entry), "every word, in any case, anywhere in the message")
+	assert.True(t, newFilter(logger.DEBUG, "es_svc").matches(entry), "the source is searched too")
+	assert.False(t, newFilter(logger.DEBUG, "failed timeout").matches(entry))
+
+	level := logger.DEBUG
+	for _, want := range []logger.Level{logger.INFO, logger.WARN, logger.ERROR, logger.DEBUG} {
+		level = nextLevel(level)
+		assert.Equal(t, want, level)
+	}
+}
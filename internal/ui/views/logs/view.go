@@ -0,0 +1,350 @@
+// Package logs is the :logs view, which tails today's log files as they are written.
+package logs
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+
+	"github.com/tpelletiersophos/cloudcutter/internal/logger"
+	"github.com/tpelletiersophos/cloudcutter/internal/ui/common"
+	"github.com/tpelletiersophos/cloudcutter/internal/ui/components/types"
+	"github.com/tpelletiersophos/cloudcutter/internal/ui/components/vtable"
+	"github.com/tpelletiersophos/cloudcutter/internal/ui/manager"
+	"github.com/tpelletiersophos/cloudcutter/internal/ui/style"
+	"github.com/tpelletiersophos/cloudcutter/internal/ui/views"
+)
+
+const (
+	// maxEntries is how many entries the view keeps; older ones are dropped as new ones arrive.
+	maxEntries = 5000
+	// pollInterval is how often the files are checked for new entries while the view is shown.
+	pollInterval = time.Second
+)
+
+var (
+	_ views.Themed       = (*View)(nil)
+	_ views.KeyContexter = (*View)(nil)
+)
+
+type View struct {
+	manager *manager.Manager
+	tail    *tail
+	keys    *common.KeyResolver
+
+	content *tview.Flex
+	search  *tview.InputField
+	table   *tview.Table
+	details *tview.TextView
+
+	entries []logger.Entry // everything read, oldest first
+	shown   []logger.Entry // the entries passing the filter
+	level   logger.Level
+	query   string
+	follow  bool // keep the newest entry selected as entries arrive
+	cancel  context.CancelFunc
+
+	refreshing bool
+}
+
+// NewView returns the view of the log files in dir.
+func NewView(manager *manager.Manager, dir string) *View {
+	v := &View{
+		manager: manager,
+		tail:    newTail(dir),
+		keys:    common.NewKeyResolver(),
+		level:   logger.DEBUG,
+		follow:  true,
+	}
+	v.setupLayout()
+	return v
+}
+
+func (v *View) Name() string {
+	return manager.ViewLogs
+}
+
+func (v *View) Content() tview.Primitive {
+	return v.content
+}
+
+// Show reads what was logged while the view was hidden and keeps reading until it is hidden again.
+func (v *View) Show() {
+	v.manager.SetFocus(v.table)
+	if v.cancel != nil {
+		return
+	}
+	ctx, cancel := context.WithCancel(v.manager.ViewContext())
+	v.cancel = cancel
+	v.poll()
+	go func() {
+		ticker := time.NewTicker(pollInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				v.manager.App().QueueUpdateDraw(func() {
+					if ctx.Err() == nil {
+						v.poll()
+					}
+				})
+			}
+		}
+	}()
+}
+
+func (v *View) Hide() {
+	if v.cancel != nil {
+		v.cancel()
+		v.cancel = nil
+	}
+}
+
+// ApplyTheme recolors the entries, whose levels are colored as they are drawn.
+func (v *View) ApplyTheme(*style.Theme) {
+	v.refresh()
+}
+
+// KeyContext is "logs" everywhere but the search input, which takes every key typed into it.
+func (v *View) KeyContext(focus tview.Primitive) string {
+	if focus == v.search {
+		return "logs.search"
+	}
+	return "logs"
+}
+
+func (v *View) setupLayout() {
+	v.content = v.manager.CreateLayout(types.LayoutConfig{
+		Direction: tview.FlexRow,
+		Components: []types.Component{
+			{
+				ID:        "logsSearch",
+				Type:      types.ComponentInputField,
+				FixedSize: 3,
+				Style: types.InputFieldStyle{
+					BaseStyle: types.BaseStyle{
+						Border:           true,
+						BorderColor:      style.Border,
+						FocusBorderColor: style.BorderFocus,
+					},
+					LabelColor:           style.Label,
+					FieldBackgroundColor: style.InputBackground,
+					FieldTextColor:       style.InputText,
+				},
+				Properties: types.InputFieldProperties{
+					Label: " Search >_ ",
+					ChangedFunc: func(text string) {
+						v.query = text
+						v.refresh()
+					},
+				},
+			},
+			{
+				ID:         "logsTable",
+				Type:       types.ComponentTable,
+				Proportion: 2,
+				Focus:      true,
+				Style: types.TableStyle{
+					BaseStyle: types.BaseStyle{
+						Border:           true,
+						BorderColor:      style.Border,
+						FocusBorderColor: style.BorderFocus,
+						TitleColor:       style.Title,
+					},
+					SelectedTextColor:       style.SelectionText,
+					SelectedBackgroundColor: style.Selection,
+				},
+			},
+			{
+				ID:         "logsDetails",
+				Type:       types.ComponentTextView,
+				Proportion: 1,
+				Style: types.TextViewStyle{
+					BaseStyle: types.BaseStyle{
+						Border:           true,
+						BorderColor:      style.Border,
+						FocusBorderColor: style.BorderFocus,
+						Title:            " Entry ",
+						TitleColor:       style.Title,
+						TextColor:        style.Text,
+					},
+				},
+				Properties: types.TextViewProperties{
+					Wrap:       true,
+					Scrollable: true,
+				},
+			},
+		},
+	}).(*tview.Flex)
+	v.manager.Pages().AddPage(manager.ViewLogs, v.content, true, false)
+
+	v.search = v.manager.GetPrimitiveByID("logsSearch").(*tview.InputField)
+	v.table = v.manager.GetPrimitiveByID("logsTable").(*tview.Table)
+	v.details = v.manager.GetPrimitiveByID("logsDetails").(*tview.TextView)
+
+	v.table.SetSelectable(true, false).SetFixed(1, 0)
+	v.table.SetContent(vtable.New(entryRows(nil)))
+	v.table.SetSelectionChangedFunc(func(row, _ int) {
+		if !v.refreshing {
+			// Moving off the newest entry stops following; moving back onto it resumes
+			v.follow = row == len(v.shown)
+		}
+		v.showEntry(row - 1)
+	})
+	v.search.SetDoneFunc(func(tcell.Key) {
+		v.manager.SetFocus(v.table)
+	})
+	v.refresh()
+}
+
+// poll adds the entries logged since the last poll.
+func (v *View) poll() {
+	entries, err := v.tail.read()
+	if err != nil {
+		v.manager.NotifyError(v.Name(), fmt.Sprintf("Error reading logs: %v", err))
+		return
+	}
+	if len(entries) == 0 {
+		return
+	}
+	v.entries = append(v.entries, entries...)
+	if len(v.entries) > maxEntries {
+		v.entries = append([]logger.Entry(nil), v.entries[len(v.entries)-maxEntries:]...)
+	}
+	v.refresh()
+}
+
+// refresh filters the entries again and redraws the table, keeping to the end while following.
+func (v *View) refresh() {
+	v.refreshing = true
+	defer func() { v.refreshing = false }()
+
+	f := newFilter(v.level, v.query)
+	v.shown = v.shown[:0]
+	for _, e := range v.entries {
+		if f.matches(e) {
+			v.shown = append(v.shown, e)
+		}
+	}
+	v.table.SetContent(vtable.New(entryRows(v.shown)))
+
+	follow := "off"
+	if v.follow {
+		follow = "on"
+	}
+	v.table.SetTitle(fmt.Sprintf(" Logs: %d of %d entries | Level ≥ %s | Follow %s ", len(v.shown), len(v.entries), v.level, follow))
+
+	if len(v.shown) == 0 {
+		v.details.Clear()
+		return
+	}
+	row, _ := v.table.GetSelection()
+	if v.follow || row > len(v.shown) {
+		row = len(v.shown)
+		v.table.Select(row, 0)
+	}
+	v.showEntry(row - 1)
+}
+
+func (v *View) showEntry(i int) {
+	if i < 0 || i >= len(v.shown) {
+		return
+	}
+	e := v.shown[i]
+	v.details.SetText(fmt.Sprintf("%s [%s] %s\n\n%s", e.Time.Format("2006-01-02 15:04:05.000"), e.Level, e.Source, e.Message))
+	v.details.ScrollToBeginning()
+}
+
+func (v *View) InputHandler() func(event *tcell.EventKey) *tcell.EventKey {
+	return func(event *tcell.EventKey) *tcell.EventKey {
+		focus := v.manager.App().GetFocus()
+		if focus == v.search {
+			if event.Key() == tcell.KeyEsc {
+				v.search.SetText("")
+				v.manager.SetFocus(v.table)
+				return nil
+			}
+			return event
+		}
+
+		action, pending := v.keys.Resolve(v.KeyContext(focus), event)
+		if pending || v.handleAction(action) {
+			return nil
+		}
+		return event
+	}
+}
+
+func (v *View) handleAction(action string) bool {
+	switch action {
+	case "focus_next":
+		if v.manager.App().GetFocus() == v.table {
+			v.manager.SetFocus(v.details)
+		} else {
+			v.manager.SetFocus(v.table)
+		}
+	case "search":
+		v.manager.SetFocus(v.search)
+	case "level":
+		v.level = nextLevel(v.level)
+		v.refresh()
+	case "follow":
+		v.follow = !v.follow
+		v.refresh()
+	case "first_row":
+		v.table.Select(1, 0).ScrollToBeginning()
+	case "last_row":
+		v.table.Select(len(v.shown), 0).ScrollToEnd()
+	case "diagnostics":
+		v.manager.ShowDiagnostics()
+	default:
+		return false
+	}
+	return true
+}
+
+// entryRows shows entries as a virtual table, one line each.
+type entryRows []logger.Entry
+
+var entryHeaders = []string{"Time", "Level", "Source", "Message"}
+
+func (r entryRows) RowCount() int    { return len(r) }
+func (r entryRows) ColumnCount() int { return len(entryHeaders) }
+
+func (r entryRows) Header(column int) *tview.TableCell {
+	return tview.NewTableCell(entryHeaders[column]).
+		SetTextColor(style.Color(style.Title)).
+		SetSelectable(false)
+}
+
+func (r entryRows) Cell(row, column int) *tview.TableCell {
+	e := r[row]
+	switch column {
+	case 0:
+		return tview.NewTableCell(e.Time.Format("15:04:05.000")).SetTextColor(style.Color(style.Muted))
+	case 1:
+		return tview.NewTableCell(e.Level.String()).SetTextColor(style.Color(levelColor(e.Level)))
+	case 2:
+		return tview.NewTableCell(e.Source).SetTextColor(style.Color(style.Text))
+	default:
+		return tview.NewTableCell(tview.Escape(e.Summary())).SetTextColor(style.Color(style.Text)).SetExpansion(1)
+	}
+}
+
+func levelColor(level logger.Level) style.Role {
+	switch {
+	case level >= logger.ERROR:
+		return style.StatusError
+	case level >= logger.WARN:
+		return style.Warning
+	case level < logger.INFO:
+		return style.Muted
+	default:
+		return style.Text
+	}
+}
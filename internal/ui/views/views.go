@@ -4,6 +4,9 @@ import (
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/gdamore/tcell/v2"
 	"github.com/rivo/tview"
+	"github.com/tpelletiersophos/cloudcutter/internal/deeplink"
+	"github.com/tpelletiersophos/cloudcutter/internal/session"
+	"github.com/tpelletiersophos/cloudcutter/internal/ui/style"
 )
 
 type View interface {
@@ -17,3 +20,29 @@ type View interface {
 type Reinitializer interface {
 	Reinitialize(cfg aws.Config) error
 }
+
+// Linker is implemented by views whose state can be opened from, and shared as, a deep link.
+// ApplyLink may be called on the event loop, so it must not wait for queued updates.
+type Linker interface {
+	ApplyLink(link deeplink.Link)
+	FillLink(link *deeplink.Link)
+}
+
+// Persistent is implemented by views whose query and layout are saved with the profile's session,
+// to be restored the next time the profile is opened.
+type Persistent interface {
+	SaveState(state *session.State)
+	RestoreState(state session.State)
+}
+
+// Themed is implemented by views that color what they render themselves, such as table cells, and
+// redraw it when the theme changes. Components built from a layout config follow the theme anyway.
+type Themed interface {
+	ApplyTheme(t *style.Theme)
+}
+
+// KeyContexter is implemented by views whose panes have keymap contexts of their own, such as
+// "elastic.results". KeyContext names the context focus is in; the help lists its bindings first.
+type KeyContexter interface {
+	KeyContext(focus tview.Primitive) string
+}